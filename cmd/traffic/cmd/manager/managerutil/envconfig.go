@@ -43,7 +43,8 @@ type Env struct {
 	APIPort             uint16        `env:"AGENT_REST_API_PORT,      parser=port-number, default=0"`
 	AgentArrivalTimeout time.Duration `env:"AGENT_ARRIVAL_TIMEOUT,    parser=time.ParseDuration, default=0"`
 
-	MaxReceiveSize resource.Quantity `env:"GRPC_MAX_RECEIVE_SIZE, parser=quantity"`
+	MaxReceiveSize   resource.Quantity `env:"GRPC_MAX_RECEIVE_SIZE,   parser=quantity"`
+	KeepaliveMinTime time.Duration     `env:"GRPC_KEEPALIVE_MIN_TIME, parser=time.ParseDuration, default=10s"`
 
 	PodCIDRStrategy string         `env:"POD_CIDR_STRATEGY, parser=nonempty-string"`
 	PodCIDRs        []netip.Prefix `env:"POD_CIDRS,         parser=split-ipnet, default="`
@@ -51,6 +51,7 @@ func TestEnvconfig(t *testing.T) {
 		ClientDnsExcludeSuffixes: []string{".com", ".io", ".net", ".org", ".ru"},
 		LogLevel:                 "info",
 		MaxReceiveSize:           resource.MustParse("4Mi"),
+		KeepaliveMinTime:         10 * time.Second,
 		PodCIDRStrategy:          "auto",
 		PodIP:                    netip.AddrFrom4([4]byte{203, 0, 113, 18}),
 		ServerPort:               8081,
@@ -73,6 +74,14 @@ func TestEnvconfig(t *testing.T) {
 				e.AgentRegistry = "ghcr.io/telepresenceio"
 			},
 		},
+		"keepalive": {
+			Input: map[string]string{
+				"GRPC_KEEPALIVE_MIN_TIME": "30s",
+			},
+			Output: func(e *managerutil.Env) {
+				e.KeepaliveMinTime = 30 * time.Second
+			},
+		},
 		"complex": {
 			Input: map[string]string{
 				"CLIENT_ROUTING_NEVER_PROXY_SUBNETS": "10.20.30.0/24 10.20.40.0/24",
@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	empty "google.golang.org/protobuf/types/known/emptypb"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/datawire/dlib/derror"
 	"github.com/datawire/dlib/dgroup"
@@ -956,7 +957,13 @@ func (s *service) WatchWorkloads(request *rpc.WorkloadEventsRequest, stream rpc.
 	} else if !s.State().ManagesNamespace(ctx, namespace) {
 		return status.Error(codes.FailedPrecondition, fmt.Sprintf("namespace %s is not managed", namespace))
 	}
-	ww := s.state.NewWorkloadInfoWatcher(clientSession, namespace)
+	var selector labels.Selector
+	if request.LabelSelector != "" {
+		if selector, err = labels.Parse(request.LabelSelector); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid label selector %q: %v", request.LabelSelector, err)
+		}
+	}
+	ww := s.state.NewWorkloadInfoWatcher(clientSession, namespace, selector)
 	return ww.Watch(ctx, stream)
 }
 
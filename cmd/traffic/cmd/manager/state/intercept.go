@@ -215,6 +215,7 @@ func (s *state) RestoreAppContainer(ctx context.Context, ii *managerrpc.Intercep
 			return false, nil
 		}
 		cn.Replace = false
+		cn.KeepAgentResources = false
 
 		// The pods for this workload will be killed once the new updated sidecar
 		// reaches the configmap. We remove them now, so that they don't continue to
@@ -339,6 +340,10 @@ func (s *state) getOrCreateAgentConfig(
 				cn.Replace = agentconfig.ReplacePolicy(spec.Replace)
 				doUpdate = true
 			}
+			if keep := spec.Replace && spec.KeepAgentResources; cn.KeepAgentResources != keep {
+				cn.KeepAgentResources = keep
+				doUpdate = true
+			}
 		}
 		if dryRun {
 			return false, nil
@@ -608,11 +613,8 @@ func findIntercept(ac *agentconfig.Sidecar, spec *managerrpc.InterceptSpec) (fou
 			if foundIC == nil {
 				foundCN = cn
 				if spec.ContainerName != "" {
-					for _, cx := range ac.Containers {
-						if cx.Name == spec.ContainerName {
-							foundCN = cx
-							break
-						}
+					if foundCN, err = findContainer(ac, spec.ContainerName, cn); err != nil {
+						return nil, nil, err
 					}
 				}
 				foundIC = ic
@@ -657,6 +659,25 @@ func findIntercept(ac *agentconfig.Sidecar, spec *managerrpc.InterceptSpec) (fou
 	return nil, nil, errcat.User.Newf("%s %s.%s has no interceptable port%s", ac.WorkloadKind, ac.WorkloadName, ac.Namespace, ss)
 }
 
+// findContainer returns the container of the given config whose name matches the given name, which may be
+// a glob. The dflt container is returned when no container matches.
+func findContainer(ac *agentconfig.Sidecar, name string, dflt *agentconfig.Container) (*agentconfig.Container, error) {
+	names := make([]string, len(ac.Containers))
+	for i, cx := range ac.Containers {
+		names[i] = cx.Name
+	}
+	name, err := agentconfig.MatchContainerName(name, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, cx := range ac.Containers {
+		if cx.Name == name {
+			return cx, nil
+		}
+	}
+	return dflt, nil
+}
+
 type InterceptFinalizer func(ctx context.Context, interceptInfo *managerrpc.InterceptInfo) error
 
 type interceptState struct {
@@ -0,0 +1,54 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
+)
+
+func Test_findInterceptContainerGlob(t *testing.T) {
+	ac := &agentconfig.Sidecar{
+		WorkloadName: "app",
+		WorkloadKind: "Deployment",
+		Namespace:    "default",
+		Containers: []*agentconfig.Container{
+			{
+				Name: "app-1",
+				Intercepts: []*agentconfig.Intercept{{
+					ServiceName:   "app",
+					ContainerPort: 8080,
+					ServicePort:   80,
+					Protocol:      "TCP",
+				}},
+			},
+			{
+				Name: "app-2",
+			},
+		},
+	}
+	find := func(containerName string) (*agentconfig.Container, error) {
+		cn, _, err := findIntercept(ac, &manager.InterceptSpec{ServiceName: "app", ContainerName: containerName})
+		return cn, err
+	}
+
+	cn, err := find("app-2*")
+	require.NoError(t, err)
+	assert.Equal(t, "app-2", cn.Name)
+
+	cn, err = find("app-1*")
+	require.NoError(t, err)
+	assert.Equal(t, "app-1", cn.Name)
+
+	_, err = find("app-*")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "app-1, app-2")
+
+	// No match falls back to the container that owns the intercepted port.
+	cn, err = find("other-*")
+	require.NoError(t, err)
+	assert.Equal(t, "app-1", cn.Name)
+}
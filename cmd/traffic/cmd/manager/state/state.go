@@ -18,6 +18,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/datawire/k8sapi/pkg/k8sapi"
@@ -93,7 +94,7 @@ type State interface {
 	WatchWorkloads(ctx context.Context, sessionID string) (ch <-chan []workload.Event, err error)
 	WatchLookupDNS(string) <-chan *rpc.DNSRequest
 	ValidateCreateAgent(context.Context, k8sapi.Workload, agentconfig.SidecarExt) error
-	NewWorkloadInfoWatcher(clientSession, namespace string) WorkloadInfoWatcher
+	NewWorkloadInfoWatcher(clientSession, namespace string, selector labels.Selector) WorkloadInfoWatcher
 	ManagesNamespace(context.Context, string) bool
 }
 
@@ -555,13 +556,15 @@ func (s *state) AddIntercept(ctx context.Context, sessionID, clusterID string, c
 }
 
 func (s *state) NewInterceptInfo(interceptID string, session *rpc.SessionInfo, ciReq *rpc.CreateInterceptRequest) *rpc.InterceptInfo {
+	now := timestamppb.Now()
 	return &rpc.InterceptInfo{
 		Spec:          ciReq.InterceptSpec,
 		Disposition:   rpc.InterceptDispositionType_WAITING,
 		Message:       "Waiting for Agent approval",
 		Id:            interceptID,
 		ClientSession: session,
-		ModifiedAt:    timestamppb.Now(),
+		ModifiedAt:    now,
+		CreatedAt:     now,
 	}
 }
 
@@ -6,13 +6,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/blang/semver/v4"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/datawire/k8sapi/pkg/k8sapi"
 	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/cmd/traffic/cmd/manager/managerutil"
 	"github.com/telepresenceio/telepresence/v2/pkg/agentmap"
 	"github.com/telepresenceio/telepresence/v2/pkg/workload"
 )
@@ -25,6 +28,7 @@ type workloadInfoWatcher struct {
 	State
 	clientSession  string
 	namespace      string
+	selector       labels.Selector
 	stream         rpc.Manager_WatchWorkloadsServer
 	workloadEvents map[string]*rpc.WorkloadEvent
 	lastEvents     map[string]*rpc.WorkloadEvent
@@ -32,13 +36,22 @@ type workloadInfoWatcher struct {
 	interceptInfos map[string]*rpc.InterceptInfo
 	start          time.Time
 	ticker         *time.Ticker
+
+	// agentVersion is the version of the traffic-agent that the traffic-manager injects, or nil if unknown.
+	agentVersion *semver.Version
+
+	// matched contains the names of the workloads that have been reported as matching the selector.
+	matched map[string]struct{}
 }
 
-func (s *state) NewWorkloadInfoWatcher(clientSession, namespace string) WorkloadInfoWatcher {
+// NewWorkloadInfoWatcher returns a watcher for the workloads in the given namespace. Only workloads that match
+// the given label selector are reported when the selector is non-nil.
+func (s *state) NewWorkloadInfoWatcher(clientSession, namespace string, selector labels.Selector) WorkloadInfoWatcher {
 	return &workloadInfoWatcher{
 		State:         s,
 		clientSession: clientSession,
 		namespace:     namespace,
+		selector:      selector,
 	}
 }
 
@@ -52,10 +65,13 @@ func (wf *workloadInfoWatcher) Watch(ctx context.Context, stream rpc.Manager_Wat
 		wf.agentInfos = nil
 		wf.interceptInfos = nil
 		wf.workloadEvents = nil
+		wf.matched = nil
 	}()
 
 	wf.stream = stream
+	wf.agentVersion = expectedAgentVersion(ctx)
 	wf.workloadEvents = make(map[string]*rpc.WorkloadEvent)
+	wf.matched = make(map[string]struct{})
 
 	sessionDone, err := wf.SessionDone(wf.clientSession)
 	if err != nil {
@@ -114,14 +130,20 @@ func (wf *workloadInfoWatcher) Watch(ctx context.Context, stream rpc.Manager_Wat
 func (wf *workloadInfoWatcher) getIntercepts(name, namespace string) (iis []*rpc.WorkloadInfo_Intercept) {
 	for _, ii := range wf.interceptInfos {
 		if name == ii.Spec.Agent && namespace == ii.Spec.Namespace && ii.Disposition == rpc.InterceptDispositionType_ACTIVE {
-			iis = append(iis, &rpc.WorkloadInfo_Intercept{
-				Client: ii.Spec.Client,
-			})
+			iis = append(iis, interceptHolder(ii))
 		}
 	}
 	return iis
 }
 
+// interceptHolder returns the client that holds the given intercept, and when the intercept was created.
+func interceptHolder(ii *rpc.InterceptInfo) *rpc.WorkloadInfo_Intercept {
+	return &rpc.WorkloadInfo_Intercept{
+		Client:    ii.Spec.Client,
+		CreatedAt: ii.CreatedAt,
+	}
+}
+
 func (wf *workloadInfoWatcher) sendEvents(ctx context.Context, sendEmpty bool) {
 	// Time to send what we have
 	wf.ticker.Reset(time.Duration(math.MaxInt64))
@@ -178,22 +200,72 @@ func rpcWorkloadState(s workload.State) (state rpc.WorkloadInfo_State) {
 		state = rpc.WorkloadInfo_AVAILABLE
 	case workload.StateProgressing:
 		state = rpc.WorkloadInfo_PROGRESSING
+	case workload.StatePaused:
+		state = rpc.WorkloadInfo_PAUSED
 	default:
 		state = rpc.WorkloadInfo_UNKNOWN_UNSPECIFIED
 	}
 	return state
 }
 
-func rpcWorkload(wl k8sapi.Workload, as rpc.WorkloadInfo_AgentState, iClients []*rpc.WorkloadInfo_Intercept) *rpc.WorkloadInfo {
+func rpcWorkload(wl k8sapi.Workload, as rpc.WorkloadInfo_AgentState, iClients []*rpc.WorkloadInfo_Intercept, outdated bool) *rpc.WorkloadInfo {
 	return &rpc.WorkloadInfo{
-		Kind:             rpcKind(wl.GetKind()),
-		Name:             wl.GetName(),
-		Namespace:        wl.GetNamespace(),
-		Uid:              string(wl.GetUID()),
-		State:            rpcWorkloadState(workload.GetWorkloadState(wl)),
-		AgentState:       as,
-		InterceptClients: iClients,
+		Kind:                 rpcKind(wl.GetKind()),
+		Name:                 wl.GetName(),
+		Namespace:            wl.GetNamespace(),
+		Uid:                  string(wl.GetUID()),
+		State:                rpcWorkloadState(workload.GetWorkloadState(wl)),
+		AgentState:           as,
+		InterceptClients:     iClients,
+		AgentVersionMismatch: outdated,
+	}
+}
+
+// expectedAgentVersion returns the version of the traffic-agent that the traffic-manager injects, i.e. the
+// tag of the agent image, or nil when no agent image is configured or its tag isn't a version.
+func expectedAgentVersion(ctx context.Context) *semver.Version {
+	ir := managerutil.GetAgentImageRetriever(ctx)
+	if ir == nil {
+		return nil
+	}
+	img := ir.GetImage()
+	i := strings.LastIndexByte(img, ':')
+	if i < 0 || strings.ContainsRune(img[i:], '/') {
+		return nil
+	}
+	v, err := semver.Parse(strings.TrimPrefix(img[i+1:], "v"))
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+// agentOutdated returns true if an agent of the given workload has a version that differs from the
+// version that the traffic-manager injects.
+func (wf *workloadInfoWatcher) agentOutdated(name, namespace string) bool {
+	if wf.agentVersion == nil {
+		return false
+	}
+	for _, a := range wf.agentInfos {
+		if a.Name == name && a.Namespace == namespace {
+			if v, err := semver.Parse(strings.TrimPrefix(a.Version, "v")); err == nil && !v.EQ(*wf.agentVersion) {
+				return true
+			}
+		}
 	}
+	return false
+}
+
+// matches returns true if the workload matches the label selector of this watcher.
+func (wf *workloadInfoWatcher) matches(wl k8sapi.Workload) bool {
+	return wf.selector == nil || wf.selector.Matches(labels.Set(wl.GetLabels()))
+}
+
+// reported returns true if the named workload has been reported as matching the label selector, and hasn't
+// been reported as deleted since.
+func (wf *workloadInfoWatcher) reported(name string) bool {
+	_, ok := wf.matched[name]
+	return ok
 }
 
 func (wf *workloadInfoWatcher) addEvent(
@@ -202,28 +274,50 @@ func (wf *workloadInfoWatcher) addEvent(
 	as rpc.WorkloadInfo_AgentState,
 	iClients []*rpc.WorkloadInfo_Intercept,
 ) {
+	if !wf.matches(wl) {
+		return
+	}
+	if eventType == workload.EventTypeDelete {
+		delete(wf.matched, wl.GetName())
+	} else if wf.matched != nil {
+		wf.matched[wl.GetName()] = struct{}{}
+	}
+	outdated := as != rpc.WorkloadInfo_NO_AGENT_UNSPECIFIED && wf.agentOutdated(wl.GetName(), wl.GetNamespace())
 	wf.workloadEvents[wl.GetName()] = &rpc.WorkloadEvent{
 		Type:     rpc.WorkloadEvent_Type(eventType),
-		Workload: rpcWorkload(wl, as, iClients),
+		Workload: rpcWorkload(wl, as, iClients, outdated),
 	}
 	wf.resetTicker()
 }
 
 func (wf *workloadInfoWatcher) handleWorkloadsSnapshot(ctx context.Context, wes []workload.Event, initial bool) {
-	if len(wes) == 0 {
-		if initial {
-			// The initial snapshot may be empty, but must be sent anyway.
-			wf.sendEvents(ctx, true)
-		}
-		return
+	if initial {
+		// The initial snapshot must be sent, even when it's empty or when none of its workloads match the
+		// selector, because the client waits for it.
+		defer wf.sendEvents(ctx, true)
 	}
 	for _, we := range wes {
 		wl := we.Workload
 		if wf.namespace != "" && wl.GetNamespace() != wf.namespace {
 			continue
 		}
+		if !wf.matches(wl) {
+			// A workload that no longer matches the selector, e.g. because its labels were changed, is
+			// reported as deleted.
+			if wf.reported(wl.GetName()) {
+				delete(wf.matched, wl.GetName())
+				dlog.Debugf(ctx, "WorkloadInfoEvent: Workload %s %s.%s no longer matches %s", wl.GetKind(), wl.GetName(), wl.GetNamespace(), wf.selector)
+				wf.workloadEvents[wl.GetName()] = &rpc.WorkloadEvent{
+					Type:     rpc.WorkloadEvent_DELETED,
+					Workload: rpcWorkload(wl, rpc.WorkloadInfo_NO_AGENT_UNSPECIFIED, nil, false),
+				}
+				wf.resetTicker()
+			}
+			continue
+		}
 		if w, ok := wf.workloadEvents[wl.GetName()]; ok {
 			if we.Type == workload.EventTypeDelete && w.Type != rpc.WorkloadEvent_DELETED {
+				delete(wf.matched, wl.GetName())
 				w.Type = rpc.WorkloadEvent_DELETED
 				dlog.Debugf(ctx, "WorkloadInfoEvent: Workload %s %s %s.%s %s", we.Type, wl.GetKind(), wl.GetName(), wl.GetNamespace(), workload.GetWorkloadState(wl))
 				wf.resetTicker()
@@ -231,6 +325,7 @@ func (wf *workloadInfoWatcher) handleWorkloadsSnapshot(ctx context.Context, wes
 		} else {
 			var iClients []*rpc.WorkloadInfo_Intercept
 			as := rpc.WorkloadInfo_NO_AGENT_UNSPECIFIED
+			outdated := false
 			if wf.HasAgent(wl.GetName(), wl.GetNamespace()) {
 				if iis := wf.getIntercepts(wl.GetName(), wl.GetNamespace()); len(iis) > 0 {
 					as = rpc.WorkloadInfo_INTERCEPTED
@@ -238,6 +333,7 @@ func (wf *workloadInfoWatcher) handleWorkloadsSnapshot(ctx context.Context, wes
 				} else {
 					as = rpc.WorkloadInfo_INSTALLED
 				}
+				outdated = wf.agentOutdated(wl.GetName(), wl.GetNamespace())
 			}
 
 			// If we've sent an ADDED event for this workload, and this is a MODIFIED event without any changes that
@@ -245,7 +341,7 @@ func (wf *workloadInfoWatcher) handleWorkloadsSnapshot(ctx context.Context, wes
 			if we.Type == workload.EventTypeUpdate {
 				lew, ok := wf.lastEvents[wl.GetName()]
 				if ok && (lew.Type == rpc.WorkloadEvent_ADDED_UNSPECIFIED || lew.Type == rpc.WorkloadEvent_MODIFIED) &&
-					proto.Equal(lew.Workload, rpcWorkload(we.Workload, as, iClients)) {
+					proto.Equal(lew.Workload, rpcWorkload(we.Workload, as, iClients, outdated)) {
 					break
 				}
 			}
@@ -266,6 +362,7 @@ func (wf *workloadInfoWatcher) handleAgentSnapshot(ctx context.Context, ais map[
 				wl := w.Workload
 				if wl.AgentState != as {
 					wl.AgentState = as
+					wl.AgentVersionMismatch = false
 					dlog.Debugf(ctx, "WorkloadInfoEvent: AgentInfo %s.%s %s %s", a.Name, a.Namespace, as, wl.State)
 					wf.resetTicker()
 				}
@@ -274,7 +371,8 @@ func (wf *workloadInfoWatcher) handleAgentSnapshot(ctx context.Context, ais map[
 				wf.addEvent(workload.EventTypeUpdate, wl, as, nil)
 			} else {
 				dlog.Debugf(ctx, "Unable to get workload %s.%s: %v", name, a.Namespace, err)
-				if errors.IsNotFound(err) {
+				if errors.IsNotFound(err) && (wf.selector == nil || wf.reported(name)) {
+					delete(wf.matched, name)
 					wf.workloadEvents[name] = &rpc.WorkloadEvent{
 						Type: rpc.WorkloadEvent_DELETED,
 						Workload: &rpc.WorkloadInfo{
@@ -304,6 +402,11 @@ func (wf *workloadInfoWatcher) handleAgentSnapshot(ctx context.Context, ais map[
 				wl.InterceptClients = iClients
 				wf.resetTicker()
 			}
+			if outdated := wf.agentOutdated(name, a.Namespace); wl.AgentVersionMismatch != outdated {
+				dlog.Debugf(ctx, "WorkloadInfoEvent: AgentInfo %s.%s version %s mismatch %t", a.Name, a.Namespace, a.Version, outdated)
+				wl.AgentVersionMismatch = outdated
+				wf.resetTicker()
+			}
 		} else if wl, err := agentmap.GetWorkload(ctx, name, a.Namespace, ""); err == nil {
 			dlog.Debugf(ctx, "WorkloadInfoEvent: AgentInfo %s.%s %s %s", a.Name, a.Namespace, as, workload.GetWorkloadState(wl))
 			wf.addEvent(workload.EventTypeUpdate, wl, as, iClients)
@@ -344,7 +447,7 @@ func (wf *workloadInfoWatcher) handleInterceptSnapshot(ctx context.Context, iis
 		iClients := make([]*rpc.WorkloadInfo_Intercept, len(iis))
 		as := rpc.WorkloadInfo_INTERCEPTED
 		for i, ii := range iis {
-			iClients[i] = &rpc.WorkloadInfo_Intercept{Client: ii.Spec.Client}
+			iClients[i] = interceptHolder(ii)
 		}
 		if w, ok := wf.workloadEvents[name]; ok && w.Type != rpc.WorkloadEvent_DELETED {
 			if w.Workload.AgentState != as {
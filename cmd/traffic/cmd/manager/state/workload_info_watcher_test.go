@@ -0,0 +1,197 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	apps "k8s.io/api/apps/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/cmd/traffic/cmd/manager/managerutil"
+	"github.com/telepresenceio/telepresence/v2/pkg/workload"
+)
+
+func Test_interceptHolder(t *testing.T) {
+	s := &state{}
+	ii := s.NewInterceptInfo("session:app", &manager.SessionInfo{SessionId: "session"}, &manager.CreateInterceptRequest{
+		InterceptSpec: &manager.InterceptSpec{Name: "app", Client: "alice@laptop", Agent: "app", Namespace: "default"},
+	})
+	assert.NotNil(t, ii.CreatedAt)
+	assert.Equal(t, ii.ModifiedAt, ii.CreatedAt)
+
+	ii.Disposition = manager.InterceptDispositionType_ACTIVE
+	wf := &workloadInfoWatcher{interceptInfos: map[string]*manager.InterceptInfo{ii.Id: ii}}
+	holders := wf.getIntercepts("app", "default")
+	if assert.Len(t, holders, 1) {
+		assert.Equal(t, "alice@laptop", holders[0].Client)
+		assert.Equal(t, ii.CreatedAt, holders[0].CreatedAt)
+	}
+	assert.Empty(t, wf.getIntercepts("app", "other"))
+}
+
+type fakeWorkloadEventsStream struct {
+	grpc.ServerStream
+	deltas []*manager.WorkloadEventsDelta
+}
+
+func (s *fakeWorkloadEventsStream) Send(delta *manager.WorkloadEventsDelta) error {
+	s.deltas = append(s.deltas, delta)
+	return nil
+}
+
+// newSelectorWatcher returns a watcher for the "default" namespace that is ready to handle snapshots, and the
+// stream that receives what it sends.
+func newSelectorWatcher(t *testing.T, selector string) (*workloadInfoWatcher, *fakeWorkloadEventsStream) {
+	ctx := dlog.NewTestContext(t, false)
+	sel, err := labels.Parse(selector)
+	require.NoError(t, err)
+	wf := NewState(ctx).NewWorkloadInfoWatcher("session", "default", sel).(*workloadInfoWatcher)
+	stream := &fakeWorkloadEventsStream{}
+	wf.stream = stream
+	wf.workloadEvents = make(map[string]*manager.WorkloadEvent)
+	wf.matched = make(map[string]struct{})
+	wf.ticker = time.NewTicker(time.Hour)
+	t.Cleanup(wf.ticker.Stop)
+	return wf, stream
+}
+
+func deployment(name string, lbs map[string]string) k8sapi.Workload {
+	return k8sapi.Deployment(&apps.Deployment{
+		TypeMeta:   meta.TypeMeta{Kind: "Deployment"},
+		ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default", Labels: lbs},
+	})
+}
+
+func deltaEvents(delta *manager.WorkloadEventsDelta) map[string]manager.WorkloadEvent_Type {
+	evs := make(map[string]manager.WorkloadEvent_Type, len(delta.Events))
+	for _, ev := range delta.Events {
+		evs[ev.Workload.Name] = ev.Type
+	}
+	return evs
+}
+
+func Test_workloadInfoWatcherSelector(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	wf, stream := newSelectorWatcher(t, "app=echo")
+	events := func() map[string]manager.WorkloadEvent_Type {
+		evs := make(map[string]manager.WorkloadEvent_Type, len(wf.workloadEvents))
+		for name, ev := range wf.workloadEvents {
+			evs[name] = ev.Type
+		}
+		wf.lastEvents = wf.workloadEvents
+		wf.workloadEvents = make(map[string]*manager.WorkloadEvent)
+		return evs
+	}
+
+	echo := deployment("echo", map[string]string{"app": "echo"})
+	other := deployment("other", map[string]string{"app": "other"})
+	wf.handleWorkloadsSnapshot(ctx, []workload.Event{
+		{Type: workload.EventTypeAdd, Workload: echo},
+		{Type: workload.EventTypeAdd, Workload: other},
+	}, true)
+	require.Len(t, stream.deltas, 1)
+	assert.Equal(t, map[string]manager.WorkloadEvent_Type{"echo": manager.WorkloadEvent_ADDED_UNSPECIFIED}, deltaEvents(stream.deltas[0]))
+	assert.Empty(t, events())
+
+	// A workload that stops matching is reported as deleted.
+	wf.handleWorkloadsSnapshot(ctx, []workload.Event{
+		{Type: workload.EventTypeUpdate, Workload: deployment("echo", map[string]string{"app": "changed"})},
+		{Type: workload.EventTypeUpdate, Workload: other},
+	}, false)
+	assert.Equal(t, map[string]manager.WorkloadEvent_Type{"echo": manager.WorkloadEvent_DELETED}, events())
+
+	// Deletion of a workload that was never reported produces no event.
+	wf.handleWorkloadsSnapshot(ctx, []workload.Event{{Type: workload.EventTypeDelete, Workload: other}}, false)
+	assert.Empty(t, events())
+
+	// Deletion of a matching workload is reported.
+	wf.handleWorkloadsSnapshot(ctx, []workload.Event{{Type: workload.EventTypeAdd, Workload: echo}}, false)
+	assert.Equal(t, map[string]manager.WorkloadEvent_Type{"echo": manager.WorkloadEvent_ADDED_UNSPECIFIED}, events())
+	wf.handleWorkloadsSnapshot(ctx, []workload.Event{{Type: workload.EventTypeDelete, Workload: echo}}, false)
+	assert.Equal(t, map[string]manager.WorkloadEvent_Type{"echo": manager.WorkloadEvent_DELETED}, events())
+}
+
+func Test_workloadInfoWatcherSelectorNoMatch(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	wf, stream := newSelectorWatcher(t, "foo=bar")
+
+	// The initial snapshot is sent even when none of its workloads match, so that the client isn't left waiting.
+	wf.handleWorkloadsSnapshot(ctx, []workload.Event{
+		{Type: workload.EventTypeAdd, Workload: deployment("echo", map[string]string{"app": "echo"})},
+		{Type: workload.EventTypeAdd, Workload: deployment("other", map[string]string{"app": "other"})},
+	}, true)
+	require.Len(t, stream.deltas, 1)
+	assert.Empty(t, stream.deltas[0].Events)
+
+	// Subsequent snapshots without matches send nothing.
+	wf.handleWorkloadsSnapshot(ctx, []workload.Event{
+		{Type: workload.EventTypeUpdate, Workload: deployment("echo", map[string]string{"app": "echo", "x": "y"})},
+	}, false)
+	wf.sendEvents(ctx, false)
+	assert.Len(t, stream.deltas, 1)
+}
+
+func Test_expectedAgentVersion(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	assert.Nil(t, expectedAgentVersion(ctx))
+	for img, want := range map[string]string{
+		"ghcr.io/telepresenceio/tel2:2.22.0":  "2.22.0",
+		"ghcr.io/telepresenceio/tel2:v2.21.3": "2.21.3",
+		"localhost:5000/tel2":                 "",
+		"ghcr.io/telepresenceio/tel2:latest":  "",
+	} {
+		v := expectedAgentVersion(managerutil.WithResolvedAgentImageRetriever(ctx, managerutil.ImageFromEnv(img)))
+		if want == "" {
+			assert.Nil(t, v, img)
+		} else if assert.NotNil(t, v, img) {
+			assert.Equal(t, want, v.String())
+		}
+	}
+}
+
+func Test_workloadInfoWatcherAgentVersionMismatch(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	ctx = managerutil.WithResolvedAgentImageRetriever(ctx, managerutil.ImageFromEnv("ghcr.io/telepresenceio/tel2:2.22.0"))
+	wf := NewState(ctx).NewWorkloadInfoWatcher("session", "default", nil).(*workloadInfoWatcher)
+	wf.agentVersion = expectedAgentVersion(ctx)
+	wf.workloadEvents = make(map[string]*manager.WorkloadEvent)
+	wf.ticker = time.NewTicker(time.Hour)
+	defer wf.ticker.Stop()
+
+	agents := func(version string) map[string]*manager.AgentInfo {
+		return map[string]*manager.AgentInfo{
+			"echo-7f6d5c": {Name: "echo", Namespace: "default", PodName: "echo-7f6d5c", Version: version},
+		}
+	}
+	echo := k8sapi.Deployment(&apps.Deployment{
+		TypeMeta:   meta.TypeMeta{Kind: "Deployment"},
+		ObjectMeta: meta.ObjectMeta{Name: "echo", Namespace: "default"},
+	})
+
+	wf.agentInfos = agents("v2.22.0")
+	wf.addEvent(workload.EventTypeAdd, echo, manager.WorkloadInfo_INSTALLED, nil)
+	assert.False(t, wf.workloadEvents["echo"].Workload.AgentVersionMismatch)
+
+	// An agent of another version, e.g. one injected by a traffic-manager that has since been upgraded.
+	wf.handleAgentSnapshot(ctx, agents("v2.21.3"))
+	assert.True(t, wf.workloadEvents["echo"].Workload.AgentVersionMismatch)
+
+	// The mismatch is reported in a MODIFIED event once the previous event has been sent.
+	wf.lastEvents = wf.workloadEvents
+	wf.workloadEvents = make(map[string]*manager.WorkloadEvent)
+	wf.addEvent(workload.EventTypeUpdate, echo, manager.WorkloadInfo_INSTALLED, nil)
+	ev := wf.workloadEvents["echo"]
+	assert.Equal(t, manager.WorkloadEvent_MODIFIED, ev.Type)
+	assert.True(t, ev.Workload.AgentVersionMismatch)
+
+	// A re-injected agent clears the flag.
+	wf.handleAgentSnapshot(ctx, agents("v2.22.0"))
+	assert.False(t, wf.workloadEvents["echo"].Workload.AgentVersionMismatch)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 
 	"github.com/go-json-experiment/json"
 	core "k8s.io/api/core/v1"
@@ -112,6 +113,19 @@ func (s *cidrConflictSuite) Test_AutoConflictResolution() {
 	err = json.Unmarshal(envData, &env)
 	rq.NoError(err)
 
+	// The listed translations must contain the virtual IPs that were found in the environment.
+	var vips []struct {
+		RemoteIP  netip.Addr `json:"remote_ip"`
+		VirtualIP netip.Addr `json:"virtual_ip"`
+		Subnet    string     `json:"subnet"`
+	}
+	rq.NoError(json.Unmarshal([]byte(itest.TelepresenceOk(ctx, "vip", "list", "--output", "json")), &vips))
+	listed := make(map[netip.Addr]netip.Addr, len(vips))
+	for _, vi := range vips {
+		rq.Equal(viSn.String(), vi.Subnet)
+		listed[vi.VirtualIP] = vi.RemoteIP
+	}
+
 	// Verify that these IPs in the environment have been translated into virtual IPs.
 	for _, key := range []string{"LISTEN_ADDRESS", "ECHO_SERVICE_HOST"} {
 		addrVal, ok := env[key]
@@ -119,6 +133,10 @@ func (s *cidrConflictSuite) Test_AutoConflictResolution() {
 		addr, err := netip.ParseAddr(addrVal)
 		rq.NoError(err)
 		rq.Truef(viSn.Contains(addr), "virtual subnet %s does not contain %s %s", viSn, key, addr)
+		remote, ok := listed[addr]
+		rq.Truef(ok, "virtual IP %s of %s is not listed by telepresence vip list", addr, key)
+		rq.Truef(slices.ContainsFunc(s.subnets, func(sn netip.Prefix) bool { return sn.Contains(remote) }),
+			"remote IP %s of %s is not in any of the cluster subnets %v", remote, key, s.subnets)
 	}
 }
 
@@ -1,8 +1,10 @@
 package integration_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/telepresenceio/telepresence/v2/integration_test/itest"
 )
@@ -55,3 +57,17 @@ func (s *connectedSuite) Test_StatusWithJSON() {
 	s.NotEmpty(status.TrafficManager.Version)
 	s.NotEmpty(status.TrafficManager.TrafficAgent)
 }
+
+func (s *connectedSuite) Test_DNSSearch() {
+	// The search paths must match the namespace that was used when connecting.
+	var sp struct {
+		SearchPaths  []string `json:"search_paths"`
+		DropSuffixes []string `json:"drop_suffixes"`
+	}
+	s.Eventually(func() bool {
+		stdout, _, err := itest.Telepresence(s.Context(), "dns", "search", "--output", "json")
+		return err == nil && json.Unmarshal([]byte(stdout), &sp) == nil && len(sp.SearchPaths) == 2
+	}, 10*time.Second, time.Second)
+	s.Equal([]string{"tel2-search", s.AppNamespace()}, sp.SearchPaths)
+	s.Contains(sp.DropSuffixes, "tel2-search.")
+}
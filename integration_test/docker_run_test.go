@@ -2,6 +2,8 @@ package integration_test
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"path/filepath"
 	"regexp"
 	goRuntime "runtime"
@@ -292,3 +294,65 @@ func (s *dockerDaemonSuite) Test_DockerRunCommand() {
 	dlog.Infof(ctx, "stdout = %s", stdout)
 	s.Contains(stdout, "dev tel0")
 }
+
+func (s *dockerDaemonSuite) Test_DockerRun_PublishUDP() {
+	svc := "echo"
+	ctx := s.Context()
+	s.ApplyEchoService(ctx, svc, 80)
+	defer s.DeleteSvcAndWorkload(ctx, "deploy", svc)
+
+	s.TelepresenceConnect(ctx, "--docker")
+	defer itest.TelepresenceQuitOk(ctx)
+
+	// The handler is a UDP echo server that listens on the published port.
+	soft, softCancel := context.WithCancel(dcontext.WithSoftness(ctx))
+	wch := make(chan struct{})
+	go func() {
+		defer close(wch)
+		so, se, err := itest.Telepresence(soft, "intercept", "--mount", "false", svc,
+			"--docker-run", "--publish", "5353:5353/udp", "--", "--rm", "alpine/socat", "udp-recvfrom:5353,fork", "exec:cat")
+		dlog.Info(ctx, so)
+		if se != "" {
+			dlog.Error(ctx, se)
+		}
+		if err != nil {
+			dlog.Error(ctx, err.Error())
+		}
+	}()
+	defer func() {
+		softCancel()
+		select {
+		case <-wch:
+		case <-time.After(30 * time.Second):
+			s.Fail("interceptor did not terminate")
+		}
+	}()
+
+	// Each echo uses a new connection, and hence a new source port, so that the publisher must serve more than one peer.
+	echo := func(msg string) bool {
+		conn, err := net.Dial("udp", "127.0.0.1:5353")
+		if err != nil {
+			dlog.Error(ctx, err)
+			return false
+		}
+		defer conn.Close()
+		if _, err = conn.Write([]byte(msg)); err != nil {
+			dlog.Error(ctx, err)
+			return false
+		}
+		if err = conn.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+			return false
+		}
+		buf := make([]byte, 256)
+		n, err := conn.Read(buf)
+		if err != nil {
+			dlog.Error(ctx, err)
+			return false
+		}
+		return string(buf[:n]) == msg
+	}
+	s.Eventually(func() bool { return echo("Hello") }, 60*time.Second, 3*time.Second, "UDP echo through published port never succeeds")
+	for i := 0; i < 3; i++ {
+		s.True(echo(fmt.Sprintf("Hello from peer %d", i)))
+	}
+}
@@ -297,3 +297,90 @@ func (s *ingestSuite) Test_IngestProxyVia() {
 		rq.NoError(err)
 	}))
 }
+
+func (s *ingestSuite) Test_IngestPod() {
+	ctx := s.Context()
+	rq := s.Require()
+	s.TelepresenceConnect(ctx)
+	defer itest.TelepresenceDisconnectOk(ctx)
+
+	// Ingest once to ensure that the traffic-agent is injected before scaling up.
+	itest.TelepresenceOk(ctx, "ingest", "--mount", "false", "echo")
+	itest.TelepresenceOk(ctx, "leave", "echo")
+
+	rq.NoError(s.Kubectl(ctx, "scale", "deploy", "echo", "--replicas", "2"))
+	defer func() {
+		s.NoError(s.Kubectl(ctx, "scale", "deploy", "echo", "--replicas", "1"))
+		s.Eventually(func() bool {
+			return len(itest.RunningPodNames(ctx, "echo", s.AppNamespace())) == 1
+		}, itest.PodCreateTimeout(ctx), 2*time.Second)
+	}()
+
+	var pods []string
+	rq.Eventually(func() bool {
+		pods = itest.RunningPodNames(ctx, "echo", s.AppNamespace())
+		return len(pods) == 2
+	}, itest.PodCreateTimeout(ctx), 2*time.Second)
+
+	for _, pod := range pods {
+		var rsp ingest.Info
+		rq.Eventually(func() bool {
+			js, _, err := itest.Telepresence(ctx, "ingest", "--mount", "false", "--pod", pod, "echo", "--output", "json")
+			return err == nil && json.Unmarshal([]byte(js), &rsp) == nil
+		}, 30*time.Second, 3*time.Second)
+		s.Equal(pod, rsp.PodName)
+		itest.TelepresenceOk(ctx, "leave", "echo")
+	}
+
+	_, stderr, err := itest.Telepresence(ctx, "ingest", "--mount", "false", "--pod", "echo-no-such-pod", "echo")
+	s.Error(err)
+	s.Contains(stderr, "has no pod named echo-no-such-pod")
+}
+
+func (s *ingestSuite) Test_IngestWritable() {
+	ctx := s.Context()
+	mountPoint := s.mountPoint()
+	if mountPoint == "false" {
+		s.T().Skip("Mounts are disabled")
+	}
+	s.TelepresenceConnect(ctx)
+	defer itest.TelepresenceDisconnectOk(ctx)
+
+	js, stderr, err := itest.Telepresence(ctx, "ingest", "--mount", mountPoint, "--writable", "echo-env", "--output", "json")
+	s.Require().NoError(err)
+	defer itest.TelepresenceOk(ctx, "leave", "echo-env")
+	var rsp ingest.Info
+	s.Require().NoError(json.Unmarshal([]byte(js), &rsp))
+	s.Require().NotNil(rsp.Mount)
+	s.False(rsp.Mount.ReadOnly)
+	s.Contains(stderr, "mounted read-write")
+}
+
+func (s *ingestSuite) Test_IngestList() {
+	ctx := s.Context()
+	rq := s.Require()
+	s.TelepresenceConnect(ctx)
+	defer itest.TelepresenceDisconnectOk(ctx)
+
+	s.Equal("No active ingests", itest.TelepresenceOk(ctx, "ingest", "--list"))
+
+	itest.TelepresenceOk(ctx, "ingest", "--mount", "false", "echo")
+	defer itest.TelepresenceOk(ctx, "leave", "echo")
+	itest.TelepresenceOk(ctx, "ingest", "--mount", "false", "echo-env")
+	defer itest.TelepresenceOk(ctx, "leave", "echo-env")
+
+	var list struct {
+		Stdout []ingest.ListEntry `json:"stdout"`
+	}
+	rq.NoError(json.Unmarshal([]byte(itest.TelepresenceOk(ctx, "ingest", "--list", "--output", "json")), &list))
+	rq.Len(list.Stdout, 2)
+	s.Equal("echo", list.Stdout[0].Workload)
+	s.Equal("echo", list.Stdout[0].Container)
+	s.NotEmpty(list.Stdout[0].PodIP)
+	s.Equal("echo-env", list.Stdout[1].Workload)
+	s.Equal("echo-env", list.Stdout[1].Container)
+
+	stdout := itest.TelepresenceOk(ctx, "ingest", "--list")
+	s.Contains(stdout, "WORKLOAD")
+	s.Contains(stdout, "echo-env")
+}
@@ -0,0 +1,44 @@
+package integration_test
+
+import (
+	"regexp"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/telepresenceio/telepresence/v2/integration_test/itest"
+)
+
+func (s *singleServiceSuite) Test_InterceptDuration() {
+	ctx := s.Context()
+	port, cancel := itest.StartLocalHttpEchoServer(ctx, s.ServiceName())
+	defer cancel()
+
+	rxIntercepted := regexp.MustCompile(s.ServiceName() + `\s*: intercepted`)
+	itest.TelepresenceOk(ctx, "intercept", "--port", strconv.Itoa(port), "--duration", "5s", s.ServiceName())
+	defer func() {
+		// Harmless if the intercept is already gone.
+		_, _, _ = itest.Telepresence(ctx, "leave", s.ServiceName())
+	}()
+	s.Regexp(rxIntercepted, itest.TelepresenceOk(ctx, "list", "--intercepts"))
+
+	// The intercept must be gone once the duration has elapsed.
+	s.Eventually(func() bool {
+		stdout, _, err := itest.Telepresence(ctx, "list", "--intercepts")
+		return err == nil && !rxIntercepted.MatchString(stdout)
+	}, 20*time.Second, time.Second)
+}
+
+func (s *singleServiceSuite) Test_InterceptDurationStopsHandler() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("The handler uses the sleep command")
+	}
+	ctx := s.Context()
+	start := time.Now()
+
+	// The handler would run for a minute, but the intercept ends after five seconds. That is not an error.
+	itest.TelepresenceOk(ctx, "intercept", "--mount", "false", "--port", "9080", "--duration", "5s", s.ServiceName(), "--", "sleep", "60")
+	s.Less(time.Since(start), 50*time.Second)
+	stdout := itest.TelepresenceOk(ctx, "list", "--intercepts")
+	s.NotRegexp(s.ServiceName()+`\s*: intercepted`, stdout)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/logging"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/portforward"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/userd"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/userd/trafficmgr"
 	"github.com/telepresenceio/telepresence/v2/pkg/dos"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
@@ -117,11 +118,11 @@ func (th *trafficManager) DoWithTrafficManager(ctx context.Context, f func(conte
 		return err
 	}
 
-	// Normal ticker routine to keep the client alive.
+	// Normal ticker routine to keep the client alive, using the same interval as the connector.
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	go func() {
-		ticker := time.NewTicker(5 * time.Second)
+		ticker := time.NewTicker(trafficmgr.KeepaliveInterval(ctx))
 		defer ticker.Stop()
 		for {
 			select {
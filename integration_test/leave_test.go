@@ -0,0 +1,73 @@
+package integration_test
+
+import (
+	"github.com/go-json-experiment/json"
+
+	"github.com/telepresenceio/telepresence/v2/integration_test/itest"
+)
+
+type leaveResult struct {
+	Kind                    string `json:"kind"`
+	Name                    string `json:"name"`
+	Workload                string `json:"workload"`
+	Container               string `json:"container"`
+	HandlerContainerStopped bool   `json:"handler_container_stopped"`
+}
+
+func (s *ingestSuite) Test_LeaveOutput() {
+	ctx := s.Context()
+	rq := s.Require()
+	s.TelepresenceConnect(ctx)
+	defer itest.TelepresenceDisconnectOk(ctx)
+
+	leave := func(args ...string) leaveResult {
+		var lr leaveResult
+		stdout := itest.TelepresenceOk(ctx, append([]string{"leave", "--output", "json"}, args...)...)
+		rq.NoError(json.Unmarshal([]byte(stdout), &lr))
+		return lr
+	}
+
+	itest.TelepresenceOk(ctx, "intercept", "--mount", "false", "echo-env")
+	s.Equal(leaveResult{
+		Kind:      "intercept",
+		Name:      "echo-env",
+		Workload:  "echo-env",
+		Container: "echo-env",
+	}, leave("echo-env"))
+
+	itest.TelepresenceOk(ctx, "ingest", "--mount", "false", "echo-env")
+	s.Equal(leaveResult{
+		Kind:      "ingest",
+		Name:      "echo-env",
+		Workload:  "echo-env",
+		Container: "echo-env",
+	}, leave("echo-env"))
+
+	_, _, err := itest.Telepresence(ctx, "leave", "echo-env", "--output", "json")
+	s.Error(err)
+}
+
+func (s *ingestSuite) Test_LeaveAll() {
+	ctx := s.Context()
+	rq := s.Require()
+	s.TelepresenceConnect(ctx)
+	defer itest.TelepresenceDisconnectOk(ctx)
+
+	itest.TelepresenceOk(ctx, "intercept", "--mount", "false", "--port", "8081", "echo-env")
+	itest.TelepresenceOk(ctx, "intercept", "--mount", "false", "--port", "8082", "echo")
+	itest.TelepresenceOk(ctx, "ingest", "--mount", "false", "echo-env")
+
+	_, _, err := itest.Telepresence(ctx, "leave", "--all", "echo")
+	rq.Error(err)
+
+	var lrs []leaveResult
+	stdout := itest.TelepresenceOk(ctx, "leave", "--all", "--output", "json")
+	rq.NoError(json.Unmarshal([]byte(stdout), &lrs))
+	s.ElementsMatch([]leaveResult{
+		{Kind: "intercept", Name: "echo-env", Workload: "echo-env", Container: "echo-env"},
+		{Kind: "intercept", Name: "echo", Workload: "echo", Container: "echo"},
+		{Kind: "ingest", Name: "echo-env", Workload: "echo-env", Container: "echo-env"},
+	}, lrs)
+
+	s.Contains(itest.TelepresenceOk(ctx, "leave", "--all"), "Left 0 intercept(s) and 0 ingest(s)")
+}
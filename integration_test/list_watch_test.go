@@ -2,6 +2,9 @@ package integration_test
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/telepresenceio/telepresence/v2/integration_test/itest"
@@ -27,3 +30,96 @@ func (s *connectedSuite) Test_ListWatch() {
 		s.Contains(<-ch, svc)
 	})
 }
+
+func (s *connectedSuite) Test_ListWatchSelector() {
+	const (
+		selected = "echo-one"
+		other    = "echo-two"
+	)
+	ctx := s.Context()
+	s.ApplyApp(ctx, selected, "deploy/"+selected)
+	defer s.DeleteSvcAndWorkload(ctx, "deploy", selected)
+	s.ApplyApp(ctx, other, "deploy/"+other)
+	defer s.DeleteSvcAndWorkload(ctx, "deploy", other)
+
+	// Use a context to end tele list -w
+	cancelctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan string)
+	go func() {
+		stdout, _, _ := itest.Telepresence(cancelctx, "list", "--output", "json-stream", "--selector", "list-selector-test=yes")
+		ch <- stdout
+	}()
+	time.Sleep(time.Second)
+
+	// Labeling the workload must make it show up in the stream, even though it already existed.
+	s.KubectlOk(ctx, "label", "deploy", selected, "list-selector-test=yes")
+	defer func() {
+		_ = s.Kubectl(ctx, "label", "deploy", selected, "list-selector-test-")
+	}()
+	time.Sleep(3 * time.Second)
+	cancel()
+	stdout := <-ch
+	s.Contains(stdout, selected)
+	s.NotContains(stdout, other)
+
+	stdout = itest.TelepresenceOk(ctx, "list", "--selector", "list-selector-test=yes")
+	s.Contains(stdout, selected)
+	s.NotContains(stdout, other)
+}
+
+func (s *connectedSuite) Test_ListWatchEvents() {
+	const svc = "echo-easy"
+	ctx := s.Context()
+
+	// Use a context to end telepresence list --watch
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan string)
+	go func() {
+		stdout, _, _ := itest.Telepresence(cancelCtx, "list", "--watch", "--output", "json")
+		ch <- stdout
+	}()
+	time.Sleep(2 * time.Second)
+
+	// Create, intercept, and delete a workload.
+	s.ApplyApp(ctx, svc, "deploy/"+svc)
+	time.Sleep(2 * time.Second)
+	svcPort, svcCancel := itest.StartLocalHttpEchoServer(ctx, svc)
+	defer svcCancel()
+	itest.TelepresenceOk(ctx, "intercept", "--mount", "false", svc, "--port", strconv.Itoa(svcPort))
+	time.Sleep(3 * time.Second)
+	itest.TelepresenceOk(ctx, "leave", svc)
+	time.Sleep(2 * time.Second)
+	s.DeleteSvcAndWorkload(ctx, "deploy", svc)
+	time.Sleep(3 * time.Second)
+	cancel()
+	stdout := <-ch
+
+	// Each line is one event. The workload must be added, then modified by the intercept, and then deleted.
+	var seen []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		var ev struct {
+			Type     string `json:"type"`
+			Workload struct {
+				Name           string            `json:"name"`
+				InterceptInfos []json.RawMessage `json:"intercept_infos"`
+			} `json:"workload"`
+		}
+		s.Require().NoError(json.Unmarshal([]byte(line), &ev), line)
+		if ev.Workload.Name != svc {
+			continue
+		}
+		st := ev.Type
+		if st == "modified" && len(ev.Workload.InterceptInfos) > 0 {
+			st = "intercepted"
+		}
+		if len(seen) == 0 || seen[len(seen)-1] != st {
+			seen = append(seen, st)
+		}
+	}
+	s.Require().NotEmpty(seen, stdout)
+	s.Equal("added", seen[0], stdout)
+	s.Equal("deleted", seen[len(seen)-1], stdout)
+	s.Contains(seen, "intercepted", stdout)
+}
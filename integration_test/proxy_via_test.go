@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -10,6 +11,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -127,6 +129,147 @@ func (s *proxyViaSuite) Test_ProxyViaLoopBack() {
 	}
 }
 
+func (s *proxyViaSuite) Test_ProxyViaPort() {
+	ctx := s.Context()
+	host := "127.0.0.1"
+	if s.IsIPv6() {
+		host = "::1"
+		ctx = itest.WithConfig(ctx, func(config client.Config) {
+			config.Routing().VirtualSubnet = netip.MustParsePrefix("abac:0de0::/64")
+		})
+	}
+
+	s.TelepresenceHelmInstallOK(ctx, true, "--set", "client.dns.includeSuffixes={mydomain.local}")
+	defer s.RollbackTM(ctx)
+
+	// A local server that listens to the loopback address that the alias resolves to remotely.
+	localPort, cancel := itest.StartLocalHttpEchoServerWithHost(ctx, "local-echo", host)
+	defer cancel()
+
+	addr := netip.MustParseAddr(host)
+	prefix := netip.PrefixFrom(addr, addr.BitLen())
+	s.TelepresenceConnect(ctx, "--proxy-via", fmt.Sprintf("%s:8080=echo", prefix))
+	defer itest.TelepresenceQuitOk(ctx)
+
+	rq := s.Require()
+
+	// Port 8080 is routed via the echo workload.
+	rq.Eventually(func() bool {
+		out, err := itest.Output(ctx, "curl", "--silent", "--max-time", "2", net.JoinHostPort(alias, "8080"))
+		dlog.Info(ctx, out)
+		return err == nil && strings.Contains(out, "Host: "+alias+":8080")
+	}, 30*time.Second, 2*time.Second)
+
+	// Other ports are dialed locally, using the original destination IP.
+	rq.Eventually(func() bool {
+		out, err := itest.Output(ctx, "curl", "--silent", "--max-time", "2", net.JoinHostPort(alias, strconv.Itoa(localPort)))
+		dlog.Info(ctx, out)
+		return err == nil && strings.Contains(out, "local-echo from intercept")
+	}, 10*time.Second, 2*time.Second)
+}
+
+func (s *proxyViaSuite) Test_ProxyViaPreview() {
+	ctx := s.Context()
+	rq := s.Require()
+	if s.IsIPv6() {
+		ctx = itest.WithConfig(ctx, func(config client.Config) {
+			config.Routing().VirtualSubnet = netip.MustParsePrefix("abac:0de0::/64")
+		})
+	}
+	spec := "127.0.0.1/32=echo"
+	if s.IsIPv6() {
+		spec = "::1/128=echo"
+	}
+
+	var pvs struct {
+		ProxyVias []struct {
+			Subnet        string `json:"subnet"`
+			Workload      string `json:"workload"`
+			WorkloadKind  string `json:"workload_kind"`
+			Namespace     string `json:"namespace"`
+			VirtualSubnet string `json:"virtual_subnet"`
+		} `json:"proxy_vias"`
+	}
+	stdout := itest.TelepresenceOk(ctx, "proxy-via", "preview", "--namespace", s.AppNamespace(), "--output", "json", spec)
+	rq.NoError(json.Unmarshal([]byte(stdout), &pvs))
+	rq.Len(pvs.ProxyVias, 1)
+	pv := pvs.ProxyVias[0]
+	rq.Equal("echo", pv.Workload)
+	rq.Equal("Deployment", pv.WorkloadKind)
+	rq.Equal(s.AppNamespace(), pv.Namespace)
+
+	s.TelepresenceConnect(ctx, "--proxy-via", spec)
+	defer itest.TelepresenceQuitOk(ctx)
+	st := itest.TelepresenceStatusOk(ctx)
+	rq.NotNil(st.RootDaemon)
+	vs, err := netip.ParsePrefix(pv.VirtualSubnet)
+	rq.NoError(err)
+	rq.Contains(st.RootDaemon.Subnets, vs)
+}
+
+func (s *proxyViaSuite) Test_ProxyViaPodIP() {
+	ctx := s.Context()
+	rq := s.Require()
+	if s.IsIPv6() {
+		ctx = itest.WithConfig(ctx, func(config client.Config) {
+			config.Routing().VirtualSubnet = netip.MustParsePrefix("abac:0de0::/64")
+		})
+	}
+
+	// A proxy-via using a pod IP requires that the pod already has a traffic-agent.
+	s.TelepresenceConnect(ctx)
+	itest.TelepresenceOk(ctx, "intercept", "--mount", "false", "echo")
+	itest.TelepresenceOk(ctx, "leave", "echo")
+	itest.TelepresenceDisconnectOk(ctx)
+
+	podIP, err := s.KubectlOut(ctx, "get", "pod", "-l", "app=echo", "-o", "jsonpath={.items[0].status.podIP}")
+	rq.NoError(err)
+	podIP = strings.TrimSpace(podIP)
+	rq.NotEmpty(podIP)
+
+	spec := "127.0.0.1/32=" + podIP
+	if s.IsIPv6() {
+		spec = "::1/128=" + podIP
+	}
+	s.TelepresenceConnect(ctx, "--proxy-via", spec)
+	defer itest.TelepresenceDisconnectOk(ctx)
+
+	virtualSubnet := client.GetConfig(ctx).Routing().VirtualSubnet
+	var vip netip.Addr
+	rq.Eventually(func() bool {
+		// The alias resolves to a loopback address remotely which is then translated into a virtual IP
+		ips, err := net.LookupIP(alias)
+		if err != nil || len(ips) != 1 {
+			return false
+		}
+		var ok bool
+		vip, ok = netip.AddrFromSlice(ips[0])
+		return ok
+	}, 30*time.Second, 2*time.Second)
+	rq.Truef(virtualSubnet.Contains(vip), "virtualIPSubnet %s does not contain %s", virtualSubnet, vip)
+
+	// The traffic to the virtual IP is routed via the traffic-agent of the pod with the given IP.
+	rq.Eventually(func() bool {
+		out, err := itest.Output(ctx, "curl", "--silent", "--max-time", "2", net.JoinHostPort(alias, "8080"))
+		dlog.Info(ctx, out)
+		return err == nil && strings.Contains(out, "Host: "+alias+":8080")
+	}, 10*time.Second, 2*time.Second)
+
+	var vips []struct {
+		VirtualIP string `json:"virtual_ip"`
+		Workload  string `json:"workload"`
+	}
+	rq.NoError(json.Unmarshal([]byte(itest.TelepresenceOk(ctx, "vip", "list", "--output", "json")), &vips))
+	found := false
+	for _, v := range vips {
+		if v.VirtualIP == vip.String() {
+			found = true
+			s.Equal(podIP, v.Workload)
+		}
+	}
+	s.Truef(found, "virtual IP %s is not listed", vip)
+}
+
 func (s *proxyViaSuite) Test_ProxyViaEverything() {
 	ctx := s.Context()
 	s.TelepresenceConnect(ctx)
@@ -0,0 +1,43 @@
+package integration_test
+
+import (
+	"net/netip"
+	"runtime"
+
+	"github.com/telepresenceio/telepresence/v2/integration_test/itest"
+)
+
+func (s *connectedSuite) Test_RoutingUpdate() {
+	if runtime.GOOS != "linux" {
+		s.T().Skip("ip route get is only available on linux")
+	}
+	ctx := s.Context()
+	rq := s.Require()
+	st := itest.TelepresenceStatusOk(ctx)
+	rq.NotEmpty(st.RootDaemon.Subnets)
+
+	testIP := st.RootDaemon.Subnets[0].Addr().Next().Next()
+	neverProxy := netip.PrefixFrom(testIP, testIP.BitLen()).String()
+	routeDev := func() string {
+		out, err := itest.Output(ctx, "ip", "route", "get", testIP.String())
+		rq.NoError(err)
+		return out
+	}
+
+	rq.Contains(routeDev(), "dev tel0") // tel0 is OK, we only run this on linux
+
+	itest.TelepresenceOk(ctx, "routing", "add", "--never-proxy", neverProxy)
+	removed := false
+	defer func() {
+		if !removed {
+			itest.TelepresenceOk(ctx, "routing", "remove", "--never-proxy", neverProxy)
+		}
+	}()
+	rq.NotContains(routeDev(), "dev tel0")
+	st = itest.TelepresenceStatusOk(ctx)
+	rq.Contains(st.RootDaemon.NeverProxy, netip.MustParsePrefix(neverProxy))
+
+	itest.TelepresenceOk(ctx, "routing", "remove", "--never-proxy", neverProxy)
+	removed = true
+	rq.Contains(routeDev(), "dev tel0")
+}
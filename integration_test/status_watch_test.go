@@ -0,0 +1,75 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+
+	"github.com/telepresenceio/telepresence/v2/integration_test/itest"
+)
+
+func (s *connectedSuite) Test_StatusWatch() {
+	const svc = "echo-easy"
+	ctx := s.Context()
+	s.ApplyApp(ctx, svc, "deploy/"+svc)
+	defer s.DeleteSvcAndWorkload(ctx, "deploy", svc)
+
+	// Use a context to end telepresence status --watch
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan string)
+	go func() {
+		stdout, _, _ := itest.Telepresence(cancelCtx, "status", "--watch", "--output", "json-stream")
+		ch <- stdout
+	}()
+	time.Sleep(2 * time.Second)
+
+	svcPort, svcCancel := itest.StartLocalHttpEchoServer(ctx, svc)
+	defer svcCancel()
+	itest.TelepresenceOk(ctx, "intercept", "--mount", "false", svc, "--port", strconv.Itoa(svcPort))
+	time.Sleep(3 * time.Second)
+	itest.TelepresenceOk(ctx, "leave", svc)
+	time.Sleep(3 * time.Second)
+	cancel()
+	stdout := <-ch
+
+	// The stream consists of one status object per change. The intercept must show up in one of
+	// them, and then be gone from a later one.
+	intercepted := false
+	left := false
+	dec := jsontext.NewDecoder(strings.NewReader(stdout))
+	for {
+		var st itest.StatusResponse
+		err := json.UnmarshalDecode(dec, &st)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		s.Require().NoError(err, stdout)
+		us := st.UserDaemon
+		if us == nil && st.ContainerizedDaemon != nil {
+			us = st.ContainerizedDaemon.UserDaemonStatus
+		}
+		s.Require().NotNil(us, stdout)
+		s.Equal("Connected", us.Status)
+		found := false
+		for _, ic := range us.Intercepts {
+			if ic.Name == svc {
+				found = true
+				break
+			}
+		}
+		if found {
+			intercepted = true
+		} else if intercepted {
+			left = true
+		}
+	}
+	s.True(intercepted, "the intercept never showed up in the status stream")
+	s.True(left, "the intercept never disappeared from the status stream")
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-json-experiment/json"
+
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/v2/integration_test/itest"
 )
@@ -69,3 +71,37 @@ func (s *notConnectedSuite) Test_Uninstall() {
 		500*time.Millisecond, // polling interval
 	)
 }
+
+func (s *singleServiceSuite) Test_UninstallDryRun() {
+	ctx := s.Context()
+	rq := s.Require()
+	svc := s.ServiceName()
+
+	// Intercepting ensures that the workload has an agent.
+	itest.TelepresenceOk(ctx, "intercept", "--mount", "false", "--port", "9080", svc)
+	itest.TelepresenceOk(ctx, "leave", svc)
+
+	var list struct {
+		Stdout []struct {
+			Name string `json:"name"`
+		} `json:"stdout"`
+	}
+	stdout := itest.TelepresenceOk(ctx, "list", "--agents", "--output", "json")
+	rq.NoError(json.Unmarshal([]byte(stdout), &list))
+	var installed []string
+	for _, wl := range list.Stdout {
+		installed = append(installed, wl.Name)
+	}
+	rq.Contains(installed, svc)
+
+	var names []string
+	stdout = itest.TelepresenceOk(ctx, "uninstall", "--dry-run", "--all-agents", "--output", "json")
+	rq.NoError(json.Unmarshal([]byte(stdout), &names))
+	s.ElementsMatch(installed, names)
+
+	stdout = itest.TelepresenceOk(ctx, "uninstall", "--dry-run", svc)
+	s.Contains(stdout, "Would uninstall the traffic-agent from:\n  "+svc)
+
+	// Nothing was removed.
+	s.Contains(itest.TelepresenceOk(ctx, "list", "--agents"), svc+": ready to intercept (traffic-agent already installed)")
+}
@@ -0,0 +1,51 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"time"
+
+	"github.com/telepresenceio/telepresence/v2/integration_test/itest"
+)
+
+type interceptHolder struct {
+	Client    string    `json:"client"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *singleServiceSuite) Test_Who() {
+	ctx := s.Context()
+	rq := s.Require()
+
+	getHolders := func() []interceptHolder {
+		var holders []interceptHolder
+		rq.NoError(json.Unmarshal([]byte(itest.TelepresenceOk(ctx, "who", s.ServiceName(), "--output", "json")), &holders))
+		return holders
+	}
+	s.Empty(getHolders())
+
+	port, cancel := itest.StartLocalHttpEchoServer(ctx, s.ServiceName())
+	defer cancel()
+	start := time.Now()
+	itest.TelepresenceOk(ctx, "intercept", "--mount", "false", "--port", strconv.Itoa(port), s.ServiceName())
+	defer itest.TelepresenceOk(ctx, "leave", s.ServiceName())
+
+	// The holder is the client that created the intercept.
+	usr, err := user.Current()
+	rq.NoError(err)
+	host, err := os.Hostname()
+	rq.NoError(err)
+	var holders []interceptHolder
+	rq.Eventually(func() bool {
+		holders = getHolders()
+		return len(holders) == 1
+	}, 20*time.Second, time.Second)
+	s.Equal(fmt.Sprintf("%s@%s", usr.Username, host), holders[0].Client)
+	s.WithinDuration(start, holders[0].CreatedAt, time.Minute)
+
+	_, _, err = itest.Telepresence(ctx, "who", "no-such-workload")
+	s.Error(err)
+}
@@ -150,7 +150,9 @@ func AgentContainer(
 		},
 		ImagePullPolicy: core.PullPolicy(config.PullPolicy),
 	}
-	if r := config.Resources; r != nil {
+	if r := replacedResources(pod, config); r != nil {
+		ac.Resources = *r
+	} else if r := config.Resources; r != nil {
 		ac.Resources = *r
 	}
 
@@ -169,6 +171,23 @@ func AgentContainer(
 	return ac
 }
 
+// replacedResources returns the resource requirements of the first replaced container that declares any,
+// so that the traffic-agent that takes its place is scheduled the same way. It returns nil when no container
+// is replaced, when the pod is annotated with InjectReplacedResources set to "false", or when the containers
+// were replaced using --keep-agent-resources.
+func replacedResources(pod *core.Pod, config *Sidecar) *core.ResourceRequirements {
+	if pod.ObjectMeta.Annotations[InjectReplacedResources] == "false" {
+		return nil
+	}
+	var rr *core.ResourceRequirements
+	EachContainer(pod, config, func(app *core.Container, cc *Container) {
+		if rr == nil && cc.Replace && !cc.KeepAgentResources && (len(app.Resources.Limits) > 0 || len(app.Resources.Requests) > 0) {
+			rr = app.Resources.DeepCopy()
+		}
+	})
+	return rr
+}
+
 // Find security context of the first container (with both intercepts and a set security context) and ensure
 // that any env interpolations in it are prefixed with the env-prefix of the corresponding config container.
 func firstAppSecurityContext(pod *core.Pod, config *Sidecar) (*core.SecurityContext, error) {
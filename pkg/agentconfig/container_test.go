@@ -1,11 +1,15 @@
 package agentconfig
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-json-experiment/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_prefixInterpolated(t *testing.T) {
@@ -98,3 +102,81 @@ func Test_ReplacePolicy(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, string(data), `{"replace":1}`)
 }
+
+func TestAgentContainer_replacedResources(t *testing.T) {
+	appResources := core.ResourceRequirements{
+		Limits: core.ResourceList{
+			core.ResourceCPU:    resource.MustParse("500m"),
+			core.ResourceMemory: resource.MustParse("256Mi"),
+		},
+		Requests: core.ResourceList{
+			core.ResourceCPU:    resource.MustParse("100m"),
+			core.ResourceMemory: resource.MustParse("128Mi"),
+		},
+	}
+	agentResources := core.ResourceRequirements{
+		Limits: core.ResourceList{
+			core.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	}
+	makePod := func(annotations map[string]string) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Name:        "echo",
+				Namespace:   "default",
+				Annotations: annotations,
+			},
+			Spec: core.PodSpec{
+				Containers: []core.Container{{
+					Name:      "echo",
+					Image:     "jmalloc/echo-server",
+					Ports:     []core.ContainerPort{{Name: "http", ContainerPort: 8080}},
+					Resources: appResources,
+				}},
+			},
+		}
+	}
+	makeConfig := func(replace bool) *Sidecar {
+		return &Sidecar{
+			AgentImage: "ghcr.io/telepresenceio/tel2:2.21.0",
+			AgentName:  "echo",
+			Namespace:  "default",
+			Resources:  &agentResources,
+			Containers: []*Container{{
+				Name:      "echo",
+				EnvPrefix: "A_",
+				Replace:   ReplacePolicy(replace),
+				Intercepts: []*Intercept{{
+					ContainerPortName: "http",
+					ContainerPort:     8080,
+					AgentPort:         9900,
+					Protocol:          core.ProtocolTCP,
+				}},
+			}},
+		}
+	}
+	ctx := context.Background()
+
+	t.Run("replaced", func(t *testing.T) {
+		ac := AgentContainer(ctx, makePod(nil), makeConfig(true))
+		require.NotNil(t, ac)
+		assert.Equal(t, appResources, ac.Resources)
+	})
+	t.Run("not replaced", func(t *testing.T) {
+		ac := AgentContainer(ctx, makePod(nil), makeConfig(false))
+		require.NotNil(t, ac)
+		assert.Equal(t, agentResources, ac.Resources)
+	})
+	t.Run("opt out", func(t *testing.T) {
+		ac := AgentContainer(ctx, makePod(map[string]string{InjectReplacedResources: "false"}), makeConfig(true))
+		require.NotNil(t, ac)
+		assert.Equal(t, agentResources, ac.Resources)
+	})
+	t.Run("keep agent resources", func(t *testing.T) {
+		config := makeConfig(true)
+		config.Containers[0].KeepAgentResources = true
+		ac := AgentContainer(ctx, makePod(nil), config)
+		require.NotNil(t, ac)
+		assert.Equal(t, agentResources, ac.Resources)
+	})
+}
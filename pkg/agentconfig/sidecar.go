@@ -45,6 +45,7 @@ const (
 	DomainPrefix                         = "telepresence.getambassador.io/"
 	InjectAnnotation                     = DomainPrefix + "inject-" + ContainerName
 	InjectIgnoreVolumeMounts             = DomainPrefix + "inject-ignore-volume-mounts"
+	InjectReplacedResources              = DomainPrefix + "inject-replaced-resources"
 	TerminatingTLSSecretAnnotation       = DomainPrefix + "inject-terminating-tls-secret"
 	OriginatingTLSSecretAnnotation       = DomainPrefix + "inject-originating-tls-secret"
 	LegacyTerminatingTLSSecretAnnotation = "getambassador.io/inject-terminating-tls-secret"
@@ -136,6 +137,10 @@ type Container struct {
 
 	// Replace is whether the agent should replace the intercepted container
 	Replace ReplacePolicy `json:"replace,omitzero"`
+
+	// KeepAgentResources is whether an agent that replaces the container should use the configured agent
+	// resources instead of inheriting those of the container.
+	KeepAgentResources bool `json:"keepAgentResources,omitzero"`
 }
 
 // The Sidecar configures the traffic-agent sidecar.
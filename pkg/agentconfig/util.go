@@ -1,9 +1,14 @@
 package agentconfig
 
 import (
+	"path"
+	"slices"
+	"strings"
+
 	core "k8s.io/api/core/v1"
 
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
 // SpecMatchesIntercept answers the question if an InterceptSpec matches the given
@@ -80,3 +85,31 @@ func (s *Sidecar) numberOfPossibleIntercepts() (count int) {
 	}
 	return
 }
+
+// MatchContainerName returns the name in names that matches the given pattern. The pattern is either an
+// exact container name or a glob as described by path.Match. An empty string is returned when no name
+// matches, and an error is returned when the pattern is malformed or when it matches more than one name.
+func MatchContainerName(pattern string, names []string) (string, error) {
+	if slices.Contains(names, pattern) {
+		return pattern, nil
+	}
+	var matches []string
+	for _, name := range names {
+		ok, err := path.Match(pattern, name)
+		if err != nil {
+			return "", errcat.User.Newf("invalid container name pattern %q: %v", pattern, err)
+		}
+		if ok {
+			matches = append(matches, name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0], nil
+	default:
+		slices.Sort(matches)
+		return "", errcat.User.Newf("container name pattern %q is ambiguous. It matches %s", pattern, strings.Join(matches, ", "))
+	}
+}
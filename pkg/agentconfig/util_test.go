@@ -0,0 +1,36 @@
+package agentconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchContainerName(t *testing.T) {
+	names := []string{"app-2", "app-1", "sidecar"}
+	tests := []struct {
+		pattern   string
+		want      string
+		wantError string
+	}{
+		{pattern: "app-1", want: "app-1"},
+		{pattern: "app-1*", want: "app-1"},
+		{pattern: "side?ar", want: "sidecar"},
+		{pattern: "other-*", want: ""},
+		{pattern: "app-*", wantError: `container name pattern "app-*" is ambiguous. It matches app-1, app-2`},
+		{pattern: "app-[", wantError: `invalid container name pattern "app-["`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := MatchContainerName(tt.pattern, names)
+			if tt.wantError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -264,12 +264,13 @@ func (s *clients) GetClient(ip netip.Addr) (pvd tunnel.Provider) {
 }
 
 // GetWorkloadClient returns tunnel.Provider that opens a tunnel to a traffic-agent that
-// belongs to a pod created for the given workload.
+// belongs to a pod created for the given workload. The workload can also be the IP of the
+// agent's pod.
 //
 // The function returns nil when there are no agents for the given workload in the connected namespace.
 func (s *clients) GetWorkloadClient(workload string) (pvd tunnel.Provider) {
 	s.clients.Range(func(_ string, ac *client) bool {
-		if ac.info.WorkloadName == workload {
+		if matchesProxyVia(ac.info, workload) {
 			pvd = ac
 			return false
 		}
@@ -283,8 +284,24 @@ func (s *clients) SetProxyVia(workload string) {
 }
 
 func (s *clients) isProxyVIA(info *manager.AgentPodInfo) bool {
-	_, isPV := s.proxyVias.Load(info.WorkloadName)
-	return isPV
+	if _, isPV := s.proxyVias.Load(info.WorkloadName); isPV {
+		return true
+	}
+	if podIP, ok := netip.AddrFromSlice(info.PodIp); ok {
+		_, isPV := s.proxyVias.Load(podIP.String())
+		return isPV
+	}
+	return false
+}
+
+// matchesProxyVia returns true if the given proxy-via target is the name of the agent's workload,
+// or the IP of the agent's pod.
+func matchesProxyVia(info *manager.AgentPodInfo, target string) bool {
+	if info.WorkloadName == target {
+		return true
+	}
+	podIP, ok := netip.AddrFromSlice(info.PodIp)
+	return ok && podIP.String() == target
 }
 
 func (s *clients) hasWaiterFor(info *manager.AgentPodInfo) bool {
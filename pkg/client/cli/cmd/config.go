@@ -1,21 +1,29 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
 	"github.com/spf13/cobra"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 
+	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
 func configCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use: "config",
 	}
-	cmd.AddCommand(configView())
+	cmd.AddCommand(configView(), configSet(), configValidate())
 	return cmd
 }
 
@@ -23,8 +31,10 @@ const clientOnlyFlag = "client-only"
 
 func configView() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:               "view",
-		Args:              cobra.NoArgs,
+		Use:  "view",
+		Args: cobra.NoArgs,
+
+		// The output is YAML unless another format is requested using --output.
 		PersistentPreRunE: output.DefaultYAML,
 		Short:             "View current Telepresence configuration",
 		RunE:              runConfigView,
@@ -59,7 +69,10 @@ func runConfigView(cmd *cobra.Command, _ []string) error {
 
 		ctx, _, err := daemon.GetCommandKubeConfig(cmd)
 		if err != nil {
-			return err
+			// Without a kubeconfig, there's no kubeconfig extension to merge, so the config is
+			// just what's found in the client file.
+			dlog.Debugf(cmd.Context(), "unable to load kubeconfig: %v", err)
+			ctx = cmd.Context()
 		}
 		cfg.Config = client.GetConfig(ctx)
 		cfg.ClientFile = client.GetConfigFile(ctx)
@@ -85,3 +98,81 @@ func runConfigView(cmd *cobra.Command, _ []string) error {
 	output.Object(ctx, &cfg, true)
 	return nil
 }
+
+func configSet() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Set a value in the Telepresence client configuration file",
+		Long: `Set a value in the Telepresence client configuration file.
+
+The key is the dotted path to the value, e.g. "timeouts.intercept". Unknown keys are rejected. Lists
+are given as comma separated values. Comments in the configuration file are retained.`,
+		Example: `  telepresence config set timeouts.intercept 2m
+  telepresence config set routing.autoResolveConflicts true
+  telepresence config set routing.neverProxySubnets 10.0.0.0/8,192.168.0.0/16`,
+		RunE:              runConfigSet,
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	path := client.GetConfigFile(ctx)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	data, err = client.SetConfigValue(data, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s set to %s in %s\n", args[0], args[1], path)
+	return nil
+}
+
+func configValidate() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [file]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Validate a Telepresence client configuration file",
+		Long: `Validate a Telepresence client configuration file.
+
+The file is parsed in the same way as when Telepresence loads its configuration, and each problem is reported
+together with the path of the erroneous value and the type that was expected. The client configuration file
+is validated when no file is given.`,
+		RunE: runConfigValidate,
+	}
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = client.GetConfigFile(ctx)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errcat.User.New(err)
+	}
+	errs := client.ValidateConfigYAML(ctx, path, data)
+	if len(errs) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", path)
+		return nil
+	}
+	for _, err := range errs {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", path, err)
+	}
+	if len(errs) == 1 {
+		return errcat.User.Newf("%s has 1 problem", path)
+	}
+	return errcat.User.Newf("%s has %d problems", path, len(errs))
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/global"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+)
+
+func Test_configViewClientOnlyJSON(t *testing.T) {
+	// No kubeconfig and no daemon. The client file config must still be produced.
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "no-such-kubeconfig"))
+	cfg := client.GetDefaultConfig()
+	cfg.Cluster().DefaultManagerNamespace = "tm-ns"
+	ctx := client.WithConfig(dlog.NewTestContext(t, false), cfg)
+	ctx = connect.WithCommandInitializer(ctx, func(*cobra.Command) error { return nil })
+
+	root := &cobra.Command{Use: "telepresence", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().AddFlagSet(global.Flags(false))
+	root.AddCommand(configCmd())
+	root.SetContext(ctx)
+	out := &bytes.Buffer{}
+	root.SetOut(out)
+	root.SetArgs([]string{"config", "view", "--client-only", "--output", "json"})
+	_, formatted, err := output.Execute(root)
+	require.NoError(t, err)
+	assert.True(t, formatted)
+
+	data := out.Bytes()
+	require.True(t, jsontext.Value(data).IsValid(), out.String())
+	var sc client.SessionConfig
+	require.NoError(t, client.UnmarshalJSON(data, &sc, false))
+	assert.Equal(t, "tm-ns", sc.Cluster().DefaultManagerNamespace)
+	assert.Equal(t, client.GetConfigFile(ctx), sc.ClientFile)
+}
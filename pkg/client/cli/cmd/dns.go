@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+)
+
+func dnsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dns",
+		Short: "Inspect the DNS resolver of the current connection",
+	}
+	cmd.AddCommand(dnsSearch())
+	return cmd
+}
+
+type dnsSearchPaths struct {
+	SearchPaths  []string `json:"search_paths"`
+	DropSuffixes []string `json:"drop_suffixes"`
+	Routes       []string `json:"routes"`
+}
+
+func dnsSearch() *cobra.Command {
+	return &cobra.Command{
+		Use:   "search",
+		Args:  cobra.NoArgs,
+		Short: "Show the search paths and drop suffixes that are currently in use by the DNS resolver",
+		Annotations: map[string]string{
+			ann.Session: ann.Required,
+		},
+		RunE:              runDNSSearch,
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+}
+
+func runDNSSearch(cmd *cobra.Command, _ []string) error {
+	if err := connect.InitCommand(cmd); err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	rsp, err := daemon.GetUserClient(ctx).GetDNSSearchPaths(ctx, &empty.Empty{})
+	if err != nil {
+		return err
+	}
+	sp := dnsSearchPaths{
+		SearchPaths:  rsp.SearchPaths,
+		DropSuffixes: rsp.DropSuffixes,
+		Routes:       rsp.Routes,
+	}
+
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, sp, false)
+		return nil
+	}
+	out := output.Out(ctx)
+	fmt.Fprintf(out, "Search paths : %s\n", strings.Join(sp.SearchPaths, ", "))
+	fmt.Fprintf(out, "Drop suffixes: %s\n", strings.Join(sp.DropSuffixes, ", "))
+	fmt.Fprintf(out, "Routes       : %s\n", strings.Join(sp.Routes, ", "))
+	return nil
+}
@@ -40,9 +40,20 @@ func dockerRunCmd() *cobra.Command {
 		DisableFlagsInUseLine: true,
 		ValidArgsFunction:     cliDocker.AutocompleteRun,
 	}
+	cmd.Flags().Bool(flagNoContainerNetwork, false, ``+
+		`Don't attach the container to the daemon container's network. Rely on the host's routing and DNS instead. `+
+		`Required when the daemon runs on the host`)
+	cmd.Flags().Bool(flagNoTel2Search, false, ``+
+		`Don't add the "tel2-search" search domain to a container that relies on the host's DNS. `+
+		`Single label names of services in mapped namespaces will then not resolve`)
 	return cmd
 }
 
+const (
+	flagNoContainerNetwork = "no-container-network"
+	flagNoTel2Search       = "no-tel2-search"
+)
+
 func findAndParseFlag(flags *pflag.FlagSet, flagName string, args []string) ([]string, error) {
 	if i := slices.Index(args, "--"+flagName); i >= 0 && i+1 < len(args) {
 		if err := flags.Parse(args[i : i+2]); err != nil {
@@ -58,9 +69,21 @@ func findAndParseFlag(flags *pflag.FlagSet, flagName string, args []string) ([]s
 	return args, nil
 }
 
+func findAndParseBoolFlag(flags *pflag.FlagSet, flagName string, args []string) ([]string, error) {
+	if i := slices.IndexFunc(args, func(s string) bool { return s == "--"+flagName || strings.HasPrefix(s, "--"+flagName+"=") }); i >= 0 {
+		if err := flags.Parse(args[i : i+1]); err != nil {
+			return nil, err
+		}
+		args = slices.Delete(args, i, i+1)
+	}
+	return args, nil
+}
+
 func parseFlags(cmd *cobra.Command, args []string) (*cliDocker.RunFlags, []string, error) {
 	// The command has all flag parsing disabled, but we must check for the global flags. Luckily, these flags do not conflict with
-	// the docker run flags.
+	// the docker run flags. Only the options that precede the image are parsed. The image and its command and arguments are
+	// passed on untouched.
+	args, cmdArgs := cliDocker.SplitRunArgs(cmd.Context(), args, "--"+flagNoContainerNetwork, "--"+flagNoTel2Search)
 	opts := cmd.Flags()
 	var err error
 	args, err = findAndParseFlag(opts, global.FlagUse, args)
@@ -71,11 +94,19 @@ func parseFlags(cmd *cobra.Command, args []string) (*cliDocker.RunFlags, []strin
 	if err != nil {
 		return nil, nil, err
 	}
+	args, err = findAndParseBoolFlag(opts, flagNoContainerNetwork, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err = findAndParseBoolFlag(opts, flagNoTel2Search, args)
+	if err != nil {
+		return nil, nil, err
+	}
 	networkFlags, args, err := cliDocker.ParseRunFlags(args)
 	if err != nil {
 		return nil, nil, err
 	}
-	return networkFlags, args, nil
+	return networkFlags, append(args, cmdArgs...), nil
 }
 
 func runDockerRunCLI(cmd *cobra.Command, args []string) error {
@@ -92,9 +123,12 @@ func runDockerRun(cmd *cobra.Command, args []string) error {
 		return proc.StdCommand(cmd.Context(), cliDocker.Exe, slices.Insert(args, 0, "run")...).Run()
 	}
 
-	for _, n := range opts.Networks {
-		if strings.HasPrefix(n, "container:") {
-			return errors.New("this command adds the daemon container network. Adding another container network is not possible")
+	noContainerNetwork, _ := cmd.Flags().GetBool(flagNoContainerNetwork)
+	if !noContainerNetwork {
+		for _, n := range opts.Networks {
+			if strings.HasPrefix(n, "container:") {
+				return errors.New("this command adds the daemon container network. Adding another container network is not possible")
+			}
 		}
 	}
 
@@ -109,8 +143,9 @@ func runDockerRun(cmd *cobra.Command, args []string) error {
 	if ud == nil {
 		return fmt.Errorf("%s requires a connection", cmd.UseLine())
 	}
-	if !ud.Containerized() {
-		return fmt.Errorf("%s requires that --docker was used when the connection was established", cmd.UseLine())
+	if !(noContainerNetwork || ud.Containerized()) {
+		return fmt.Errorf("%s requires that --docker was used when the connection was established, or that --%s is used",
+			cmd.UseLine(), flagNoContainerNetwork)
 	}
 
 	cidFileName, err := ioutil.CreateTempName("", "docker-run*.cid")
@@ -118,10 +153,18 @@ func runDockerRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	daemonName := ud.DaemonID().ContainerName()
+	var daemonName string
+	if !noContainerNetwork {
+		daemonName = ud.DaemonID().ContainerName()
+	}
+	ctx = docker.EnableClient(ctx)
+	if err = cliDocker.CheckRemoteDaemon(ctx, daemonName != "", nil); err != nil {
+		return err
+	}
 	ctx = dos.WithStdio(ctx, cmd)
 
-	cc := proc.StdCommand(ctx, cliDocker.Exe, slices.Insert(args, 0, "run", "--cidfile", cidFileName, "--network", "container:"+daemonName)...)
+	noTel2Search, _ := cmd.Flags().GetBool(flagNoTel2Search)
+	cc := proc.StdCommand(ctx, cliDocker.Exe, dockerRunArgs(cidFileName, daemonName, noTel2Search, opts, args)...)
 	cc.Stdin = dos.Stdin(ctx)
 	cc.Env = dos.Environ(ctx)
 	tty := flags.HasOption("tty", 't', args)
@@ -143,15 +186,13 @@ func runDockerRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	ctx = docker.EnableClient(ctx)
-
 	var exited, signalled atomic.Bool
 	if !tty {
 		go cliDocker.EnsureStopContainer(ctx, containerID, nil, &exited, &signalled)
 	}
 
-	if len(opts.Networks) > 0 {
-		connectCancel, err := cliDocker.ConnectNetworksToDaemon(ctx, opts.Networks, daemonName)
+	if daemonName != "" && len(opts.Networks) > 0 {
+		connectCancel, err := cliDocker.ConnectNetworksToDaemon(ctx, opts.Networks, daemonName, nil)
 		defer connectCancel()
 		if err != nil {
 			return err
@@ -161,7 +202,38 @@ func runDockerRun(cmd *cobra.Command, args []string) error {
 	err = cc.Wait()
 	exited.Store(true)
 	if signalled.Load() {
-		err = nil
+		return nil
+	}
+	// The CLI will exit with the exit code of the container.
+	return proc.HandlerExit(err)
+}
+
+// dockerRunArgs returns the arguments for the "docker run" command. The container will share the network of
+// the daemon container with the given name. When no name is given, the container uses its own network, and
+// relies on the routing and DNS of a daemon that runs on the host, just like a --docker-run ingest or intercept
+// does. The "tel2-search" search domain is then added unless noTel2Search is true. The --env and --env-file
+// flags that preceded the image and were consumed when parsing the flags are passed on unchanged. Flags that
+// follow the image belong to the container's command, and are left where they are.
+func dockerRunArgs(cidFileName, daemonName string, noTel2Search bool, opts *cliDocker.RunFlags, args []string) []string {
+	ourArgs := []string{"run", "--cidfile", cidFileName}
+	for _, f := range opts.EnvFiles {
+		ourArgs = append(ourArgs, "--env-file", f)
+	}
+	for _, e := range opts.Env {
+		ourArgs = append(ourArgs, "-e", e)
+	}
+	if daemonName != "" {
+		ourArgs = append(ourArgs, "--network", "container:"+daemonName)
+	} else {
+		if !noTel2Search {
+			ourArgs = append(ourArgs, "--dns-search", "tel2-search")
+		}
+		for _, p := range opts.PublishedPorts {
+			ourArgs = append(ourArgs, "-p", p.String())
+		}
+		for _, n := range opts.Networks {
+			ourArgs = append(ourArgs, "--network", n)
+		}
 	}
-	return err
+	return append(ourArgs, args...)
 }
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+)
+
+func testDockerRunCmd(t *testing.T) *cobra.Command {
+	cmd := testDockerRunCmd(t)
+	cmd.SetContext(dlog.NewTestContext(t, false))
+	return cmd
+}
+
+func Test_dockerRunArgs(t *testing.T) {
+	cmd := testDockerRunCmd(t)
+	opts, args, err := parseFlags(cmd, []string{"--no-container-network", "-p", "8080:80", "--network", "my-net", "--rm", "nginx"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--rm", "nginx"}, args)
+	noContainerNetwork, err := cmd.Flags().GetBool(flagNoContainerNetwork)
+	require.NoError(t, err)
+	require.True(t, noContainerNetwork)
+
+	t.Run("containerized daemon", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"run", "--cidfile", "x.cid", "--network", "container:tp-ctx", "--rm", "nginx"},
+			dockerRunArgs("x.cid", "tp-ctx", false, opts, args))
+	})
+
+	t.Run("host daemon", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{
+				"run", "--cidfile", "x.cid", "--dns-search", "tel2-search",
+				"-p", "8080:80", "--network", "my-net", "--rm", "nginx",
+			},
+			dockerRunArgs("x.cid", "", false, opts, args))
+	})
+
+	t.Run("host daemon without tel2-search", func(t *testing.T) {
+		cmd := testDockerRunCmd(t)
+		opts, args, err := parseFlags(cmd, []string{"--no-container-network", "--no-tel2-search", "--rm", "nginx"})
+		require.NoError(t, err)
+		noTel2Search, err := cmd.Flags().GetBool(flagNoTel2Search)
+		require.NoError(t, err)
+		require.True(t, noTel2Search)
+		assert.Equal(t,
+			[]string{"run", "--cidfile", "x.cid", "--rm", "nginx"},
+			dockerRunArgs("x.cid", "", noTel2Search, opts, args))
+	})
+
+	t.Run("env flags", func(t *testing.T) {
+		cmd := testDockerRunCmd(t)
+		opts, args, err := parseFlags(cmd, []string{"-e", "FOO=bar", "--env-file", "app.env", "--env=BAR", "--rm", "nginx"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"--rm", "nginx"}, args)
+		assert.Equal(t,
+			[]string{"run", "--cidfile", "x.cid", "--env-file", "app.env", "-e", "FOO=bar", "-e", "BAR", "--network", "container:tp-ctx", "--rm", "nginx"},
+			dockerRunArgs("x.cid", "tp-ctx", false, opts, args))
+	})
+
+	t.Run("flags of the container command", func(t *testing.T) {
+		cmd := testDockerRunCmd(t)
+		opts, args, err := parseFlags(cmd, []string{"--rm", "alpine", "grep", "-e", "foo", "/etc/hosts"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"--rm", "alpine", "grep", "-e", "foo", "/etc/hosts"}, args)
+		assert.Empty(t, opts.Env)
+		assert.Equal(t,
+			[]string{"run", "--cidfile", "x.cid", "--network", "container:tp-ctx", "--rm", "alpine", "grep", "-e", "foo", "/etc/hosts"},
+			dockerRunArgs("x.cid", "tp-ctx", false, opts, args))
+
+		opts, args, err = parseFlags(testDockerRunCmd(t), []string{"--no-tel2-search", "-e", "A=B", "alpine", "sh", "-ec", "echo hi"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"alpine", "sh", "-ec", "echo hi"}, args)
+		assert.Equal(t, []string{"A=B"}, opts.Env)
+	})
+
+	t.Run("env-file of the container command", func(t *testing.T) {
+		opts, args, err := parseFlags(testDockerRunCmd(t), []string{"--env-file", "app.env", "my-tool", "--env-file", "tool.env"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"my-tool", "--env-file", "tool.env"}, args)
+		assert.Equal(t, []string{"app.env"}, opts.EnvFiles)
+		assert.Equal(t,
+			[]string{"run", "--cidfile", "x.cid", "--env-file", "app.env", "--network", "container:tp-ctx", "my-tool", "--env-file", "tool.env"},
+			dockerRunArgs("x.cid", "tp-ctx", false, opts, args))
+	})
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// flagEnvName returns the name of the environment variable that provides the default value for the flag with
+// the given name of the given command, e.g. TELEPRESENCE_INTERCEPT_ENV_SYNTAX for the --env-syntax flag of
+// "telepresence intercept".
+func flagEnvName(cmd *cobra.Command, flagName string) string {
+	parts := append(strings.Fields(cmd.CommandPath()), flagName)
+	return strings.ToUpper(strings.ReplaceAll(strings.Join(parts, "_"), "-", "_"))
+}
+
+// ApplyEnvDefaults finds the subcommand of the given root command that the given command line arguments will
+// execute, and assigns the defaults found in the environment of the root command's context to its flags.
+func ApplyEnvDefaults(rootCmd *cobra.Command, args []string) error {
+	sub, _, err := rootCmd.Find(args)
+	if err != nil || sub == rootCmd || sub.DisableFlagParsing {
+		// Errors are reported when the command is executed.
+		return nil
+	}
+	ctx := rootCmd.Context()
+	return applyEnvDefaults(sub, func(name string) (string, bool) {
+		return dos.LookupEnv(ctx, name)
+	})
+}
+
+// applyEnvDefaults assigns the value of each environment variable named TELEPRESENCE_<COMMAND>_<FLAG> to
+// the corresponding flag of the given command. This must be done before the command line is parsed, so that
+// flags that are given explicitly take precedence. A flag that is assigned from the environment isn't marked as
+// changed, so that validations of flags that are mutually exclusive only consider those given explicitly.
+func applyEnvDefaults(cmd *cobra.Command, lookupEnv func(string) (string, bool)) error {
+	var err error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err != nil {
+			return
+		}
+		name := flagEnvName(cmd, f.Name)
+		v, ok := lookupEnv(name)
+		if !ok {
+			return
+		}
+		if sv, isSlice := f.Value.(pflag.SliceValue); isSlice {
+			// Replace rather than Set, so that an explicit flag replaces the default instead of appending to it.
+			var vs []string
+			if v != "" {
+				vs = strings.Split(v, ",")
+			}
+			err = sv.Replace(vs)
+		} else {
+			err = f.Value.Set(v)
+		}
+		if err != nil {
+			err = errcat.User.New(fmt.Errorf("invalid value %q in environment variable %s: %w", v, name, err))
+			return
+		}
+		f.DefValue = v
+	})
+	return err
+}
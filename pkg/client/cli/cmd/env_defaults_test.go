@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/intercept"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+)
+
+func TestApplyEnvDefaults(t *testing.T) {
+	env := dos.MapEnv{
+		"TELEPRESENCE_INTERCEPT_MOUNT":      "false",
+		"TELEPRESENCE_INTERCEPT_ENV_SYNTAX": "json",
+		"TELEPRESENCE_INTERCEPT_DNS_SEARCH": "blue,green",
+	}
+
+	// validate applies the environment defaults to a real intercept command, parses the given arguments, and
+	// validates the result.
+	validate := func(t *testing.T, env dos.MapEnv, args ...string) (*intercept.Command, error) {
+		ctx := dlog.NewTestContext(t, false)
+		ctx = client.WithConfig(ctx, client.GetDefaultConfig())
+		ctx = dos.WithEnv(ctx, env)
+		ic := &intercept.Command{}
+		rootCmd := &cobra.Command{Use: "telepresence"}
+		rootCmd.SetContext(ctx)
+		interceptCmd := newInterceptCmd(ic)
+		interceptCmd.SetContext(ctx)
+		rootCmd.AddCommand(interceptCmd)
+
+		args = append([]string{"intercept", "echo"}, args...)
+		if err := ApplyEnvDefaults(rootCmd, args); err != nil {
+			return nil, err
+		}
+		sub, flagArgs, err := rootCmd.Find(args)
+		require.NoError(t, err)
+		require.Same(t, interceptCmd, sub)
+		require.NoError(t, sub.ParseFlags(flagArgs))
+		return ic, ic.Validate(sub, sub.Flags().Args())
+	}
+
+	t.Run("env default applied", func(t *testing.T) {
+		ic, err := validate(t, env)
+		require.NoError(t, err)
+		assert.False(t, ic.MountFlags.Enabled)
+		assert.Equal(t, "json", ic.EnvFlags.Syntax.String())
+		assert.Equal(t, []string{"blue", "green"}, ic.DNSSearch)
+	})
+
+	t.Run("explicit flag overrides", func(t *testing.T) {
+		ic, err := validate(t, env, "--mount=true", "--env-syntax", "sh", "--dns-search", "red")
+		require.NoError(t, err)
+		assert.True(t, ic.MountFlags.Enabled)
+		assert.Equal(t, "sh", ic.EnvFlags.Syntax.String())
+		assert.Equal(t, []string{"red"}, ic.DNSSearch)
+	})
+
+	t.Run("env port with explicit local port", func(t *testing.T) {
+		portEnv := dos.MapEnv{"TELEPRESENCE_INTERCEPT_PORT": "8080"}
+		ic, err := validate(t, portEnv)
+		require.NoError(t, err)
+		assert.Equal(t, "8080", ic.Port)
+
+		ic, err = validate(t, portEnv, "--local-port", "9090")
+		require.NoError(t, err)
+		assert.Equal(t, "9090", ic.Port)
+	})
+
+	t.Run("no env", func(t *testing.T) {
+		ic, err := validate(t, dos.MapEnv{})
+		require.NoError(t, err)
+		assert.True(t, ic.MountFlags.Enabled)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := validate(t, dos.MapEnv{"TELEPRESENCE_INTERCEPT_DURATION": "soon"})
+		assert.ErrorContains(t, err, "TELEPRESENCE_INTERCEPT_DURATION")
+	})
+}
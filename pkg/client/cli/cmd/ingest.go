@@ -11,17 +11,22 @@ import (
 func ingestCmd() *cobra.Command {
 	ic := &ingest.Command{}
 	cmd := &cobra.Command{
-		Use:   "ingest [flags] <workload> [-- <command with arguments...>]",
-		Args:  cobra.MinimumNArgs(1),
+		Use:   "ingest [flags] {<workload> [-- <command with arguments...>] | --list}",
+		Args:  ic.Args,
 		Short: "Ingest a container",
 		Annotations: map[string]string{
 			ann.Session:           ann.Required,
 			ann.UpdateCheckFormat: ann.Tel2,
 		},
-		SilenceUsage:      true,
-		SilenceErrors:     true,
-		RunE:              ic.Run,
-		ValidArgsFunction: intercept.ValidArgs, // a list that this command shares with intercept
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          ic.Run,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if ic.List {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return intercept.ValidArgs(cmd, args, toComplete) // a list that this command shares with intercept
+		},
 	}
 	ic.AddFlags(cmd)
 	return cmd
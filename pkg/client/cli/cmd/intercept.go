@@ -1,14 +1,30 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/intercept"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
 )
 
 func interceptCmd() *cobra.Command {
-	ic := &intercept.Command{}
+	return newInterceptCmd(&intercept.Command{})
+}
+
+func newInterceptCmd(ic *intercept.Command) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "intercept [flags] <intercept_base_name> [-- <command with arguments...>]",
 		Args:  cobra.MinimumNArgs(1),
@@ -23,5 +39,92 @@ func interceptCmd() *cobra.Command {
 		ValidArgsFunction: intercept.ValidArgs,
 	}
 	ic.AddFlags(cmd)
+	cmd.AddCommand(interceptLogs())
 	return cmd
 }
+
+func interceptLogs() *cobra.Command {
+	return &cobra.Command{
+		Use:  "logs <intercept_name>",
+		Args: cobra.ExactArgs(1),
+
+		Short: "Show a summary of each connection that is routed to an intercept",
+		Long: "Show a summary of each connection that is routed to the local handler of an intercept. " +
+			"A line is printed when a connection ends. The method, path, and status of the first HTTP/1.x request " +
+			"on the connection are included when present. The command runs until it is interrupted or the intercept ends.",
+		Annotations: map[string]string{
+			ann.Session: ann.Required,
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := connect.InitCommand(cmd); err != nil {
+				return err
+			}
+			return watchInterceptTraffic(cmd, strings.TrimSpace(args[0]))
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			shellCompDir := cobra.ShellCompDirectiveNoFileComp
+			if len(args) != 0 {
+				return nil, shellCompDir
+			}
+			if err := connect.InitCommand(cmd); err != nil {
+				return nil, shellCompDir | cobra.ShellCompDirectiveError
+			}
+			ctx := cmd.Context()
+			resp, err := daemon.GetUserClient(ctx).List(ctx, &connector.ListRequest{Filter: connector.ListRequest_INTERCEPTS})
+			if err != nil {
+				return nil, shellCompDir | cobra.ShellCompDirectiveError
+			}
+			var completions []string
+			for _, wl := range resp.Workloads {
+				for _, ii := range wl.InterceptInfos {
+					if name := ii.Spec.Name; strings.HasPrefix(name, toComplete) {
+						completions = append(completions, name)
+					}
+				}
+			}
+			return completions, shellCompDir
+		},
+	}
+}
+
+func watchInterceptTraffic(cmd *cobra.Command, name string) error {
+	ctx := cmd.Context()
+	stream, err := daemon.GetUserClient(ctx).WatchInterceptTraffic(ctx, &connector.InterceptTrafficRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	for {
+		e, err := stream.Recv()
+		if err != nil {
+			switch {
+			case errors.Is(err, io.EOF), ctx.Err() != nil:
+				return nil
+			case status.Code(err) == codes.NotFound:
+				return errcat.User.Newf("intercept %q not found", name)
+			default:
+				return errcat.NoDaemonLogs.Newf("%v", err)
+			}
+		}
+		printInterceptTrafficEntry(out, e)
+	}
+}
+
+func printInterceptTrafficEntry(out io.Writer, e *connector.InterceptTrafficEntry) {
+	var httpInfo string
+	if e.HttpMethod != "" {
+		httpInfo = fmt.Sprintf(", %s %s", e.HttpMethod, e.HttpPath)
+		if e.HttpStatus != 0 {
+			httpInfo += fmt.Sprintf(" %d", e.HttpStatus)
+		}
+	}
+	ioutil.Printf(out, "%s %s %s -> %s%s, duration %s, received %d bytes, sent %d bytes\n",
+		e.StartTime.AsTime().Local().Format(time.RFC3339),
+		e.Protocol,
+		e.Source,
+		e.Destination,
+		httpInfo,
+		e.Duration.AsDuration().Round(time.Millisecond),
+		e.BytesReceived,
+		e.BytesSent)
+}
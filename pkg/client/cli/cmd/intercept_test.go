@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptLogsIsSubCommand(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "telepresence"}
+	ic := interceptCmd()
+	rootCmd.AddCommand(ic)
+
+	sub, args, err := rootCmd.Find([]string{"intercept", "logs", "echo"})
+	require.NoError(t, err)
+	assert.Equal(t, "logs", sub.Name())
+	assert.Same(t, ic, sub.Parent())
+	assert.Equal(t, []string{"echo"}, args)
+
+	// Other names are still the name of an intercept.
+	sub, args, err = rootCmd.Find([]string{"intercept", "echo", "--port", "8080"})
+	require.NoError(t, err)
+	assert.Same(t, ic, sub)
+	assert.Equal(t, []string{"echo", "--port", "8080"}, args)
+}
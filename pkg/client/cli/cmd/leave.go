@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -16,15 +17,28 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/intercept"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
 func leave() *cobra.Command {
 	var containerName string
+	var all bool
 	cmd := &cobra.Command{
-		Use:  "leave [flags] <intercept_name>",
-		Args: cobra.ExactArgs(1),
+		Use: "leave [flags] {<intercept_name> | --all}",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all {
+				if len(args) > 0 {
+					return errcat.User.New("--all cannot be combined with the name of an intercept or ingest")
+				}
+				if containerName != "" {
+					return errcat.User.New("--all cannot be combined with --container")
+				}
+				return nil
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 
 		Short: "Remove existing intercept",
 		Annotations: map[string]string{
@@ -34,11 +48,22 @@ func leave() *cobra.Command {
 			if err := connect.InitCommand(cmd); err != nil {
 				return err
 			}
-			return removeIngestOrIntercept(cmd.Context(), strings.TrimSpace(args[0]), containerName)
+			ctx := cmd.Context()
+			if all {
+				return removeAll(cmd)
+			}
+			left, err := removeIngestOrIntercept(ctx, strings.TrimSpace(args[0]), containerName)
+			if err != nil {
+				return err
+			}
+			if output.WantsFormatted(cmd) {
+				output.Object(ctx, left, true)
+			}
+			return nil
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			shellCompDir := cobra.ShellCompDirectiveNoFileComp
-			if len(args) != 0 {
+			if len(args) != 0 || all {
 				return nil, shellCompDir
 			}
 			if err := connect.InitCommand(cmd); err != nil {
@@ -72,21 +97,78 @@ func leave() *cobra.Command {
 			return completions, shellCompDir
 		},
 	}
-	cmd.Flags().StringVarP(&containerName, "container", "c", "", "Container name (only relevant for ingest)")
+	flags := cmd.Flags()
+	flags.StringVarP(&containerName, "container", "c", "", "Container name (only relevant for ingest)")
+	flags.BoolVar(&all, "all", false, "Remove all intercepts and ingests")
 	return cmd
 }
 
-func removeIngestOrIntercept(ctx context.Context, name, container string) error {
+// removeAll removes all intercepts and ingests of the current session. A failure to remove one of them
+// doesn't prevent the others from being removed. All errors are returned together.
+func removeAll(cmd *cobra.Command) error {
+	ctx := cmd.Context()
+	resp, err := daemon.GetUserClient(ctx).List(ctx, &connector.ListRequest{Filter: connector.ListRequest_INTERCEPTS | connector.ListRequest_INGESTS})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	lefts := make([]*leaveResult, 0)
+	for _, wl := range resp.Workloads {
+		for _, ii := range wl.InterceptInfos {
+			left, err := removeIngestOrIntercept(ctx, ii.Spec.Name, "")
+			if err != nil {
+				errs = append(errs, fmt.Errorf("leave intercept %s: %w", ii.Spec.Name, err))
+			} else {
+				lefts = append(lefts, left)
+			}
+		}
+		for _, ig := range wl.IngestInfos {
+			left, err := removeIngestOrIntercept(ctx, ig.Workload, ig.Container)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("leave ingest %s[%s]: %w", ig.Workload, ig.Container, err))
+			} else {
+				lefts = append(lefts, left)
+			}
+		}
+	}
+
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, lefts, true)
+	} else {
+		var intercepts, ingests int
+		for _, left := range lefts {
+			if left.Kind == "intercept" {
+				intercepts++
+			} else {
+				ingests++
+			}
+		}
+		fmt.Fprintf(output.Out(ctx), "Left %d intercept(s) and %d ingest(s)\n", intercepts, ingests)
+	}
+	return errors.Join(errs...)
+}
+
+// leaveResult describes the intercept or ingest that was removed by the leave command.
+type leaveResult struct {
+	Kind                    string `json:"kind"`
+	Name                    string `json:"name"`
+	Workload                string `json:"workload"`
+	Container               string `json:"container,omitempty"`
+	HandlerContainerStopped bool   `json:"handler_container_stopped"`
+}
+
+func removeIngestOrIntercept(ctx context.Context, name, container string) (*leaveResult, error) {
 	userD := daemon.GetUserClient(ctx)
 
 	var ic *manager.InterceptInfo
 	var ig *connector.IngestInfo
 	var env map[string]string
+	var left *leaveResult
 	var err error
 	if container == "" {
 		ic, err = userD.GetIntercept(ctx, &manager.GetInterceptRequest{Name: name})
 		if err != nil && status.Code(err) != codes.NotFound {
-			return err
+			return nil, err
 		}
 	}
 
@@ -97,14 +179,26 @@ func removeIngestOrIntercept(ctx context.Context, name, container string) error
 		})
 		if err != nil {
 			if status.Code(err) != codes.NotFound {
-				return err
+				return nil, err
 			}
 			// User probably misspelled the name of the intercept/ingest
-			return errcat.User.Newf("Intercept or ingest named %q not found", name)
+			return nil, errcat.User.Newf("Intercept or ingest named %q not found", name)
 		}
 		env = ig.Environment
+		left = &leaveResult{
+			Kind:      "ingest",
+			Name:      ig.Workload,
+			Workload:  ig.Workload,
+			Container: ig.Container,
+		}
 	} else {
 		env = ic.Environment
+		left = &leaveResult{
+			Kind:      "intercept",
+			Name:      ic.Spec.Name,
+			Workload:  ic.Spec.Agent,
+			Container: ic.Spec.ContainerName,
+		}
 	}
 
 	handlerContainer, stopContainer := env["TELEPRESENCE_HANDLER_CONTAINER_NAME"]
@@ -114,6 +208,8 @@ func removeIngestOrIntercept(ctx context.Context, name, container string) error
 		err = docker.StopContainer(docker.EnableClient(ctx), handlerContainer)
 		if err != nil {
 			dlog.Error(ctx, err)
+		} else {
+			left.HandlerContainerStopped = true
 		}
 	}
 
@@ -131,5 +227,8 @@ func removeIngestOrIntercept(ctx context.Context, name, container string) error
 			err = nil
 		}
 	}
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return left, nil
 }
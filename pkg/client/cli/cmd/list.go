@@ -2,10 +2,17 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/rpc/v2/connector"
@@ -13,6 +20,7 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/global"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/intercept"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
@@ -26,6 +34,9 @@ type listCommand struct {
 	onlyInterceptable bool
 	debug             bool
 	namespace         string
+	selector          string
+	format            string
+	textFormat        output.TextFormat
 	watch             bool
 }
 
@@ -49,11 +60,17 @@ func list() *cobra.Command {
 	flags.BoolVarP(&s.onlyInterceptable, "only-interceptable", "o", true, "interceptable workloads only")
 	flags.BoolVar(&s.debug, "debug", false, "include debugging information")
 	flags.StringVarP(&s.namespace, "namespace", "n", "", "If present, the namespace scope for this CLI request")
+	flags.StringVarP(&s.selector, "selector", "l", "", ``+
+		`Label selector to filter on, supports '=', '==', '!=', 'in', 'notin', and 'exists' (e.g. -l key1=value1,key2=value2). `+
+		`Requires a traffic-manager of version 2.22.0 or later`)
+	flags.StringVar(&s.format, "format", string(output.TextDetailed), ``+
+		`The format of the text output, one of `+output.TextFormatNames()+`. The "name" format prints one workload name `+
+		`per line, and the "wide" format adds the pod IPs, ports, and mount points of intercepts and ingests to the table`)
 
-	flags.BoolVarP(&s.watch, "watch", "w", false, "watch a namespace. --agents and --intercepts are disabled if this flag is set")
-	wf := flags.Lookup("watch")
-	wf.Hidden = true
-	wf.Deprecated = `Use "--output json-stream" instead of "--watch"`
+	flags.BoolVarP(&s.watch, "watch", "w", false, ``+
+		`watch a namespace and print one line of JSON each time a workload is added, modified, or deleted. `+
+		`A workload is modified when its intercepts or ingests change. Implies --output json-stream`)
+	cmd.PersistentPreRunE = s.watchAsStream
 
 	_ = cmd.RegisterFlagCompletionFunc("namespace", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		shellCompDir := cobra.ShellCompDirectiveNoFileComp
@@ -82,8 +99,34 @@ type watchWorkloadStreamResponse struct {
 	err                  error
 }
 
+// watchAsStream is a PersistentPreRunE function that makes the --watch flag imply "--output json-stream".
+// It can be combined with "--output json", because a stream of events is printed either way.
+func (s *listCommand) watchAsStream(cmd *cobra.Command, args []string) error {
+	if s.watch {
+		if output.WantsFormatted(cmd) && !output.WantsStream(cmd) && !strings.EqualFold(cmd.Flag(global.FlagOutput).Value.String(), "json") {
+			return errcat.User.New("--watch can only be combined with --output json or --output json-stream")
+		}
+		if err := cmd.Root().PersistentFlags().Set(global.FlagOutput, "json-stream"); err != nil {
+			return err
+		}
+	}
+	return cmd.Root().PersistentPreRunE(cmd, args)
+}
+
 // list requests a list current intercepts from the daemon.
 func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
+	if s.selector != "" {
+		if _, err := labels.Parse(s.selector); err != nil {
+			return errcat.User.Newf("invalid --selector %q: %v", s.selector, err)
+		}
+	}
+	var err error
+	if s.textFormat, err = output.ParseTextFormat(s.format); err != nil {
+		return err
+	}
+	if s.textFormat != output.TextDetailed && output.WantsFormatted(cmd) {
+		return errcat.User.New("--format cannot be used together with --output")
+	}
 	if err := connect.InitCommand(cmd); err != nil {
 		return err
 	}
@@ -114,7 +157,11 @@ func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
 
 	formattedOutput := output.WantsFormatted(cmd)
 	if !output.WantsStream(cmd) {
-		r, err := userD.List(ctx, &connector.ListRequest{Filter: filter, Namespace: s.namespace}, grpc.MaxCallRecvMsgSize(int(maxRecSize)))
+		r, err := userD.List(ctx, &connector.ListRequest{
+			Filter:        filter,
+			Namespace:     s.namespace,
+			LabelSelector: s.selector,
+		}, grpc.MaxCallRecvMsgSize(int(maxRecSize)))
 		if err != nil {
 			return err
 		}
@@ -122,7 +169,10 @@ func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
 		return nil
 	}
 
-	stream, streamErr := userD.WatchWorkloads(ctx, &connector.WatchWorkloadsRequest{Namespaces: []string{s.namespace}}, grpc.MaxCallRecvMsgSize(int(maxRecSize)))
+	stream, streamErr := userD.WatchWorkloads(ctx, &connector.WatchWorkloadsRequest{
+		Namespaces:    []string{s.namespace},
+		LabelSelector: s.selector,
+	}, grpc.MaxCallRecvMsgSize(int(maxRecSize)))
 	if streamErr != nil {
 		return streamErr
 	}
@@ -142,6 +192,7 @@ func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
 		}
 	}()
 
+	var known map[string]*connector.WorkloadInfo
 	for {
 		select {
 		case r, ok := <-ch:
@@ -151,6 +202,14 @@ func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
 			if r.err != nil {
 				return errcat.NoDaemonLogs.Newf("%v", r.err)
 			}
+			if s.watch {
+				var events []*workloadEvent
+				events, known = workloadEvents(known, r.workloadInfoSnapshot.Workloads)
+				for _, ev := range events {
+					output.Object(ctx, ev, true)
+				}
+				continue
+			}
 			s.printList(ctx, r.workloadInfoSnapshot.Workloads, stdout, formattedOutput)
 		case <-ctx.Done():
 			return nil
@@ -158,33 +217,59 @@ func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
 	}
 }
 
+// workloadEvent is printed by list --watch each time a workload is added, modified, or deleted. The types
+// are the same as those of the manager.WorkloadEvent, but the workload is the one that the list command prints,
+// which includes the intercepts and ingests.
+type workloadEvent struct {
+	Type     string                  `json:"type"`
+	Workload *connector.WorkloadInfo `json:"workload"`
+}
+
+const (
+	workloadAdded    = "added"
+	workloadModified = "modified"
+	workloadDeleted  = "deleted"
+)
+
+// workloadEvents compares the given snapshot of workloads with the known workloads, and returns the events
+// that describe the differences, together with the workloads of the snapshot keyed by name and namespace.
+func workloadEvents(known map[string]*connector.WorkloadInfo, workloads []*connector.WorkloadInfo) ([]*workloadEvent, map[string]*connector.WorkloadInfo) {
+	var events []*workloadEvent
+	current := make(map[string]*connector.WorkloadInfo, len(workloads))
+	for _, wl := range workloads {
+		key := wl.Name + "." + wl.Namespace
+		current[key] = wl
+		if old, ok := known[key]; !ok {
+			events = append(events, &workloadEvent{Type: workloadAdded, Workload: wl})
+		} else if !proto.Equal(old, wl) {
+			events = append(events, &workloadEvent{Type: workloadModified, Workload: wl})
+		}
+	}
+	var deleted []string
+	for key := range known {
+		if _, ok := current[key]; !ok {
+			deleted = append(deleted, key)
+		}
+	}
+	slices.Sort(deleted)
+	for _, key := range deleted {
+		events = append(events, &workloadEvent{Type: workloadDeleted, Workload: known[key]})
+	}
+	return events, current
+}
+
 func (s *listCommand) printList(ctx context.Context, workloads []*connector.WorkloadInfo, stdout io.Writer, formattedOut bool) {
 	if len(workloads) == 0 {
 		if formattedOut {
 			output.Object(ctx, []struct{}{}, false)
 		} else {
-			ioutil.Println(stdout, "No Workloads (Deployments, StatefulSets, ReplicaSets, or Rollouts)")
+			if s.textFormat != output.TextName {
+				ioutil.Println(stdout, "No Workloads (Deployments, StatefulSets, ReplicaSets, or Rollouts)")
+			}
 		}
 		return
 	}
 
-	state := func(workload *connector.WorkloadInfo) string {
-		if iis, igs := workload.InterceptInfos, workload.IngestInfos; len(iis)+len(igs) > 0 {
-			return intercept.DescribeIntercepts(ctx, iis, igs, nil, s.debug)
-		}
-		if workload.NotInterceptableReason == "Progressing" {
-			return "progressing..."
-		}
-		if workload.AgentVersion != "" {
-			return "ready to intercept (traffic-agent already installed)"
-		}
-		if workload.NotInterceptableReason != "" {
-			return "not interceptable (traffic-agent not installed): " + workload.NotInterceptableReason
-		} else {
-			return "ready to intercept (traffic-agent not yet installed)"
-		}
-	}
-
 	if formattedOut {
 		output.Object(ctx, workloads, false)
 	} else {
@@ -198,6 +283,10 @@ func (s *listCommand) printList(ctx context.Context, workloads []*connector.Work
 			}
 			ns = depNs
 		}
+		if s.textFormat != "" && s.textFormat != output.TextDetailed {
+			s.printTable(workloads, stdout, includeNs)
+			return
+		}
 		nameLen := 0
 		for _, dep := range workloads {
 			n := dep.Name
@@ -214,7 +303,110 @@ func (s *listCommand) printList(ctx context.Context, workloads []*connector.Work
 			if includeNs {
 				n += "." + workload.Namespace
 			}
-			ioutil.Printf(stdout, "%-*s: %s\n", nameLen, n, state(workload))
+			ioutil.Printf(stdout, "%-*s: %s\n", nameLen, n, s.state(ctx, workload))
+		}
+	}
+}
+
+// printTable prints the given workloads as a table, or just their names, depending on the text format.
+func (s *listCommand) printTable(workloads []*connector.WorkloadInfo, stdout io.Writer, includeNs bool) {
+	names := make([]string, len(workloads))
+	for i, wl := range workloads {
+		names[i] = wl.Name
+		if includeNs {
+			names[i] += "." + wl.Namespace
+		}
+	}
+	if s.textFormat == output.TextName {
+		_ = output.Names(stdout, names)
+		return
+	}
+	wide := s.textFormat == output.TextWide
+	header := []string{"NAME", "KIND", "STATE"}
+	if wide {
+		header = append(header, "POD IP", "PORTS", "MOUNT POINT")
+	}
+	rows := make([][]string, len(workloads))
+	for i, wl := range workloads {
+		rows[i] = []string{names[i], wl.WorkloadResourceType, shortState(wl)}
+		if wide {
+			rows[i] = append(rows[i], wideColumns(wl)...)
 		}
 	}
+	_ = output.Table(stdout, header, rows)
+}
+
+// shortState returns a one-word description of the state of the given workload, suitable for a table cell.
+func shortState(wl *connector.WorkloadInfo) string {
+	switch {
+	case len(wl.InterceptInfos) > 0 && len(wl.IngestInfos) > 0:
+		return "intercepted,ingested"
+	case len(wl.InterceptInfos) > 0:
+		return "intercepted"
+	case len(wl.IngestInfos) > 0:
+		return "ingested"
+	case wl.NotInterceptableReason == "Progressing":
+		return "progressing"
+	case wl.NotInterceptableReason == "Paused":
+		return "paused"
+	case wl.AgentVersionMismatch:
+		return "outdated-agent"
+	case wl.AgentVersion != "":
+		return "installed"
+	case wl.NotInterceptableReason != "":
+		return "not-interceptable"
+	default:
+		return "ready"
+	}
+}
+
+// wideColumns returns the pod IPs, ports, and mount points of the intercepts and ingests of the given workload,
+// each as a comma separated list.
+func wideColumns(wl *connector.WorkloadInfo) []string {
+	var podIPs, ports, mounts []string
+	add := func(vs []string, v string) []string {
+		if v != "" && !slices.Contains(vs, v) {
+			vs = append(vs, v)
+		}
+		return vs
+	}
+	for _, ii := range wl.InterceptInfos {
+		spec := ii.Spec
+		podIPs = add(podIPs, ii.PodIp)
+		pid := spec.PortIdentifier
+		if pid == "" {
+			pid = strconv.Itoa(int(spec.ContainerPort))
+		}
+		ports = add(ports, pid+"->"+net.JoinHostPort(spec.TargetHost, strconv.Itoa(int(spec.TargetPort))))
+		mounts = add(mounts, ii.ClientMountPoint)
+	}
+	for _, ig := range wl.IngestInfos {
+		podIPs = add(podIPs, ig.PodIp)
+		mounts = add(mounts, ig.ClientMountPoint)
+	}
+	return []string{strings.Join(podIPs, ","), strings.Join(ports, ","), strings.Join(mounts, ",")}
+}
+
+func (s *listCommand) state(ctx context.Context, workload *connector.WorkloadInfo) string {
+	if iis, igs := workload.InterceptInfos, workload.IngestInfos; len(iis)+len(igs) > 0 {
+		return intercept.DescribeIntercepts(ctx, iis, workload.InterceptMounts, igs, nil, s.debug)
+	}
+	if workload.NotInterceptableReason == "Progressing" {
+		return "progressing..."
+	}
+	if workload.NotInterceptableReason == "Paused" {
+		return "paused (the rollout will not progress until it is resumed)"
+	}
+	if workload.AgentVersion != "" {
+		if workload.AgentVersionMismatch {
+			return fmt.Sprintf("ready to intercept (traffic-agent %s is outdated, use \"telepresence uninstall %s\" to re-inject it on next intercept)",
+				workload.AgentVersion, workload.Name)
+		}
+		return "ready to intercept (traffic-agent already installed)"
+	}
+	if workload.NotInterceptableReason != "" {
+		return "not interceptable (traffic-agent not installed): " + workload.NotInterceptableReason
+	} else {
+		return "ready to intercept (traffic-agent not yet installed)"
+	}
 }
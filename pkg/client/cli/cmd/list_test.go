@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/global"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+)
+
+func Test_listState(t *testing.T) {
+	s := &listCommand{}
+	ctx := context.Background()
+	wl := &connector.WorkloadInfo{Name: "echo", Namespace: "default", AgentVersion: "v2.21.3"}
+	assert.Equal(t, "ready to intercept (traffic-agent already installed)", s.state(ctx, wl))
+
+	wl.AgentVersionMismatch = true
+	st := s.state(ctx, wl)
+	assert.Contains(t, st, "traffic-agent v2.21.3 is outdated")
+	assert.Contains(t, st, `"telepresence uninstall echo"`)
+}
+
+func Test_printListFormats(t *testing.T) {
+	workloads := []*connector.WorkloadInfo{
+		{
+			Name:                 "echo",
+			Namespace:            "default",
+			WorkloadResourceType: "Deployment",
+			AgentVersion:         "v2.22.0",
+			InterceptInfos: []*manager.InterceptInfo{{
+				Id:               "echo:echo",
+				Disposition:      manager.InterceptDispositionType_ACTIVE,
+				PodIp:            "10.1.0.7",
+				ClientMountPoint: "/tmp/echo",
+				Spec: &manager.InterceptSpec{
+					Name:           "echo",
+					WorkloadKind:   "Deployment",
+					PortIdentifier: "http",
+					TargetHost:     "127.0.0.1",
+					TargetPort:     8080,
+				},
+			}},
+		},
+		{
+			Name:                 "hello",
+			Namespace:            "default",
+			WorkloadResourceType: "StatefulSet",
+			AgentVersion:         "v2.22.0",
+			IngestInfos: []*connector.IngestInfo{{
+				Workload:     "hello",
+				WorkloadKind: "StatefulSet",
+				Container:    "hello",
+				PodIp:        "10.1.0.9",
+			}},
+		},
+		{
+			Name:                   "rollout",
+			Namespace:              "default",
+			WorkloadResourceType:   "Rollout",
+			NotInterceptableReason: "Paused",
+		},
+		{
+			Name:                 "web",
+			Namespace:            "default",
+			WorkloadResourceType: "Deployment",
+		},
+	}
+
+	tests := []struct {
+		format output.TextFormat
+		want   string
+	}{
+		{
+			format: output.TextName,
+			want: `echo
+hello
+rollout
+web
+`,
+		},
+		{
+			format: output.TextTable,
+			want: `NAME     KIND         STATE
+echo     Deployment   intercepted
+hello    StatefulSet  ingested
+rollout  Rollout      paused
+web      Deployment   ready
+`,
+		},
+		{
+			format: output.TextWide,
+			want: `NAME     KIND         STATE        POD IP    PORTS                 MOUNT POINT
+echo     Deployment   intercepted  10.1.0.7  http->127.0.0.1:8080  /tmp/echo
+hello    StatefulSet  ingested     10.1.0.9  -                     -
+rollout  Rollout      paused       -         -                     -
+web      Deployment   ready        -         -                     -
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			s := &listCommand{textFormat: tt.format}
+			out := &bytes.Buffer{}
+			s.printList(context.Background(), workloads, out, false)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+
+	t.Run(string(output.TextDetailed), func(t *testing.T) {
+		s := &listCommand{textFormat: output.TextDetailed}
+		out := &bytes.Buffer{}
+		s.printList(client.WithConfig(context.Background(), client.GetDefaultConfig()), workloads, out, false)
+		lines := strings.Split(out.String(), "\n")
+		assert.Equal(t, "echo   : intercepted", lines[0])
+		assert.Contains(t, out.String(), "Intercept name")
+		assert.Contains(t, out.String(), "hello  : ingested")
+		assert.Contains(t, out.String(), "rollout: paused (the rollout will not progress until it is resumed)")
+		assert.Contains(t, out.String(), "web    : ready to intercept (traffic-agent not yet installed)")
+	})
+}
+
+func Test_listWatchAsStream(t *testing.T) {
+	newRoot := func(args ...string) (*cobra.Command, *cobra.Command) {
+		root := &cobra.Command{Use: "telepresence", PersistentPreRunE: func(*cobra.Command, []string) error { return nil }}
+		root.PersistentFlags().String(global.FlagOutput, "default", "")
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		lc := list()
+		lc.RunE = func(*cobra.Command, []string) error { return nil }
+		root.AddCommand(lc)
+		root.SetArgs(append([]string{"list"}, args...))
+		return root, lc
+	}
+
+	root, lc := newRoot("--watch")
+	require.NoError(t, root.Execute())
+	assert.True(t, output.WantsStream(lc))
+
+	root, lc = newRoot("--watch", "--output", "json-stream")
+	require.NoError(t, root.Execute())
+	assert.True(t, output.WantsStream(lc))
+
+	root, lc = newRoot("--watch", "--output", "json")
+	require.NoError(t, root.Execute())
+	assert.True(t, output.WantsStream(lc))
+
+	root, _ = newRoot("--watch", "--output", "yaml")
+	assert.ErrorContains(t, root.Execute(), "--watch can only be combined with --output json or --output json-stream")
+}
+
+func Test_workloadEvents(t *testing.T) {
+	echo := &connector.WorkloadInfo{Name: "echo", Namespace: "default", WorkloadResourceType: "Deployment"}
+	hello := &connector.WorkloadInfo{Name: "hello", Namespace: "default", WorkloadResourceType: "Deployment"}
+
+	events, known := workloadEvents(nil, []*connector.WorkloadInfo{echo, hello})
+	require.Len(t, events, 2)
+	assert.Equal(t, workloadAdded, events[0].Type)
+	assert.Equal(t, "echo", events[0].Workload.Name)
+	assert.Equal(t, workloadAdded, events[1].Type)
+
+	// An unchanged snapshot produces no events.
+	events, known = workloadEvents(known, []*connector.WorkloadInfo{echo, hello})
+	assert.Empty(t, events)
+
+	// An intercept is reported as a modification of the workload.
+	intercepted := &connector.WorkloadInfo{
+		Name:                 "echo",
+		Namespace:            "default",
+		WorkloadResourceType: "Deployment",
+		InterceptInfos:       []*manager.InterceptInfo{{Id: "echo:echo"}},
+	}
+	events, known = workloadEvents(known, []*connector.WorkloadInfo{intercepted, hello})
+	require.Len(t, events, 1)
+	assert.Equal(t, workloadModified, events[0].Type)
+	assert.Len(t, events[0].Workload.InterceptInfos, 1)
+
+	events, _ = workloadEvents(known, []*connector.WorkloadInfo{hello})
+	require.Len(t, events, 1)
+	assert.Equal(t, workloadDeleted, events[0].Type)
+	assert.Equal(t, "echo", events[0].Workload.Name)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+func proxyViaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy-via",
+		Short: "Inspect --proxy-via specifications",
+	}
+	cmd.AddCommand(proxyViaPreview())
+	return cmd
+}
+
+type proxyViaPreviewCommand struct {
+	rq *daemon.CobraRequest
+}
+
+func proxyViaPreview() *cobra.Command {
+	pvc := &proxyViaPreviewCommand{}
+	cmd := &cobra.Command{
+		Use:   "preview [CIDR=WORKLOAD...]",
+		Args:  cobra.ArbitraryArgs,
+		Short: "Show how the given --proxy-via specifications would be handled, without connecting",
+		Long: `Show how the given --proxy-via specifications would be handled, without connecting.
+
+Each specification is parsed the same way as a "telepresence connect --proxy-via" flag. The workloads are resolved
+in the namespace that a connection would use, and the virtual subnet that IPs in each subnet would be translated to
+is computed from the client configuration.`,
+		Example:           "telepresence proxy-via preview 127.0.0.1/32=echo",
+		RunE:              pvc.run,
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+	pvc.rq = daemon.InitRequest(cmd)
+	return cmd
+}
+
+func (pvc *proxyViaPreviewCommand) run(cmd *cobra.Command, args []string) error {
+	pvs, err := pvc.rq.PreviewProxyVias(cmd, args)
+	if err != nil {
+		return err
+	}
+	if len(pvs.ProxyVias) == 0 {
+		return errcat.User.New("no proxy-via specifications were given")
+	}
+
+	ctx := cmd.Context()
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, pvs, false)
+		return nil
+	}
+	out := output.Out(ctx)
+	for _, pv := range pvs.ProxyVias {
+		sn := pv.Subnet
+		if pv.Port != 0 {
+			sn = fmt.Sprintf("%s:%d", sn, pv.Port)
+		}
+		vs := pv.VirtualSubnet
+		if pv.RandomIPv6Subnet {
+			vs = "a random IPv6 ULA /64 subnet"
+		}
+		switch {
+		case pv.Workload == "local":
+			fmt.Fprintf(out, "%s: translated locally to virtual IPs in %s\n", sn, vs)
+		case pv.WorkloadKind == "":
+			fmt.Fprintf(out, "%s: routed via the traffic-agent of pod IP %s using virtual IPs in %s\n",
+				sn, pv.Workload, vs)
+		default:
+			fmt.Fprintf(out, "%s: routed via %s %s.%s using virtual IPs in %s\n",
+				sn, pv.WorkloadKind, pv.Workload, pv.Namespace, vs)
+		}
+	}
+	if pvs.AutoResolveConflictsIgnored {
+		fmt.Fprintln(out, "routing.autoResolveConflicts is ignored when proxy-via is used. Cluster subnets that conflict with existing routes will cause the connect to fail")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"net/netip"
+
+	"github.com/spf13/cobra"
+
+	daemonRpc "github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
+)
+
+func routingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "routing",
+		Short: "Change the also-proxy and never-proxy subnets of the current connection without reconnecting",
+	}
+	cmd.AddCommand(routingUpdate(true), routingUpdate(false))
+	return cmd
+}
+
+type routingCommand struct {
+	add        bool
+	alsoProxy  []string
+	neverProxy []string
+}
+
+func routingUpdate(add bool) *cobra.Command {
+	rc := &routingCommand{add: add}
+	cmd := &cobra.Command{
+		Args: cobra.NoArgs,
+		Annotations: map[string]string{
+			ann.Session: ann.Required,
+		},
+		RunE:              rc.run,
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+	if add {
+		cmd.Use = "add"
+		cmd.Short = "Add also-proxy or never-proxy subnets to the current connection"
+		cmd.Example = "telepresence routing add --never-proxy 10.0.5.0/24"
+	} else {
+		cmd.Use = "remove"
+		cmd.Short = "Remove also-proxy or never-proxy subnets from the current connection"
+		cmd.Example = "telepresence routing remove --never-proxy 10.0.5.0/24"
+	}
+	flags := cmd.Flags()
+	flags.StringSliceVar(&rc.alsoProxy, "also-proxy", nil, `Comma separated list of also-proxy CIDR`)
+	flags.StringSliceVar(&rc.neverProxy, "never-proxy", nil, `Comma separated list of never-proxy CIDR`)
+	return cmd
+}
+
+func (rc *routingCommand) run(cmd *cobra.Command, _ []string) error {
+	if len(rc.alsoProxy)+len(rc.neverProxy) == 0 {
+		return errcat.User.New("at least one --also-proxy or --never-proxy subnet must be given")
+	}
+	for _, cidr := range append(rc.alsoProxy, rc.neverProxy...) {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			return errcat.User.Newf("invalid subnet %q: %v", cidr, err)
+		}
+	}
+	if err := connect.InitCommand(cmd); err != nil {
+		return err
+	}
+	rq := &daemonRpc.UpdateRoutingRequest{}
+	if rc.add {
+		rq.AddAlsoProxy = rc.alsoProxy
+		rq.AddNeverProxy = rc.neverProxy
+	} else {
+		rq.RemoveAlsoProxy = rc.alsoProxy
+		rq.RemoveNeverProxy = rc.neverProxy
+	}
+	ctx := cmd.Context()
+	if _, err := daemon.GetUserClient(ctx).UpdateRouting(ctx, rq); err != nil {
+		return err
+	}
+	ioutil.Println(cmd.OutOrStdout(), "Routing updated")
+	return nil
+}
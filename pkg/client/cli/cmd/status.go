@@ -20,6 +20,7 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/global"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/scout"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
 )
 
@@ -98,6 +99,7 @@ type ConnectStatusIntercept struct {
 const (
 	multiDaemonFlag = "multi-daemon"
 	jsonFlag        = "json"
+	watchFlag       = "watch"
 )
 
 func statusCmd() *cobra.Command {
@@ -116,6 +118,9 @@ func statusCmd() *cobra.Command {
 	flags.Bool(multiDaemonFlag, false, "always use multi-daemon output format, even if there's only one daemon connected")
 	flags.BoolP(jsonFlag, "j", false, "output as json object")
 	flags.Lookup(jsonFlag).Hidden = true
+	flags.BoolP(watchFlag, "w", false, ``+
+		`watch the status and print it again each time it changes, e.g. when the connection state, the subnets, `+
+		`or the active intercepts and ingests change. Use --output json-stream to get one json object per status`)
 	return cmd
 }
 
@@ -125,9 +130,20 @@ func fixFlag(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	watch, err := flags.GetBool(watchFlag)
+	if err != nil {
+		return err
+	}
 	rootCmd := cmd.Parent()
-	if json {
-		if err = rootCmd.PersistentFlags().Set(global.FlagOutput, "json"); err != nil {
+	switch {
+	case watch && output.WantsFormatted(cmd) && !output.WantsStream(cmd):
+		return errcat.User.New("--watch can only be combined with --output json-stream")
+	case json:
+		format := "json"
+		if watch {
+			format = "json-stream"
+		}
+		if err = rootCmd.PersistentFlags().Set(global.FlagOutput, format); err != nil {
 			return err
 		}
 	}
@@ -145,6 +161,13 @@ func run(cmd *cobra.Command, _ []string) error {
 	}
 	ctx := cmd.Context()
 
+	if watch, _ := cmd.Flags().GetBool(watchFlag); watch {
+		if len(mdErr) > 0 {
+			return errcat.User.New("--watch cannot be used when more than one daemon is running")
+		}
+		return watchStatus(cmd)
+	}
+
 	var sis []ioutil.WriterTos
 	if len(mdErr) > 0 {
 		sis = make([]ioutil.WriterTos, len(mdErr))
@@ -172,29 +195,74 @@ func run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	as := connectStatusInfo(cmd, sx, sis)
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, &as, true)
+	} else {
+		_, _ = ioutil.WriteAllTo(cmd.OutOrStdout(), as.WriterTos()...)
+	}
+	return nil
+}
+
+// watchStatus prints the status of the connected daemon each time it changes, until the
+// command's context is cancelled. Each status is printed as a separate object when the
+// output is a json-stream.
+func watchStatus(cmd *cobra.Command) error {
+	ctx := cmd.Context()
+	userD := daemon.GetUserClient(ctx)
+	if userD == nil {
+		return errcat.User.New("--watch requires a running user daemon")
+	}
+	sx, err := GetStatusInfo(ctx)
+	if err != nil {
+		return err
+	}
+	stream, err := userD.WatchStatus(ctx, &empty.Empty{})
+	if err != nil {
+		return err
+	}
+
+	formattedOutput := output.WantsStream(cmd)
+	out := cmd.OutOrStdout()
+	for first := true; ; first = false {
+		ci, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errcat.NoDaemonLogs.Newf("%v", err)
+		}
+		si, err := newStatusInfo(ctx, userD, nil, ci)
+		if err != nil {
+			return err
+		}
+		as := connectStatusInfo(cmd, sx, []ioutil.WriterTos{si})
+		if formattedOutput {
+			output.Object(ctx, as, true)
+			continue
+		}
+		if !first {
+			ioutil.Println(out, "")
+		}
+		_, _ = ioutil.WriteAllTo(out, as.WriterTos()...)
+	}
+}
+
+func connectStatusInfo(cmd *cobra.Command, sx ioutil.WriterTos, sis []ioutil.WriterTos) ioutil.WriterTos {
 	multiFormat := len(sis) > 1
 	if !multiFormat {
 		multiFormat, _ = cmd.Flags().GetBool(multiDaemonFlag)
 	}
-	var as ioutil.WriterTos
 	if multiFormat {
-		as = &MultiConnectStatusInfo{
+		return &MultiConnectStatusInfo{
 			extendedInfo: sx,
 			statusInfos:  sis,
 		}
-	} else {
-		as = &SingleConnectStatusInfo{
-			extendedInfo: sx,
-			statusInfo:   sis[0],
-		}
 	}
-
-	if output.WantsFormatted(cmd) {
-		output.Object(ctx, &as, true)
-	} else {
-		_, _ = ioutil.WriteAllTo(cmd.OutOrStdout(), as.WriterTos()...)
+	return &SingleConnectStatusInfo{
+		extendedInfo: sx,
+		statusInfo:   sis[0],
 	}
-	return nil
 }
 
 // GetStatusInfo may return an extended struct
@@ -248,11 +316,20 @@ func (s *StatusInfo) toMap() map[string]any {
 }
 
 func getStatusInfo(ctx context.Context, di *daemon.Info) (*StatusInfo, error) {
-	wt := &StatusInfo{}
 	userD := daemon.GetUserClient(ctx)
 	if userD == nil {
-		return wt, nil
+		return &StatusInfo{}, nil
 	}
+	status, err := userD.Status(ctx, &empty.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return newStatusInfo(ctx, userD, di, status)
+}
+
+// newStatusInfo creates the StatusInfo that describes the given status of the given user daemon.
+func newStatusInfo(ctx context.Context, userD daemon.UserClient, di *daemon.Info, status *connector.ConnectInfo) (*StatusInfo, error) {
+	wt := &StatusInfo{}
 	ctx = scout.NewReporter(ctx, "cli")
 	us := &wt.UserDaemon
 	installID, err := client.InstallID(ctx)
@@ -281,10 +358,6 @@ func getStatusInfo(ctx context.Context, di *daemon.Info) (*StatusInfo, error) {
 		us.versionName = "User daemon"
 	}
 
-	status, err := userD.Status(ctx, &empty.Empty{})
-	if err != nil {
-		return nil, err
-	}
 	switch status.Error {
 	case connector.ConnectInfo_UNSPECIFIED, connector.ConnectInfo_ALREADY_CONNECTED:
 		us.Status = "Connected"
@@ -482,8 +555,17 @@ func printDNS(kvf *ioutil.KeyValueFormatter, d *client.DNSSnake) {
 	if d.RemoteIP.IsValid() {
 		dnsKvf.Add("Remote IP", d.RemoteIP.String())
 	}
+	if len(d.Fallback) > 0 {
+		dnsKvf.Add("Fallback", fmt.Sprintf("%v", d.Fallback))
+	}
+	if d.Redirect != "" {
+		dnsKvf.Add("Redirect", string(d.Redirect))
+	}
 	dnsKvf.Add("Exclude suffixes", fmt.Sprintf("%v", d.ExcludeSuffixes))
 	dnsKvf.Add("Include suffixes", fmt.Sprintf("%v", d.IncludeSuffixes))
+	if len(d.BypassSuffixes) > 0 {
+		dnsKvf.Add("Bypass suffixes", fmt.Sprintf("%v", d.BypassSuffixes))
+	}
 	if len(d.Excludes) > 0 {
 		dnsKvf.Add("Excludes", fmt.Sprintf("%v", d.Excludes))
 	}
@@ -510,10 +592,24 @@ func printRouting(kvf *ioutil.KeyValueFormatter, r *client.RoutingSnake) {
 		}
 		kvf.Add(title, out.String())
 	}
-	printSubnets("Subnets", r.Subnets)
+	if len(r.RoutedSubnets) > 0 {
+		out := &strings.Builder{}
+		ioutil.Printf(out, "(%d subnets)", len(r.RoutedSubnets))
+		for _, rs := range r.RoutedSubnets {
+			if rs.Source == "" {
+				ioutil.Printf(out, "\n- %s", rs.Subnet)
+			} else {
+				ioutil.Printf(out, "\n- %s (%s)", rs.Subnet, rs.Source)
+			}
+		}
+		kvf.Add("Subnets", out.String())
+	} else {
+		printSubnets("Subnets", r.Subnets)
+	}
 	printSubnets("Also Proxy", r.AlsoProxy)
 	printSubnets("Never Proxy", r.NeverProxy)
 	printSubnets("Allow conflicts for", r.AllowConflicting)
+	printSubnets("Virtual subnets", r.VirtualSubnets)
 }
 
 func (cs *UserDaemonStatus) WriteTo(out io.Writer) (int64, error) {
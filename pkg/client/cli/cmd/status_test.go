@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/global"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+)
+
+func Test_statusWatchOutput(t *testing.T) {
+	newRoot := func(args ...string) (*cobra.Command, *cobra.Command) {
+		root := &cobra.Command{Use: "telepresence", PersistentPreRunE: func(*cobra.Command, []string) error { return nil }}
+		root.PersistentFlags().String(global.FlagOutput, "default", "")
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		sc := statusCmd()
+		sc.RunE = func(*cobra.Command, []string) error { return nil }
+		root.AddCommand(sc)
+		root.SetArgs(append([]string{"status"}, args...))
+		return root, sc
+	}
+
+	root, sc := newRoot("--watch")
+	require.NoError(t, root.Execute())
+	assert.False(t, output.WantsFormatted(sc))
+
+	root, sc = newRoot("--watch", "--output", "json-stream")
+	require.NoError(t, root.Execute())
+	assert.True(t, output.WantsStream(sc))
+
+	root, sc = newRoot("--watch", "--json")
+	require.NoError(t, root.Execute())
+	assert.True(t, output.WantsStream(sc))
+
+	root, _ = newRoot("--watch", "--output", "json")
+	assert.ErrorContains(t, root.Execute(), "--watch can only be combined with --output json-stream")
+
+	root, _ = newRoot("--watch", "--output", "yaml")
+	assert.ErrorContains(t, root.Execute(), "--watch can only be combined with --output json-stream")
+}
@@ -137,8 +137,8 @@ func WithSubCommands(ctx context.Context) context.Context {
 	return MergeSubCommands(ctx,
 		configCmd(), connectCmd(), currentClusterId(), gatherLogs(), genYAML(), helmCmd(),
 		ingestCmd(), interceptCmd(), kubeauthCmd(), leave(), list(), listContexts(), listNamespaces(), loglevel(), quit(), statusCmd(),
-		dockerRunCmd(), curlCmd(),
-		uninstall(), version(), listNamespaces(), listContexts(),
+		dnsCmd(), dockerRunCmd(), curlCmd(),
+		proxyViaCmd(), routingCmd(), uninstall(), version(), vipCmd(), who(), listNamespaces(), listContexts(),
 	)
 }
 
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"slices"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
 )
@@ -22,6 +24,7 @@ const allAgentsFlag = "all-agents"
 type uninstallCommand struct {
 	agent     bool
 	allAgents bool
+	dryRun    bool
 }
 
 func uninstall() *cobra.Command {
@@ -38,6 +41,7 @@ func uninstall() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	flags.BoolVarP(&ui.allAgents, allAgentsFlag, "a", false, "uninstall intercept agent on all workloads")
+	flags.BoolVar(&ui.dryRun, "dry-run", false, "list the workloads that would be affected without uninstalling anything")
 
 	// Hidden from help but will yield a deprecation warning if used
 	flags.BoolVarP(&ui.agent, "agent", "d", false, "")
@@ -73,12 +77,38 @@ func (u *uninstallCommand) run(cmd *cobra.Command, args []string) error {
 		ur.UninstallType = connector.UninstallRequest_NAMED_AGENTS
 		ur.Agents = args
 	}
+	ur.DryRun = u.dryRun
 	ctx := cmd.Context()
 	r, err := daemon.GetUserClient(ctx).Uninstall(ctx, ur)
 	if err != nil {
 		return err
 	}
-	return errcat.FromResult(r)
+	if err = errcat.FromResult(r); err != nil || !u.dryRun {
+		return err
+	}
+	var names []string
+	if err = json.Unmarshal(r.Data, &names); err != nil {
+		return err
+	}
+	printDryRun(cmd, names)
+	return nil
+}
+
+// printDryRun prints the names of the workloads that an uninstall would modify.
+func printDryRun(cmd *cobra.Command, names []string) {
+	if output.WantsFormatted(cmd) {
+		output.Object(cmd.Context(), names, true)
+		return
+	}
+	out := cmd.OutOrStdout()
+	if len(names) == 0 {
+		ioutil.Println(out, "No traffic-agents would be uninstalled")
+		return
+	}
+	ioutil.Println(out, "Would uninstall the traffic-agent from:")
+	for _, name := range names {
+		ioutil.Printf(out, "  %s\n", name)
+	}
 }
 
 func validWorkloads(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+)
+
+func vipCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vip",
+		Short: "Inspect the virtual IPs used by --proxy-via and automatic conflict resolution",
+	}
+	cmd.AddCommand(vipList())
+	return cmd
+}
+
+type virtualIP struct {
+	RemoteIP  netip.Addr `json:"remote_ip"`
+	VirtualIP netip.Addr `json:"virtual_ip"`
+	Subnet    string     `json:"subnet,omitempty"`
+	Workload  string     `json:"workload,omitempty"`
+}
+
+func vipList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Short: "List the current translations between remote IPs and virtual IPs",
+		Annotations: map[string]string{
+			ann.Session: ann.Required,
+		},
+		RunE:              runVIPList,
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+}
+
+func runVIPList(cmd *cobra.Command, _ []string) error {
+	if err := connect.InitCommand(cmd); err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	rsp, err := daemon.GetUserClient(ctx).GetVirtualIPs(ctx, &empty.Empty{})
+	if err != nil {
+		return err
+	}
+	vips := make([]virtualIP, len(rsp.VirtualIps))
+	for i, vi := range rsp.VirtualIps {
+		ra, _ := netip.AddrFromSlice(vi.RemoteIp)
+		va, _ := netip.AddrFromSlice(vi.VirtualIp)
+		vips[i] = virtualIP{RemoteIP: ra, VirtualIP: va, Subnet: vi.Subnet, Workload: vi.Workload}
+	}
+
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, vips, false)
+		return nil
+	}
+	out := output.Out(ctx)
+	if len(vips) == 0 {
+		fmt.Fprintln(out, "No virtual IPs")
+		return nil
+	}
+	for _, vi := range vips {
+		fmt.Fprintf(out, "%s -> %s (subnet %s)", vi.RemoteIP, vi.VirtualIP, vi.Subnet)
+		if vi.Workload != "" {
+			fmt.Fprintf(out, " via %s", vi.Workload)
+		}
+		fmt.Fprintln(out)
+	}
+	return nil
+}
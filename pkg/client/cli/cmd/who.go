@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+)
+
+type whoCommand struct {
+	namespace string
+}
+
+func who() *cobra.Command {
+	w := &whoCommand{}
+	cmd := &cobra.Command{
+		Use:   "who <workload>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Show the clients that currently intercept a workload",
+		Annotations: map[string]string{
+			ann.Session: ann.Required,
+		},
+		RunE:              w.run,
+		ValidArgsFunction: validWorkloads,
+	}
+	cmd.Flags().StringVarP(&w.namespace, "namespace", "n", "", "The namespace of the workload. Defaults to the connected namespace")
+	return cmd
+}
+
+type interceptHolder struct {
+	Client    string    `json:"client"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+func (w *whoCommand) run(cmd *cobra.Command, args []string) error {
+	if err := connect.InitCommand(cmd); err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	rsp, err := daemon.GetUserClient(ctx).GetInterceptHolders(ctx, &connector.InterceptHoldersRequest{
+		Workload:  args[0],
+		Namespace: w.namespace,
+	})
+	if err != nil {
+		return err
+	}
+	holders := make([]interceptHolder, len(rsp.Holders))
+	for i, h := range rsp.Holders {
+		holders[i].Client = h.Client
+		if h.CreatedAt != nil {
+			holders[i].CreatedAt = h.CreatedAt.AsTime()
+		}
+	}
+
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, holders, false)
+		return nil
+	}
+	out := output.Out(ctx)
+	if len(holders) == 0 {
+		fmt.Fprintf(out, "Workload %s is not intercepted\n", args[0])
+		return nil
+	}
+	for _, h := range holders {
+		if h.CreatedAt.IsZero() {
+			fmt.Fprintln(out, h.Client)
+		} else {
+			fmt.Fprintf(out, "%s since %s (%s)\n", h.Client, h.CreatedAt.Local().Format(time.DateTime), time.Since(h.CreatedAt).Round(time.Second))
+		}
+	}
+	return nil
+}
@@ -86,7 +86,9 @@ func quitDockerDaemons(ctx context.Context) {
 		dlog.Error(ctx, err)
 		return
 	}
+	inDocker := false
 	for _, info := range infos {
+		inDocker = inDocker || info.InDocker
 		udCtx, err := ExistingDaemon(ctx, info)
 		if err != nil {
 			dlog.Error(ctx, err)
@@ -100,6 +102,9 @@ func quitDockerDaemons(ctx context.Context) {
 		dlog.Error(ctx, err)
 		_ = daemon.DeleteAllInfos(ctx)
 	}
+	if inDocker {
+		docker.RemoveOrphanedPublishers(docker.EnableClient(ctx))
+	}
 }
 
 func ExistingDaemon(ctx context.Context, info *daemon.Info) (context.Context, error) {
@@ -252,6 +257,9 @@ func launchConnectorDaemon(ctx context.Context, connectorDaemon string, required
 			_ = fh.Close()
 		}
 		ctx = docker.EnableClient(ctx)
+
+		// Port publishers of daemons that died abruptly are left behind, and may occupy ports that are needed.
+		docker.RemoveOrphanedPublishers(ctx)
 		conn, err = docker.LaunchDaemon(ctx, daemonID)
 	} else {
 		args := []string{connectorDaemon, "connector-foreground"}
@@ -423,6 +431,27 @@ func connectSession(ctx context.Context, useLine string, userD daemon.UserClient
 		return nil
 	}
 
+	// inform about conflicting subnets that are routed using virtual IPs instead of failing the connect.
+	infoRouteConflicts := func() {
+		rcs, err := userD.GetRouteConflicts(ctx, &emptypb.Empty{})
+		if err != nil {
+			dlog.Debugf(ctx, "unable to get route conflicts: %v", err)
+			return
+		}
+		if len(rcs.RouteConflicts) == 0 {
+			return
+		}
+		out := output.Info(ctx)
+		ioutil.Println(out, "The following subnets conflict with existing routes and are routed using virtual IPs:")
+		for _, rc := range rcs.RouteConflicts {
+			ioutil.Printf(out, "  %s overlaps %s on %s", rc.Subnet, rc.Route, rc.Interface)
+			if rc.Gateway != "" {
+				ioutil.Printf(out, " via %s", rc.Gateway)
+			}
+			ioutil.Println(out, "")
+		}
+	}
+
 	connectResult := func(ci *connector.ConnectInfo) (*daemon.Session, error) {
 		var msg string
 		cat := errcat.Unknown
@@ -433,6 +462,7 @@ func connectSession(ctx context.Context, useLine string, userD daemon.UserClient
 			if err != nil {
 				dlog.Error(ctx, err)
 			}
+			infoRouteConflicts()
 			return session(ci, true), nil
 		case connector.ConnectInfo_ALREADY_CONNECTED:
 			return session(ci, false), nil
@@ -0,0 +1,110 @@
+package daemon
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	argorollouts "github.com/datawire/argo-rollouts-go-client/pkg/client/clientset/versioned"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+	"github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// ProxyViaPreview describes how a --proxy-via entry will be handled once a connection is established.
+type ProxyViaPreview struct {
+	// Subnet is the CIDR, or the symbolic name, of the subnet.
+	Subnet string `json:"subnet"`
+
+	// Port is the only destination port that is routed via the workload. Zero means all ports.
+	Port uint16 `json:"port,omitempty"`
+
+	// Workload is the workload that the subnet is routed via, or "local" when the subnet is just translated.
+	Workload string `json:"workload"`
+
+	// WorkloadKind is the kind of the workload, e.g. "Deployment". Empty when the workload is "local".
+	WorkloadKind string `json:"workload_kind,omitempty"`
+
+	// Namespace is the namespace of the workload. Empty when the workload is "local".
+	Namespace string `json:"namespace,omitempty"`
+
+	// VirtualSubnet is the subnet that the virtual IPs will be allocated from. Empty when RandomIPv6Subnet is true.
+	VirtualSubnet string `json:"virtual_subnet,omitempty"`
+
+	// RandomIPv6Subnet is true when the virtual IPs will be allocated from a random IPv6 ULA /64 subnet, picked by
+	// the root daemon when it connects.
+	RandomIPv6Subnet bool `json:"random_ipv6_subnet,omitempty"`
+}
+
+// ProxyViaPreviews is the preview of a set of --proxy-via entries.
+type ProxyViaPreviews struct {
+	// ProxyVias contains one preview for each entry, in the order that they were given.
+	ProxyVias []*ProxyViaPreview `json:"proxy_vias"`
+
+	// AutoResolveConflictsIgnored is true when routing.autoResolveConflicts is enabled in the client configuration.
+	// The setting has no effect when proxy-via is used, so a connection will fail instead of translating cluster
+	// subnets that conflict with existing routes.
+	AutoResolveConflictsIgnored bool `json:"auto_resolve_conflicts_ignored,omitempty"`
+}
+
+// PreviewProxyVias parses the given --proxy-via specs in the same way as the connect command does, and
+// returns a preview of the translations that a connection would use. The workloads are resolved in the
+// namespace that a connection would use, but no connection is established.
+func (cr *CobraRequest) PreviewProxyVias(cmd *cobra.Command, specs []string) (*ProxyViaPreviews, error) {
+	cr.proxyVia = append(cr.proxyVia, specs...)
+	if err := cr.CommitFlags(cmd); err != nil {
+		return nil, err
+	}
+	ctx, kc, err := client.NewKubeconfig(cmd.Context(), cr.KubeFlags, cr.ManagerNamespace)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := kubernetes.NewForConfig(kc.RestConfig)
+	if err != nil {
+		return nil, errcat.NoDaemonLogs.Newf("NewForConfig: %v", err)
+	}
+	acs, err := argorollouts.NewForConfig(kc.RestConfig)
+	if err != nil {
+		return nil, errcat.NoDaemonLogs.Newf("NewForConfig: %v", err)
+	}
+	ctx = k8sapi.WithJoinedClientSetInterface(ctx, cs, acs)
+	return previewProxyVias(ctx, cr.SubnetViaWorkloads, kc.Namespace)
+}
+
+func previewProxyVias(ctx context.Context, svs []*daemon.SubnetViaWorkload, namespace string) (*ProxyViaPreviews, error) {
+	rt := client.GetConfig(ctx).Routing()
+	vs := rt.VirtualSubnet
+	pvs := make([]*ProxyViaPreview, len(svs))
+	for i, sv := range svs {
+		pv := &ProxyViaPreview{
+			Subnet:   sv.Subnet,
+			Port:     uint16(sv.Port),
+			Workload: sv.Workload,
+		}
+		if sn, err := netip.ParsePrefix(sv.Subnet); err == nil && sn.Addr().Is6() && vs.Addr().Is4() {
+			// The root daemon picks a random IPv6 ULA subnet when IPv6 addresses are translated
+			// into an IPv4 virtual subnet.
+			pv.RandomIPv6Subnet = true
+		} else {
+			pv.VirtualSubnet = vs.String()
+		}
+		// A pod IP is used directly as the proxy endpoint, so there's no workload to resolve.
+		if _, err := netip.ParseAddr(sv.Workload); err != nil && sv.Workload != "local" {
+			wl, err := k8sapi.GetWorkload(ctx, sv.Workload, namespace, "")
+			if err != nil {
+				return nil, errcat.User.Newf("unable to resolve proxy-via workload %s.%s: %w", sv.Workload, namespace, err)
+			}
+			pv.WorkloadKind = wl.GetKind()
+			pv.Namespace = namespace
+		}
+		pvs[i] = pv
+	}
+	return &ProxyViaPreviews{
+		ProxyVias: pvs,
+		// The root daemon only resolves conflicts automatically when no proxy-via is used.
+		AutoResolveConflictsIgnored: len(pvs) > 0 && rt.AutoResolveConflicts,
+	}, nil
+}
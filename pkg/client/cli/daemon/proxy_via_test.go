@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	argofake "github.com/datawire/argo-rollouts-go-client/pkg/client/clientset/versioned/fake"
+	"github.com/datawire/dlib/dlog"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+)
+
+func Test_previewProxyVias(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	cfg := client.GetDefaultConfig()
+	cfg.Routing().VirtualSubnet = netip.MustParsePrefix("211.55.48.0/20")
+	cfg.Routing().AutoResolveConflicts = false
+	ctx = client.WithConfig(ctx, cfg)
+	ctx = k8sapi.WithJoinedClientSetInterface(ctx, fake.NewSimpleClientset(&apps.Deployment{
+		ObjectMeta: meta.ObjectMeta{Name: "echo", Namespace: "default"},
+	}), argofake.NewSimpleClientset())
+
+	svs, err := parseProxyVias([]string{"127.0.0.1/32=echo", "::1/128=echo", "service=local"})
+	require.NoError(t, err)
+	pvs, err := previewProxyVias(ctx, svs, "default")
+	require.NoError(t, err)
+	assert.False(t, pvs.AutoResolveConflictsIgnored)
+	assert.Equal(t, []*ProxyViaPreview{
+		{
+			Subnet:        "127.0.0.1/32",
+			Workload:      "echo",
+			WorkloadKind:  "Deployment",
+			Namespace:     "default",
+			VirtualSubnet: "211.55.48.0/20",
+		},
+		{
+			Subnet:           "::1/128",
+			Workload:         "echo",
+			WorkloadKind:     "Deployment",
+			Namespace:        "default",
+			RandomIPv6Subnet: true,
+		},
+		{
+			Subnet:        "service",
+			Workload:      "local",
+			VirtualSubnet: "211.55.48.0/20",
+		},
+	}, pvs.ProxyVias)
+
+	cfg.Routing().AutoResolveConflicts = true
+	pvs, err = previewProxyVias(ctx, svs, "default")
+	require.NoError(t, err)
+	assert.True(t, pvs.AutoResolveConflictsIgnored)
+
+	svs, err = parseProxyVias([]string{"127.0.0.1/32=nope"})
+	require.NoError(t, err)
+	_, err = previewProxyVias(ctx, svs, "default")
+	assert.ErrorContains(t, err, "unable to resolve proxy-via workload nope.default")
+}
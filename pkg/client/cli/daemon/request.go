@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/netip"
 	"os"
 	"regexp"
@@ -54,6 +55,9 @@ type Request struct {
 	// proxyVia holds the string version for the --proxy-via flag values.
 	proxyVia []string
 
+	// managerTLS holds the value of the --manager-tls flag. It's only used when the flag is given.
+	managerTLS bool
+
 	// vnats holds the string version for the --vnat flag values.
 	vnats []string
 }
@@ -79,12 +83,19 @@ func InitRequest(cmd *cobra.Command) *CobraRequest {
 			`Defaults to all namespaces`)
 	nwFlags.StringVar(&cr.ManagerNamespace, "manager-namespace", "", `The namespace where the traffic manager is to be found. `+
 		`Overrides any other manager namespace set in config`)
+	nwFlags.StringVar(&cr.ManagerAddress, "manager-address", "", `The host:port of a traffic manager that is reachable `+
+		`without port-forward, e.g. through an ingress or a load balancer. Cannot be combined with --manager-namespace`)
+	nwFlags.BoolVar(&cr.managerTLS, "manager-tls", false, `Use TLS when connecting to the --manager-address. `+
+		`Overrides the cluster.managerTLS config`)
 	nwFlags.StringSliceVar(&cr.AlsoProxy,
 		"also-proxy", nil, ``+
 			`Additional comma separated list of CIDR to proxy`)
 	nwFlags.StringSliceVar(&cr.NeverProxy,
 		"never-proxy", nil, ``+
 			`Comma separated list of CIDR to never proxy`)
+	nwFlags.StringSliceVar(&cr.NeverProxyHosts,
+		"never-proxy-host", nil, ``+
+			`Comma separated list of hosts to never proxy. Each host is resolved when connecting`)
 	nwFlags.StringSliceVar(&cr.vnats,
 		"vnat", nil, ``+
 			`Use Network Address Translation to create virtual IPs for the given CIDR. CIDR can be substituted for the `+
@@ -92,7 +103,9 @@ func InitRequest(cmd *cobra.Command) *CobraRequest {
 	nwFlags.StringSliceVar(&cr.proxyVia,
 		"proxy-via", nil, ``+
 			`Use Network Address Translation to create virtual IPs for the given CIDR, and route via WORKLOAD. Must be in the`+
-			`form CIDR=WORKLOAD. CIDR can be substituted for the symblic name "service", "pods", "also", or "all".`)
+			`form CIDR=WORKLOAD, or CIDR:PORT=WORKLOAD to only route connections to the given port. CIDR can be `+
+			`substituted for the symblic name "service", "pods", "also", or "all". `+
+			`WORKLOAD can be substituted for the IP of a pod that has a traffic-agent.`)
 	nwFlags.StringSliceVar(&cr.AllowConflictingSubnets,
 		"allow-conflicting-subnets", nil, ``+
 			`Comma separated list of CIDR that will be allowed to conflict with local subnets`)
@@ -153,6 +166,11 @@ func (cr *CobraRequest) CommitFlags(cmd *cobra.Command) error {
 		return err
 	}
 
+	// An explicit --manager-tls=false must override a cluster.managerTLS config that is true.
+	if cmd.Flags().Changed("manager-tls") {
+		cr.ManagerTls = &cr.managerTLS
+	}
+
 	// A --vnat CIDR is the same as --proxy-via CIDR=local
 	for _, vnat := range cr.vnats {
 		cr.proxyVia = append(cr.proxyVia, vnat+"=local")
@@ -177,6 +195,9 @@ func (cr *Request) Commit(ctx context.Context) (context.Context, error) {
 	if err != nil {
 		return ctx, errcat.User.New(err)
 	}
+	if err = validateManagerAddress(&cr.ConnectRequest); err != nil {
+		return ctx, errcat.User.New(err)
+	}
 	if len(cr.KubeconfigData) > 0 {
 		kc, err := clientcmd.Load(cr.KubeconfigData)
 		if err != nil {
@@ -203,6 +224,7 @@ type prefixViaWL struct {
 	subnet   netip.Prefix
 	symbolic string
 	workload string
+	port     uint16
 }
 
 func parseProxyVias(proxyVia []string) ([]*daemon.SubnetViaWorkload, error) {
@@ -260,26 +282,62 @@ func parseProxyVias(proxyVia []string) ([]*daemon.SubnetViaWorkload, error) {
 		svs[i] = &daemon.SubnetViaWorkload{
 			Subnet:   n,
 			Workload: pv.workload,
+			Port:     uint32(pv.port),
 		}
 	}
 	return svs, nil
 }
 
+func validateManagerAddress(cr *connector.ConnectRequest) error {
+	if cr.ManagerAddress == "" {
+		if cr.ManagerTls != nil {
+			return errors.New("--manager-tls can only be used together with --manager-address")
+		}
+		return nil
+	}
+	if cr.ManagerNamespace != "" {
+		return errors.New("--manager-address and --manager-namespace are mutually exclusive")
+	}
+	if _, _, err := net.SplitHostPort(cr.ManagerAddress); err != nil {
+		return fmt.Errorf("--manager-address %q is not in the format HOST:PORT: %w", cr.ManagerAddress, err)
+	}
+	return nil
+}
+
 func parseSubnetViaWorkload(dps string) (prefixViaWL, error) {
 	var pv prefixViaWL
 	eqIdx := strings.IndexByte(dps, '=')
 	if eqIdx <= 0 {
-		return pv, fmt.Errorf("--proxy-via %q is not in the format CIDR=WORKLOAD", dps)
+		return pv, fmt.Errorf("--proxy-via %q is not in the format CIDR[:PORT]=WORKLOAD or CIDR[:PORT]=POD_IP", dps)
 	}
 	lhs := dps[:eqIdx]
 	rhs := dps[eqIdx+1:]
-	if errs := validation.IsDNS1123Label(rhs); len(errs) > 0 {
+
+	// The port, if any, follows the mask of the CIDR, so the colons of an IPv6 address are never mistaken for it.
+	if cIdx := strings.LastIndexByte(lhs, ':'); cIdx > strings.IndexByte(lhs, '/') {
+		port, err := strconv.ParseUint(lhs[cIdx+1:], 10, 16)
+		if err != nil || port == 0 {
+			return pv, fmt.Errorf("--proxy-via %q has an invalid port %q", dps, lhs[cIdx+1:])
+		}
+		if rhs == "local" {
+			return pv, fmt.Errorf("--proxy-via %q: a port can only be used when routing via a workload or a pod IP", dps)
+		}
+		pv.port = uint16(port)
+		lhs = lhs[:cIdx]
+	}
+	if ip, err := netip.ParseAddr(rhs); err == nil {
+		// A raw pod IP is used directly as the proxy endpoint, without workload lookup.
+		rhs = ip.String()
+	} else if errs := validation.IsDNS1123Label(rhs); len(errs) > 0 {
 		return pv, errors.New(errs[0])
 	}
 	if sn, err := netip.ParsePrefix(lhs); err != nil {
 		if !(lhs == "all" || lhs == "also" || lhs == "pods" || lhs == "service") {
 			return pv, err
 		}
+		if pv.port != 0 {
+			return pv, fmt.Errorf("--proxy-via %q: a port cannot be used with the symbolic name %q", dps, lhs)
+		}
 		pv.symbolic = lhs
 	} else {
 		pv.subnet = sn
@@ -5,6 +5,9 @@ import (
 	"reflect"
 	"testing"
 
+	"google.golang.org/protobuf/proto"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/rpc/v2/daemon"
 )
 
@@ -42,6 +45,24 @@ func Test_parseSubnetViaWorkload(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"pod ip",
+			"10.0.0.0/24=10.1.2.3",
+			prefixViaWL{
+				subnet:   netip.MustParsePrefix("10.0.0.0/24"),
+				workload: "10.1.2.3",
+			},
+			false,
+		},
+		{
+			"pod ipv6",
+			"fd00::/64=fd01:0:0::7",
+			prefixViaWL{
+				subnet:   netip.MustParsePrefix("fd00::/64"),
+				workload: "fd01::7",
+			},
+			false,
+		},
 		{
 			"all",
 			"all=workload",
@@ -84,6 +105,50 @@ func Test_parseSubnetViaWorkload(t *testing.T) {
 			prefixViaWL{},
 			true,
 		},
+		{
+			"port",
+			"127.1.2.3/32:8080=workload",
+			prefixViaWL{
+				subnet:   netip.MustParsePrefix("127.1.2.3/32"),
+				port:     8080,
+				workload: "workload",
+			},
+			false,
+		},
+		{
+			"port ipv6",
+			"fd00::/64:80=workload",
+			prefixViaWL{
+				subnet:   netip.MustParsePrefix("fd00::/64"),
+				port:     80,
+				workload: "workload",
+			},
+			false,
+		},
+		{
+			"port zero",
+			"127.1.2.3/32:0=workload",
+			prefixViaWL{},
+			true,
+		},
+		{
+			"invalid port",
+			"127.1.2.3/32:http=workload",
+			prefixViaWL{},
+			true,
+		},
+		{
+			"symbolic with port",
+			"pods:80=workload",
+			prefixViaWL{},
+			true,
+		},
+		{
+			"local with port",
+			"127.1.2.3/32:80=local",
+			prefixViaWL{},
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -130,6 +195,16 @@ func Test_parseProxyVias(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:     "port",
+			proxyVia: []string{"127.1.2.3/32:8080=workload"},
+			want: []*daemon.SubnetViaWorkload{{
+				Subnet:   "127.1.2.3/32",
+				Workload: "workload",
+				Port:     8080,
+			}},
+			wantErr: false,
+		},
 		{
 			name:     "multi-overlap",
 			proxyVia: []string{"127.1.2.0/16=workload1", "127.1.3.0/16=workload2"},
@@ -200,3 +275,47 @@ func Test_parseProxyVias(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateManagerAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		cr      *connector.ConnectRequest
+		wantErr bool
+	}{
+		{
+			name: "none",
+			cr:   &connector.ConnectRequest{ManagerNamespace: "ambassador"},
+		},
+		{
+			name: "address",
+			cr:   &connector.ConnectRequest{ManagerAddress: "tm.example.com:443", ManagerTls: proto.Bool(true)},
+		},
+		{
+			name:    "address and namespace",
+			cr:      &connector.ConnectRequest{ManagerAddress: "tm.example.com:443", ManagerNamespace: "ambassador"},
+			wantErr: true,
+		},
+		{
+			name:    "no port",
+			cr:      &connector.ConnectRequest{ManagerAddress: "tm.example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "tls without address",
+			cr:      &connector.ConnectRequest{ManagerTls: proto.Bool(true)},
+			wantErr: true,
+		},
+		{
+			name:    "no tls without address",
+			cr:      &connector.ConnectRequest{ManagerTls: proto.Bool(false)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateManagerAddress(tt.cr); (err != nil) != tt.wantErr {
+				t.Errorf("validateManagerAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+)
+
+// buildCacheRepo is the repository of the tags that are given to built images, so that an image that was built
+// from an unchanged context can be found and reused.
+const buildCacheRepo = "telepresence-docker-build"
+
+// buildHashLabel is the label of a built image that holds the hash of the context and build options that the
+// image was built from.
+const buildHashLabel = "telepresence.io/docker-build-hash"
+
+// imageAPI is the subset of docker functions that is used when building images.
+type imageAPI interface {
+	BuildImage(ctx context.Context, context string, opts []string) (string, error)
+	ImageLabels(ctx context.Context, image string) (string, map[string]string, error)
+	RemoveImage(ctx context.Context, id string) error
+}
+
+// dockerImageAPI is the imageAPI that is used unless another one is given in the Flags.
+type dockerImageAPI struct{}
+
+func (dockerImageAPI) BuildImage(ctx context.Context, context string, opts []string) (string, error) {
+	return docker.BuildImage(ctx, context, opts)
+}
+
+func (dockerImageAPI) ImageLabels(ctx context.Context, image string) (string, map[string]string, error) {
+	return docker.ImageLabels(ctx, image)
+}
+
+func (dockerImageAPI) RemoveImage(ctx context.Context, id string) error {
+	return docker.RemoveImage(ctx, id)
+}
+
+// buildCacheTag returns the tag to use for an image built from the given context. The tag is derived from the
+// absolute path of the context, so a rebuild of a changed context moves the tag instead of adding a new one.
+func buildCacheTag(buildContext string) (string, error) {
+	abs, err := filepath.Abs(buildContext)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256([]byte(abs))
+	return buildCacheRepo + ":" + hex.EncodeToString(h[:])[:24], nil
+}
+
+// buildCacheInputs returns the given build options extended with what they refer to, because a change in those
+// must also invalidate a built image. Those are the values of --docker-build-arg KEY flags, which docker build takes
+// from the environment, and hashes of the content of the --docker-build-secret secrets. An error is returned when a
+// secret can't be read.
+func (f *Flags) buildCacheInputs(ctx context.Context, opts []string) ([]string, error) {
+	inputs := slices.Clone(opts)
+	for _, arg := range f.BuildArgs {
+		if !strings.Contains(arg, "=") {
+			v, _ := dos.LookupEnv(ctx, arg)
+			inputs = append(inputs, "arg "+arg+"="+v)
+		}
+	}
+	for _, secret := range f.BuildSecrets {
+		h, err := buildSecretHash(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, "secret "+secret+" "+h)
+	}
+	return inputs, nil
+}
+
+// buildSecretHash returns a hash of the content of the given --docker-build-secret. Just like with docker build,
+// the content is read from the file given by src, or from the environment variable given by env. When neither
+// is given, the environment variable named by the id is used if it's set, and a file named by the id otherwise.
+func buildSecretHash(ctx context.Context, secret string) (string, error) {
+	var id, src, envName string
+	for _, field := range strings.Split(secret, ",") {
+		k, v, _ := strings.Cut(field, "=")
+		switch k {
+		case "id":
+			id = v
+		case "src", "source":
+			src = v
+		case "env":
+			envName = v
+		}
+	}
+	var content []byte
+	switch {
+	case src != "":
+	case envName != "":
+		v, _ := dos.LookupEnv(ctx, envName)
+		content = []byte(v)
+	default:
+		if v, ok := dos.LookupEnv(ctx, id); ok {
+			content = []byte(v)
+		} else {
+			src = id
+		}
+	}
+	if src != "" {
+		var err error
+		if content, err = os.ReadFile(src); err != nil {
+			return "", fmt.Errorf("unable to read --docker-build-secret %s: %w", id, err)
+		}
+	}
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// buildCacheHash returns a hash of the given build inputs and of the content of all files in the given context
+// that aren't excluded by its .dockerignore file. An error is returned if the context isn't a local directory or
+// file.
+func buildCacheHash(buildContext string, inputs []string) (string, error) {
+	st, err := os.Stat(buildContext)
+	if err != nil {
+		return "", err
+	}
+	dir := buildContext
+	if st.Mode().IsRegular() {
+		dir = filepath.Dir(buildContext)
+	}
+	h := sha256.New()
+	for _, in := range inputs {
+		_, _ = fmt.Fprintf(h, "opt %s\n", in)
+	}
+	_, _ = fmt.Fprintf(h, "context %s\n", filepath.Base(buildContext))
+	ignore, err := readDockerIgnore(dir)
+	if err != nil {
+		return "", err
+	}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignore.excludes(rel) {
+			if d.IsDir() && !ignore.hasExceptions() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			_, _ = fmt.Fprintf(h, "entry %s %s\n", rel, d.Type())
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(h, "file %s %s %d\n", rel, info.Mode(), info.Size())
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(h, f)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type ignorePattern struct {
+	rx        *regexp.Regexp
+	exception bool
+}
+
+// dockerIgnore is the set of patterns of a .dockerignore file.
+type dockerIgnore []ignorePattern
+
+// readDockerIgnore reads the .dockerignore file in the given directory. A missing file yields an empty result.
+func readDockerIgnore(dir string) (dockerIgnore, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".dockerignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return nil, err
+	}
+	return parseDockerIgnore(data)
+}
+
+// parseDockerIgnore parses the content of a .dockerignore file.
+func parseDockerIgnore(data []byte) (dockerIgnore, error) {
+	var di dockerIgnore
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p := ignorePattern{}
+		if line[0] == '!' {
+			p.exception = true
+			line = strings.TrimSpace(line[1:])
+		}
+		line = strings.Trim(filepath.ToSlash(filepath.Clean(line)), "/")
+		if line == "" || line == "." {
+			continue
+		}
+		rx, err := regexp.Compile(ignoreRegexp(line))
+		if err != nil {
+			return nil, fmt.Errorf("invalid .dockerignore pattern %q: %w", line, err)
+		}
+		p.rx = rx
+		di = append(di, p)
+	}
+	return di, sc.Err()
+}
+
+// ignoreRegexp converts a .dockerignore pattern to a regular expression. A "**" matches any number of
+// directories, a "*" matches any sequence of non-separator characters, and a "?" matches one such character.
+// The expression also matches everything below a matching directory.
+func ignoreRegexp(pattern string) string {
+	var sb strings.Builder
+	sb.WriteByte('^')
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					// "**/" matches zero or more directories
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			// Character classes are passed on verbatim, except that a leading '!' negates the class.
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				break
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("(?:/.*)?$")
+	return sb.String()
+}
+
+// excludes returns true if the given slash separated path, relative to the context, is excluded. The last
+// matching pattern decides.
+func (di dockerIgnore) excludes(path string) bool {
+	excluded := false
+	for _, p := range di {
+		if p.rx.MatchString(path) {
+			excluded = !p.exception
+		}
+	}
+	return excluded
+}
+
+// hasExceptions returns true if the patterns contain exceptions, in which case excluded directories must
+// still be examined.
+func (di dockerIgnore) hasExceptions() bool {
+	for _, p := range di {
+		if p.exception {
+			return true
+		}
+	}
+	return false
+}
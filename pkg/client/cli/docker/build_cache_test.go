@@ -0,0 +1,233 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+)
+
+func Test_dockerIgnore(t *testing.T) {
+	di, err := parseDockerIgnore([]byte(`
+# comment
+*.log
+/build
+**/node_modules
+docs/*.md
+!docs/README.md
+temp?
+`))
+	require.NoError(t, err)
+	tests := map[string]bool{
+		"app.log":                     true,
+		"sub/app.log":                 false,
+		"build":                       true,
+		"build/out/bin":               true,
+		"src/build":                   false,
+		"node_modules/x/index.js":     true,
+		"src/web/node_modules/x.js":   true,
+		"docs/guide.md":               true,
+		"docs/README.md":              false,
+		"docs/sub/guide.md":           false,
+		"temp1":                       true,
+		"temp12":                      false,
+		"main.go":                     false,
+		"Dockerfile":                  false,
+		"src/node_modules_other/x.js": false,
+	}
+	for path, excluded := range tests {
+		assert.Equal(t, excluded, di.excludes(path), path)
+	}
+}
+
+func writeContext(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		fn := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(fn), 0o755))
+		require.NoError(t, os.WriteFile(fn, []byte(content), 0o644))
+	}
+}
+
+func Test_buildCacheHash(t *testing.T) {
+	dir := t.TempDir()
+	writeContext(t, dir, map[string]string{
+		"Dockerfile":    "FROM alpine\nCOPY . /app\n",
+		"main.go":       "package main\n",
+		".dockerignore": "*.log\n",
+	})
+	hash, err := buildCacheHash(dir, nil)
+	require.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{64}$`, hash)
+
+	again, err := buildCacheHash(dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, hash, again)
+
+	// Files that are excluded by .dockerignore don't affect the hash.
+	writeContext(t, dir, map[string]string{"app.log": "some output"})
+	again, err = buildCacheHash(dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, hash, again)
+
+	// Build options do.
+	withOpts, err := buildCacheHash(dir, []string{"--build-arg", "VERSION=1"})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, withOpts)
+
+	// And so do changed files.
+	writeContext(t, dir, map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+	changed, err := buildCacheHash(dir, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+
+	_, err = buildCacheHash("https://github.com/example/repo.git", nil)
+	assert.Error(t, err)
+}
+
+func Test_buildCacheTag(t *testing.T) {
+	dir := t.TempDir()
+	tag, err := buildCacheTag(dir)
+	require.NoError(t, err)
+	assert.Regexp(t, `^telepresence-docker-build:[0-9a-f]{24}$`, tag)
+
+	// The tag only depends on the path of the context.
+	writeContext(t, dir, map[string]string{"main.go": "package main\n"})
+	again, err := buildCacheTag(dir)
+	require.NoError(t, err)
+	assert.Equal(t, tag, again)
+
+	other, err := buildCacheTag(t.TempDir())
+	require.NoError(t, err)
+	assert.NotEqual(t, tag, other)
+}
+
+type fakeImage struct {
+	id     string
+	labels map[string]string
+}
+
+// fakeImageAPI is an imageAPI that keeps its images in memory.
+type fakeImageAPI struct {
+	images  map[string]fakeImage
+	builds  int
+	removed []string
+}
+
+func (f *fakeImageAPI) BuildImage(_ context.Context, _ string, opts []string) (string, error) {
+	f.builds++
+	img := fakeImage{id: fmt.Sprintf("sha256:built-%d", f.builds), labels: make(map[string]string)}
+	var tag string
+	for i := 0; i+1 < len(opts); i++ {
+		switch opts[i] {
+		case "--tag":
+			tag = opts[i+1]
+		case "--label":
+			k, v, _ := strings.Cut(opts[i+1], "=")
+			img.labels[k] = v
+		}
+	}
+	if tag != "" {
+		f.images[tag] = img
+	}
+	return img.id, nil
+}
+
+func (f *fakeImageAPI) ImageLabels(_ context.Context, image string) (string, map[string]string, error) {
+	img := f.images[image]
+	return img.id, img.labels, nil
+}
+
+func (f *fakeImageAPI) RemoveImage(_ context.Context, id string) error {
+	f.removed = append(f.removed, id)
+	return nil
+}
+
+func TestFlags_PullOrBuildImageCache(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	dir := t.TempDir()
+	writeContext(t, dir, map[string]string{
+		"Dockerfile": "FROM alpine\nCOPY . /app\n",
+		"main.go":    "package main\n",
+	})
+
+	api := &fakeImageAPI{images: make(map[string]fakeImage)}
+	build := func(noCacheCheck bool) string {
+		t.Helper()
+		f := Flags{build: dir, NoCacheCheck: noCacheCheck, images: api}
+		require.NoError(t, f.Validate(ctx, []string{"IMAGE"}))
+		require.NoError(t, f.PullOrBuildImage(ctx))
+		return f.args[f.imageIndex]
+	}
+
+	assert.Equal(t, "sha256:built-1", build(false))
+	assert.Equal(t, 1, api.builds)
+
+	// A second build with an unchanged context doesn't invoke docker build.
+	image := build(false)
+	assert.Equal(t, 1, api.builds)
+	assert.Regexp(t, `^telepresence-docker-build:`, image)
+
+	// Unless the cache check is disabled.
+	build(true)
+	assert.Equal(t, 2, api.builds)
+
+	// A changed context is rebuilt.
+	writeContext(t, dir, map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+	build(false)
+	assert.Equal(t, 3, api.builds)
+	build(false)
+	assert.Equal(t, 3, api.builds)
+
+	// All builds use the same tag, and each rebuild removes the image that the tag previously referenced.
+	assert.Len(t, api.images, 1)
+	assert.Equal(t, []string{"sha256:built-1", "sha256:built-2"}, api.removed)
+}
+
+func TestFlags_PullOrBuildImageCacheInputs(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	dir := t.TempDir()
+	writeContext(t, dir, map[string]string{"Dockerfile": "FROM alpine\n"})
+	secretFile := filepath.Join(t.TempDir(), "npmrc")
+	require.NoError(t, os.WriteFile(secretFile, []byte("token=1"), 0o600))
+
+	api := &fakeImageAPI{images: make(map[string]fakeImage)}
+	env := dos.MapEnv{"VERSION": "1.2", "TOKEN": "a"}
+	build := func(secrets ...string) {
+		t.Helper()
+		f := Flags{build: dir, BuildArgs: []string{"VERSION"}, BuildSecrets: secrets, images: api}
+		require.NoError(t, f.Validate(ctx, []string{"IMAGE"}))
+		require.NoError(t, f.PullOrBuildImage(dos.WithEnv(ctx, env)))
+	}
+
+	build("id=npmrc,src="+secretFile, "id=token,env=TOKEN")
+	build("id=npmrc,src="+secretFile, "id=token,env=TOKEN")
+	assert.Equal(t, 1, api.builds)
+
+	// A build arg that is taken from the environment is rebuilt when the environment changes.
+	env["VERSION"] = "1.3"
+	build("id=npmrc,src="+secretFile, "id=token,env=TOKEN")
+	assert.Equal(t, 2, api.builds)
+
+	// And so is a changed secret, regardless of whether it's a file or an environment variable.
+	require.NoError(t, os.WriteFile(secretFile, []byte("token=2"), 0o600))
+	build("id=npmrc,src="+secretFile, "id=token,env=TOKEN")
+	assert.Equal(t, 3, api.builds)
+	env["TOKEN"] = "b"
+	build("id=npmrc,src="+secretFile, "id=token,env=TOKEN")
+	assert.Equal(t, 4, api.builds)
+
+	// A secret that can't be read disables the cache.
+	missing := "id=other,src=" + filepath.Join(dir, "missing")
+	build(missing)
+	build(missing)
+	assert.Equal(t, 6, api.builds)
+}
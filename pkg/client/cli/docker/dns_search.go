@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
+)
+
+// containerSearchPath returns the search domains of the resolv.conf of the given container.
+func containerSearchPath(ctx context.Context, containerName string) ([]string, error) {
+	cli, err := docker.GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rc, _, err := cli.CopyFromContainer(ctx, containerName, "/etc/resolv.conf")
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	tr := tar.NewReader(rc)
+	if _, err = tr.Next(); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, err
+	}
+	return resolvConfSearch(data), nil
+}
+
+// resolvConfSearch returns the search domains of the given resolv.conf content. Just like with the resolver, the
+// last "search" or "domain" line wins.
+func resolvConfSearch(data []byte) []string {
+	var search []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "search", "domain":
+			search = fields[1:]
+		}
+	}
+	return search
+}
+
+// localDomain returns the value of the LOCALDOMAIN environment variable that makes a resolver use the given search
+// path followed by the extra domains. The search path must be retained, because the variable replaces it.
+func localDomain(searchPath, extra []string) string {
+	search := slices.Clone(searchPath)
+	for _, d := range extra {
+		if !slices.Contains(search, d) {
+			search = append(search, d)
+		}
+	}
+	return strings.Join(search, " ")
+}
@@ -0,0 +1,41 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resolvConfSearch(t *testing.T) {
+	tests := []struct {
+		name string
+		conf string
+		want []string
+	}{
+		{
+			name: "search",
+			conf: "nameserver 127.0.0.11\nsearch tel2-search example.com\noptions ndots:0\n",
+			want: []string{"tel2-search", "example.com"},
+		},
+		{
+			name: "last one wins",
+			conf: "domain example.com\nsearch tel2-search\n",
+			want: []string{"tel2-search"},
+		},
+		{
+			name: "none",
+			conf: "# comment\nnameserver 127.0.0.11\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolvConfSearch([]byte(tt.conf)))
+		})
+	}
+}
+
+func Test_localDomain(t *testing.T) {
+	// LOCALDOMAIN replaces the search path, so the current search path must be retained.
+	assert.Equal(t, "tel2-search example.com other-ns", localDomain([]string{"tel2-search", "example.com"}, []string{"other-ns", "example.com"}))
+	assert.Equal(t, "other-ns", localDomain(nil, []string{"other-ns"}))
+}
@@ -3,10 +3,19 @@ package docker
 import (
 	"context"
 	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/pflag"
 
+	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/flags"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/spinner"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
@@ -17,14 +26,25 @@ type Flags struct {
 	Run            bool           // --docker-run
 	Debug          bool           // set if --docker-debug was used
 	BuildOptions   []string       // --docker-build-opt key=value, // Optional flag to docker build can be repeated (but not comma separated)
+	BuildArgs      []string       // --docker-build-arg KEY=VALUE
+	BuildSecrets   []string       // --docker-build-secret id=ID,src=PATH
 	PublishedPorts PublishedPorts // --publish Port mappings that the container will expose on localhost
 	Context        string         // Set to build or debug by Validate function
 	Image          string
-	Mount          string // --docker-mount // where to mount in a docker container. Defaults to mount unless mount is "true" or "false".
-	build          string // --docker-build DIR | URL
-	debug          string // --docker-debug DIR | URL
+	Mount          string        // --docker-mount // where to mount in a docker container. Defaults to mount unless mount is "true" or "false".
+	NoTel2Search   bool          // --docker-no-tel2-search
+	NetworkAliases []string      // --docker-network-alias
+	RunTimeout     time.Duration // --docker-run-timeout
+	Volumes        []string      // --docker-volume src:dst[:ro]
+	Platform       string        // --docker-platform os/arch[/variant]
+	NoCacheCheck   bool          // --docker-no-cache-check
+	RunLogFile     string        // --docker-run-log-file
+	images         imageAPI      // docker functions used when building images, replaced in tests
+	build          string        // --docker-build DIR | URL
+	debug          string        // --docker-debug DIR | URL
 	args           []string
 	imageIndex     int
+	reuse          bool // set when --name is given together with --rm=false
 }
 
 func (f *Flags) AddFlags(flagSet *pflag.FlagSet, what string) {
@@ -42,15 +62,46 @@ func (f *Flags) AddFlags(flagSet *pflag.FlagSet, what string) {
 	flagSet.StringArrayVar(&f.BuildOptions, "docker-build-opt", nil,
 		`Options to docker-build in the form key=value, e.g. --docker-build-opt tag=mytag.`)
 
+	flagSet.StringArrayVar(&f.BuildArgs, "docker-build-arg", nil,
+		`Build-time variable for docker-build in the form KEY=VALUE, e.g. --docker-build-arg VERSION=1.2. Can be repeated`)
+
+	flagSet.StringArrayVar(&f.BuildSecrets, "docker-build-secret", nil,
+		`Secret to expose to docker-build, e.g. --docker-build-secret id=npmrc,src=$HOME/.npmrc. Can be repeated`)
+
 	flagSet.StringVar(&f.Mount, "docker-mount", "", ``+
 		`The volume mount point in docker. Defaults to same as "--mount"`)
 
 	flagSet.Var(&f.PublishedPorts,
 		"publish", ``+
 			`Ports that the container will publish. See docker run --publish for more info.`)
+
+	flagSet.BoolVar(&f.NoTel2Search, "docker-no-tel2-search", false, ``+
+		`Don't add the "tel2-search" search domain to the container when the daemon runs on the host. `+
+		`Single label names of services in intercepted namespaces will then not resolve`)
+
+	flagSet.StringSliceVar(&f.NetworkAliases, "docker-network-alias", nil, ``+
+		`Add a network-scoped alias for the container in the networks given with --network to docker run. Can be repeated`)
+
+	flagSet.DurationVar(&f.RunTimeout, "docker-run-timeout", 0, ``+
+		`Stop the container and fail if it isn't ready within this duration, e.g. '--docker-run-timeout 2m'. `+
+		`A container is ready when it is healthy, or running if its image has no health check. Zero means no timeout`)
+
+	flagSet.StringArrayVar(&f.Volumes, "docker-volume", nil, ``+
+		`Mount a local directory or file into the container in addition to the remote mounts, e.g. '--docker-volume ./src:/app/src:ro'. `+
+		`Can be repeated`)
+
+	flagSet.StringVar(&f.Platform, "docker-platform", "", ``+
+		`The platform, in the form os/arch[/variant], of the image to build and run, e.g. '--docker-platform linux/amd64'`)
+
+	flagSet.BoolVar(&f.NoCacheCheck, "docker-no-cache-check", false, ``+
+		`Always build the image, even when an image built from an unchanged docker-context exists locally`)
+
+	flagSet.StringVar(&f.RunLogFile, "docker-run-log-file", "", ``+
+		`Write the stdout and stderr of the container to the given file, in addition to streaming them. `+
+		`The file is rotated when it grows beyond 10 MiB`)
 }
 
-func (f *Flags) Validate(args []string) error {
+func (f *Flags) Validate(ctx context.Context, args []string) error {
 	drCount := 0
 	if f.Run {
 		drCount++
@@ -69,6 +120,25 @@ func (f *Flags) Validate(args []string) error {
 		return errcat.User.Newf("only one of %s can be used", alts)
 	}
 	f.Run = drCount == 1
+	if f.Context == "" {
+		if len(f.BuildArgs) > 0 {
+			return errcat.User.New("--docker-build-arg must be used together with --docker-build or --docker-debug")
+		}
+		if len(f.BuildSecrets) > 0 {
+			return errcat.User.New("--docker-build-secret must be used together with --docker-build or --docker-debug")
+		}
+		if f.NoCacheCheck {
+			return errcat.User.New("--docker-no-cache-check must be used together with --docker-build or --docker-debug")
+		}
+	}
+	for _, secret := range f.BuildSecrets {
+		if !slices.ContainsFunc(strings.Split(secret, ","), func(s string) bool { return strings.HasPrefix(s, "id=") }) {
+			return errcat.User.Newf("invalid --docker-build-secret %q, must contain an id, e.g. id=npmrc,src=$HOME/.npmrc", secret)
+		}
+	}
+	if f.RunTimeout < 0 {
+		return errcat.User.New("--docker-run-timeout cannot be negative")
+	}
 	if !f.Run {
 		if f.Mount != "" {
 			return errcat.User.Newf("--docker-mount must be used together with %s", alts)
@@ -76,13 +146,46 @@ func (f *Flags) Validate(args []string) error {
 		if len(f.PublishedPorts) > 0 {
 			return errcat.User.Newf("--publish must be used together with %s", alts)
 		}
+		if f.NoTel2Search {
+			return errcat.User.Newf("--docker-no-tel2-search must be used together with %s", alts)
+		}
+		if len(f.NetworkAliases) > 0 {
+			return errcat.User.Newf("--docker-network-alias must be used together with %s", alts)
+		}
+		if f.RunTimeout > 0 {
+			return errcat.User.Newf("--docker-run-timeout must be used together with %s", alts)
+		}
+		if len(f.Volumes) > 0 {
+			return errcat.User.Newf("--docker-volume must be used together with %s", alts)
+		}
+		if f.Platform != "" {
+			return errcat.User.Newf("--docker-platform must be used together with %s", alts)
+		}
+		if f.RunLogFile != "" {
+			return errcat.User.Newf("--docker-run-log-file must be used together with %s", alts)
+		}
 		return nil
 	}
+	if f.RunLogFile != "" {
+		var err error
+		if f.RunLogFile, err = filepath.Abs(f.RunLogFile); err != nil {
+			return errcat.User.Newf("invalid --docker-run-log-file: %v", err)
+		}
+	}
+	if f.Platform != "" && !platformRx.MatchString(f.Platform) {
+		return errcat.User.Newf("invalid --docker-platform %q, must be in the form os/arch[/variant], e.g. linux/amd64", f.Platform)
+	}
+	for i, v := range f.Volumes {
+		var err error
+		if f.Volumes[i], err = parseVolume(v); err != nil {
+			return err
+		}
+	}
 
 	if flags.HasOption("detach", 'd', args) {
 		return errcat.User.New("running docker container in background using -d or --detach is not supported")
 	}
-	f.Image, f.imageIndex = firstArg(args)
+	f.Image, f.imageIndex = firstArg(args, getRunOptions(ctx))
 	f.args = args
 
 	// Ensure that the image is ready to run before we create the intercept.
@@ -103,22 +206,92 @@ func (f *Flags) Validate(args []string) error {
 	return nil
 }
 
+// platformRx matches a platform in the form os/arch[/variant].
+var platformRx = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(?:/[a-z0-9]+)?$`) //nolint:gochecknoglobals // constant
+
+// parseVolume parses a --docker-volume in the form src:dst[:ro|:rw] and verifies that the src exists. The
+// returned volume has an absolute src, so that docker treats it as a bind mount and not as a named volume.
+func parseVolume(volume string) (string, error) {
+	spec := volume
+	mode := ""
+	for _, m := range []string{":ro", ":rw"} {
+		if strings.HasSuffix(spec, m) {
+			mode = m
+			spec = spec[:len(spec)-len(m)]
+			break
+		}
+	}
+	i := strings.LastIndexByte(spec, ':')
+	if i <= 0 || i == len(spec)-1 {
+		return "", errcat.User.Newf("invalid --docker-volume %q, must be in the form src:dst[:ro]", volume)
+	}
+	src, dst := spec[:i], spec[i+1:]
+	if !strings.HasPrefix(dst, "/") {
+		return "", errcat.User.Newf("invalid --docker-volume %q, the destination must be an absolute path", volume)
+	}
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return "", errcat.User.Newf("invalid --docker-volume %q: %v", volume, err)
+	}
+	if _, err = os.Stat(src); err != nil {
+		return "", errcat.User.Newf("invalid --docker-volume %q: %v", volume, err)
+	}
+	return src + ":" + dst + mode, nil
+}
+
 // PullOrBuildImage will pull or build the image and return the args list suitable
-// when starting it.
+// when starting it. A built image is tagged with a tag derived from the path of its docker-context and
+// labeled with a hash of its content and build options. The tagged image is reused instead of rebuilt
+// when the hash is unchanged, unless --docker-no-cache-check is used.
 func (f *Flags) PullOrBuildImage(ctx context.Context) error {
 	if f.Image != "" {
-		return docker.PullImage(ctx, f.Image)
+		return docker.PullImageForPlatform(ctx, f.Image, f.Platform)
 	}
-	opts := make([]string, len(f.BuildOptions))
-	for i, opt := range f.BuildOptions {
-		opts[i] = "--" + opt
+	images := f.images
+	if images == nil {
+		images = dockerImageAPI{}
+	}
+	opts := f.buildOptions()
+	var hash, tag string
+	inputs, err := f.buildCacheInputs(ctx, opts)
+	if err == nil {
+		hash, err = buildCacheHash(f.Context, inputs)
+	}
+	if err == nil {
+		tag, err = buildCacheTag(f.Context)
 	}
-	spin := spinner.New(ctx, "building docker image")
-	imageID, err := docker.BuildImage(ctx, f.Context, opts)
 	if err != nil {
-		return spin.Error(err)
+		// Not a local context, not readable, or a secret that can't be read. Docker build will tell if it's a problem.
+		dlog.Debugf(ctx, "unable to compute a hash of docker-context %s: %v", f.Context, err)
+		tag = ""
+	}
+	var imageID, prevID string
+	if tag != "" {
+		var labels map[string]string
+		if prevID, labels, err = images.ImageLabels(ctx, tag); err != nil {
+			dlog.Debugf(ctx, "unable to inspect image %s: %v", tag, err)
+		}
+		if prevID != "" && labels[buildHashLabel] == hash && !f.NoCacheCheck {
+			dlog.Infof(ctx, "docker-context is unchanged, reusing image %s", tag)
+			imageID = tag
+		}
+	}
+	if imageID == "" {
+		if tag != "" {
+			opts = append(opts, "--tag", tag, "--label", buildHashLabel+"="+hash)
+		}
+		spin := spinner.New(ctx, "building docker image")
+		if imageID, err = images.BuildImage(ctx, f.Context, opts); err != nil {
+			return spin.Error(err)
+		}
+		spin.DoneMsg("image built successfully")
+		if prevID != "" && prevID != imageID {
+			// The tag was moved to the new image, so the image previously built from this context is removed.
+			if err = images.RemoveImage(ctx, prevID); err != nil {
+				dlog.Debugf(ctx, "unable to remove image %s: %v", prevID, err)
+			}
+		}
 	}
-	spin.DoneMsg("image built successfully")
 	if f.imageIndex < 0 {
 		f.args = []string{imageID}
 		f.imageIndex = 0
@@ -128,12 +301,39 @@ func (f *Flags) PullOrBuildImage(ctx context.Context) error {
 	return nil
 }
 
+// buildOptions returns the options to pass to docker build.
+func (f *Flags) buildOptions() []string {
+	opts := make([]string, 0, len(f.BuildOptions)+2*(len(f.BuildArgs)+len(f.BuildSecrets))+2)
+	for _, opt := range f.BuildOptions {
+		opts = append(opts, "--"+opt)
+	}
+	for _, arg := range f.BuildArgs {
+		opts = append(opts, "--build-arg", arg)
+	}
+	for _, secret := range f.BuildSecrets {
+		opts = append(opts, "--secret", secret)
+	}
+	if f.Platform != "" {
+		opts = append(opts, "--platform", f.Platform)
+	}
+	return opts
+}
+
+// GetContainerNameAndArgs returns the name of the container and the arguments to use when running it. A
+// --name is added to the arguments unless it's already present. A container that is given a name by the
+// user, and also declared with --rm=false, is considered reusable, see ReuseContainer.
 func (f *Flags) GetContainerNameAndArgs(defaultContainerName string) (string, []string, error) {
 	name, found, err := flags.GetUnparsedValue("name", 0, false, f.args)
 	if err != nil {
 		return "", nil, err
 	}
-	if !found {
+	if found {
+		rm, set, err := flags.GetUnparsedBoolean(f.args, "rm")
+		if err != nil {
+			return "", nil, err
+		}
+		f.reuse = set && !rm
+	} else {
 		name = defaultContainerName
 		f.args = append([]string{"--name", name}, f.args...)
 		f.imageIndex += 2
@@ -141,25 +341,119 @@ func (f *Flags) GetContainerNameAndArgs(defaultContainerName string) (string, []
 	return name, f.args, nil
 }
 
-var boolFlags = map[string]bool{ //nolint:gochecknoglobals // this is a constant
-	"--detach":           true,
-	"--init":             true,
-	"--interactive":      true,
-	"--no-healthcheck":   true,
-	"--oom-kill-disable": true,
-	"--privileged":       true,
-	"--publish-all":      true,
-	"--quiet":            true,
-	"--read-only":        true,
-	"--rm":               true,
-	"--sig-proxy":        true,
-	"--tty":              true,
+// ReuseContainer returns true if an existing container with the given name should be started instead of
+// running a new one.
+func (f *Flags) ReuseContainer() bool {
+	return f.reuse
+}
+
+// runOptions describes the options of docker run that matter when searching for the image.
+type runOptions struct {
+	boolFlags   map[string]bool // long form of the boolean options, e.g. "--rm"
+	valueShorts string          // shorthands of the options that require a value, e.g. "ehp"
+}
+
+// fallbackRunOptions are used when the options can't be obtained from "docker run --help". If new boolean
+// flags or value shorthands arrive and are used, firstArg might return an incorrect image.
+var fallbackRunOptions = &runOptions{ //nolint:gochecknoglobals // this is a constant
+	boolFlags: map[string]bool{
+		"--detach":           true,
+		"--init":             true,
+		"--interactive":      true,
+		"--no-healthcheck":   true,
+		"--oom-kill-disable": true,
+		"--privileged":       true,
+		"--publish-all":      true,
+		"--quiet":            true,
+		"--read-only":        true,
+		"--rm":               true,
+		"--sig-proxy":        true,
+		"--tty":              true,
+	},
+	valueShorts: "ehlmpuvw",
+}
+
+// runHelpTimeout is the maximum time to wait for "docker run --help".
+const runHelpTimeout = 5 * time.Second
+
+// runOptionsCache holds the runOptions that were successfully parsed from the output of "docker run --help".
+var runOptionsCache struct { //nolint:gochecknoglobals // cached value
+	sync.Mutex
+	ro *runOptions
+}
+
+// getRunOptions returns the options of docker run, parsed from the output of "docker run --help", or the
+// fallbackRunOptions when that output isn't available. A successful result is cached, so the command is
+// retried on subsequent calls if it fails or times out.
+func getRunOptions(ctx context.Context) *runOptions {
+	runOptionsCache.Lock()
+	defer runOptionsCache.Unlock()
+	if runOptionsCache.ro != nil {
+		return runOptionsCache.ro
+	}
+	ctx, cancel := context.WithTimeout(ctx, runHelpTimeout)
+	defer cancel()
+	if help, err := exec.CommandContext(ctx, Exe, "run", "--help").Output(); err == nil {
+		if ro := parseRunHelp(help); ro != nil {
+			runOptionsCache.ro = ro
+			return ro
+		}
+	}
+	return fallbackRunOptions
+}
+
+// helpOptionRx matches an option in the output of "docker run --help", e.g.
+//
+//	-a, --attach list     Attach to STDIN, STDOUT or STDERR
+//	    --rm              Automatically remove the container when it exits
+var helpOptionRx = regexp.MustCompile(`^\s*(?:-([[:alnum:]]), )?--([[:alnum:]][[:alnum:]-]*)(?: ([[:alnum:]]+))?(?:\s{2,}|$)`) //nolint:gochecknoglobals // constant
+
+// parseRunHelp parses the output of "docker run --help" into runOptions. It returns nil if no options
+// were found.
+func parseRunHelp(help []byte) *runOptions {
+	ro := runOptions{boolFlags: make(map[string]bool)}
+	var valueShorts strings.Builder
+	for _, line := range strings.Split(string(help), "\n") {
+		m := helpOptionRx.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if m[3] == "" {
+			ro.boolFlags["--"+m[2]] = true
+		} else if m[1] != "" {
+			valueShorts.WriteString(m[1])
+		}
+	}
+	if len(ro.boolFlags) == 0 {
+		return nil
+	}
+	ro.valueShorts = valueShorts.String()
+	return &ro
+}
+
+// SplitRunArgs splits the given docker run arguments into the options that precede the image, and the image
+// followed by the command and its arguments. The given extraBoolFlags, e.g. "--no-tel2-search", are boolean
+// options that docker run doesn't know about but that might precede the image. All arguments are considered
+// options when no image is found.
+func SplitRunArgs(ctx context.Context, args []string, extraBoolFlags ...string) ([]string, []string) {
+	ro := getRunOptions(ctx)
+	if len(extraBoolFlags) > 0 {
+		bfs := make(map[string]bool, len(ro.boolFlags)+len(extraBoolFlags))
+		maps.Copy(bfs, ro.boolFlags)
+		for _, bf := range extraBoolFlags {
+			bfs[bf] = true
+		}
+		ro = &runOptions{boolFlags: bfs, valueShorts: ro.valueShorts}
+	}
+	if _, i := firstArg(args, ro); i >= 0 {
+		return args[:i:i], args[i:]
+	}
+	return args, nil
 }
 
 // firstArg returns the first argument that isn't an option. This requires knowledge
-// about boolean docker flags, and if new such flags arrive and are used, this
-// function might return an incorrect image.
-func firstArg(args []string) (string, int) {
+// about the docker run options that take a value.
+func firstArg(args []string, ro *runOptions) (string, int) {
 	t := len(args)
 	for i := 0; i < t; i++ {
 		arg := args[i]
@@ -170,12 +464,20 @@ func firstArg(args []string) (string, int) {
 			continue
 		}
 		if strings.HasPrefix(arg, "--") {
-			if !boolFlags[arg] {
+			if !ro.boolFlags[arg] {
 				i++
 			}
-		} else if strings.ContainsAny(arg, "ehlmpuvw") {
-			// Shorthand flag that require an argument. Might be prefixed by shorthand booleans, e.g. -itl <label>
-			i++
+			continue
+		}
+		// Shorthand flags, possibly combined, e.g. -itl <label>. The value of a shorthand flag
+		// is either the rest of the arg, e.g. -p8080:80, or the next arg when the flag is last.
+		for ci := 1; ci < len(arg); ci++ {
+			if strings.IndexByte(ro.valueShorts, arg[ci]) >= 0 {
+				if ci == len(arg)-1 {
+					i++
+				}
+				break
+			}
 		}
 	}
 	return "", -1
@@ -21,42 +21,78 @@ func parsePort(s string) (uint16, error) {
 	return uint16(pn), nil
 }
 
-func parsePublishedPort(pp string) (PublishedPort, error) {
-	pc := PublishedPort{}
+// parsePortRange parses a port, or a range of ports in the form "<first>-<last>".
+func parsePortRange(s string) (first, last uint16, err error) {
+	if fs, ls, ok := strings.Cut(s, "-"); ok {
+		if first, err = parsePort(fs); err != nil {
+			return 0, 0, err
+		}
+		if last, err = parsePort(ls); err != nil {
+			return 0, 0, err
+		}
+		if last < first {
+			return 0, 0, fmt.Errorf("%q is not a valid port range", s)
+		}
+		return first, last, nil
+	}
+	first, err = parsePort(s)
+	return first, first, err
+}
+
+// parsePublishedPorts parses the value of a --publish flag. A value that contains port ranges, e.g.
+// "8000-8010:8000-8010", is expanded into one PublishedPort for each port in the range. The host and
+// container ranges must be of equal length.
+func parsePublishedPorts(pp string) ([]PublishedPort, error) {
+	protocol := "tcp"
 	mapping, proto, found := strings.Cut(pp, "/")
-	if !found {
-		pc.Protocol = "tcp"
-	} else {
-		pc.Protocol = strings.ToLower(proto)
-		if pc.Protocol != "tcp" && pc.Protocol != "udp" {
-			return PublishedPort{}, fmt.Errorf("%q is not a valid protocol", proto)
+	if found {
+		protocol = strings.ToLower(proto)
+		if protocol != "tcp" && protocol != "udp" {
+			return nil, fmt.Errorf("%q is not a valid protocol", proto)
 		}
 	}
 
-	var hostPort uint16
+	hostAddr := netip.IPv4Unspecified()
+	var hostFirst, hostLast uint16
+	containerRange := mapping
 	if lastColon := strings.LastIndexByte(mapping, ':'); lastColon >= 0 {
-		p := mapping[lastColon+1:]
+		containerRange = mapping[lastColon+1:]
+		hostRange := mapping[:lastColon]
+		if lastColon = strings.LastIndexByte(hostRange, ':'); lastColon >= 0 {
+			addr := strings.TrimSuffix(strings.TrimPrefix(hostRange[:lastColon], "["), "]")
+			var err error
+			if hostAddr, err = netip.ParseAddr(addr); err != nil {
+				return nil, err
+			}
+			hostRange = hostRange[lastColon+1:]
+		}
 		var err error
-		pc.ContainerPort, err = parsePort(p)
-		if err != nil {
-			return PublishedPort{}, err
+		if hostFirst, hostLast, err = parsePortRange(hostRange); err != nil {
+			return nil, err
 		}
+	}
+	containerFirst, containerLast, err := parsePortRange(containerRange)
+	if err != nil {
+		return nil, err
+	}
+	if hostFirst != 0 && hostLast-hostFirst != containerLast-containerFirst {
+		return nil, fmt.Errorf("host port range %d-%d and container port range %d-%d are not of equal length",
+			hostFirst, hostLast, containerFirst, containerLast)
+	}
 
-		mapping = mapping[:lastColon]
-		if strings.ContainsRune(mapping, ':') {
-			pc.HostAddrPort, err = netip.ParseAddrPort(mapping)
-			if err != nil {
-				return PublishedPort{}, err
-			}
-			return pc, nil
-		}
-		hostPort, err = parsePort(mapping)
-		if err != nil {
-			return PublishedPort{}, err
+	pcs := make([]PublishedPort, 0, int(containerLast-containerFirst)+1)
+	for i := 0; i <= int(containerLast-containerFirst); i++ {
+		var hostPort uint16
+		if hostFirst != 0 {
+			hostPort = hostFirst + uint16(i)
 		}
+		pcs = append(pcs, PublishedPort{
+			HostAddrPort:  netip.AddrPortFrom(hostAddr, hostPort),
+			Protocol:      protocol,
+			ContainerPort: containerFirst + uint16(i),
+		})
 	}
-	pc.HostAddrPort = netip.AddrPortFrom(netip.IPv4Unspecified(), hostPort)
-	return pc, nil
+	return pcs, nil
 }
 
 func writePort(sb *strings.Builder, port uint16) {
@@ -94,7 +130,6 @@ func (p *PublishedPorts) String() string {
 			sb.WriteByte(',')
 		}
 		config.writeTo(&sb)
-		sb.WriteString(config.String())
 	}
 	sb.WriteByte(']')
 	return sb.String()
@@ -109,21 +144,21 @@ func (p *PublishedPorts) Type() string {
 }
 
 func (p *PublishedPorts) Append(s string) error {
-	c, err := parsePublishedPort(s)
+	cs, err := parsePublishedPorts(s)
 	if err == nil {
-		*p = append(*p, c)
+		*p = append(*p, cs...)
 	}
 	return err
 }
 
 func (p *PublishedPorts) Replace(vals []string) error {
-	pcs := make([]PublishedPort, len(vals))
-	for i, val := range vals {
-		pc, err := parsePublishedPort(val)
+	var pcs []PublishedPort
+	for _, val := range vals {
+		cs, err := parsePublishedPorts(val)
 		if err != nil {
 			return err
 		}
-		pcs[i] = pc
+		pcs = append(pcs, cs...)
 	}
 	*p = pcs
 	return nil
@@ -0,0 +1,89 @@
+package docker
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parsePublishedPorts(t *testing.T) {
+	any4 := netip.IPv4Unspecified()
+	lo := netip.MustParseAddr("127.0.0.1")
+	tests := []struct {
+		name    string
+		value   string
+		want    []PublishedPort
+		wantErr string
+	}{
+		{
+			name:  "single port",
+			value: "8080:80",
+			want:  []PublishedPort{{HostAddrPort: netip.AddrPortFrom(any4, 8080), Protocol: "tcp", ContainerPort: 80}},
+		},
+		{
+			name:  "container port only",
+			value: "80/udp",
+			want:  []PublishedPort{{HostAddrPort: netip.AddrPortFrom(any4, 0), Protocol: "udp", ContainerPort: 80}},
+		},
+		{
+			name:  "IPv6 address",
+			value: "[::1]:8080:80",
+			want:  []PublishedPort{{HostAddrPort: netip.MustParseAddrPort("[::1]:8080"), Protocol: "tcp", ContainerPort: 80}},
+		},
+		{
+			name:  "range",
+			value: "127.0.0.1:8000-8002:9000-9002",
+			want: []PublishedPort{
+				{HostAddrPort: netip.AddrPortFrom(lo, 8000), Protocol: "tcp", ContainerPort: 9000},
+				{HostAddrPort: netip.AddrPortFrom(lo, 8001), Protocol: "tcp", ContainerPort: 9001},
+				{HostAddrPort: netip.AddrPortFrom(lo, 8002), Protocol: "tcp", ContainerPort: 9002},
+			},
+		},
+		{
+			name:  "container range only",
+			value: "9000-9001",
+			want: []PublishedPort{
+				{HostAddrPort: netip.AddrPortFrom(any4, 0), Protocol: "tcp", ContainerPort: 9000},
+				{HostAddrPort: netip.AddrPortFrom(any4, 0), Protocol: "tcp", ContainerPort: 9001},
+			},
+		},
+		{
+			name:    "range length mismatch",
+			value:   "8000-8010:8000-8005",
+			wantErr: "host port range 8000-8010 and container port range 8000-8005 are not of equal length",
+		},
+		{
+			name:    "reversed range",
+			value:   "8010-8000",
+			wantErr: `"8010-8000" is not a valid port range`,
+		},
+		{
+			name:    "bad protocol",
+			value:   "8080:80/sctp",
+			wantErr: `"sctp" is not a valid protocol`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePublishedPorts(tt.value)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRunFlags_ranges(t *testing.T) {
+	f, args, err := ParseRunFlags([]string{"--publish", "8000-8001:9000-9001", "--expose", "7000-7001/udp", "alpine"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpine"}, args)
+	assert.Equal(t, []string{"8000:9000", "8001:9001", "7000:7000/udp", "7001:7001/udp"}, f.PublishedPorts.GetSlice())
+
+	_, _, err = ParseRunFlags([]string{"--publish", "8000-8002:9000-9001", "alpine"})
+	assert.ErrorContains(t, err, "not of equal length")
+}
@@ -0,0 +1,36 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// CheckRemoteDaemon returns an error that explains the limitation when the docker daemon used by the current
+// docker context, or DOCKER_HOST, is remote and a container started there cannot work as intended. A container
+// that doesn't share the network of a containerized telepresence daemon relies on the network, DNS, and volumes
+// of this host, and a port published on a loopback address would end up on the loopback of the remote host.
+func CheckRemoteDaemon(ctx context.Context, sharesDaemonNetwork bool, pps []PublishedPort) error {
+	host, err := docker.RemoteHost(ctx)
+	if err != nil || host == "" {
+		return err
+	}
+	if !sharesDaemonNetwork {
+		return errcat.User.Newf(
+			"the docker daemon at %s is remote, so its containers cannot use a telepresence daemon that runs on this host. "+
+				"Use telepresence connect --docker to run the telepresence daemon in a container on that host", host)
+	}
+	for _, p := range pps {
+		if p.HostAddrPort.Addr().IsLoopback() {
+			return errcat.User.Newf(
+				"unable to publish %s, because the docker daemon at %s is remote and the port would be bound to its loopback "+
+					"interface. Omit the address to publish the port on the remote host", p, host)
+		}
+	}
+	if len(pps) > 0 {
+		dlog.Infof(ctx, "The docker daemon at %s is remote. Published ports will be available on that host", host)
+	}
+	return nil
+}
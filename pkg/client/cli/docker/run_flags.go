@@ -1,52 +1,56 @@
 package docker
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"maps"
 	"net/netip"
+	"os"
 	"strings"
+	"unicode"
 
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/flags"
 	docker2 "github.com/telepresenceio/telepresence/v2/pkg/client/docker"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
 type RunFlags struct {
 	PublishedPorts PublishedPorts // --publish Port mappings that the container will expose on localhost
 	Networks       []string
+	Env            []string // --env KEY=VALUE, or KEY to inherit the value from the host
+	EnvFiles       []string // --env-file
 }
 
+// ParseRunFlags consumes the docker run flags that Telepresence must know about from the given args, and returns
+// them together with the remaining args. The args must only contain the options that precede the image, because
+// flags such as -e would otherwise be consumed from the container's command too. Use SplitRunArgs to find them.
 func ParseRunFlags(args []string) (*RunFlags, []string, error) {
 	f := RunFlags{}
-	var v string
-	var found bool
-	var err error
-	for {
-		v, found, args, err = flags.ConsumeUnparsedValue("publish", 'p', false, args)
-		if err != nil {
-			return nil, nil, err
-		}
-		if !found {
-			break
-		}
-		var pp PublishedPort
-		pp, err = parsePublishedPort(v)
+	vs, args, err := flags.ConsumeAllUnparsedValues("publish", 'p', false, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, v := range vs {
+		pps, err := parsePublishedPorts(v)
 		if err != nil {
 			return nil, nil, fmt.Errorf("invalid port format for --publish: %w", err)
 		}
-		f.PublishedPorts = append(f.PublishedPorts, pp)
+		f.PublishedPorts = append(f.PublishedPorts, pps...)
 	}
-	for {
-		v, found, args, err = flags.ConsumeUnparsedValue("expose", 0, false, args)
-		if err != nil {
-			return nil, nil, err
-		}
-		if !found {
-			break
-		}
+
+	vs, args, err = flags.ConsumeAllUnparsedValues("expose", 0, false, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, v := range vs {
 		// Convert --expose values to --publish values
 		if strings.Contains(v, ":") {
 			return nil, nil, fmt.Errorf("invalid port format for --expose: %s", v)
@@ -56,30 +60,84 @@ func ParseRunFlags(args []string) (*RunFlags, []string, error) {
 		if err != nil {
 			return nil, nil, fmt.Errorf("invalid argument for --expose: %s, error: %s", v, err)
 		}
-		if start != end {
-			return nil, nil, fmt.Errorf("invalid argument for --expose: %s, error: a range not supported", v)
+		for port := start; port <= end; port++ {
+			f.PublishedPorts = append(f.PublishedPorts, PublishedPort{
+				HostAddrPort:  netip.AddrPortFrom(netip.IPv4Unspecified(), uint16(port)),
+				Protocol:      proto,
+				ContainerPort: uint16(port),
+			})
 		}
-		port := uint16(start)
-		f.PublishedPorts = append(f.PublishedPorts, PublishedPort{
-			HostAddrPort:  netip.AddrPortFrom(netip.IPv4Unspecified(), port),
-			Protocol:      proto,
-			ContainerPort: port,
-		})
-	}
-	for {
-		v, found, args, err = flags.ConsumeUnparsedValue("network", 0, false, args)
+	}
+
+	f.Networks, args, err = flags.ConsumeAllUnparsedValues("network", 0, false, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f.Env, args, err = flags.ConsumeAllUnparsedValues("env", 'e', false, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The files are merged into the env file that Telepresence passes to docker run, so they must not be
+	// passed on.
+	f.EnvFiles, args, err = flags.ConsumeAllUnparsedValues("env-file", 0, false, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &f, args, nil
+}
+
+// readEnvFiles reads the given files using the syntax of the docker run --env-file flag, and returns their
+// KEY=VALUE, or KEY, entries in order.
+func readEnvFiles(files []string) ([]string, error) {
+	var entries []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
 		if err != nil {
-			return nil, nil, err
+			return nil, errcat.User.Newf("failed to read --env-file: %w", err)
+		}
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		for sc.Scan() {
+			line := strings.TrimLeftFunc(sc.Text(), unicode.IsSpace)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			entries = append(entries, line)
 		}
-		if !found {
-			break
+		if err = sc.Err(); err != nil {
+			return nil, errcat.User.Newf("failed to read --env-file %s: %w", file, err)
 		}
-		f.Networks = append(f.Networks, v)
 	}
-	return &f, args, nil
+	return entries, nil
 }
 
-func ConnectNetworksToDaemon(ctx context.Context, networks []string, daemonName string) (context.CancelFunc, error) {
+// mergeRunEnv returns the given environment merged with the variables of the --env flags that were passed
+// to docker run. The variables of the flags take precedence. A flag without a value, e.g. "-e KEY",
+// inherits the value of the variable from the host, and is ignored when the host has no such variable.
+func mergeRunEnv(ctx context.Context, environment map[string]string, envFlags []string) map[string]string {
+	if len(envFlags) == 0 {
+		return environment
+	}
+	merged := make(map[string]string, len(environment)+len(envFlags))
+	maps.Copy(merged, environment)
+	for _, e := range envFlags {
+		k, v, ok := strings.Cut(e, "=")
+		if !ok {
+			if v, ok = dos.LookupEnv(ctx, k); !ok {
+				dlog.Debugf(ctx, "ignoring --env %s because it isn't set in the host environment", k)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// ConnectNetworksToDaemon connects the given networks to the containerized daemon. The given aliases, if any, are
+// added to the daemon's endpoint in each network so that a container that shares the daemon's network can be
+// reached using them.
+func ConnectNetworksToDaemon(ctx context.Context, networks []string, daemonName string, aliases []string) (context.CancelFunc, error) {
 	cancel := func() {}
 	if len(networks) == 0 {
 		return cancel, nil
@@ -95,7 +153,7 @@ func ConnectNetworksToDaemon(ctx context.Context, networks []string, daemonName
 		disconnectDaemons(ctx, cli, ds, daemonName)
 	}
 	for _, n := range networks {
-		connected, err := connectDaemon(ctx, cli, n, daemonName)
+		connected, err := connectDaemon(ctx, cli, n, daemonName, aliases)
 		if err != nil {
 			return cancel, err
 		}
@@ -108,17 +166,32 @@ func ConnectNetworksToDaemon(ctx context.Context, networks []string, daemonName
 
 // connectDaemon connects the given network to the containerized daemon. It will
 // return false if the daemon already had this network attached.
-func connectDaemon(ctx context.Context, cli *client.Client, network, daemonName string) (bool, error) {
-	dlog.Debugf(ctx, "Connecting network %s to container %s", network, daemonName)
-	if err := cli.NetworkConnect(ctx, network, daemonName, nil); err != nil {
+func connectDaemon(ctx context.Context, cli *client.Client, nw, daemonName string, aliases []string) (bool, error) {
+	dlog.Debugf(ctx, "Connecting network %s to container %s", nw, daemonName)
+	var es *network.EndpointSettings
+	if len(aliases) > 0 {
+		es = &network.EndpointSettings{Aliases: aliases}
+	}
+	if err := cli.NetworkConnect(ctx, nw, daemonName, es); err != nil {
 		if strings.Contains(err.Error(), "already exists") {
+			if len(aliases) > 0 {
+				dlog.Warnf(ctx, "Network %s was already connected to container %s. The aliases %v were not added", nw, daemonName, aliases)
+			}
 			return false, nil
 		}
-		return false, fmt.Errorf("failed to connect network %s to container %s: %v", network, daemonName, err)
+		return false, fmt.Errorf("failed to connect network %s to container %s: %v", nw, daemonName, err)
 	}
 	return true, nil
 }
 
+// checkNetworkAliases returns an error if network aliases are given without a network to apply them to.
+func checkNetworkAliases(aliases, networks []string) error {
+	if len(aliases) > 0 && len(networks) == 0 {
+		return errcat.User.New("--docker-network-alias requires that a --network is passed to docker run")
+	}
+	return nil
+}
+
 // disconnectDaemons disconnects the given networks from the containerized daemon.
 func disconnectDaemons(ctx context.Context, cli *client.Client, networks []string, daemonName string) {
 	ctx = context.WithoutCancel(ctx)
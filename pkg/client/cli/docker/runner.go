@@ -2,16 +2,24 @@ package docker
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"maps"
 	"math"
+	"net/netip"
 	"os"
 	"os/exec"
 	"os/signal"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/errdefs"
 
 	"github.com/datawire/dlib/dexec"
@@ -23,6 +31,7 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/spinner"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/logging"
 	"github.com/telepresenceio/telepresence/v2/pkg/dos"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
@@ -34,16 +43,21 @@ type Runner struct {
 	ContainerName string
 	Environment   map[string]string
 	Mount         *mount.Info
+	DNSSearch     []string // --dns-search
+
+	// networks are the networks that were passed to docker run when the daemon runs on the host.
+	networks []string
 }
 
 func (s *Runner) Run(ctx context.Context, waitMessage string, args ...string) error {
 	ud := daemon.GetUserClient(ctx)
+	var networks, envFlags []string
 	if s.Flags.imageIndex > 0 {
 		// arguments between the "--" separator and the image name are docker run flags, and
-		// we must extract the relevant network flags.
+		// we must extract the relevant network and environment flags.
 		runArgs := args[:s.imageIndex]
 		args = args[s.imageIndex:]
-		networkFlags, runArgs, err := ParseRunFlags(runArgs)
+		runFlags, runArgs, err := ParseRunFlags(runArgs)
 		if err != nil {
 			return err
 		}
@@ -51,15 +65,32 @@ func (s *Runner) Run(ctx context.Context, waitMessage string, args ...string) er
 		if len(runArgs) > 0 {
 			args = append(runArgs, args...)
 		}
-		if pps := networkFlags.PublishedPorts; len(pps) > 0 {
+		if pps := runFlags.PublishedPorts; len(pps) > 0 {
 			s.Flags.PublishedPorts = append(s.Flags.PublishedPorts, pps...)
 		}
-		if nts := networkFlags.Networks; len(nts) > 0 {
-			connectCancel, err := ConnectNetworksToDaemon(ctx, networkFlags.Networks, ud.DaemonID().ContainerName())
+		networks = runFlags.Networks
+
+		// Just like with docker run, the variables of --env flags take precedence over those of --env-file flags.
+		if envFlags, err = readEnvFiles(runFlags.EnvFiles); err != nil {
+			return err
+		}
+		envFlags = append(envFlags, runFlags.Env...)
+	}
+	if err := checkNetworkAliases(s.NetworkAliases, networks); err != nil {
+		return err
+	}
+	if err := CheckRemoteDaemon(ctx, ud.Containerized(), s.Flags.PublishedPorts); err != nil {
+		return err
+	}
+	if len(networks) > 0 {
+		if ud.Containerized() {
+			connectCancel, err := ConnectNetworksToDaemon(ctx, networks, ud.DaemonID().ContainerName(), s.NetworkAliases)
 			defer connectCancel()
 			if err != nil {
 				return err
 			}
+		} else {
+			s.networks = networks
 		}
 	}
 
@@ -73,11 +104,36 @@ func (s *Runner) Run(ctx context.Context, waitMessage string, args ...string) er
 		}
 	}()
 
-	if err = env.SyntaxDocker.WriteToFileAndClose(file, s.Environment); err != nil {
+	// The variables of --env and --env-file flags passed to docker run are written to the env file together
+	// with the environment of the intercept, so that they take precedence.
+	environment := mergeRunEnv(ctx, s.Environment, envFlags)
+	if len(s.DNSSearch) > 0 && ud.Containerized() {
+		// Docker doesn't allow --dns-search when the container shares the network of the daemon
+		// container, so the resolver of the container must be told instead. Resolvers that don't
+		// honor LOCALDOMAIN, such as the ones of musl and Go, will not use the entries.
+		searchPath, err := containerSearchPath(ctx, ud.DaemonID().ContainerName())
+		if err != nil {
+			dlog.Warnf(ctx, "unable to read the search path of the daemon container: %v", err)
+		}
+		environment = maps.Clone(environment)
+		environment[env.LocalDomain] = localDomain(searchPath, s.DNSSearch)
+	}
+	if err = env.SyntaxDocker.WriteToFileAndClose(file, environment); err != nil {
 		return err
 	}
 	envFile := file.Name()
 
+	stdout, stderr := dos.Stdout(ctx), dos.Stderr(ctx)
+	if s.RunLogFile != "" {
+		lf, err := openRunLogFile(ctx, s.RunLogFile)
+		if err != nil {
+			return err
+		}
+		defer lf.Close()
+		stdout = &logTee{out: stdout, log: lf}
+		stderr = &logTee{out: stderr, log: lf}
+	}
+
 	// Ensure that the intercept handler is stopped properly if the daemon quits
 	procCtx, cancel := context.WithCancel(ctx)
 	go func() {
@@ -97,37 +153,74 @@ func (s *Runner) Run(ctx context.Context, waitMessage string, args ...string) er
 	if w.err == nil {
 		w.err = ud.AddHandler(ctx, s.Environment["TELEPRESENCE_INTERCEPT_ID"], w.cmd, w.name)
 		spin.Message("started")
-		spin.DoneMsg(waitMessage)
-		if waitMessage != "" && spin.IsNoOp() {
-			ioutil.Println(dos.Stdout(ctx), waitMessage)
-		}
+		printWaitMessage(ctx, spin, waitMessage)
 	} else {
 		_ = spin.Error(w.err)
 	}
+	var copyWG sync.WaitGroup
+	copyWG.Add(2)
 	go func() {
-		_, _ = io.Copy(dos.Stdout(ctx), outRdr)
+		defer copyWG.Done()
+		_, _ = io.Copy(stdout, outRdr)
 	}()
 	go func() {
-		_, _ = io.Copy(dos.Stderr(ctx), errRdr)
+		defer copyWG.Done()
+		_, _ = io.Copy(stderr, errRdr)
 	}()
 
-	if err = w.wait(procCtx); err != nil {
+	err = w.wait(procCtx)
+
+	// The process has exited, so closing the pipe writers ends the copying. All output must be copied
+	// before the log file is closed.
+	_ = outWrt.Close()
+	_ = errWrt.Close()
+	copyWG.Wait()
+	if err != nil {
 		return spin.Error(err)
 	}
 	spin.Done()
 	return nil
 }
 
-func (s *Runner) start(ctx context.Context, name, envFile string, args []string) *waiter {
-	ourArgs := []string{
-		"run",
-		"--env-file", envFile,
+const (
+	runLogFileMaxSize  = 10 * 1024 * 1024
+	runLogFileMaxFiles = 5
+)
+
+// openRunLogFile opens the file given with --docker-run-log-file for append. The file is rotated when it
+// grows beyond runLogFileMaxSize, and at most runLogFileMaxFiles files, including the current one, are kept.
+func openRunLogFile(ctx context.Context, path string) (*logging.RotatingFile, error) {
+	lf, err := logging.OpenRotatingFile(ctx, path, "20060102T150405", true, 0o600, logging.RotateBySize(runLogFileMaxSize), runLogFileMaxFiles)
+	if err != nil {
+		return nil, errcat.User.Newf("unable to open --docker-run-log-file: %w", err)
 	}
-	w := &waiter{name: name}
+	return lf, nil
+}
 
-	if s.Debug {
-		ourArgs = append(ourArgs, "--security-opt", "apparmor=unconfined", "--cap-add", "SYS_PTRACE")
+// logTee writes everything that is written to it to both out and log. Failures to write to log are ignored,
+// so that they don't interrupt the output.
+type logTee struct {
+	out io.Writer
+	log io.Writer
+}
+
+func (t *logTee) Write(data []byte) (int, error) {
+	_, _ = t.log.Write(data)
+	return t.out.Write(data)
+}
+
+// printWaitMessage stops the spinner with the given message. The message is printed as a plain line when
+// the spinner is a no-op, which it is when no spinner is available or when plain output has been requested.
+func printWaitMessage(ctx context.Context, spin spinner.Spinner, waitMessage string) {
+	spin.DoneMsg(waitMessage)
+	if waitMessage != "" && (spin.IsNoOp() || spinner.Plain(ctx)) {
+		ioutil.Println(dos.Stdout(ctx), waitMessage)
 	}
+}
+
+func (s *Runner) start(ctx context.Context, name, envFile string, args []string) *waiter {
+	ourArgs := s.baseRunArgs(envFile)
+	w := &waiter{name: name, startTimeout: s.RunTimeout}
 
 	// "--rm" is mandatory when using --docker-run, because without it, the name cannot be reused and
 	// the volumes cannot be removed.
@@ -143,15 +236,7 @@ func (s *Runner) start(ctx context.Context, name, envFile string, args []string)
 	ud := daemon.GetUserClient(ctx)
 	if !ud.Containerized() {
 		// The process is containerized but the user daemon runs on the host
-		ourArgs = append(ourArgs, "--dns-search", "tel2-search")
-		for _, p := range s.Flags.PublishedPorts {
-			ourArgs = append(ourArgs, "-p", p.String())
-		}
-		if m := s.Mount; m != nil {
-			for _, mv := range m.Mounts {
-				ourArgs = append(ourArgs, "-v", fmt.Sprintf("%s/%s:%s", m.LocalDir, mv, mv))
-			}
-		}
+		ourArgs = append(ourArgs, s.hostDaemonArgs()...)
 	} else {
 		daemonName := ud.DaemonID().ContainerName()
 		ourArgs = append(ourArgs, "--network", "container:"+daemonName)
@@ -161,9 +246,9 @@ func (s *Runner) start(ctx context.Context, name, envFile string, args []string)
 			if err != nil {
 				ioutil.Printf(output.Err(ctx), "Remote mount disabled: %s\n", err)
 			} else {
-				container := s.Environment["TELEPRESENCE_CONTAINER"]
-				dlog.Infof(ctx, "Mounting %s from container %s", m.RemoteDir, container)
-				w.volumes, w.err = docker.StartVolumeMounts(ctx, pluginName, daemonName, container, m.Port, m.Mounts, nil, m.ReadOnly)
+				containerName := s.Environment["TELEPRESENCE_CONTAINER"]
+				dlog.Infof(ctx, "Mounting %s from container %s", m.RemoteDir, containerName)
+				w.volumes, w.err = docker.StartVolumeMounts(ctx, pluginName, daemonName, containerName, m.Port, m.Mounts, nil, m.ReadOnly)
 				if w.err != nil {
 					dlog.Error(ctx, w.err)
 					return w
@@ -177,6 +262,32 @@ func (s *Runner) start(ctx context.Context, name, envFile string, args []string)
 				}
 			}
 		}
+		// The container runs on the host, so the local volumes are bind mounted from there just like
+		// when the daemon runs on the host.
+		ourArgs = append(ourArgs, s.volumeArgs()...)
+	}
+
+	if s.ReuseContainer() {
+		var hash string
+		if hash, w.err = runConfigHash(envFile, ourArgs, args); w.err != nil {
+			return w
+		}
+		var reuse bool
+		if reuse, w.err = s.reusableContainer(ctx, name, args, hash); w.err != nil {
+			return w
+		}
+		if reuse {
+			runOpts := args
+			if s.imageIndex >= 0 && s.imageIndex <= len(args) {
+				runOpts = args[:s.imageIndex]
+			}
+			w.cmd, w.err = proc.Start(context.WithoutCancel(ctx), nil, "docker", containerStartArgs(name, runOpts)...)
+			if w.err == nil {
+				s.startPortPublishers(ctx, w)
+			}
+			return w
+		}
+		ourArgs = append(ourArgs, "--label", runHashLabel+"="+hash)
 	}
 
 	args = append(ourArgs, args...)
@@ -185,21 +296,133 @@ func (s *Runner) start(ctx context.Context, name, envFile string, args []string)
 		return w
 	}
 
-	if ud.Containerized() {
-		// Using a -p <publicPort>:<privatePort> directly on the started container was not possible because it
-		// inherits the containerized daemons network config. That config includes the "telepresence" network though,
-		// so we can now create socat listeners that dispatch from this network to the daemon containers network.
-		daemonID := ud.DaemonID().ContainerName()
-		for _, p := range s.Flags.PublishedPorts {
-			var portCancel context.CancelFunc
-			portCancel, w.err = startPortPublisher(ctx, daemonID, p)
-			w.procsToCancel = append(w.procsToCancel, portCancel)
-			if w.err != nil {
-				return w
-			}
+	s.startPortPublishers(ctx, w)
+	return w
+}
+
+// baseRunArgs returns the docker run arguments that are added regardless of where the daemon runs.
+func (s *Runner) baseRunArgs(envFile string) []string {
+	args := []string{
+		"run",
+		"--env-file", envFile,
+	}
+	if s.Platform != "" {
+		args = append(args, "--platform", s.Platform)
+	}
+	if s.Debug {
+		args = append(args, "--security-opt", "apparmor=unconfined", "--cap-add", "SYS_PTRACE")
+	}
+	return args
+}
+
+// startPortPublishers starts the socat containers that publish the ports of a container that shares the network
+// of a containerized daemon. Using a -p <publicPort>:<privatePort> directly on the started container isn't possible
+// because it inherits the containerized daemons network config. That config includes the "telepresence" network
+// though, so we can create socat listeners that dispatch from this network to the daemon containers network.
+func (s *Runner) startPortPublishers(ctx context.Context, w *waiter) {
+	ud := daemon.GetUserClient(ctx)
+	if !ud.Containerized() {
+		return
+	}
+	daemonID := ud.DaemonID().ContainerName()
+	for _, p := range s.Flags.PublishedPorts {
+		var portCancel context.CancelFunc
+		portCancel, w.err = startPortPublisher(ctx, daemonID, p)
+		w.procsToCancel = append(w.procsToCancel, portCancel)
+		if w.err != nil {
+			return
 		}
 	}
-	return w
+}
+
+// runHashLabel is the label of a reusable container that holds the hash of the environment and the docker run
+// arguments that the container was created with.
+const runHashLabel = "telepresence.io/docker-run-hash"
+
+// runConfigHash returns a hash of the content of the given env file, the given arguments that Telepresence adds
+// to docker run, and the given arguments of the user. The name of the env file is excluded, because it's a new
+// temporary file for each run.
+func runConfigHash(envFile string, ourArgs, args []string) (string, error) {
+	env, err := os.ReadFile(envFile)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "env %d\n", len(env))
+	_, _ = h.Write(env)
+	for i := 0; i < len(ourArgs); i++ {
+		if ourArgs[i] == "--env-file" && i+1 < len(ourArgs) && ourArgs[i+1] == envFile {
+			i++
+			continue
+		}
+		_, _ = fmt.Fprintf(h, "opt %s\n", ourArgs[i])
+	}
+	for _, arg := range args {
+		_, _ = fmt.Fprintf(h, "arg %s\n", arg)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// reusableContainer returns true if a container with the given name exists and can be started instead of running
+// a new container. An error is returned when the container exists but cannot be reused. A container that was
+// created with a different environment, different volumes, or different DNS or network arguments, is removed so
+// that a new one can be created.
+func (s *Runner) reusableContainer(ctx context.Context, name string, args []string, hash string) (bool, error) {
+	cli, err := docker.GetClient(ctx)
+	if err != nil {
+		return false, err
+	}
+	cj, err := cli.ContainerInspect(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	image := ""
+	if s.imageIndex >= 0 && s.imageIndex < len(args) {
+		image = args[s.imageIndex]
+	}
+	reuse, err := checkReusable(cj, image, hash)
+	if err != nil {
+		return false, err
+	}
+	if !reuse {
+		ioutil.Printf(output.Info(ctx), "Recreating container %s because its environment or configuration has changed\n", name)
+		if err = cli.ContainerRemove(ctx, cj.ID, container.RemoveOptions{}); err != nil {
+			return false, fmt.Errorf("failed to remove container %s: %w", name, err)
+		}
+		return false, nil
+	}
+	dlog.Infof(ctx, "Reusing existing container %s", name)
+	return true, nil
+}
+
+// checkReusable returns an error unless the given container is stopped and was created from the given image. It
+// returns false when the container must be recreated, because it wasn't created with the given hash.
+func checkReusable(cj types.ContainerJSON, image, hash string) (bool, error) {
+	if cj.ContainerJSONBase == nil || cj.Config == nil {
+		return false, fmt.Errorf("unable to inspect existing container %s", cj.Name)
+	}
+	name := strings.TrimPrefix(cj.Name, "/")
+	if cj.State != nil && cj.State.Running {
+		return false, errcat.User.Newf("container %s is already running", name)
+	}
+	if image != cj.Config.Image && image != cj.Image {
+		return false, errcat.User.Newf("container %s exists but was created from image %s, not %s. Remove it, or use another --name",
+			name, cj.Config.Image, image)
+	}
+	return cj.Config.Labels[runHashLabel] == hash, nil
+}
+
+// containerStartArgs returns the arguments for the "docker start" that starts an existing container, given
+// the "docker run" options that preceded the image name.
+func containerStartArgs(name string, runOpts []string) []string {
+	startArgs := []string{"start", "--attach"}
+	if flags.HasOption("interactive", 'i', runOpts) {
+		startArgs = append(startArgs, "--interactive")
+	}
+	return append(startArgs, name)
 }
 
 type waiter struct {
@@ -215,18 +438,30 @@ type waiter struct {
 	volumes []string
 
 	procsToCancel []context.CancelFunc
+
+	// startTimeout is the time that the container is given to start. Zero means no timeout.
+	startTimeout time.Duration
+
+	// isReady reports whether the container is ready. Defaults to containerReady.
+	isReady func(context.Context) bool
 }
 
+// stopGracePeriod is the time that a docker run is given to exit after its container has been stopped
+// because it didn't start in time.
+var stopGracePeriod = 15 * time.Second //nolint:gochecknoglobals // can be changed by tests
+
 func startPortPublisher(ctx context.Context, daemonID string, p PublishedPort) (context.CancelFunc, error) {
 	portCtx, portCancel := context.WithCancel(ctx)
+	if bindsNetworkAddr(p) {
+		if err := checkNetworkAddr(ctx, "telepresence", p.HostAddrPort.Addr()); err != nil {
+			return portCancel, err
+		}
+	}
 	cidFileName, err := ioutil.CreateTempName("", "docker-run*.cid")
 	if err != nil {
 		return portCancel, err
 	}
-	_, err = proc.Start(portCtx, nil, "docker",
-		"run", "--cidfile", cidFileName, "--rm", "--network", "telepresence", "-p", p.String(), "alpine/socat",
-		fmt.Sprintf("%s-listen:%d,fork,reuseaddr", p.Protocol, p.ContainerPort),
-		fmt.Sprintf("%s-connect:%s:%d", p.Protocol, daemonID, p.ContainerPort))
+	_, err = proc.Start(portCtx, nil, "docker", portPublisherArgs(cidFileName, daemonID, p)...)
 	if err != nil {
 		return portCancel, err
 	}
@@ -242,6 +477,72 @@ func startPortPublisher(ctx context.Context, daemonID string, p PublishedPort) (
 	}, nil
 }
 
+// bindsNetworkAddr returns true when the published port is bound to an explicit address that isn't a loopback
+// address. Such an address must be an address on the telepresence network, and the port publisher will then
+// listen on that address only. The host binding of loopback addresses is handled by docker using the -p flag.
+func bindsNetworkAddr(p PublishedPort) bool {
+	addr := p.HostAddrPort.Addr()
+	return !(addr.IsUnspecified() || addr.IsLoopback())
+}
+
+// checkNetworkAddr returns an error unless the given address belongs to a subnet of the given docker network.
+func checkNetworkAddr(ctx context.Context, networkName string, addr netip.Addr) error {
+	subnets, err := docker.GetNetworkSubnets(ctx, networkName)
+	if err != nil {
+		return err
+	}
+	for _, subnet := range subnets {
+		if subnet.Contains(addr) {
+			return nil
+		}
+	}
+	return errcat.User.Newf("address %s of published port is not assigned to the %s network", addr, networkName)
+}
+
+// udpPublisherIdleTimeout is the number of seconds of inactivity after which socat ends the child process
+// that forwards the datagrams of one UDP peer. UDP has no end of stream, so such processes would otherwise
+// linger forever.
+const udpPublisherIdleTimeout = "60"
+
+// portPublisherArgs returns the arguments for the "docker run" that starts a socat container that listens on
+// the telepresence network and dispatches to the given port on the daemon container. The container is labeled
+// with the daemon, so that it can be removed should it outlive the daemon.
+//
+// A socat udp-listen only serves the peer that sends the first datagram, even when forking, so UDP ports use
+// udp-recvfrom instead. It forks a child for each received datagram, and that child then forwards the replies
+// from the daemon container to the sender.
+func portPublisherArgs(cidFileName, daemonID string, p PublishedPort) []string {
+	args := []string{"run", "--cidfile", cidFileName, "--rm", "--network", "telepresence", "--label", docker.PublisherLabel + "=" + daemonID}
+	listenType := "listen"
+	var socatOpts []string
+	if p.Protocol == "udp" {
+		listenType = "recvfrom"
+		socatOpts = []string{"-T", udpPublisherIdleTimeout}
+	}
+	listen := fmt.Sprintf("%s-%s:%d", p.Protocol, listenType, p.ContainerPort)
+	if bindsNetworkAddr(p) {
+		addr := p.HostAddrPort.Addr()
+		port := p.HostAddrPort.Port()
+		if port == 0 {
+			port = p.ContainerPort
+		}
+		if addr.Is4() {
+			args = append(args, "--ip", addr.String())
+			listen = fmt.Sprintf("%s-%s:%d,bind=%s", p.Protocol, listenType, port, addr)
+		} else {
+			args = append(args, "--ip6", addr.String())
+			listen = fmt.Sprintf("%s6-%s:%d,bind=[%s]", p.Protocol, listenType, port, addr)
+		}
+	} else {
+		args = append(args, "-p", p.String())
+	}
+	args = append(args, "alpine/socat")
+	args = append(args, socatOpts...)
+	return append(args,
+		listen+",fork,reuseaddr",
+		fmt.Sprintf("%s-connect:%s:%d", p.Protocol, daemonID, p.ContainerPort))
+}
+
 func (w *waiter) wait(ctx context.Context) error {
 	if len(w.procsToCancel) > 0 {
 		defer func() {
@@ -261,21 +562,90 @@ func (w *waiter) wait(ctx context.Context) error {
 	})
 	defer killTimer.Stop()
 
-	var exited, signalled atomic.Bool
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var exited, signalled, timedOut atomic.Bool
 	go EnsureStopContainer(ctx, w.name, w.volumes, &exited, &signalled)
 
+	if w.startTimeout > 0 {
+		go func() {
+			if !w.awaitReady(ctx) && ctx.Err() == nil && !exited.Load() {
+				timedOut.Store(true)
+				dlog.Errorf(ctx, "container %s did not start within %s", w.name, w.startTimeout)
+				// Cancelling the context makes EnsureStopContainer stop the container. The docker run is
+				// killed if it doesn't exit in time after that.
+				cancel()
+				killTimer.Reset(stopGracePeriod)
+			}
+		}()
+	}
+
 	err := w.cmd.Wait()
+	exited.Store(true)
+	if timedOut.Load() {
+		return errcat.User.Newf("container %s did not start within %s", w.name, w.startTimeout)
+	}
 	if err != nil {
 		if signalled.Load() {
 			// Errors caused by context or signal termination don't count.
 			err = nil
 		} else {
-			err = errcat.NoDaemonLogs.New(err)
+			// The CLI will exit with the exit code of the container.
+			err = errcat.NoDaemonLogs.New(proc.HandlerExit(err))
 		}
 	}
 	return err
 }
 
+// awaitReady waits until the container is ready. It returns false if that doesn't happen within the start
+// timeout, or if the context is cancelled.
+func (w *waiter) awaitReady(ctx context.Context) bool {
+	isReady := w.isReady
+	if isReady == nil {
+		isReady = w.containerReady
+	}
+	ctx, cancel := context.WithTimeout(ctx, w.startTimeout)
+	defer cancel()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if isReady(ctx) {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// containerReady returns true when the container of this waiter is ready. A container with a health check is
+// ready when it is healthy. Other containers are ready when they are running, because docker provides no other
+// signal that they are.
+func (w *waiter) containerReady(ctx context.Context) bool {
+	cli, err := docker.GetClient(ctx)
+	if err != nil {
+		return false
+	}
+	cj, err := cli.ContainerInspect(ctx, w.name)
+	if err != nil || cj.ContainerJSONBase == nil || cj.State == nil {
+		return false
+	}
+	health := ""
+	if cj.State.Health != nil {
+		health = cj.State.Health.Status
+	}
+	return isReadyState(cj.State.Running, health)
+}
+
+// isReadyState returns true when a container in the given state is ready. An empty health status means that the
+// container has no health check.
+func isReadyState(running bool, health string) bool {
+	return running && (health == "" || health == "healthy")
+}
+
 func EnsureStopContainer(ctx context.Context, containerID string, volumes []string, exited, signalled *atomic.Bool) {
 	if len(volumes) > 0 {
 		defer func() {
@@ -322,3 +692,39 @@ func ReadContainerID(ctx context.Context, cidFile string) (containerID string, e
 	}, backoff.WithContext(backoff.NewConstantBackOff(10*time.Millisecond), ctx))
 	return containerID, err
 }
+
+// hostDaemonArgs returns the docker run arguments for DNS, published ports, and volumes that
+// are needed when the container doesn't share the network of a containerized daemon.
+func (s *Runner) hostDaemonArgs() []string {
+	var args []string
+	if !s.NoTel2Search {
+		args = append(args, "--dns-search", "tel2-search")
+	}
+	for _, ds := range s.DNSSearch {
+		args = append(args, "--dns-search", ds)
+	}
+	for _, p := range s.Flags.PublishedPorts {
+		args = append(args, "-p", p.String())
+	}
+	for _, n := range s.networks {
+		args = append(args, "--network", n)
+	}
+	for _, a := range s.NetworkAliases {
+		args = append(args, "--network-alias", a)
+	}
+	if m := s.Mount; m != nil {
+		for _, mv := range m.Mounts {
+			args = append(args, "-v", fmt.Sprintf("%s/%s:%s", m.LocalDir, mv, mv))
+		}
+	}
+	return append(args, s.volumeArgs()...)
+}
+
+// volumeArgs returns the docker run arguments for the volumes given with --docker-volume.
+func (s *Runner) volumeArgs() []string {
+	args := make([]string, 0, len(s.Volumes)*2)
+	for _, v := range s.Volumes {
+		args = append(args, "-v", v)
+	}
+	return args
+}
@@ -0,0 +1,641 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/env"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/mount"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/spinner"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+)
+
+func TestRunner_hostDaemonArgs(t *testing.T) {
+	pps, err := parsePublishedPorts("8080:80")
+	require.NoError(t, err)
+	r := Runner{
+		Flags:     Flags{PublishedPorts: pps},
+		DNSSearch: []string{"other-ns", "svc.example.com"},
+		Mount:     &mount.Info{LocalDir: "/tmp/tel", Mounts: []string{"/var/run/secrets"}},
+	}
+	assert.Equal(t, []string{
+		"--dns-search", "tel2-search",
+		"--dns-search", "other-ns",
+		"--dns-search", "svc.example.com",
+		"-p", "8080:80",
+		"-v", "/tmp/tel//var/run/secrets:/var/run/secrets",
+	}, r.hostDaemonArgs())
+
+	r.NoTel2Search = true
+	assert.Equal(t, []string{
+		"--dns-search", "other-ns",
+		"--dns-search", "svc.example.com",
+		"-p", "8080:80",
+		"-v", "/tmp/tel//var/run/secrets:/var/run/secrets",
+	}, r.hostDaemonArgs())
+}
+
+func TestPublishedPorts_String(t *testing.T) {
+	var pps PublishedPorts
+	require.NoError(t, pps.Replace([]string{"8080:80", "127.0.0.1:8081:81", "82/udp", "[::1]:8083:83/udp"}))
+	assert.Equal(t, "[8080:80,127.0.0.1:8081:81,82/udp,[::1]:8083:83/udp]", pps.String())
+	assert.Equal(t, []string{"8080:80", "127.0.0.1:8081:81", "82/udp", "[::1]:8083:83/udp"}, pps.GetSlice())
+}
+
+func Test_portPublisherArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		port   string
+		expect []string
+	}{
+		{
+			"all interfaces",
+			"8080:80",
+			[]string{"-p", "8080:80", "alpine/socat", "tcp-listen:80,fork,reuseaddr", "tcp-connect:tp-daemon:80"},
+		},
+		{
+			"loopback",
+			"127.0.0.1:8080:80",
+			[]string{"-p", "127.0.0.1:8080:80", "alpine/socat", "tcp-listen:80,fork,reuseaddr", "tcp-connect:tp-daemon:80"},
+		},
+		{
+			"network address",
+			"172.18.0.100:8080:80/udp",
+			[]string{"--ip", "172.18.0.100", "alpine/socat", "-T", "60", "udp-recvfrom:8080,bind=172.18.0.100,fork,reuseaddr", "udp-connect:tp-daemon:80"},
+		},
+		{
+			"udp all interfaces",
+			"5353:5353/udp",
+			[]string{"-p", "5353:5353/udp", "alpine/socat", "-T", "60", "udp-recvfrom:5353,fork,reuseaddr", "udp-connect:tp-daemon:5353"},
+		},
+		{
+			"udp loopback",
+			"127.0.0.1:5354:53/udp",
+			[]string{"-p", "127.0.0.1:5354:53/udp", "alpine/socat", "-T", "60", "udp-recvfrom:53,fork,reuseaddr", "udp-connect:tp-daemon:53"},
+		},
+		{
+			"udp network IPv6 address",
+			"[fd00::100]:5353:53/udp",
+			[]string{"--ip6", "fd00::100", "alpine/socat", "-T", "60", "udp6-recvfrom:5353,bind=[fd00::100],fork,reuseaddr", "udp-connect:tp-daemon:53"},
+		},
+		{
+			"network IPv6 address",
+			"[fd00::100]:8080:80",
+			[]string{"--ip6", "fd00::100", "alpine/socat", "tcp6-listen:8080,bind=[fd00::100],fork,reuseaddr", "tcp-connect:tp-daemon:80"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pps, err := parsePublishedPorts(tt.port)
+			require.NoError(t, err)
+			require.Len(t, pps, 1)
+			expect := append([]string{
+				"run", "--cidfile", "x.cid", "--rm", "--network", "telepresence", "--label", "telepresence.io/publisher=tp-daemon",
+			}, tt.expect...)
+			assert.Equal(t, expect, portPublisherArgs("x.cid", "tp-daemon", pps[0]))
+		})
+	}
+}
+
+func TestFlags_GetContainerNameAndArgs(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	tests := []struct {
+		name      string
+		args      []string
+		wantName  string
+		wantArgs  []string
+		wantReuse bool
+	}{
+		{
+			"default name",
+			[]string{"--rm=false", "alpine"},
+			"intercept-echo-8080",
+			[]string{"--name", "intercept-echo-8080", "--rm=false", "alpine"},
+			false,
+		},
+		{
+			"given name",
+			[]string{"--name", "mydev", "alpine"},
+			"mydev",
+			[]string{"--name", "mydev", "alpine"},
+			false,
+		},
+		{
+			"given name with rm",
+			[]string{"--name=mydev", "--rm", "alpine"},
+			"mydev",
+			[]string{"--name=mydev", "--rm", "alpine"},
+			false,
+		},
+		{
+			"given name without rm",
+			[]string{"--name", "mydev", "--rm=false", "-it", "alpine"},
+			"mydev",
+			[]string{"--name", "mydev", "--rm=false", "-it", "alpine"},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Flags{Run: true}
+			require.NoError(t, f.Validate(ctx, tt.args))
+			name, args, err := f.GetContainerNameAndArgs("intercept-echo-8080")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantArgs, args)
+			assert.Equal(t, "alpine", args[f.imageIndex])
+			assert.Equal(t, tt.wantReuse, f.ReuseContainer())
+		})
+	}
+}
+
+func Test_checkReusable(t *testing.T) {
+	cj := func(running bool) types.ContainerJSON {
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				Name:  "/mydev",
+				Image: "sha256:1234",
+				State: &types.ContainerState{Running: running},
+			},
+			Config: &container.Config{Image: "alpine", Labels: map[string]string{runHashLabel: "abc"}},
+		}
+	}
+	reuse, err := checkReusable(cj(false), "alpine", "abc")
+	assert.NoError(t, err)
+	assert.True(t, reuse)
+	reuse, err = checkReusable(cj(false), "sha256:1234", "abc")
+	assert.NoError(t, err)
+	assert.True(t, reuse)
+	reuse, err = checkReusable(cj(false), "alpine", "def")
+	assert.NoError(t, err)
+	assert.False(t, reuse, "a container created with another configuration must be recreated")
+	_, err = checkReusable(cj(false), "busybox", "abc")
+	assert.EqualError(t, err,
+		"container mydev exists but was created from image alpine, not busybox. Remove it, or use another --name")
+	_, err = checkReusable(cj(true), "alpine", "abc")
+	assert.EqualError(t, err, "container mydev is already running")
+
+	assert.Equal(t, []string{"start", "--attach", "--interactive", "mydev"}, containerStartArgs("mydev", []string{"--name", "mydev", "-it"}))
+	assert.Equal(t, []string{"start", "--attach", "mydev"}, containerStartArgs("mydev", []string{"--name", "mydev"}))
+}
+
+func Test_runConfigHash(t *testing.T) {
+	writeEnv := func(vars map[string]string) string {
+		f, err := os.CreateTemp(t.TempDir(), "tel-*.env")
+		require.NoError(t, err)
+		require.NoError(t, env.SyntaxDocker.WriteToFileAndClose(f, vars))
+		return f.Name()
+	}
+	args := []string{"--name", "mydev", "--rm=false", "alpine"}
+	hash := func(envFile string, ourArgs ...string) string {
+		h, err := runConfigHash(envFile, append([]string{"run", "--env-file", envFile}, ourArgs...), args)
+		require.NoError(t, err)
+		return h
+	}
+
+	// The name of the env file doesn't matter, but its content does.
+	first := hash(writeEnv(map[string]string{"TELEPRESENCE_INTERCEPT_ID": "s1:echo"}), "--dns-search", "tel2-search")
+	assert.Equal(t, first, hash(writeEnv(map[string]string{"TELEPRESENCE_INTERCEPT_ID": "s1:echo"}), "--dns-search", "tel2-search"))
+	assert.NotEqual(t, first, hash(writeEnv(map[string]string{"TELEPRESENCE_INTERCEPT_ID": "s2:echo"}), "--dns-search", "tel2-search"))
+	assert.NotEqual(t, first, hash(writeEnv(map[string]string{"TELEPRESENCE_INTERCEPT_ID": "s1:echo"}), "--network", "my-net"))
+}
+
+func Test_waiterExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test uses sh")
+	}
+	ctx, cancel := context.WithCancel(dlog.NewTestContext(t, false))
+	defer cancel()
+
+	cmd, err := proc.Start(ctx, nil, "sh", "-c", "exit 3")
+	require.NoError(t, err)
+	w := &waiter{cmd: cmd, name: "no-such-container"}
+	err = w.wait(ctx)
+	var he *proc.HandlerExitError
+	require.ErrorAs(t, err, &he)
+	assert.Equal(t, 3, he.ExitCode)
+
+	cmd, err = proc.Start(ctx, nil, "sh", "-c", "exit 0")
+	require.NoError(t, err)
+	w = &waiter{cmd: cmd, name: "no-such-container"}
+	assert.NoError(t, w.wait(ctx))
+}
+
+func TestFlags_NetworkAliases(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	f := Flags{NetworkAliases: []string{"mydev"}}
+	assert.EqualError(t, f.Validate(ctx, nil), "--docker-network-alias must be used together with --docker-run, --docker-build, or --docker-debug")
+
+	f = Flags{Run: true, NetworkAliases: []string{"mydev"}}
+	assert.NoError(t, f.Validate(ctx, []string{"--network", "my-net", "alpine"}))
+
+	assert.NoError(t, checkNetworkAliases(nil, nil))
+	assert.NoError(t, checkNetworkAliases([]string{"mydev"}, []string{"my-net"}))
+	assert.EqualError(t, checkNetworkAliases([]string{"mydev"}, nil), "--docker-network-alias requires that a --network is passed to docker run")
+
+	r := Runner{
+		Flags:    Flags{NetworkAliases: []string{"mydev", "api"}, NoTel2Search: true},
+		networks: []string{"my-net"},
+	}
+	assert.Equal(t, []string{
+		"--network", "my-net",
+		"--network-alias", "mydev",
+		"--network-alias", "api",
+	}, r.hostDaemonArgs())
+}
+
+func Test_waiterStartTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test uses sleep")
+	}
+	ctx, cancel := context.WithCancel(dlog.NewTestContext(t, false))
+	defer cancel()
+
+	defer func(p time.Duration) { stopGracePeriod = p }(stopGracePeriod)
+	stopGracePeriod = 100 * time.Millisecond
+
+	cmd, err := proc.Start(ctx, nil, "sleep", "30")
+	require.NoError(t, err)
+	w := &waiter{
+		cmd:          cmd,
+		name:         "no-such-container",
+		startTimeout: 300 * time.Millisecond,
+		isReady:      func(context.Context) bool { return false },
+	}
+	start := time.Now()
+	err = w.wait(ctx)
+	require.ErrorContains(t, err, "container no-such-container did not start within 300ms")
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func Test_waiterReadyBeforeTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test uses sleep")
+	}
+	ctx, cancel := context.WithCancel(dlog.NewTestContext(t, false))
+	defer cancel()
+
+	cmd, err := proc.Start(ctx, nil, "sleep", "1")
+	require.NoError(t, err)
+	w := &waiter{
+		cmd:          cmd,
+		name:         "no-such-container",
+		startTimeout: 300 * time.Millisecond,
+		isReady:      func(context.Context) bool { return true },
+	}
+	assert.NoError(t, w.wait(ctx))
+}
+
+type recordingSpinner struct {
+	doneMsg string
+}
+
+func (s *recordingSpinner) Helper()               {}
+func (s *recordingSpinner) Start()                {}
+func (s *recordingSpinner) Done()                 {}
+func (s *recordingSpinner) IsNoOp() bool          { return false }
+func (s *recordingSpinner) DoneMsg(msg string)    { s.doneMsg = msg }
+func (s *recordingSpinner) Error(err error) error { return err }
+func (s *recordingSpinner) Message(string)        {}
+
+type recordingProvider []*recordingSpinner
+
+func (p *recordingProvider) New(string) spinner.Spinner {
+	s := &recordingSpinner{}
+	*p = append(*p, s)
+	return s
+}
+
+func TestRunner_printWaitMessage(t *testing.T) {
+	const msg = "Container echo is running"
+	sp := &recordingProvider{}
+	out := &bytes.Buffer{}
+	ctx := spinner.WithProvider(dlog.NewTestContext(t, false), sp)
+	ctx = dos.WithStdout(ctx, out)
+
+	// The spinner shows the message.
+	ctx1 := dos.WithEnv(ctx, dos.MapEnv{})
+	printWaitMessage(ctx1, spinner.New(ctx1, "container echo"), msg)
+	require.Len(t, *sp, 1)
+	assert.Equal(t, msg, (*sp)[0].doneMsg)
+	assert.Empty(t, out.String())
+
+	// NO_COLOR forces a no-op spinner and a plain line.
+	ctx2 := dos.WithEnv(ctx, dos.MapEnv{"NO_COLOR": "1"})
+	spin := spinner.New(ctx2, "container echo")
+	assert.True(t, spin.IsNoOp())
+	printWaitMessage(ctx2, spin, msg)
+	assert.Len(t, *sp, 1)
+	assert.Equal(t, msg+"\n", out.String())
+
+	// So does --no-spinner.
+	out.Reset()
+	ctx3 := spinner.WithPlain(ctx1)
+	printWaitMessage(ctx3, spinner.New(ctx3, "container echo"), msg)
+	assert.Len(t, *sp, 1)
+	assert.Equal(t, msg+"\n", out.String())
+}
+
+func Test_mergeRunEnv(t *testing.T) {
+	f, args, err := ParseRunFlags([]string{"-e", "DATABASE_URL=postgres://localhost/app", "--env", "HOME_DIR", "--env=MISSING", "-e", "EMPTY=", "--rm", "alpine"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--rm", "alpine"}, args)
+	assert.Equal(t, []string{"DATABASE_URL=postgres://localhost/app", "HOME_DIR", "MISSING", "EMPTY="}, f.Env)
+
+	ctx := dos.WithEnv(dlog.NewTestContext(t, false), dos.MapEnv{"HOME_DIR": "/home/me"})
+	interceptEnv := map[string]string{
+		"DATABASE_URL": "postgres://db.prod/app",
+		"HOME_DIR":     "/root",
+		"MISSING":      "from-cluster",
+		"GREETING":     "hello",
+	}
+	got := mergeRunEnv(ctx, interceptEnv, f.Env)
+	assert.Equal(t, map[string]string{
+		"DATABASE_URL": "postgres://localhost/app", // user value wins
+		"HOME_DIR":     "/home/me",                 // inherited from the host
+		"MISSING":      "from-cluster",             // not set on the host
+		"GREETING":     "hello",
+		"EMPTY":        "",
+	}, got)
+	assert.Equal(t, "postgres://db.prod/app", interceptEnv["DATABASE_URL"], "intercept environment must not be modified")
+
+	assert.Equal(t, interceptEnv, mergeRunEnv(ctx, interceptEnv, nil))
+}
+
+func Test_mergeRunEnvFile(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "theirs.env")
+	require.NoError(t, os.WriteFile(envFile, []byte(`# local overrides
+DATABASE_URL=postgres://localhost/app
+  GREETING=hi there
+HOME_DIR
+`), 0o644))
+
+	f, args, err := ParseRunFlags([]string{"--env-file", envFile, "-e", "GREETING=hello", "alpine"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpine"}, args)
+	assert.Equal(t, []string{envFile}, f.EnvFiles)
+
+	entries, err := readEnvFiles(f.EnvFiles)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DATABASE_URL=postgres://localhost/app", "GREETING=hi there", "HOME_DIR"}, entries)
+
+	ctx := dos.WithEnv(dlog.NewTestContext(t, false), dos.MapEnv{"HOME_DIR": "/home/me"})
+	got := mergeRunEnv(ctx, map[string]string{
+		"DATABASE_URL": "postgres://db.prod/app",
+		"COLOR":        "blue",
+	}, append(entries, f.Env...))
+	assert.Equal(t, map[string]string{
+		"DATABASE_URL": "postgres://localhost/app", // the user's file overrides the intercept
+		"GREETING":     "hello",                    // --env overrides --env-file
+		"HOME_DIR":     "/home/me",
+		"COLOR":        "blue",
+	}, got)
+
+	_, err = readEnvFiles([]string{filepath.Join(t.TempDir(), "missing.env")})
+	assert.Error(t, err)
+}
+
+const dockerRunHelpText = `
+Usage:  docker run [OPTIONS] IMAGE [COMMAND] [ARG...]
+
+Create and run a new container from an image
+
+Aliases:
+  docker container run, docker run
+
+Options:
+      --add-host list                    Add a custom host-to-IP mapping (host:ip)
+  -a, --attach list                      Attach to STDIN, STDOUT or STDERR
+  -c, --cpu-shares int                   CPU shares (relative weight)
+  -d, --detach                           Run container in background and print container ID
+  -e, --env list                         Set environment variables
+  -i, --interactive                      Keep STDIN open even if not attached
+      --name string                      Assign a name to the container
+  -p, --publish list                     Publish a container's port(s) to the host
+      --rm                               Automatically remove the container and its associated anonymous volumes when it exits
+  -t, --tty                              Allocate a pseudo-TTY
+      --use-api-socket                   Bind mount Docker API socket and required auth
+  -v, --volume list                      Bind mount a volume
+`
+
+func Test_parseRunHelp(t *testing.T) {
+	ro := parseRunHelp([]byte(dockerRunHelpText))
+	require.NotNil(t, ro)
+	assert.Equal(t, "acepv", ro.valueShorts)
+	assert.Equal(t, map[string]bool{
+		"--detach":         true,
+		"--interactive":    true,
+		"--rm":             true,
+		"--tty":            true,
+		"--use-api-socket": true,
+	}, ro.boolFlags)
+	assert.Nil(t, parseRunHelp([]byte("docker: command not found")))
+}
+
+func Test_firstArg(t *testing.T) {
+	ro := parseRunHelp([]byte(dockerRunHelpText))
+	tests := []struct {
+		name      string
+		args      []string
+		wantImage string
+		wantIndex int
+	}{
+		{"image only", []string{"alpine"}, "alpine", 0},
+		{"bool flags", []string{"--rm", "-it", "alpine", "sh"}, "alpine", 2},
+		{"new bool flag", []string{"--use-api-socket", "alpine"}, "alpine", 1},
+		{"value flag", []string{"--name", "x", "alpine"}, "alpine", 2},
+		{"attached value", []string{"--name=x", "alpine"}, "alpine", 1},
+		{"shorthand value", []string{"-c", "512", "alpine"}, "alpine", 2},
+		{"combined shorthand value", []string{"-itc", "512", "alpine"}, "alpine", 2},
+		{"shorthand with value in arg", []string{"-p8080:80", "alpine"}, "alpine", 1},
+		{"no image", []string{"--rm", "-e", "A=B"}, "", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, index := firstArg(tt.args, ro)
+			assert.Equal(t, tt.wantImage, image)
+			assert.Equal(t, tt.wantIndex, index)
+		})
+	}
+
+	// The fallback doesn't know about new boolean flags or the -c shorthand.
+	image, _ := firstArg([]string{"--use-api-socket", "alpine", "sh"}, fallbackRunOptions)
+	assert.Equal(t, "sh", image)
+	image, _ = firstArg([]string{"-it", "-p8080:80", "alpine"}, fallbackRunOptions)
+	assert.Equal(t, "alpine", image)
+}
+
+func TestRunner_volumeArgs(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	src := t.TempDir()
+	f := Flags{Run: true, Volumes: []string{src + ":/app/src:ro", src + ":/app/data"}}
+	require.NoError(t, f.Validate(ctx, []string{"alpine"}))
+	r := Runner{
+		Flags: f,
+		Mount: &mount.Info{LocalDir: "/tmp/tel", Mounts: []string{"/var/run/secrets"}},
+	}
+	r.NoTel2Search = true
+	assert.Equal(t, []string{
+		"-v", "/tmp/tel//var/run/secrets:/var/run/secrets",
+		"-v", src + ":/app/src:ro",
+		"-v", src + ":/app/data",
+	}, r.hostDaemonArgs())
+}
+
+func Test_parseVolume(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	require.NoError(t, os.Mkdir(src, 0o755))
+
+	v, err := parseVolume(filepath.Join(src, "..", "src") + ":/app/src:ro")
+	require.NoError(t, err)
+	assert.Equal(t, src+":/app/src:ro", v)
+
+	missing := filepath.Join(dir, "missing") + ":/app/src"
+	_, err = parseVolume(missing)
+	assert.ErrorContains(t, err, fmt.Sprintf("invalid --docker-volume %q", missing))
+
+	_, err = parseVolume(src)
+	assert.ErrorContains(t, err, "must be in the form src:dst[:ro]")
+
+	_, err = parseVolume(src + ":app")
+	assert.ErrorContains(t, err, "the destination must be an absolute path")
+
+	f := Flags{Volumes: []string{src + ":/app/src"}}
+	assert.EqualError(t, f.Validate(ctx, nil), "--docker-volume must be used together with --docker-run, --docker-build, or --docker-debug")
+}
+
+func TestFlags_Platform(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	f := Flags{Platform: "linux/amd64"}
+	assert.EqualError(t, f.Validate(ctx, nil), "--docker-platform must be used together with --docker-run, --docker-build, or --docker-debug")
+
+	f = Flags{debug: ".", Platform: "amd64"}
+	assert.ErrorContains(t, f.Validate(ctx, []string{"IMAGE"}), `invalid --docker-platform "amd64"`)
+
+	f = Flags{build: ".", Platform: "linux/arm64/v8", BuildOptions: []string{"tag=mytag"}}
+	require.NoError(t, f.Validate(ctx, []string{"IMAGE"}))
+	assert.Equal(t, []string{"--tag=mytag", "--platform", "linux/arm64/v8"}, f.buildOptions())
+
+	r := Runner{Flags: f}
+	assert.Equal(t, []string{"run", "--env-file", "tel.env", "--platform", "linux/arm64/v8"}, r.baseRunArgs("tel.env"))
+
+	r.Platform = ""
+	assert.Equal(t, []string{"run", "--env-file", "tel.env"}, r.baseRunArgs("tel.env"))
+}
+
+func TestFlags_BuildArgsAndSecrets(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	f := Flags{BuildArgs: []string{"VERSION=1.2"}}
+	assert.EqualError(t, f.Validate(ctx, nil), "--docker-build-arg must be used together with --docker-build or --docker-debug")
+
+	f = Flags{Run: true, BuildSecrets: []string{"id=npmrc"}}
+	assert.EqualError(t, f.Validate(ctx, []string{"alpine"}), "--docker-build-secret must be used together with --docker-build or --docker-debug")
+
+	f = Flags{build: ".", BuildSecrets: []string{"src=/home/me/.npmrc"}}
+	assert.ErrorContains(t, f.Validate(ctx, []string{"IMAGE"}), `invalid --docker-build-secret "src=/home/me/.npmrc"`)
+
+	f = Flags{
+		build:        ".",
+		BuildOptions: []string{"tag=mytag"},
+		BuildArgs:    []string{"VERSION=1.2", "HTTP_PROXY"},
+		BuildSecrets: []string{"id=npmrc,src=/home/me/.npmrc", "type=env,id=TOKEN"},
+	}
+	require.NoError(t, f.Validate(ctx, []string{"IMAGE"}))
+	assert.Equal(t, []string{
+		"--tag=mytag",
+		"--build-arg", "VERSION=1.2",
+		"--build-arg", "HTTP_PROXY",
+		"--secret", "id=npmrc,src=/home/me/.npmrc",
+		"--secret", "type=env,id=TOKEN",
+	}, f.buildOptions())
+}
+
+func TestFlags_RunLogFile(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	f := Flags{RunLogFile: "handler.log"}
+	assert.EqualError(t, f.Validate(ctx, nil), "--docker-run-log-file must be used together with --docker-run, --docker-build, or --docker-debug")
+
+	f = Flags{Run: true, RunLogFile: "handler.log"}
+	require.NoError(t, f.Validate(ctx, []string{"alpine"}))
+	assert.True(t, filepath.IsAbs(f.RunLogFile))
+}
+
+func Test_runLogFile(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	logFile := filepath.Join(t.TempDir(), "logs", "handler.log")
+	lf, err := openRunLogFile(ctx, logFile)
+	require.NoError(t, err)
+
+	// Simulate the output of the container as it is copied by Runner.Run.
+	var stdout, stderr bytes.Buffer
+	outRdr, outWrt := io.Pipe()
+	errRdr, errWrt := io.Pipe()
+	outDone := make(chan struct{})
+	errDone := make(chan struct{})
+	go func() {
+		defer close(outDone)
+		_, _ = io.Copy(&logTee{out: &stdout, log: lf}, outRdr)
+	}()
+	go func() {
+		defer close(errDone)
+		_, _ = io.Copy(&logTee{out: &stderr, log: lf}, errRdr)
+	}()
+	_, _ = fmt.Fprintln(outWrt, "hello from stdout")
+	_ = outWrt.Close()
+	<-outDone
+	_, _ = fmt.Fprintln(errWrt, "hello from stderr")
+	_ = errWrt.Close()
+	<-errDone
+	require.NoError(t, lf.Close())
+
+	assert.Equal(t, "hello from stdout\n", stdout.String())
+	assert.Equal(t, "hello from stderr\n", stderr.String())
+	data, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, "hello from stdout\nhello from stderr\n", string(data))
+
+	// A reopened file is appended to.
+	lf, err = openRunLogFile(ctx, logFile)
+	require.NoError(t, err)
+	_, _ = (&logTee{out: io.Discard, log: lf}).Write([]byte("more\n"))
+	require.NoError(t, lf.Close())
+	data, err = os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, "hello from stdout\nhello from stderr\nmore\n", string(data))
+}
+
+func Test_isReadyState(t *testing.T) {
+	tests := []struct {
+		name    string
+		running bool
+		health  string
+		want    bool
+	}{
+		{"not running", false, "", false},
+		{"running without health check", true, "", true},
+		{"starting", true, "starting", false},
+		{"healthy", true, "healthy", true},
+		{"unhealthy", true, "unhealthy", false},
+		{"exited while healthy", false, "healthy", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isReadyState(tt.running, tt.health))
+		})
+	}
+}
@@ -1,27 +1,142 @@
 package env
 
 import (
-	"github.com/spf13/pflag"
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
+// LocalDomain is the name of the environment variable that replaces the DNS search list of
+// resolvers that honor it, such as the one in glibc.
+const LocalDomain = "LOCALDOMAIN"
+
 type Flags struct {
 	File   string // --env-file
 	Syntax Syntax // --env-syntax
 	JSON   string // --env-json
+	Pair   string // --env-file-pair
+
+	// SyntaxSet is true when the Syntax was set explicitly, and hence must not be inferred from
+	// the extension of the File.
+	SyntaxSet bool
+
+	OverrideFile string // --env-override-file
+	overrides    map[string]string
 }
 
-func (f *Flags) AddFlags(flagSet *pflag.FlagSet) {
+// AddFlags adds the env flags to the given command, and registers the completion of their values.
+func (f *Flags) AddFlags(cmd *cobra.Command) {
+	flagSet := cmd.Flags()
 	flagSet.StringVarP(&f.File, "env-file", "e", "", ``+
 		`Also emit the remote environment to an file. The syntax used in the file can be determined using flag --env-syntax`)
 
-	flagSet.Var(&f.Syntax, "env-syntax", `Syntax used for env-file. One of `+SyntaxUsage())
+	flagSet.Var(&syntaxFlag{f}, "env-syntax", `Syntax used for env-file. One of `+SyntaxUsage()+
+		`. Inferred from a .json, .ps1, .env, or .sh extension of the env-file unless given`)
 
 	flagSet.StringVarP(&f.JSON, "env-json", "j", "", `Also emit the remote environment to a file as a JSON blob.`)
+
+	flagSet.StringVar(&f.Pair, "env-file-pair", "", ``+
+		`Also emit the remote environment to the files <basename>.env, using "compose" syntax, and <basename>.json`)
+
+	flagSet.StringVar(&f.OverrideFile, "env-override-file", "", ``+
+		`File with KEY=VALUE lines that are merged over the remote environment. A line with an empty VALUE removes the KEY`)
+
+	_ = cmd.RegisterFlagCompletionFunc("env-syntax", CompleteSyntax)
+}
+
+// Validate reads the --env-override-file, if one was given.
+func (f *Flags) Validate() error {
+	if f.OverrideFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(f.OverrideFile)
+	if err != nil {
+		return errcat.User.New(err)
+	}
+	f.overrides, err = parseOverrides(data)
+	if err != nil {
+		return errcat.User.Newf("%s: %v", f.OverrideFile, err)
+	}
+	return nil
+}
+
+// parseOverrides parses lines of KEY=VALUE pairs. Empty lines and lines starting with '#' are ignored.
+func parseOverrides(data []byte) (map[string]string, error) {
+	overrides := make(map[string]string)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for ln := 1; sc.Scan(); ln++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", ln)
+		}
+		overrides[k] = v
+	}
+	return overrides, sc.Err()
+}
+
+// ApplyOverrides merges the variables of the --env-override-file over the given environment. The
+// overrides take precedence, and a variable with an empty value is removed from the environment.
+func (f *Flags) ApplyOverrides(env map[string]string) {
+	for k, v := range f.overrides {
+		if v == "" {
+			delete(env, k)
+		} else {
+			env[k] = v
+		}
+	}
+}
+
+// syntaxFlag is the pflag.Value of the --env-syntax flag. It records that the syntax was set explicitly.
+type syntaxFlag struct {
+	*Flags
+}
+
+func (s *syntaxFlag) Set(n string) error {
+	if err := s.Syntax.Set(n); err != nil {
+		return err
+	}
+	s.SyntaxSet = true
+	return nil
+}
+
+func (s *syntaxFlag) String() string {
+	return s.Syntax.String()
+}
+
+func (s *syntaxFlag) Type() string {
+	return s.Syntax.Type()
+}
+
+// FileSyntax returns the syntax to use when writing the File. Unless the syntax was set explicitly, it is
+// inferred from the extension of the File.
+func (f *Flags) FileSyntax() Syntax {
+	if !f.SyntaxSet && f.Syntax == SyntaxDocker {
+		if s, ok := SyntaxFromFileName(f.File); ok {
+			return s
+		}
+	}
+	return f.Syntax
+}
+
+// WritesFiles returns true if the flags will make PerhapsWrite write the environment to one or more files.
+func (f *Flags) WritesFiles() bool {
+	return f.File != "" && f.File != "-" || f.JSON != "" || f.Pair != ""
 }
 
 func (f *Flags) PerhapsWrite(env map[string]string) error {
 	if f.File != "" {
-		if err := f.Syntax.writeFile(f.File, env); err != nil {
+		if err := f.FileSyntax().writeFile(f.File, env); err != nil {
 			return err
 		}
 	}
@@ -30,5 +145,13 @@ func (f *Flags) PerhapsWrite(env map[string]string) error {
 			return err
 		}
 	}
+	if f.Pair != "" {
+		if err := SyntaxCompose.writeFile(f.Pair+".env", env); err != nil {
+			return err
+		}
+		if err := SyntaxJSON.writeFile(f.Pair+".json", env); err != nil {
+			return err
+		}
+	}
 	return nil
 }
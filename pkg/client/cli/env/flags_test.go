@@ -0,0 +1,99 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlags_PerhapsWritePair(t *testing.T) {
+	base := filepath.Join(t.TempDir(), ".env.local")
+	f := Flags{Pair: base}
+	assert.True(t, f.WritesFiles())
+
+	env := map[string]string{
+		"API_URL":  "http://api.example.com:8080",
+		"GREETING": "hello world",
+		"MULTI":    "line1\nline2",
+	}
+	require.NoError(t, f.PerhapsWrite(env))
+
+	data, err := os.ReadFile(base + ".json")
+	require.NoError(t, err)
+	var fromJSON map[string]string
+	require.NoError(t, json.Unmarshal(data, &fromJSON))
+	assert.Equal(t, env, fromJSON)
+
+	data, err = os.ReadFile(base + ".env")
+	require.NoError(t, err)
+	assert.Equal(t, `API_URL=http://api.example.com:8080
+GREETING='hello world'
+MULTI="line1\nline2"
+`, string(data))
+}
+
+func TestFlags_WritesFiles(t *testing.T) {
+	assert.False(t, (&Flags{}).WritesFiles())
+	assert.False(t, (&Flags{File: "-"}).WritesFiles())
+	assert.True(t, (&Flags{File: "x.env"}).WritesFiles())
+	assert.True(t, (&Flags{JSON: "x.json"}).WritesFiles())
+}
+
+func TestFlags_FileSyntax(t *testing.T) {
+	tests := []struct {
+		file      string
+		syntax    Syntax
+		syntaxSet bool
+		want      Syntax
+	}{
+		{file: "app.json", want: SyntaxJSON},
+		{file: "app.JSON", want: SyntaxJSON},
+		{file: "app.ps1", want: SyntaxPS},
+		{file: ".env", want: SyntaxCompose},
+		{file: "app.env", want: SyntaxCompose},
+		{file: "app.sh", want: SyntaxSh},
+		{file: "app.txt", want: SyntaxDocker},
+		{file: "app", want: SyntaxDocker},
+		{file: "-", want: SyntaxDocker},
+		{file: "app.json", syntax: SyntaxDocker, syntaxSet: true, want: SyntaxDocker},
+		{file: "app.sh", syntax: SyntaxShExport, syntaxSet: true, want: SyntaxShExport},
+		{file: "app.env", syntax: SyntaxCsh, want: SyntaxCsh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file+"/"+tt.syntax.String(), func(t *testing.T) {
+			f := Flags{File: tt.file, Syntax: tt.syntax, SyntaxSet: tt.syntaxSet}
+			assert.Equal(t, tt.want, f.FileSyntax())
+		})
+	}
+}
+
+func TestFlags_SyntaxFlag(t *testing.T) {
+	var f Flags
+	cmd := &cobra.Command{Use: "test"}
+	f.AddFlags(cmd)
+	require.NoError(t, cmd.Flags().Parse([]string{"--env-file", "app.json", "--env-syntax", "sh"}))
+	assert.True(t, f.SyntaxSet)
+	assert.Equal(t, SyntaxSh, f.FileSyntax())
+
+	file := filepath.Join(t.TempDir(), "app.json")
+	f = Flags{File: file}
+	require.NoError(t, f.PerhapsWrite(map[string]string{"GREETING": "hello"}))
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"GREETING": "hello"}`, string(data))
+}
+
+func TestFlags_SyntaxCompletion(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	(&Flags{}).AddFlags(cmd)
+	complete, ok := cmd.GetFlagCompletionFunc("env-syntax")
+	require.True(t, ok)
+	names, directive := complete(cmd, nil, "")
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	assert.Equal(t, syntaxNames, names)
+}
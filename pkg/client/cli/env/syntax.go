@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
 
 	"github.com/go-json-experiment/json"
 	"github.com/go-json-experiment/json/jsontext"
+	"github.com/spf13/cobra"
 
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 	"github.com/telepresenceio/telepresence/v2/pkg/shellquote"
@@ -48,6 +50,34 @@ func SyntaxUsage() string {
 	return `"docker", "compose", "sh", "csh", "cmd", "json", and "ps"; where "sh", "csh", and "ps" can be suffixed with ":export"`
 }
 
+// SyntaxFromFileName returns the syntax that is implied by the extension of the given file name, and
+// true, or false when the extension implies no particular syntax.
+func SyntaxFromFileName(fileName string) (Syntax, bool) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return SyntaxJSON, true
+	case ".ps1":
+		return SyntaxPS, true
+	case ".env":
+		return SyntaxCompose, true
+	case ".sh":
+		return SyntaxSh, true
+	}
+	return SyntaxDocker, false
+}
+
+// CompleteSyntax is a cobra completion function that lists the valid values for the --env-syntax flag
+// that start with the given prefix.
+func CompleteSyntax(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	for _, n := range syntaxNames {
+		if strings.HasPrefix(n, toComplete) {
+			names = append(names, n)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
 // Set uses a pointer receiver intentionally, even though the internal type is int, because
 // it must change the actual receiver value.
 //
@@ -55,7 +85,7 @@ func SyntaxUsage() string {
 func (e *Syntax) Set(n string) error {
 	ex := slices.Index(syntaxNames, n)
 	if ex < 0 {
-		return fmt.Errorf("invalid env syntax: %s", n)
+		return fmt.Errorf("invalid env syntax %q, must be one of %s", n, SyntaxUsage())
 	}
 	*e = Syntax(ex)
 	return nil
@@ -63,7 +93,7 @@ func (e *Syntax) Set(n string) error {
 
 //goland:noinspection GoMixedReceiverTypes
 func (e Syntax) String() string {
-	if e >= 0 && e <= SyntaxCmd {
+	if e >= 0 && int(e) < len(syntaxNames) {
 		return syntaxNames[e]
 	}
 	return "unknown"
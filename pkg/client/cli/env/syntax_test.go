@@ -3,6 +3,8 @@ package env
 import (
 	"testing"
 
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -114,3 +116,42 @@ func TestSyntax_WriteEntry(t *testing.T) {
 		})
 	}
 }
+
+func TestSyntax_Completion(t *testing.T) {
+	names, directive := CompleteSyntax(nil, nil, "")
+	require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	require.Equal(t, syntaxNames, names)
+	for _, name := range names {
+		var s Syntax
+		require.NoError(t, s.Set(name))
+		require.Equal(t, name, s.String())
+	}
+}
+
+func TestSyntax_CompletionPrefix(t *testing.T) {
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{toComplete: "c", want: []string{"compose", "csh", "csh:export", "cmd"}},
+		{toComplete: "sh", want: []string{"sh", "sh:export"}},
+		{toComplete: "ps:", want: []string{"ps:export"}},
+		{toComplete: "json", want: []string{"json"}},
+		{toComplete: "bash", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.toComplete, func(t *testing.T) {
+			names, directive := CompleteSyntax(nil, nil, tt.toComplete)
+			assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestSyntax_SetInvalid(t *testing.T) {
+	var s Syntax
+	err := s.Set("bash")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"bash"`)
+	require.Contains(t, err.Error(), SyntaxUsage())
+}
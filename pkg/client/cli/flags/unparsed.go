@@ -26,7 +26,7 @@ func ConsumeUnparsedValue(longForm string, shortForm byte, isBool bool, args []s
 		longFlagV := longFlag + "="
 		if shortForm != 0 {
 			ixf = func(s string) bool {
-				return s == longFlag || strings.HasPrefix(s, longFlagV) || len(s) >= 2 && s[0] == '-' && s[1] != '-' && strings.IndexByte(s, shortForm) > 0
+				return s == longFlag || strings.HasPrefix(s, longFlagV) || hasShortForm(s, shortForm)
 			}
 		} else {
 			ixf = func(s string) bool {
@@ -38,7 +38,7 @@ func ConsumeUnparsedValue(longForm string, shortForm byte, isBool bool, args []s
 			return "", false, args, nil
 		}
 		ixf = func(s string) bool {
-			return len(s) >= 2 && s[0] == '-' && s[1] != '-' && strings.IndexByte(s, shortForm) > 0
+			return hasShortForm(s, shortForm)
 		}
 	}
 	flagIndex := slices.IndexFunc(args, ixf)
@@ -108,6 +108,25 @@ func ConsumeUnparsedValue(longForm string, shortForm byte, isBool bool, args []s
 	return "true", true, args, nil
 }
 
+// ConsumeAllUnparsedValues parses the given args for all occurrences of a matching option, and removes
+// them and their values from args. The values, in the order that they were found, and the possibly modified
+// args array is returned. The function may also return an error for a malformed option. Typically a
+// non-bool option that lacks a value.
+func ConsumeAllUnparsedValues(longForm string, shortForm byte, isBool bool, args []string) ([]string, []string, error) {
+	var vs []string
+	for {
+		v, found, rest, err := ConsumeUnparsedValue(longForm, shortForm, isBool, args)
+		if err != nil {
+			return nil, rest, err
+		}
+		if !found {
+			return vs, rest, nil
+		}
+		vs = append(vs, v)
+		args = rest
+	}
+}
+
 // GetUnparsedBoolean returns the value of a boolean flag that has been provided after a "--" on the command
 // line, and hence hasn't been parsed as a normal flag. Typical use case is:
 //
@@ -124,9 +143,23 @@ func GetUnparsedBoolean(args []string, flag string) (bool, bool, error) {
 	return bv, true, nil
 }
 
+// HasOption returns true if the given args contain the option with the given long form, e.g. "--tty" or
+// "--tty=true", or short form, possibly combined with other short forms, e.g. "-it". The option is
+// considered present regardless of its value.
 func HasOption(longForm string, shortForm byte, args []string) bool {
 	longFlag := "--" + longForm
+	longFlagV := longFlag + "="
 	return slices.ContainsFunc(args, func(s string) bool {
-		return s == longFlag || len(s) >= 2 && s[0] == '-' && s[1] != '-' && strings.IndexByte(s, shortForm) > 0
+		return s == longFlag || strings.HasPrefix(s, longFlagV) || hasShortForm(s, shortForm)
 	})
 }
+
+// hasShortForm returns true if the given arg is a short-form option string, such as "-xyz" or "-xyz=val",
+// that contains the given short form. A value that is attached using '=' is not considered.
+func hasShortForm(s string, shortForm byte) bool {
+	if len(s) < 2 || s[0] != '-' || s[1] == '-' {
+		return false
+	}
+	flag, _, _ := strings.Cut(s, "=")
+	return strings.IndexByte(flag, shortForm) > 0
+}
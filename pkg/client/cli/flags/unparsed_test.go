@@ -193,6 +193,105 @@ func TestConsumeUnparsedFlagValue(t *testing.T) {
 	}
 }
 
+func TestConsumeAllUnparsedValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		longForm  string
+		shortForm byte
+		isBool    bool
+		wantVs    []string
+		wantArgs  []string
+		wantErr   bool
+	}{
+		{
+			"not found",
+			[]string{"--rm", "image"},
+			"env",
+			'e',
+			false,
+			nil,
+			[]string{"--rm", "image"},
+			false,
+		},
+		{
+			"repeated short-form",
+			[]string{"-e", "A", "-e", "B", "image"},
+			"env",
+			'e',
+			false,
+			[]string{"A", "B"},
+			[]string{"image"},
+			false,
+		},
+		{
+			"mixed forms",
+			[]string{"--env=A", "--rm", "--env", "B", "-e=C", "image"},
+			"env",
+			'e',
+			false,
+			[]string{"A", "B", "C"},
+			[]string{"--rm", "image"},
+			false,
+		},
+		{
+			"short-form combined",
+			[]string{"-te", "A", "-e", "B", "image"},
+			"env",
+			'e',
+			false,
+			[]string{"A", "B"},
+			[]string{"-t", "image"},
+			false,
+		},
+		{
+			"short-form in attached value is ignored",
+			[]string{"-v=/home/me:/data", "-e", "A", "image"},
+			"env",
+			'e',
+			false,
+			[]string{"A"},
+			[]string{"-v=/home/me:/data", "image"},
+			false,
+		},
+		{
+			"repeated boolean",
+			[]string{"-ti", "-i", "image"},
+			"interactive",
+			'i',
+			true,
+			[]string{"true", "true"},
+			[]string{"-t", "image"},
+			false,
+		},
+		{
+			"missing value on second occurrence",
+			[]string{"-e", "A", "-e"},
+			"env",
+			'e',
+			false,
+			nil,
+			[]string{"-e"},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotVs, gotArgs, err := ConsumeAllUnparsedValues(tt.longForm, tt.shortForm, tt.isBool, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConsumeAllUnparsedValues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !slices.Equal(gotVs, tt.wantVs) {
+				t.Errorf("ConsumeAllUnparsedValues() values = %v, want %v", gotVs, tt.wantVs)
+			}
+			if !slices.Equal(gotArgs, tt.wantArgs) {
+				t.Errorf("ConsumeAllUnparsedValues() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
 func TestGetUnparsedFlagBoolean(t *testing.T) {
 	tests := []struct {
 		args    []string
@@ -309,3 +408,32 @@ func TestGetUnparsedFlagBoolean(t *testing.T) {
 		})
 	}
 }
+
+func TestHasOption(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		longForm  string
+		shortForm byte
+		want      bool
+	}{
+		{"long form", []string{"--tty", "alpine"}, "tty", 't', true},
+		{"long form with value", []string{"--tty=true", "alpine"}, "tty", 't', true},
+		{"long form with false value", []string{"--tty=false", "alpine"}, "tty", 't', true},
+		{"short form", []string{"-t", "alpine"}, "tty", 't', true},
+		{"combined short form", []string{"-it", "alpine"}, "tty", 't', true},
+		{"combined short form with value", []string{"-it=true", "alpine"}, "tty", 't', true},
+		{"longer long form", []string{"--tty-something", "alpine"}, "tty", 't', false},
+		{"longer long form with value", []string{"--tty-something=true", "alpine"}, "tty", 't', false},
+		{"short form in value", []string{"-v=/tmp/t:/t", "alpine"}, "tty", 't', false},
+		{"no short form", []string{"-t", "alpine"}, "tty", 0, false},
+		{"absent", []string{"--rm", "alpine"}, "tty", 't', false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasOption(tt.longForm, tt.shortForm, tt.args); got != tt.want {
+				t.Errorf("HasOption() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,19 +1,30 @@
 package ingest
 
 import (
+	"context"
 	"errors"
 	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 
+	argorollouts "github.com/datawire/argo-rollouts-go-client/pkg/client/clientset/versioned"
+	"github.com/datawire/dlib/dlog"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/docker"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/env"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/mount"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/dos"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
+	"github.com/telepresenceio/telepresence/v2/pkg/workload"
 )
 
 type Command struct {
@@ -22,29 +33,42 @@ type Command struct {
 	MountFlags      mount.Flags
 	WorkloadName    string // --workload || Command[0] // only valid if !localOnly
 	ContainerName   string // --container
+	PodName         string // --pod
 	WaitMessage     string
 	ToPod           []string // --to-pod
 	Cmdline         []string
 	FormattedOutput bool
+	List            bool // --list
 }
 
 func (c *Command) AddFlags(cmd *cobra.Command) {
 	flagSet := cmd.Flags()
-	flagSet.StringVarP(&c.ContainerName, "container", "c", "", "Name of container that provides the environment and mounts for the ingest")
+	flagSet.StringVarP(&c.ContainerName, "container", "c", "", "Name, or glob pattern matching the name, of container that provides the environment and mounts for the ingest")
+
+	flagSet.StringVar(&c.PodName, "pod", "", "Name of the pod to ingest when the workload has multiple replicas")
 
 	flagSet.StringSliceVar(&c.ToPod, "to-pod", []string{}, ``+
 		`An additional port to forward from the ingested pod, will be made available at localhost:PORT `+
 		`Use this to, for example, access proxy/helper sidecars in the ingested pod. The default protocol is TCP. `+
 		`Use <port>/UDP for UDP ports`)
 
-	c.EnvFlags.AddFlags(flagSet)
+	c.EnvFlags.AddFlags(cmd)
 	c.MountFlags.AddFlags(flagSet, true)
 	c.DockerFlags.AddFlags(flagSet, "ingested")
 	flagSet.StringVar(&c.WaitMessage, "wait-message", "", "Message to print when ingest handler has started")
+	flagSet.BoolVar(&c.List, "list", false, "List the active ingests instead of starting a new one")
 
 	_ = cmd.RegisterFlagCompletionFunc("container", AutocompleteContainer)
 }
 
+// Args validates the positional arguments. A workload is required unless --list is used.
+func (c *Command) Args(cmd *cobra.Command, args []string) error {
+	if c.List {
+		return cobra.NoArgs(cmd, args)
+	}
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
+
 func (c *Command) Validate(cmd *cobra.Command, positional []string) error {
 	if len(positional) > 1 && cmd.Flags().ArgsLenAtDash() != 1 {
 		return errcat.User.New("commands to be run with ingest must come after options")
@@ -52,16 +76,25 @@ func (c *Command) Validate(cmd *cobra.Command, positional []string) error {
 	c.WorkloadName = positional[0]
 	c.Cmdline = positional[1:]
 	c.FormattedOutput = output.WantsFormatted(cmd)
+	if err := c.EnvFlags.Validate(); err != nil {
+		return err
+	}
 	if err := c.MountFlags.Validate(cmd); err != nil {
 		return err
 	}
+	if c.MountFlags.Enabled && !c.MountFlags.ReadOnly {
+		ioutil.Println(cmd.ErrOrStderr(), "Warning: the volumes of the ingested container are mounted read-write. Writes will modify the filesystem of the live pod.")
+	}
 	if c.DockerFlags.Mount != "" && !c.MountFlags.Enabled {
 		return errors.New("--docker-mount cannot be used with --mount=false")
 	}
-	return c.DockerFlags.Validate(c.Cmdline)
+	return c.DockerFlags.Validate(cmd.Context(), c.Cmdline)
 }
 
 func (c *Command) Run(cmd *cobra.Command, positional []string) error {
+	if c.List {
+		return c.list(cmd)
+	}
 	if err := c.Validate(cmd, positional); err != nil {
 		return err
 	}
@@ -69,7 +102,7 @@ func (c *Command) Run(cmd *cobra.Command, positional []string) error {
 		return err
 	}
 	ctx := dos.WithStdio(cmd.Context(), cmd)
-	return NewState(c, c.MountFlags.ValidateConnected(ctx)).Run(ctx)
+	return NewState(c, c.MountFlags.ValidateConnected(ctx, c.WorkloadName, c.ContainerName)).Run(ctx)
 }
 
 func AutocompleteContainer(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
@@ -80,20 +113,121 @@ func AutocompleteContainer(cmd *cobra.Command, args []string, _ string) ([]strin
 	if s == nil || err != nil {
 		return nil, cobra.ShellCompDirectiveError
 	}
-	sc, err := s.GetAgentConfig(ctx, args[0])
-	if err != nil {
-		return nil, cobra.ShellCompDirectiveError
-	}
-	css := make([]string, 0, len(sc.Containers))
 	var svcName string
 	if sf := cmd.Flags().Lookup("service"); sf != nil && sf.Changed {
 		// Only include containers matching this service
 		svcName = sf.Value.String()
 	}
-	for _, c := range sc.Containers {
-		if svcName == "" || slices.ContainsFunc(c.Intercepts, func(ix *agentconfig.Intercept) bool { return ix.ServiceName == svcName }) {
+	cc := &containerCompletion{session: s, podTemplate: livePodTemplate(cmd, s)}
+	css, err := cc.containers(ctx, args[0], svcName)
+	if err != nil {
+		dlog.Debugf(ctx, "unable to complete containers for %s: %v", args[0], err)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return css, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+type agentConfigGetter interface {
+	GetAgentConfig(ctx context.Context, workload string) (*agentconfig.Sidecar, error)
+}
+
+// containerCompletion finds the names of the containers of a workload. The agent config is consulted first, and
+// when it isn't available, because the workload has never been engaged, the pod template of the live workload
+// is used instead. Pod templates are cached for the duration of the completion call.
+type containerCompletion struct {
+	session     agentConfigGetter
+	podTemplate func(ctx context.Context, workload string) (*core.PodTemplateSpec, error)
+	templates   map[string]*core.PodTemplateSpec
+}
+
+func (cc *containerCompletion) containers(ctx context.Context, wlName, svcName string) ([]string, error) {
+	sc, err := cc.session.GetAgentConfig(ctx, wlName)
+	if err == nil {
+		css := make([]string, 0, len(sc.Containers))
+		for _, c := range sc.Containers {
+			if svcName == "" || slices.ContainsFunc(c.Intercepts, func(ix *agentconfig.Intercept) bool { return ix.ServiceName == svcName }) {
+				css = append(css, c.Name)
+			}
+		}
+		return css, nil
+	}
+	dlog.Debugf(ctx, "no agent config found for %s, using the pod template of the workload: %v", wlName, err)
+
+	// The containers that a service maps to are only known by the agent config, so the
+	// service filter cannot be applied here.
+	pt, err := cc.cachedPodTemplate(ctx, wlName)
+	if err != nil {
+		return nil, err
+	}
+	css := make([]string, 0, len(pt.Spec.Containers))
+	for _, c := range pt.Spec.Containers {
+		if c.Name != agentconfig.ContainerName {
 			css = append(css, c.Name)
 		}
 	}
-	return css, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	return css, nil
+}
+
+func (cc *containerCompletion) cachedPodTemplate(ctx context.Context, wlName string) (*core.PodTemplateSpec, error) {
+	if pt, ok := cc.templates[wlName]; ok {
+		return pt, nil
+	}
+	pt, err := cc.podTemplate(ctx, wlName)
+	if err != nil {
+		return nil, err
+	}
+	if cc.templates == nil {
+		cc.templates = make(map[string]*core.PodTemplateSpec)
+	}
+	cc.templates[wlName] = pt
+	return pt, nil
+}
+
+// livePodTemplate returns a function that retrieves the pod template of a workload in the connected namespace.
+// The workload kind that the connector selected for the workload is used, so that a workload is found even
+// when workloads of different kinds share its name.
+func livePodTemplate(cmd *cobra.Command, s *daemon.Session) func(context.Context, string) (*core.PodTemplateSpec, error) {
+	return func(ctx context.Context, wlName string) (*core.PodTemplateSpec, error) {
+		ctx, kc, err := daemon.GetCommandKubeConfig(cmd)
+		if err != nil {
+			return nil, err
+		}
+		cs, err := kubernetes.NewForConfig(kc.RestConfig)
+		if err != nil {
+			return nil, err
+		}
+		acs, err := argorollouts.NewForConfig(kc.RestConfig)
+		if err != nil {
+			return nil, err
+		}
+		ns := s.Info.GetNamespace()
+		if ns == "" {
+			ns = kc.Namespace
+		}
+		var kind string
+		if r, err := s.List(ctx, &connector.ListRequest{Filter: connector.ListRequest_INTERCEPTABLE, Namespace: ns}); err == nil {
+			for _, wl := range r.Workloads {
+				if wl.Name == wlName {
+					kind = k8sKind(wl.WorkloadResourceType)
+					break
+				}
+			}
+		}
+		wl, err := k8sapi.GetWorkload(k8sapi.WithJoinedClientSetInterface(ctx, cs, acs), wlName, ns, kind)
+		if err != nil {
+			return nil, err
+		}
+		return wl.GetPodTemplate(), nil
+	}
+}
+
+// k8sKind returns the Kubernetes kind, e.g. "StatefulSet", that corresponds to the given resource type
+// of a connector.WorkloadInfo, e.g. "STATEFULSET", or an empty string if there's no such kind.
+func k8sKind(resourceType string) string {
+	for _, k := range []workload.Kind{workload.DeploymentKind, workload.ReplicaSetKind, workload.StatefulSetKind, workload.RolloutKind} {
+		if strings.EqualFold(string(k), resourceType) {
+			return string(k)
+		}
+	}
+	return ""
 }
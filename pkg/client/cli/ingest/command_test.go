@@ -0,0 +1,85 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
+)
+
+type fakeSession struct {
+	sc  *agentconfig.Sidecar
+	err error
+}
+
+func (f *fakeSession) GetAgentConfig(context.Context, string) (*agentconfig.Sidecar, error) {
+	return f.sc, f.err
+}
+
+func Test_containerCompletion(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	lookups := 0
+	podTemplate := func(_ context.Context, workload string) (*core.PodTemplateSpec, error) {
+		lookups++
+		if workload != "echo" {
+			return nil, errors.New("not found")
+		}
+		return &core.PodTemplateSpec{Spec: core.PodSpec{Containers: []core.Container{
+			{Name: "echo"},
+			{Name: "sidecar"},
+			{Name: agentconfig.ContainerName},
+		}}}, nil
+	}
+
+	t.Run("agent config", func(t *testing.T) {
+		lookups = 0
+		cc := &containerCompletion{
+			session: &fakeSession{sc: &agentconfig.Sidecar{Containers: []*agentconfig.Container{
+				{Name: "echo", Intercepts: []*agentconfig.Intercept{{ServiceName: "echo"}}},
+				{Name: "other", Intercepts: []*agentconfig.Intercept{{ServiceName: "other"}}},
+			}}},
+			podTemplate: podTemplate,
+		}
+		css, err := cc.containers(ctx, "echo", "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"echo", "other"}, css)
+
+		css, err = cc.containers(ctx, "echo", "other")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"other"}, css)
+		assert.Zero(t, lookups)
+	})
+
+	t.Run("pod template fallback", func(t *testing.T) {
+		lookups = 0
+		cc := &containerCompletion{
+			session:     &fakeSession{err: errors.New("no agent config")},
+			podTemplate: podTemplate,
+		}
+		css, err := cc.containers(ctx, "echo", "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"echo", "sidecar"}, css)
+
+		// The second lookup uses the cache.
+		css, err = cc.containers(ctx, "echo", "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"echo", "sidecar"}, css)
+		assert.Equal(t, 1, lookups)
+
+		_, err = cc.containers(ctx, "unknown", "")
+		assert.Error(t, err)
+	})
+}
+
+func Test_k8sKind(t *testing.T) {
+	assert.Equal(t, "Deployment", k8sKind("DEPLOYMENT"))
+	assert.Equal(t, "StatefulSet", k8sKind("STATEFULSET"))
+	assert.Equal(t, "Rollout", k8sKind("ROLLOUT"))
+	assert.Equal(t, "", k8sKind("UNSPECIFIED"))
+}
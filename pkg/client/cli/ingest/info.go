@@ -15,6 +15,7 @@ type Info struct {
 	Environment  map[string]string `json:"environment,omitempty"     yaml:"environment,omitempty"`
 	Mount        *mount.Info       `json:"mount,omitempty"           yaml:"mount,omitempty"`
 	PodIP        string            `json:"pod_ip,omitempty"          yaml:"pod_ip,omitempty"`
+	PodName      string            `json:"pod_name,omitempty"        yaml:"pod_name,omitempty"`
 }
 
 func NewInfo(ctx context.Context, ii *rpc.IngestInfo, mountError error) *Info {
@@ -22,13 +23,15 @@ func NewInfo(ctx context.Context, ii *rpc.IngestInfo, mountError error) *Info {
 	if mountError != nil {
 		m = &mount.Info{Error: mountError.Error()}
 	} else if ii.MountPoint != "" {
-		m = mount.NewInfo(ctx, ii.Environment, ii.FtpPort, ii.SftpPort, ii.ClientMountPoint, ii.MountPoint, ii.PodIp, true)
+		m = mount.NewInfo(ctx, ii.Environment, ii.FtpPort, ii.SftpPort, ii.ClientMountPoint, ii.MountPoint, ii.PodIp, !ii.MountWritable)
+		m.SetStatus(ii.MountStatus)
 	}
 	return &Info{
 		WorkloadKind: ii.WorkloadKind,
 		Container:    ii.Container,
 		Mount:        m,
 		PodIP:        ii.PodIp,
+		PodName:      ii.PodName,
 		Environment:  ii.Environment,
 	}
 }
@@ -41,6 +44,9 @@ func (ii *Info) WriteTo(w io.Writer) (int64, error) {
 	if m := ii.Mount; m != nil {
 		if m.LocalDir != "" {
 			kvf.Add("Volume Mount Point", m.LocalDir)
+			if st := m.Status(); st != "" {
+				kvf.Add("Volume Mount Status", st)
+			}
 		} else if m.Error != "" {
 			kvf.Add("Volume Mount Error", m.Error)
 		}
@@ -0,0 +1,55 @@
+package ingest
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+)
+
+// ListEntry describes an active ingest in the output of ingest --list.
+type ListEntry struct {
+	Workload   string `json:"workload"              yaml:"workload"`
+	Container  string `json:"container"             yaml:"container"`
+	PodIP      string `json:"pod_ip,omitempty"      yaml:"pod_ip,omitempty"`
+	MountPoint string `json:"mount_point,omitempty" yaml:"mount_point,omitempty"`
+}
+
+func (c *Command) list(cmd *cobra.Command) error {
+	if err := connect.InitCommand(cmd); err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	rsp, err := daemon.GetUserClient(ctx).ListIngests(ctx, &empty.Empty{})
+	if err != nil {
+		return err
+	}
+	entries := make([]ListEntry, len(rsp.Ingests))
+	for i, ii := range rsp.Ingests {
+		entries[i] = ListEntry{
+			Workload:   ii.Workload,
+			Container:  ii.Container,
+			PodIP:      ii.PodIp,
+			MountPoint: ii.ClientMountPoint,
+		}
+	}
+
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, entries, false)
+		return nil
+	}
+	out := output.Out(ctx)
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No active ingests")
+		return nil
+	}
+	rows := make([][]string, len(entries))
+	for i, e := range entries {
+		rows[i] = []string{e.Workload, e.Container, e.PodIP, e.MountPoint}
+	}
+	return output.Table(out, []string{"WORKLOAD", "CONTAINER", "POD IP", "MOUNT POINT"}, rows)
+}
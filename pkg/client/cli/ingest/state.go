@@ -60,9 +60,12 @@ func (s *state) CreateRequest() (*rpc.IngestRequest, error) {
 		Identifier: &rpc.IngestIdentifier{
 			WorkloadName:  s.WorkloadName,
 			ContainerName: s.ContainerName,
+			PodName:       s.PodName,
 		},
-		LocalMountPort: int32(s.MountFlags.LocalMountPort),
-		MountPoint:     s.MountFlags.Mount,
+		LocalMountPort:    int32(s.MountFlags.LocalMountPort),
+		LocalMountAddress: s.MountFlags.LocalMountAddress,
+		MountPoint:        s.MountFlags.Mount,
+		MountWritable:     !s.MountFlags.ReadOnly,
 	}
 
 	for _, toPod := range s.ToPod {
@@ -133,7 +136,7 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 	}
 	if s.MountFlags.Enabled {
 		if ir.LocalMountPort != 0 {
-			ii.PodIp = "127.0.0.1"
+			ii.PodIp = s.MountFlags.LocalMountHost()
 			ii.SftpPort = ir.LocalMountPort
 		}
 	} else {
@@ -154,6 +157,7 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 		s.info.Environment = env
 	}
 	env["TELEPRESENCE_ROOT"] = s.info.ClientMountPoint
+	s.EnvFlags.ApplyOverrides(env)
 	if err = s.EnvFlags.PerhapsWrite(env); err != nil {
 		return true, err
 	}
@@ -201,7 +205,7 @@ func (s *state) runCommand(ctx context.Context) error {
 		}
 		// The external command will not output anything to the logs. An error here
 		// is likely caused by the user hitting <ctrl>-C to terminate the process.
-		return errcat.NoDaemonLogs.New(proc.Wait(ctx, func() {}, cmd))
+		return errcat.NoDaemonLogs.New(proc.HandlerExit(proc.Wait(ctx, func() {}, cmd)))
 	}
 
 	ii := NewInfo(ctx, s.info, s.mountError)
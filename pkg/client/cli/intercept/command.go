@@ -7,8 +7,10 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/datawire/dlib/dlog"
@@ -32,15 +34,27 @@ type Command struct {
 	MountFlags    mount.Flags
 	Name          string // Command[0] || `${Command[0]}-${--namespace}` // which depends on a combinationof --workload and --namespace
 	AgentName     string // --workload || Command[0] // only valid if !localOnly
-	Port          string // --port
+	Port          string // --port, or composed from --local-port, --container-port, and --svc-port
+	LocalPort     uint16 // --local-port
+	ContainerPort uint16 // --container-port
+	SvcPort       string // --svc-port
 	ServiceName   string // --service
 	ContainerName string // --container
 	Address       string // --address
 
-	Replace bool // whether --replace was passed
+	Replace            bool // whether --replace was passed
+	KeepAgentResources bool // --keep-agent-resources
 
 	ToPod []string // --to-pod
 
+	DNSSearch []string // --dns-search
+
+	VerifyTLS bool // --verify-tls
+
+	Duration time.Duration // --duration
+
+	SaveEnvOnChange bool // --save-env-on-change
+
 	Cmdline []string // Command[1:]
 
 	Mechanism       string // --mechanism tcp
@@ -62,6 +76,14 @@ func (c *Command) AddFlags(cmd *cobra.Command) {
 		`<local port>:<container port>:<svcPortIdentifier>.`,
 	)
 
+	flagSet.Uint16Var(&c.LocalPort, "local-port", 0, ``+
+		`Local port to forward to. An alternative to the <local port> part of --port`)
+	flagSet.Uint16Var(&c.ContainerPort, "container-port", 0, ``+
+		`Port of the --docker-run container to forward to, when the daemon doesn't run in docker. `+
+		`An alternative to the <container port> part of --port`)
+	flagSet.StringVar(&c.SvcPort, "svc-port", "", ``+
+		`Name or number of the service port to intercept. An alternative to the <svcPortIdentifier> part of --port`)
+
 	flagSet.StringVar(&c.Address, "address", "127.0.0.1", ``+
 		`Local address to forward to, Only accepts IP address as a value. `+
 		`e.g. '--address 10.0.0.2'`,
@@ -70,14 +92,29 @@ func (c *Command) AddFlags(cmd *cobra.Command) {
 	flagSet.StringVar(&c.ServiceName, "service", "", "Name of service to intercept. If not provided, we will try to auto-detect one")
 
 	flagSet.StringVar(&c.ContainerName, "container", "",
-		"Name of container that provides the environment and mounts for the intercept. Defaults to the container matching the targetPort")
+		"Name, or glob pattern matching the name, of container that provides the environment and mounts for the intercept. "+
+			"Defaults to the container matching the targetPort")
 
 	flagSet.StringSliceVar(&c.ToPod, "to-pod", []string{}, ``+
 		`An additional port to forward from the intercepted pod, will be made available at localhost:PORT `+
 		`Use this to, for example, access proxy/helper sidecars in the intercepted pod. The default protocol is TCP. `+
 		`Use <port>/UDP for UDP ports`)
 
-	c.EnvFlags.AddFlags(flagSet)
+	flagSet.StringSliceVar(&c.DNSSearch, "dns-search", nil, ``+
+		`Comma separated list of DNS search entries for the intercept handler, e.g. a namespace used to resolve short names. `+
+		`Passed as --dns-search to a --docker-run container, and as LOCALDOMAIN in the environment of a local handler`)
+
+	flagSet.BoolVar(&c.VerifyTLS, "verify-tls", false, ``+
+		`Perform a TLS handshake against the intercepted container port, and report if its certificate is valid for the service`)
+
+	flagSet.DurationVar(&c.Duration, "duration", 0, ``+
+		`Leave the intercept, and stop its handler, once this duration has elapsed, e.g. '--duration 10m'`)
+
+	flagSet.BoolVar(&c.SaveEnvOnChange, "save-env-on-change", false, ``+
+		`Rewrite the --env-file, --env-json, and --env-file-pair files when the environment of the intercepted container changes. `+
+		`Without a command to run, the intercept command keeps running until interrupted`)
+
+	c.EnvFlags.AddFlags(cmd)
 	c.MountFlags.AddFlags(flagSet, false)
 	c.DockerFlags.AddFlags(flagSet, "intercepted")
 
@@ -92,6 +129,10 @@ func (c *Command) AddFlags(cmd *cobra.Command) {
 		`Indicates if the traffic-agent should replace application containers in workload pods. `+
 			`The default behavior is for the agent sidecar to be installed alongside existing containers.`)
 
+	flagSet.BoolVar(&c.KeepAgentResources, "keep-agent-resources", false, ``+
+		`Give a traffic-agent that replaces a container the configured agent resources instead of the resource `+
+		`requests and limits of the replaced container. Requires --replace`)
+
 	_ = cmd.RegisterFlagCompletionFunc("container", ingest.AutocompleteContainer)
 	_ = cmd.RegisterFlagCompletionFunc("service", autocompleteService)
 }
@@ -108,19 +149,78 @@ func (c *Command) Validate(cmd *cobra.Command, positional []string) error {
 	if c.AgentName == "" {
 		c.AgentName = c.Name
 	}
+	if c.Duration < 0 {
+		return errcat.User.New("--duration cannot be negative")
+	}
+	if c.KeepAgentResources && !c.Replace {
+		return errcat.User.New("--keep-agent-resources must be used together with --replace")
+	}
+	if c.SaveEnvOnChange && !c.EnvFlags.WritesFiles() {
+		return errcat.User.New("--save-env-on-change requires --env-file, --env-json, or --env-file-pair")
+	}
+	if err := c.EnvFlags.Validate(); err != nil {
+		return err
+	}
+	if err := c.MountFlags.Validate(cmd); err != nil {
+		return err
+	}
+	if c.DockerFlags.Mount != "" && !c.MountFlags.Enabled {
+		return errors.New("--docker-mount cannot be used with --mount=false")
+	}
+	if err := c.DockerFlags.Validate(cmd.Context(), c.Cmdline); err != nil {
+		return err
+	}
+	if err := c.resolvePortFlags(cmd.Flags()); err != nil {
+		return err
+	}
 	if c.Port == "" {
 		// Port defaults to the targeted container port unless a default is explicitly set in the client config.
 		if dp := client.GetConfig(cmd.Context()).Intercept().DefaultPort; dp != 0 {
 			c.Port = strconv.Itoa(dp)
 		}
 	}
-	if err := c.MountFlags.Validate(cmd); err != nil {
-		return err
+	return nil
+}
+
+// resolvePortFlags composes the --port spec from the --local-port, --container-port, and --svc-port flags when
+// any of them are used. An error is returned if they are mixed with --port.
+func (c *Command) resolvePortFlags(flags *pflag.FlagSet) error {
+	var used []string
+	for _, f := range []string{"local-port", "container-port", "svc-port"} {
+		if flags.Changed(f) {
+			used = append(used, "--"+f)
+		}
 	}
-	if c.DockerFlags.Mount != "" && !c.MountFlags.Enabled {
-		return errors.New("--docker-mount cannot be used with --mount=false")
+	if len(used) == 0 {
+		return nil
+	}
+	if flags.Changed("port") {
+		return errcat.User.Newf("--port cannot be combined with %s", strings.Join(used, ", "))
+	}
+	if c.ContainerPort != 0 && !c.DockerFlags.Run {
+		return errcat.User.New("--container-port must be used together with --docker-run, --docker-build, or --docker-debug")
+	}
+	var sb strings.Builder
+	if c.LocalPort != 0 {
+		sb.WriteString(strconv.Itoa(int(c.LocalPort)))
+	}
+	switch {
+	case c.DockerFlags.Run && (c.ContainerPort != 0 || c.SvcPort != ""):
+		// The spec always has three parts when the container port is given with --docker-run, so that neither
+		// port is mistaken for the other. An empty container port means that it's the same as the local port,
+		// and an empty svc port means that the default port is used.
+		sb.WriteByte(':')
+		if c.ContainerPort != 0 {
+			sb.WriteString(strconv.Itoa(int(c.ContainerPort)))
+		}
+		sb.WriteByte(':')
+		sb.WriteString(c.SvcPort)
+	case c.SvcPort != "":
+		sb.WriteByte(':')
+		sb.WriteString(c.SvcPort)
 	}
-	return c.DockerFlags.Validate(c.Cmdline)
+	c.Port = sb.String()
+	return nil
 }
 
 func (c *Command) Run(cmd *cobra.Command, positional []string) error {
@@ -131,7 +231,7 @@ func (c *Command) Run(cmd *cobra.Command, positional []string) error {
 		return err
 	}
 	ctx := dos.WithStdio(cmd.Context(), cmd)
-	_, err := NewState(c, c.MountFlags.ValidateConnected(ctx)).Run(ctx)
+	_, err := NewState(c, c.MountFlags.ValidateConnected(ctx, c.AgentName, c.ContainerName)).Run(ctx)
 	return err
 }
 
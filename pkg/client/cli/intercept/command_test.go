@@ -0,0 +1,110 @@
+package intercept
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_resolvePortFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		dockerRun  bool
+		wantSpec   string
+		wantLocal  uint16
+		wantDocker uint16
+		wantSvc    string
+		wantErr    string
+
+		// wantContainerizedErr is the error expected when the spec is parsed for a containerized daemon.
+		wantContainerizedErr string
+	}{
+		{
+			name:      "local port",
+			args:      []string{"--local-port", "8080"},
+			wantSpec:  "8080",
+			wantLocal: 8080,
+		},
+		{
+			name:      "local and svc port",
+			args:      []string{"--local-port", "8080", "--svc-port", "http"},
+			wantSpec:  "8080:http",
+			wantLocal: 8080,
+			wantSvc:   "http",
+		},
+		{
+			name:                 "docker-run with local and container port",
+			args:                 []string{"--local-port", "8080", "--container-port", "9090"},
+			dockerRun:            true,
+			wantSpec:             "8080:9090:",
+			wantLocal:            8080,
+			wantDocker:           9090,
+			wantContainerizedErr: "cannot be used when the daemon runs in a container",
+		},
+		{
+			name:       "all three",
+			args:       []string{"--local-port", "8080", "--container-port", "9090", "--svc-port", "80"},
+			dockerRun:  true,
+			wantSpec:   "8080:9090:80",
+			wantLocal:  8080,
+			wantDocker: 9090,
+			wantSvc:    "80",
+		},
+		{
+			name:       "docker-run with local and svc port",
+			args:       []string{"--local-port", "8080", "--svc-port", "http"},
+			dockerRun:  true,
+			wantSpec:   "8080::http",
+			wantLocal:  8080,
+			wantDocker: 8080,
+			wantSvc:    "http",
+		},
+		{
+			name:      "only --port",
+			args:      []string{"--port", "8080:http"},
+			wantSpec:  "8080:http",
+			wantLocal: 8080,
+			wantSvc:   "http",
+		},
+		{
+			name:    "mixed with --port",
+			args:    []string{"--port", "8080", "--svc-port", "http"},
+			wantErr: "--port cannot be combined with --svc-port",
+		},
+		{
+			name:    "container port without docker-run",
+			args:    []string{"--local-port", "8080", "--container-port", "9090"},
+			wantErr: "--container-port must be used together with --docker-run",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Command{}
+			cmd := &cobra.Command{}
+			c.AddFlags(cmd)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+			c.DockerFlags.Run = tt.dockerRun
+			err := c.resolvePortFlags(cmd.Flags())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSpec, c.Port)
+
+			local, docker, svc, err := parsePort(c.Port, tt.dockerRun, false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLocal, local)
+			assert.Equal(t, tt.wantDocker, docker)
+			assert.Equal(t, tt.wantSvc, svc)
+
+			if tt.wantContainerizedErr != "" {
+				_, _, _, err = parsePort(c.Port, tt.dockerRun, true)
+				assert.ErrorContains(t, err, tt.wantContainerizedErr)
+			}
+		})
+	}
+}
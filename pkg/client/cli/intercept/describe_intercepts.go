@@ -9,13 +9,22 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ingest"
 )
 
-func DescribeIntercepts(ctx context.Context, iis []*manager.InterceptInfo, igs []*rpc.IngestInfo, volumeMountsPrevented error, debug bool) string {
+// DescribeIntercepts describes the given intercepts and ingests. The mountStatuses map contains the status of the
+// intercepts' remote mounts, keyed by intercept id.
+func DescribeIntercepts(
+	ctx context.Context,
+	iis []*manager.InterceptInfo,
+	mountStatuses map[string]*rpc.MountStatus,
+	igs []*rpc.IngestInfo,
+	volumeMountsPrevented error,
+	debug bool,
+) string {
 	sb := strings.Builder{}
 	if len(iis) > 0 {
 		sb.WriteString("intercepted")
 		for _, ii := range iis {
 			sb.WriteByte('\n')
-			describeIntercept(ctx, ii, volumeMountsPrevented, debug, &sb)
+			describeIntercept(ctx, ii, mountStatuses[ii.Id], volumeMountsPrevented, debug, &sb)
 		}
 	}
 	if len(igs) > 0 {
@@ -28,8 +37,11 @@ func DescribeIntercepts(ctx context.Context, iis []*manager.InterceptInfo, igs [
 	return sb.String()
 }
 
-func describeIntercept(ctx context.Context, ii *manager.InterceptInfo, volumeMountsPrevented error, debug bool, sb *strings.Builder) {
+func describeIntercept(ctx context.Context, ii *manager.InterceptInfo, ms *rpc.MountStatus, volumeMountsPrevented error, debug bool, sb *strings.Builder) {
 	info := NewInfo(ctx, ii, false, volumeMountsPrevented)
+	if m := info.Mount; m != nil && m.LocalDir != "" {
+		m.SetStatus(ms)
+	}
 	info.debug = debug
 	_, _ = info.WriteTo(sb)
 }
@@ -41,6 +41,7 @@ type Info struct {
 	PreviewURL    string            `json:"preview_url,omitempty"     yaml:"preview_url,omitempty"`
 	Ingress       *Ingress          `json:"ingress,omitempty"         yaml:"ingress,omitempty"`
 	PodIP         string            `json:"pod_ip,omitempty"          yaml:"pod_ip,omitempty"`
+	ServiceTLS    *TLSVerification  `json:"service_tls,omitempty"     yaml:"service_tls,omitempty"`
 	debug         bool
 }
 
@@ -149,6 +150,9 @@ func (ii *Info) WriteTo(w io.Writer) (int64, error) {
 	if m := ii.Mount; m != nil {
 		if m.LocalDir != "" {
 			kvf.Add("Volume Mount Point", m.LocalDir)
+			if st := m.Status(); st != "" {
+				kvf.Add("Volume Mount Status", st)
+			}
 		} else if m.Error != "" {
 			kvf.Add("Volume Mount Error", m.Error)
 		}
@@ -166,6 +170,9 @@ func (ii *Info) WriteTo(w io.Writer) (int64, error) {
 	if ii.ServiceUID == "" {
 		kvf.Add("Address", iputil.JoinHostPort(ii.PodIP, uint16(ii.ContainerPort)))
 	}
+	if ii.ServiceTLS != nil {
+		kvf.Add("Service TLS", ii.ServiceTLS.String())
+	}
 
 	if ii.PreviewURL != "" {
 		previewURL := ii.PreviewURL
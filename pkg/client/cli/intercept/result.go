@@ -66,9 +66,11 @@ func Result(r *connector.InterceptResult, err error) error {
 	case common.InterceptError_NOT_FOUND:
 		msg = fmt.Sprintf("Intercept named %q not found", r.ErrorText)
 	case common.InterceptError_MOUNT_POINT_BUSY:
-		msg = fmt.Sprintf("Mount point already in use by intercept %q", r.ErrorText)
+		msg = r.ErrorText
 	case common.InterceptError_MISCONFIGURED_WORKLOAD:
 		msg = r.ErrorText
+	case common.InterceptError_PERMISSION_DENIED:
+		msg = r.ErrorText
 	case common.InterceptError_UNKNOWN_FLAG:
 		msg = fmt.Sprintf("Unknown flag: %s", r.ErrorText)
 	default:
@@ -2,14 +2,20 @@ package intercept
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/netip"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	grpcCodes "google.golang.org/grpc/codes"
 	grpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/datawire/dlib/dlog"
@@ -19,6 +25,7 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
 	cliDocker "github.com/telepresenceio/telepresence/v2/pkg/client/cli/docker"
+	cliEnv "github.com/telepresenceio/telepresence/v2/pkg/client/cli/env"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/scout"
@@ -45,6 +52,8 @@ type state struct {
 	info             *Info // Info from the created intercept
 	mountError       error
 	handlerContainer string
+	leaveDeadline    time.Time // when the intercept will be removed due to --duration
+	namespace        string    // namespace of the created intercept
 
 	// Possibly extended version of the state. Use when calling interface methods.
 	self State
@@ -68,15 +77,22 @@ func (s *state) SetSelf(self State) {
 
 func (s *state) CreateRequest(ctx context.Context) (*connector.CreateInterceptRequest, error) {
 	spec := &manager.InterceptSpec{
-		Name:    s.Name(),
-		Replace: s.Replace,
+		Name:               s.Name(),
+		Replace:            s.Replace,
+		KeepAgentResources: s.KeepAgentResources,
 	}
 	ir := &connector.CreateInterceptRequest{
-		Spec:           spec,
-		ExtendedInfo:   s.ExtendedInfo,
-		LocalMountPort: int32(s.MountFlags.LocalMountPort),
-		MountPoint:     s.MountFlags.Mount,
-		MountReadOnly:  s.MountFlags.ReadOnly,
+		Spec:              spec,
+		ExtendedInfo:      s.ExtendedInfo,
+		LocalMountPort:    int32(s.MountFlags.LocalMountPort),
+		LocalMountAddress: s.MountFlags.LocalMountAddress,
+		MountPoint:        s.MountFlags.Mount,
+		MountReadOnly:     s.MountFlags.ReadOnly,
+	}
+	if s.Duration > 0 {
+		ir.Duration = durationpb.New(s.Duration)
+		// The daemon starts its timer once the intercept is active, so this is never later than the actual removal.
+		s.leaveDeadline = time.Now().Add(s.Duration)
 	}
 
 	spec.ServiceName = s.ServiceName
@@ -130,6 +146,15 @@ func (s *state) Run(ctx context.Context) (*Info, error) {
 		if err != nil {
 			return nil, err
 		}
+		if s.SaveEnvOnChange {
+			// Keep running until interrupted. The intercept remains when this command ends.
+			if !s.Silent {
+				ioutil.Printf(dos.Stdout(ctx), "Watching for environment changes of intercept %s. Press Ctrl-C to stop\n", s.Name())
+			}
+			if err = s.saveEnvOnChange(ctx); err != nil {
+				return nil, err
+			}
+		}
 		return s.info, nil
 	}
 
@@ -200,6 +225,11 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 	if !s.Silent && !detailedOutput {
 		ioutil.Printf(dos.Stdout(ctx), "Using %s %s\n", r.WorkloadKind, s.AgentName)
 	}
+	if !s.Silent {
+		for _, w := range r.Warnings {
+			ioutil.Printf(dos.Stderr(ctx), "Warning: %s\n", w)
+		}
+	}
 	var intercept *manager.InterceptInfo
 
 	// Add metadata to scout from InterceptResult
@@ -213,19 +243,18 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 	intercept = r.InterceptInfo
 	scout.SetMetadatum(ctx, "intercept_id", intercept.Id)
 
-	s.env = intercept.Environment
-	if s.env == nil {
-		s.env = make(map[string]string)
-	}
-	s.env["TELEPRESENCE_INTERCEPT_ID"] = intercept.Id
-	s.env["TELEPRESENCE_ROOT"] = intercept.ClientMountPoint
+	s.namespace = intercept.Spec.Namespace
+	s.env = s.interceptEnv(intercept)
+	intercept.Environment = s.env
 	if err = s.EnvFlags.PerhapsWrite(s.env); err != nil {
 		return true, err
 	}
 
+	// The pod IP is replaced with the local mount host below.
+	podIP := intercept.PodIp
 	if s.MountFlags.Enabled {
 		if ir.LocalMountPort != 0 {
-			intercept.PodIp = "127.0.0.1"
+			intercept.PodIp = s.MountFlags.LocalMountHost()
 			intercept.SftpPort = ir.LocalMountPort
 		}
 	} else {
@@ -235,6 +264,9 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 	}
 
 	s.info = NewInfo(ctx, intercept, s.MountFlags.ReadOnly, s.mountError)
+	if s.VerifyTLS {
+		s.info.ServiceTLS = s.verifyServiceTLS(ctx, podIP, intercept.Spec)
+	}
 	if !s.Silent {
 		if detailedOutput {
 			output.Object(ctx, s.info, true)
@@ -247,6 +279,23 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 	return true, nil
 }
 
+// verifyServiceTLS verifies the TLS certificate that the intercepted container presents on its intercepted port. The
+// container is dialed directly, because traffic to the service is routed to the intercept handler.
+func (s *state) verifyServiceTLS(ctx context.Context, podIP string, spec *manager.InterceptSpec) *TLSVerification {
+	if podIP == "" || spec.ContainerPort == 0 {
+		dlog.Debugf(ctx, "intercept %s has no container address, so there's no TLS to verify", s.Name())
+		return nil
+	}
+	if spec.Replace {
+		return &TLSVerification{Error: "the intercepted container is replaced"}
+	}
+	name := spec.ServiceName
+	if name == "" {
+		name = spec.Agent
+	}
+	return VerifyTLS(ctx, iputil.JoinHostPort(podIP, uint16(spec.ContainerPort)), serviceNames(name, spec.Namespace), nil)
+}
+
 func (s *state) leave(ctx context.Context) error {
 	n := strings.TrimSpace(s.Name())
 	dlog.Debugf(ctx, "Leaving intercept %s", n)
@@ -261,10 +310,100 @@ func (s *state) leave(ctx context.Context) error {
 	return Result(r, err)
 }
 
+// interceptEnv returns the environment of the given intercept, extended with the variables that
+// Telepresence adds and with the overrides of the env flags. The same environment is written to the
+// env files when the intercept is created and when its environment changes.
+func (s *state) interceptEnv(ii *manager.InterceptInfo) map[string]string {
+	env := maps.Clone(ii.Environment)
+	if env == nil {
+		env = make(map[string]string)
+	}
+	env["TELEPRESENCE_INTERCEPT_ID"] = ii.Id
+	env["TELEPRESENCE_ROOT"] = ii.ClientMountPoint
+	if s.handlerContainer != "" {
+		env["TELEPRESENCE_HANDLER_CONTAINER_NAME"] = s.handlerContainer
+	}
+	s.EnvFlags.ApplyOverrides(env)
+	return env
+}
+
+// saveEnvOnChange rewrites the env files each time the environment of the intercept changes. It returns
+// when the context is cancelled or the intercept ends.
+func (s *state) saveEnvOnChange(ctx context.Context) error {
+	stream, err := daemon.GetUserClient(ctx).WatchWorkloads(ctx, &connector.WatchWorkloadsRequest{Namespaces: []string{s.namespace}})
+	if err != nil {
+		return err
+	}
+	return s.handleEnvUpdates(ctx, stream.Recv)
+}
+
+func (s *state) handleEnvUpdates(ctx context.Context, recv func() (*connector.WorkloadInfoSnapshot, error)) error {
+	for {
+		ws, err := recv()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		var ii *manager.InterceptInfo
+		for _, wl := range ws.Workloads {
+			if i := slices.IndexFunc(wl.InterceptInfos, func(ii *manager.InterceptInfo) bool { return ii.Spec.Name == s.Name() }); i >= 0 {
+				ii = wl.InterceptInfos[i]
+				break
+			}
+		}
+		if ii == nil {
+			dlog.Debugf(ctx, "Intercept %s ended, no longer watching its environment", s.Name())
+			return nil
+		}
+		env := s.interceptEnv(ii)
+		if maps.Equal(env, s.env) {
+			continue
+		}
+		dlog.Infof(ctx, "Environment of intercept %s changed, rewriting env files", s.Name())
+		s.env = env
+		if err = s.EnvFlags.PerhapsWrite(env); err != nil {
+			return err
+		}
+	}
+}
+
 func (s *state) runCommand(ctx context.Context) error {
+	if s.SaveEnvOnChange {
+		wCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			if err := s.saveEnvOnChange(wCtx); err != nil {
+				dlog.Errorf(ctx, "failed to save environment on change: %v", err)
+			}
+		}()
+	}
+	err := s.runHandler(ctx)
+	if s.stoppedByDuration(err) {
+		dlog.Debugf(ctx, "Intercept handler ended when the intercept duration elapsed: %v", err)
+		return nil
+	}
+	return err
+}
+
+// stoppedByDuration returns true if the given handler error is caused by the daemon stopping the handler, or by the
+// cancellation of the command, after the --duration elapsed.
+func (s *state) stoppedByDuration(err error) bool {
+	if err == nil || s.leaveDeadline.IsZero() || time.Now().Before(s.leaveDeadline) {
+		return false
+	}
+	return errors.Is(err, context.Canceled) || proc.Terminated(err)
+}
+
+func (s *state) runHandler(ctx context.Context) error {
 	// start the interceptor process
 	if !s.DockerFlags.Run {
 		env := s.info.Environment
+		if len(s.DNSSearch) > 0 {
+			env = maps.Clone(env)
+			env[cliEnv.LocalDomain] = strings.Join(s.DNSSearch, " ")
+		}
 		cmd, err := proc.Start(ctx, env, s.Cmdline[0], s.Cmdline[1:]...)
 		if err != nil {
 			dlog.Errorf(ctx, "error interceptor starting process: %v", err)
@@ -275,7 +414,7 @@ func (s *state) runCommand(ctx context.Context) error {
 		}
 		// The external command will not output anything to the logs. An error here
 		// is likely caused by the user hitting <ctrl>-C to terminate the process.
-		return errcat.NoDaemonLogs.New(proc.Wait(ctx, func() {}, cmd))
+		return errcat.NoDaemonLogs.New(proc.HandlerExit(proc.Wait(ctx, func() {}, cmd)))
 	}
 
 	dr := cliDocker.Runner{
@@ -283,6 +422,7 @@ func (s *state) runCommand(ctx context.Context) error {
 		ContainerName: s.handlerContainer,
 		Environment:   s.info.Environment,
 		Mount:         s.info.Mount,
+		DNSSearch:     s.DNSSearch,
 	}
 	if s.dockerPort != 0 {
 		dr.Flags.PublishedPorts = append(dr.Flags.PublishedPorts, cliDocker.PublishedPort{
@@ -329,19 +469,25 @@ func parsePort(portSpec string, dockerRun, containerized bool) (local uint16, do
 			}
 		}
 	case 3:
-		if containerized && dockerRun {
-			return 0, 0, "", errcat.User.New(
-				"the format --port <local-port>:<container-port>:<svcPortIdentifier> cannot be used when the daemon runs in a container")
-		}
 		if !dockerRun {
 			return portError()
 		}
-		if docker, err = agentconfig.ParseNumericPort(portMapping[1]); err != nil {
-			return portError()
+		// An empty container port means that it's the same as the local port.
+		if p := portMapping[1]; p != "" {
+			if containerized {
+				return 0, 0, "", errcat.User.New(
+					"the format --port <local-port>:<container-port>:<svcPortIdentifier> cannot be used when the daemon runs in a container")
+			}
+			if docker, err = agentconfig.ParseNumericPort(p); err != nil {
+				return portError()
+			}
 		}
-		svcPortId = portMapping[2]
-		if err := agentconfig.ValidatePort(svcPortId); err != nil {
-			return portError()
+		// An empty svc port identifier means that the default port is used.
+		if p := portMapping[2]; p != "" {
+			if err := agentconfig.ValidatePort(p); err != nil {
+				return portError()
+			}
+			svcPortId = p
 		}
 	default:
 		return portError()
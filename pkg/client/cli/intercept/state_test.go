@@ -0,0 +1,173 @@
+package intercept
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/env"
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+)
+
+func Test_handleEnvUpdates(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "app.env")
+	jsonFile := filepath.Join(dir, "app.json")
+
+	snapshot := func(appEnv map[string]string) *connector.WorkloadInfoSnapshot {
+		return &connector.WorkloadInfoSnapshot{Workloads: []*connector.WorkloadInfo{
+			{Name: "other"},
+			{
+				Name: "app",
+				InterceptInfos: []*manager.InterceptInfo{{
+					Id:               "session:app",
+					Spec:             &manager.InterceptSpec{Name: "app"},
+					ClientMountPoint: "/tmp/app",
+					Environment:      appEnv,
+				}},
+			},
+		}}
+	}
+	readJSON := func() (m map[string]string) {
+		data, err := os.ReadFile(jsonFile)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &m))
+		return m
+	}
+
+	s := &state{
+		Command: &Command{
+			Name:            "app",
+			EnvFlags:        env.Flags{File: envFile, JSON: jsonFile},
+			SaveEnvOnChange: true,
+		},
+		handlerContainer: "intercept-app-8080",
+	}
+	s.env = s.interceptEnv(snapshot(map[string]string{"GREETING": "hello"}).Workloads[1].InterceptInfos[0])
+	require.NoError(t, s.EnvFlags.PerhapsWrite(s.env))
+	assert.Equal(t, "hello", readJSON()["GREETING"])
+
+	snapshots := []*connector.WorkloadInfoSnapshot{
+		snapshot(map[string]string{"GREETING": "hello"}),
+		snapshot(map[string]string{"GREETING": "hi", "COLOR": "blue"}),
+	}
+	recv := func() (*connector.WorkloadInfoSnapshot, error) {
+		if len(snapshots) == 0 {
+			return nil, io.EOF
+		}
+		ws := snapshots[0]
+		snapshots = snapshots[1:]
+		return ws, nil
+	}
+	require.NoError(t, s.handleEnvUpdates(ctx, recv))
+
+	m := readJSON()
+	assert.Equal(t, "hi", m["GREETING"])
+	assert.Equal(t, "blue", m["COLOR"])
+	assert.Equal(t, "session:app", m["TELEPRESENCE_INTERCEPT_ID"])
+	assert.Equal(t, "/tmp/app", m["TELEPRESENCE_ROOT"])
+	assert.Equal(t, "intercept-app-8080", m["TELEPRESENCE_HANDLER_CONTAINER_NAME"])
+
+	data, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "GREETING=hi")
+	assert.Contains(t, string(data), "COLOR=blue")
+
+	// The watch ends when the intercept is gone.
+	snapshots = []*connector.WorkloadInfoSnapshot{{Workloads: []*connector.WorkloadInfo{{Name: "app"}}}, snapshot(nil)}
+	require.NoError(t, s.handleEnvUpdates(ctx, recv))
+	assert.Len(t, snapshots, 1)
+}
+
+func Test_handleEnvUpdatesOverride(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "app.env")
+	overrideFile := filepath.Join(dir, "override.env")
+	require.NoError(t, os.WriteFile(overrideFile, []byte(`# Use the local database
+DATABASE_URL=postgres://localhost:5432/app
+DEBUG=true
+SECRET=
+`), 0o644))
+
+	s := &state{Command: &Command{
+		Name:     "app",
+		EnvFlags: env.Flags{File: envFile, OverrideFile: overrideFile},
+	}}
+	require.NoError(t, s.EnvFlags.Validate())
+
+	snapshots := []*connector.WorkloadInfoSnapshot{{Workloads: []*connector.WorkloadInfo{{
+		Name: "app",
+		InterceptInfos: []*manager.InterceptInfo{{
+			Id:   "session:app",
+			Spec: &manager.InterceptSpec{Name: "app"},
+			Environment: map[string]string{
+				"DATABASE_URL": "postgres://db.prod:5432/app",
+				"GREETING":     "hello",
+				"SECRET":       "s3cr3t",
+			},
+		}},
+	}}}}
+	recv := func() (*connector.WorkloadInfoSnapshot, error) {
+		if len(snapshots) == 0 {
+			return nil, io.EOF
+		}
+		ws := snapshots[0]
+		snapshots = snapshots[1:]
+		return ws, nil
+	}
+	require.NoError(t, s.handleEnvUpdates(ctx, recv))
+
+	data, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "DATABASE_URL=postgres://localhost:5432/app\n")
+	assert.Contains(t, string(data), "DEBUG=true\n")
+	assert.Contains(t, string(data), "GREETING=hello\n")
+	assert.NotContains(t, string(data), "SECRET")
+}
+
+func Test_stoppedByDuration(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	ctx := dlog.NewTestContext(t, false)
+	run := func(script string) error {
+		cmd, err := proc.Start(ctx, nil, "sh", "-c", script)
+		require.NoError(t, err)
+		return proc.HandlerExit(proc.Wait(ctx, func() {}, cmd))
+	}
+	terminated := run("kill -TERM $$")
+	require.Error(t, terminated)
+	failed := run("exit 3")
+	require.Error(t, failed)
+	stopped := run("exit 143")
+	require.Error(t, stopped)
+
+	s := &state{}
+	assert.False(t, s.stoppedByDuration(terminated), "no --duration")
+
+	s.leaveDeadline = time.Now().Add(time.Hour)
+	assert.False(t, s.stoppedByDuration(terminated), "before the deadline")
+
+	s.leaveDeadline = time.Now().Add(-time.Second)
+	assert.True(t, s.stoppedByDuration(terminated))
+	assert.True(t, s.stoppedByDuration(stopped), "docker run of a stopped container")
+	assert.True(t, s.stoppedByDuration(fmt.Errorf("handler: %w", context.Canceled)))
+	assert.False(t, s.stoppedByDuration(nil))
+	assert.False(t, s.stoppedByDuration(failed), "a handler that fails on its own")
+	assert.False(t, s.stoppedByDuration(errors.New("unable to start")))
+}
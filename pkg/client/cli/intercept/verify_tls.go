@@ -0,0 +1,94 @@
+package intercept
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TLSVerification is the result of a TLS handshake against the intercepted service.
+type TLSVerification struct {
+	Valid    bool      `json:"valid"               yaml:"valid"`
+	Subject  string    `json:"subject,omitempty"   yaml:"subject,omitempty"`
+	Issuer   string    `json:"issuer,omitempty"    yaml:"issuer,omitempty"`
+	NotAfter time.Time `json:"not_after,omitempty" yaml:"not_after,omitempty"`
+	Error    string    `json:"error,omitempty"     yaml:"error,omitempty"`
+}
+
+func (v *TLSVerification) String() string {
+	if v.Valid {
+		return "valid, issued by " + v.Issuer + ", expires " + v.NotAfter.Format(time.RFC3339)
+	}
+	return "invalid: " + v.Error
+}
+
+const verifyTLSTimeout = 10 * time.Second
+
+// serviceNames returns the DNS names that a certificate of the given service can be issued for.
+func serviceNames(service, namespace string) []string {
+	return []string{
+		service,
+		service + "." + namespace,
+		service + "." + namespace + ".svc",
+		service + "." + namespace + ".svc.cluster.local",
+	}
+}
+
+// VerifyTLS performs a TLS handshake against the given address and verifies that the certificate chain that it
+// presents is valid for at least one of the given DNS names. The system's root CAs are used when rootCAs is nil.
+func VerifyTLS(ctx context.Context, addr string, names []string, rootCAs *x509.CertPool) *TLSVerification {
+	ctx, cancel := context.WithTimeout(ctx, verifyTLSTimeout)
+	defer cancel()
+
+	// The address is typically a pod IP, so the chain is verified below, using the names of the service.
+	dialer := tls.Dialer{Config: &tls.Config{
+		ServerName:         names[0],
+		InsecureSkipVerify: true, //nolint:gosec // verified by verifyChain
+		MinVersion:         tls.VersionTLS12,
+	}}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return &TLSVerification{Error: err.Error()}
+	}
+	defer conn.Close()
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	leaf := certs[0]
+	v := &TLSVerification{
+		Subject:  leaf.Subject.String(),
+		Issuer:   leaf.Issuer.String(),
+		NotAfter: leaf.NotAfter,
+	}
+	if err = verifyChain(certs, names, rootCAs); err != nil {
+		v.Error = err.Error()
+	} else {
+		v.Valid = true
+	}
+	return v
+}
+
+func verifyChain(certs []*x509.Certificate, names []string, rootCAs *x509.CertPool) error {
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	for _, name := range names {
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			DNSName:       name,
+			Roots:         rootCAs,
+			Intermediates: intermediates,
+		})
+		if err == nil {
+			return nil
+		}
+		var he x509.HostnameError
+		if !errors.As(err, &he) {
+			// The chain itself is invalid, so trying other names is pointless.
+			return err
+		}
+	}
+	return fmt.Errorf("certificate is not valid for any of the names %s", strings.Join(names, ", "))
+}
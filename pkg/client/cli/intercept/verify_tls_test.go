@@ -0,0 +1,52 @@
+package intercept
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	addr := strings.TrimPrefix(srv.URL, "https://")
+	trusted := x509.NewCertPool()
+	trusted.AddCert(srv.Certificate())
+
+	t.Run("self-signed", func(t *testing.T) {
+		v := VerifyTLS(ctx, addr, []string{"example.com"}, x509.NewCertPool())
+		assert.False(t, v.Valid)
+		assert.Contains(t, v.Error, "certificate signed by unknown authority")
+		assert.Equal(t, srv.Certificate().NotAfter, v.NotAfter)
+	})
+
+	t.Run("trusted", func(t *testing.T) {
+		// The certificate of the test server is issued for example.com.
+		v := VerifyTLS(ctx, addr, serviceNames("example", "com"), trusted)
+		assert.True(t, v.Valid, v.Error)
+		assert.Equal(t, srv.Certificate().Subject.String(), v.Subject)
+		assert.Empty(t, v.Error)
+	})
+
+	t.Run("other service", func(t *testing.T) {
+		v := VerifyTLS(ctx, addr, serviceNames("echo", "default"), trusted)
+		assert.False(t, v.Valid)
+		assert.Contains(t, v.Error, "not valid for any of the names echo, echo.default")
+	})
+
+	t.Run("not TLS", func(t *testing.T) {
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+		defer plain.Close()
+		v := VerifyTLS(ctx, strings.TrimPrefix(plain.URL, "http://"), []string{"example.com"}, trusted)
+		assert.False(t, v.Valid)
+		assert.NotEmpty(t, v.Error)
+	})
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"slices"
@@ -40,6 +41,10 @@ func InitContext(ctx context.Context) context.Context {
 				proc.SetRunningInContainer(false)
 			}
 		}
+		if !proc.RunningInContainer() {
+			// A containerized daemon doesn't run in the user's environment.
+			ctx = client.WithConfigEnvExpansion(ctx)
+		}
 		ctx = userd.WithNewServiceFunc(ctx, userDaemon.NewService)
 		ctx = userd.WithNewSessionFunc(ctx, trafficmgr.NewSession)
 	case rootd.ProcessName:
@@ -49,6 +54,7 @@ func InitContext(ctx context.Context) context.Context {
 		ctx = rootd.WithNewSessionFunc(ctx, rootd.NewSession)
 	default:
 		client.DisplayName = "OSS Client"
+		ctx = client.WithConfigEnvExpansion(ctx)
 		ctx = connect.WithCommandInitializer(ctx, connect.CommandInitializer)
 		ctx = cmd.WithSubCommands(ctx)
 	}
@@ -76,9 +82,14 @@ func Main(ctx context.Context) {
 			os.Exit(1)
 		}
 	} else {
-		if cmd, fmtOutput, err := output.Execute(cmd.Telepresence(ctx)); err != nil {
+		tpCmd := cmd.Telepresence(ctx)
+		if err := cmd.ApplyEnvDefaults(tpCmd, os.Args[1:]); err != nil {
+			fmt.Fprintf(tpCmd.ErrOrStderr(), "%s: error: %v\n", tpCmd.CommandPath(), err)
+			os.Exit(1)
+		}
+		if cmd, fmtOutput, err := output.Execute(tpCmd); err != nil {
 			if fmtOutput {
-				os.Exit(1)
+				os.Exit(exitCode(err))
 			}
 			fmt.Fprintf(cmd.ErrOrStderr(), "%s: error: %v\n", cmd.CommandPath(), err)
 			if errcat.GetCategory(err) > errcat.NoDaemonLogs {
@@ -92,11 +103,21 @@ func Main(ctx context.Context) {
 						"https://github.com/telepresenceio/telepresence/issues/new?template=Bug_report.md .")
 				}
 			}
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 	}
 }
 
+// exitCode returns the exit code of the intercept or ingest handler, or the docker-run container, that caused
+// the given error, so that the CLI can exit with that same code. It returns 1 for other errors.
+func exitCode(err error) int {
+	var he *proc.HandlerExitError
+	if errors.As(err, &he) {
+		return he.ExitCode
+	}
+	return 1
+}
+
 // summarizeLogs outputs the logs from the root and user daemons. It returns true
 // if output were produced, false otherwise (might happen if no logs exist yet).
 func summarizeLogs(ctx context.Context, cmd *cobra.Command) bool {
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -18,11 +19,24 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
+// defaultLocalMountAddress is the address that the --local-mount-port is bound to unless the
+// --local-mount-address flag is used, or the daemon is containerized.
+const defaultLocalMountAddress = "127.0.0.1"
+
 type Flags struct {
-	LocalMountPort uint16 // --local-mount-port
-	Mount          string // --mount // "true", "false", or desired mount point
-	Enabled        bool
-	ReadOnly       bool
+	LocalMountPort     uint16 // --local-mount-port
+	LocalMountAddress  string // --local-mount-address
+	Mount              string // --mount // "true", "false", or desired mount point
+	MountPointTemplate string // --mount-point-template
+	Enabled            bool
+	ReadOnly           bool
+	Writable           bool // --writable, only available when read-only is forced by default
+}
+
+// mountPointTemplateData is the data that a --mount-point-template is expanded with.
+type mountPointTemplateData struct {
+	Workload  string
+	Container string
 }
 
 func (f *Flags) AddFlags(flagSet *pflag.FlagSet, forceReadOnly bool) {
@@ -32,9 +46,18 @@ func (f *Flags) AddFlags(flagSet *pflag.FlagSet, forceReadOnly bool) {
 		mountText += ` Append ":ro" to mount everything read-only.`
 	}
 	flagSet.StringVar(&f.Mount, "mount", "true", mountText)
+	flagSet.StringVar(&f.MountPointTemplate, "mount-point-template", "", ``+
+		`Template for the mount point that is used instead of a random one when --mount doesn't specify a path, `+
+		`e.g. '/tmp/tp/{{.Workload}}'. The template can use {{.Workload}} and {{.Container}}`)
 
 	flagSet.Uint16Var(&f.LocalMountPort, "local-mount-port", 0,
-		`Do not mount remote directories. Instead, expose this port on localhost to an external mounter`)
+		`Do not mount remote directories. Instead, expose this port on the --local-mount-address to an external mounter`)
+	flagSet.StringVar(&f.LocalMountAddress, "local-mount-address", defaultLocalMountAddress,
+		`The IP address that the --local-mount-port is bound to. Use 0.0.0.0 to bind all interfaces`)
+	if forceReadOnly {
+		flagSet.BoolVar(&f.Writable, "writable", false,
+			`Mount the remote volumes read-write. WARNING: writes will modify the filesystem of the live pod`)
+	}
 	f.ReadOnly = forceReadOnly
 }
 
@@ -42,12 +65,24 @@ func (f *Flags) Validate(cmd *cobra.Command) error {
 	if f.LocalMountPort > 0 && client.GetConfig(cmd.Context()).Intercept().UseFtp {
 		return errcat.User.New("only SFTP can be used with --local-mount-port. Client is configured to perform remote mounts using FTP")
 	}
+	if cmd.Flag("local-mount-address").Changed {
+		if net.ParseIP(f.LocalMountAddress) == nil {
+			return errcat.User.Newf("--local-mount-address %q is not a valid IP address", f.LocalMountAddress)
+		}
+	} else {
+		// The default is assigned by ValidateConnected, because a containerized daemon must listen on all
+		// interfaces.
+		f.LocalMountAddress = ""
+	}
 	if !cmd.Flag("mount").Changed {
 		// Default is that mount is enabled and the path is unspecified
 		f.Mount = "" // Get rid of the default string "true"
 		f.Enabled = true
 	} else if len(f.Mount) > 0 {
 		if strings.HasSuffix(f.Mount, ":ro") {
+			if f.Writable {
+				return errcat.User.New("--writable cannot be used with a read-only --mount")
+			}
 			f.ReadOnly = true
 			f.Mount = f.Mount[:len(f.Mount)-3]
 		}
@@ -62,10 +97,27 @@ func (f *Flags) Validate(cmd *cobra.Command) error {
 			f.LocalMountPort = 0
 		}
 	}
+	if f.MountPointTemplate != "" {
+		if !f.Enabled {
+			return errcat.User.New("--mount-point-template cannot be used with --mount=false")
+		}
+		if f.Mount != "" {
+			return errcat.User.New("--mount-point-template cannot be used together with a --mount path")
+		}
+		if _, err := template.New("mount-point").Parse(f.MountPointTemplate); err != nil {
+			return errcat.User.Newf("invalid --mount-point-template: %v", err)
+		}
+	}
+	if f.Writable {
+		f.ReadOnly = false
+	}
 	return nil
 }
 
-func (f *Flags) ValidateConnected(ctx context.Context) (err error) {
+// ValidateConnected ensures that the mount can be performed and prepares the mount point. The workload
+// and container are used when expanding the --mount-point-template. The container may be empty when it
+// isn't known yet.
+func (f *Flags) ValidateConnected(ctx context.Context, workload, container string) (err error) {
 	if !f.Enabled {
 		return nil
 	}
@@ -90,6 +142,9 @@ func (f *Flags) ValidateConnected(ctx context.Context) (err error) {
 		}
 		return nil
 	}
+	if f.LocalMountAddress == "" {
+		f.LocalMountAddress = defaultLocalMountAddress
+	}
 
 	if err = checkCapability(ctx); err != nil {
 		err = fmt.Errorf("remote volume mounts are disabled: %w", err)
@@ -106,10 +161,42 @@ func (f *Flags) ValidateConnected(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if f.Mount == "" && f.MountPointTemplate != "" {
+		var mountPoint string
+		if mountPoint, err = expandMountPointTemplate(f.MountPointTemplate, workload, container); err != nil {
+			return err
+		}
+		f.Mount, err = prepareFromTemplate(mountPoint)
+		return err
+	}
 	f.Mount, err = prepare(cwd, f.Mount)
 	return err
 }
 
+// expandMountPointTemplate expands the given --mount-point-template using the given workload and container.
+func expandMountPointTemplate(tpl, workload, container string) (string, error) {
+	t, err := template.New("mount-point").Parse(tpl)
+	if err != nil {
+		return "", errcat.User.Newf("invalid --mount-point-template: %v", err)
+	}
+	var sb strings.Builder
+	if err = t.Execute(&sb, &mountPointTemplateData{Workload: workload, Container: container}); err != nil {
+		return "", errcat.User.Newf("unable to expand --mount-point-template: %v", err)
+	}
+	if sb.Len() == 0 {
+		return "", errcat.User.Newf("--mount-point-template %q expands to an empty path", tpl)
+	}
+	return sb.String(), nil
+}
+
+// LocalMountHost returns the host that an external mounter uses when connecting to the --local-mount-port.
+func (f *Flags) LocalMountHost() string {
+	if ip := net.ParseIP(f.LocalMountAddress); ip != nil && !ip.IsUnspecified() {
+		return ip.String()
+	}
+	return defaultLocalMountAddress
+}
+
 func checkCapability(ctx context.Context) error {
 	r, err := daemon.GetUserClient(ctx).RemoteMountAvailability(ctx, &empty.Empty{})
 	if err != nil {
@@ -0,0 +1,150 @@
+package mount
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+)
+
+func TestFlags_Validate(t *testing.T) {
+	ctx := client.WithConfig(dlog.NewTestContext(t, false), client.GetDefaultConfig())
+	validate := func(forceReadOnly bool, args ...string) (*Flags, error) {
+		f := &Flags{}
+		cmd := &cobra.Command{}
+		f.AddFlags(cmd.Flags(), forceReadOnly)
+		require.NoError(t, cmd.Flags().Parse(args))
+		cmd.SetContext(ctx)
+		return f, f.Validate(cmd)
+	}
+
+	f, err := validate(true)
+	require.NoError(t, err)
+	assert.True(t, f.Enabled)
+	assert.True(t, f.ReadOnly)
+
+	f, err = validate(true, "--writable")
+	require.NoError(t, err)
+	assert.True(t, f.Enabled)
+	assert.False(t, f.ReadOnly)
+
+	f, err = validate(true, "--writable", "--mount", "/tmp/mnt")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/mnt", f.Mount)
+	assert.False(t, f.ReadOnly)
+
+	_, err = validate(true, "--writable", "--mount", "/tmp/mnt:ro")
+	assert.Error(t, err)
+
+	f, err = validate(false, "--mount", "/tmp/mnt:ro")
+	require.NoError(t, err)
+	assert.True(t, f.ReadOnly)
+
+	_, err = validate(false, "--writable")
+	assert.Error(t, err, "--writable is only defined when read-only is forced")
+}
+
+func TestFlags_ValidateMountPointTemplate(t *testing.T) {
+	ctx := client.WithConfig(dlog.NewTestContext(t, false), client.GetDefaultConfig())
+	validate := func(args ...string) (*Flags, error) {
+		f := &Flags{}
+		cmd := &cobra.Command{}
+		f.AddFlags(cmd.Flags(), false)
+		require.NoError(t, cmd.Flags().Parse(args))
+		cmd.SetContext(ctx)
+		return f, f.Validate(cmd)
+	}
+
+	f, err := validate("--mount-point-template", "/tmp/tp/{{.Workload}}")
+	require.NoError(t, err)
+	assert.True(t, f.Enabled)
+	assert.Empty(t, f.Mount)
+
+	_, err = validate("--mount-point-template", "/tmp/tp/{{.Workload}")
+	assert.ErrorContains(t, err, "invalid --mount-point-template")
+
+	_, err = validate("--mount", "false", "--mount-point-template", "/tmp/tp/{{.Workload}}")
+	assert.Error(t, err)
+
+	_, err = validate("--mount", "/tmp/mnt", "--mount-point-template", "/tmp/tp/{{.Workload}}")
+	assert.Error(t, err)
+}
+
+func Test_expandMountPointTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		tpl       string
+		workload  string
+		container string
+		want      string
+		wantErr   string
+	}{
+		{
+			name:     "workload",
+			tpl:      "/tmp/tp/{{.Workload}}",
+			workload: "echo",
+			want:     "/tmp/tp/echo",
+		},
+		{
+			name:      "workload and container",
+			tpl:       "/tmp/tp/{{.Workload}}-{{.Container}}",
+			workload:  "echo",
+			container: "app",
+			want:      "/tmp/tp/echo-app",
+		},
+		{
+			name:    "unknown field",
+			tpl:     "/tmp/tp/{{.Pod}}",
+			wantErr: "unable to expand",
+		},
+		{
+			name:    "empty",
+			tpl:     "{{.Container}}",
+			wantErr: "empty path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandMountPointTemplate(tt.tpl, tt.workload, tt.container)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFlags_ValidateLocalMountAddress(t *testing.T) {
+	ctx := client.WithConfig(dlog.NewTestContext(t, false), client.GetDefaultConfig())
+	validate := func(args ...string) (*Flags, error) {
+		f := &Flags{}
+		cmd := &cobra.Command{}
+		f.AddFlags(cmd.Flags(), false)
+		require.NoError(t, cmd.Flags().Parse(args))
+		cmd.SetContext(ctx)
+		return f, f.Validate(cmd)
+	}
+
+	f, err := validate("--local-mount-port", "8022")
+	require.NoError(t, err)
+	assert.Empty(t, f.LocalMountAddress, "default is assigned when connected")
+	assert.Equal(t, "127.0.0.1", f.LocalMountHost())
+
+	f, err = validate("--local-mount-port", "8022", "--local-mount-address", "0.0.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "0.0.0.0", f.LocalMountAddress)
+	assert.Equal(t, "127.0.0.1", f.LocalMountHost())
+
+	f, err = validate("--local-mount-port", "8022", "--local-mount-address", "192.168.1.10")
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.1.10", f.LocalMountHost())
+
+	_, err = validate("--local-mount-port", "8022", "--local-mount-address", "localhost")
+	assert.ErrorContains(t, err, "not a valid IP address")
+}
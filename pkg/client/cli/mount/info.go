@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
 )
 
@@ -15,6 +16,8 @@ type Info struct {
 	Port      int32    `json:"port,omitempty"          yaml:"port,omitempty"`
 	Mounts    []string `json:"mounts,omitempty"        yaml:"mounts,omitempty"`
 	ReadOnly  bool     `json:"read_only,omitempty"     yaml:"read_only,omitempty"`
+	Connected bool     `json:"connected"               yaml:"connected"`
+	LastError string   `json:"last_error,omitempty"    yaml:"last_error,omitempty"`
 }
 
 func NewInfo(ctx context.Context, env map[string]string, ftpPort, sftpPort int32, localDir, remoteDir, podIP string, ro bool) *Info {
@@ -38,3 +41,24 @@ func NewInfo(ctx context.Context, env map[string]string, ftpPort, sftpPort int32
 		ReadOnly:  ro,
 	}
 }
+
+// SetStatus assigns the live state of the mount from the given status, which is nil when the mount hasn't
+// been started.
+func (mi *Info) SetStatus(ms *connector.MountStatus) {
+	if ms != nil {
+		mi.Connected, mi.LastError = ms.Connected, ms.Error
+	}
+}
+
+// Status returns a short description of the live state of the mount, or an empty string when the
+// state is unknown because the mount hasn't reported it yet.
+func (mi *Info) Status() string {
+	switch {
+	case mi.Connected:
+		return "connected"
+	case mi.LastError != "":
+		return "disconnected: " + mi.LastError
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,30 @@
+package mount
+
+import (
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+)
+
+func TestInfo_connected(t *testing.T) {
+	mi := &Info{LocalDir: "/tmp/mnt"}
+	mi.SetStatus(&connector.MountStatus{Error: "connection refused"})
+
+	// A disconnected mount must say so explicitly.
+	data, err := json.Marshal(mi)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"connected":false`)
+	data, err = yaml.Marshal(mi)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "connected: false")
+
+	mi.SetStatus(&connector.MountStatus{Connected: true})
+	data, err = json.Marshal(mi)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"connected":true`)
+}
@@ -4,8 +4,12 @@
 package mount
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
 func prepare(cwd string, mountPoint string) (string, error) {
@@ -21,3 +25,25 @@ func prepare(cwd string, mountPoint string) (string, error) {
 
 	return mountPoint, os.MkdirAll(mountPoint, 0o700)
 }
+
+// prepareFromTemplate prepares a mount point that was expanded from a --mount-point-template. The mount
+// point must be absolute, and it must either not exist or be an empty directory, so that the mount
+// doesn't collide with an existing mount or hide existing files.
+func prepareFromTemplate(mountPoint string) (string, error) {
+	if !filepath.IsAbs(mountPoint) {
+		return "", errcat.User.Newf("mount point %q is not an absolute path", mountPoint)
+	}
+	mountPoint = filepath.Clean(mountPoint)
+	entries, err := os.ReadDir(mountPoint)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return "", errcat.User.Newf("unable to use %s as mount point: %v", mountPoint, err)
+	case len(entries) > 0:
+		return "", errcat.User.Newf("unable to use %s as mount point: directory is not empty", mountPoint)
+	}
+	if err = os.MkdirAll(mountPoint, 0o700); err != nil {
+		return "", errcat.User.Newf("unable to create mount point %s: %v", mountPoint, err)
+	}
+	return mountPoint, nil
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+// +build !windows
+
+package mount
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_prepareFromTemplate(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("creates missing directory", func(t *testing.T) {
+		mp := filepath.Join(dir, "tp", "echo")
+		got, err := prepareFromTemplate(mp)
+		require.NoError(t, err)
+		assert.Equal(t, mp, got)
+		assert.DirExists(t, mp)
+	})
+
+	t.Run("reuses empty directory", func(t *testing.T) {
+		mp := filepath.Join(dir, "empty")
+		require.NoError(t, os.Mkdir(mp, 0o700))
+		got, err := prepareFromTemplate(mp + "/")
+		require.NoError(t, err)
+		assert.Equal(t, mp, got)
+	})
+
+	t.Run("collides with non-empty directory", func(t *testing.T) {
+		mp := filepath.Join(dir, "busy")
+		require.NoError(t, os.Mkdir(mp, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(mp, "file"), nil, 0o600))
+		_, err := prepareFromTemplate(mp)
+		assert.ErrorContains(t, err, "directory is not empty")
+	})
+
+	t.Run("collides with file", func(t *testing.T) {
+		mp := filepath.Join(dir, "file")
+		require.NoError(t, os.WriteFile(mp, nil, 0o600))
+		_, err := prepareFromTemplate(mp)
+		assert.ErrorContains(t, err, "unable to use")
+	})
+
+	t.Run("relative", func(t *testing.T) {
+		_, err := prepareFromTemplate("tp/echo")
+		assert.ErrorContains(t, err, "not an absolute path")
+	})
+}
@@ -32,3 +32,16 @@ func prepare(_ string, mountPoint string) (string, error) {
 	}
 	return mountPoint, err
 }
+
+// prepareFromTemplate prepares a mount point that was expanded from a --mount-point-template. The mount
+// point must be a drive letter that isn't in use.
+func prepareFromTemplate(mountPoint string) (string, error) {
+	mountPoint, err := prepare("", mountPoint)
+	if err != nil {
+		return "", err
+	}
+	if _, err = os.Stat(mountPoint + `\`); !os.IsNotExist(err) {
+		return "", errcat.User.Newf("unable to use %s as mount point: drive is already in use", mountPoint)
+	}
+	return mountPoint, nil
+}
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/global"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/spinner"
 	"github.com/telepresenceio/telepresence/v2/pkg/dos"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
@@ -71,9 +72,11 @@ func Object(ctx context.Context, obj any, override bool) {
 			}
 
 			if o.format == formatJSONStream {
+				// Each object is written on a line of its own, so that the stream is newline-delimited JSON.
 				if err := json.MarshalWrite(o.originalStdout, obj, json.Deterministic(true)); err != nil {
 					panic(err)
 				}
+				_, _ = o.originalStdout.Write([]byte{'\n'})
 			} else {
 				o.obj = obj
 			}
@@ -166,7 +169,7 @@ func Execute(cmd *cobra.Command) (*cobra.Command, bool, error) {
 
 // setFormat assigns a cobra.Command.PersistentPreRunE function that all sub commands will inherit. This
 // function checks if the global `--output` flag was used, and if so, ensures that formatted output is
-// initialized.
+// initialized. It also requests plain output from spinners when the global `--no-spinner` flag is used.
 func setFormat(cmd *cobra.Command) {
 	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		fmt, err := validateFlag(cmd)
@@ -183,7 +186,11 @@ func setFormat(cmd *cobra.Command) {
 			cmd.SilenceErrors = true
 			cmd.SilenceUsage = true
 		}
-		cmd.SetContext(context.WithValue(cmd.Context(), key{}, cmd))
+		ctx := cmd.Context()
+		if noSpin, _ := cmd.Flags().GetBool(global.FlagNoSpin); noSpin {
+			ctx = spinner.WithPlain(ctx)
+		}
+		cmd.SetContext(context.WithValue(ctx, key{}, cmd))
 		return nil
 	}
 }
@@ -215,4 +215,28 @@ func TestWithOutput(t *testing.T) {
 		require.Empty(t, m["stderr"], "did not get empty stderr")
 		require.Equal(t, m["err"], "this went south")
 	})
+
+	t.Run("json-stream output with multiple native json", func(t *testing.T) {
+		cmd, outBuf, _ := newCmdWithBufs()
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			Object(cmd.Context(), map[string]float64{"a": 1}, true)
+			Object(cmd.Context(), map[string]float64{"a": 2}, true)
+			return nil
+		}
+		cmd.SetArgs([]string{"--output=json-stream"})
+		_, _, err := Execute(cmd)
+		require.NoError(t, err)
+		require.Equal(t, "{\"a\":1}\n{\"a\":2}\n", outBuf.String())
+	})
+}
+
+func TestParseTextFormat(t *testing.T) {
+	f, err := ParseTextFormat("")
+	require.NoError(t, err)
+	require.Equal(t, TextDetailed, f)
+	f, err = ParseTextFormat("Wide")
+	require.NoError(t, err)
+	require.Equal(t, TextWide, f)
+	_, err = ParseTextFormat("csv")
+	require.ErrorContains(t, err, "detailed|table|wide|name")
 }
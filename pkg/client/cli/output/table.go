@@ -0,0 +1,91 @@
+package output
+
+import (
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// TextFormat is the format of human-readable output, i.e. the output that is produced when no formatted
+// output has been requested using the global `--output` flag. Commands that support more than one text
+// format let the user select one using a `--format` flag.
+type TextFormat string
+
+const (
+	// TextDetailed is the default, verbose, description of each object.
+	TextDetailed TextFormat = "detailed"
+
+	// TextTable is a table with one row for each object.
+	TextTable TextFormat = "table"
+
+	// TextWide is a table with one row for each object, and with more columns than TextTable.
+	TextWide TextFormat = "wide"
+
+	// TextName is just the name of each object, one per line.
+	TextName TextFormat = "name"
+)
+
+// TextFormats are the valid values for a `--format` flag.
+var TextFormats = []TextFormat{TextDetailed, TextTable, TextWide, TextName} //nolint:gochecknoglobals // constant
+
+// ParseTextFormat returns the TextFormat with the given name, or an error if no such format exists. An
+// empty string yields TextDetailed.
+func ParseTextFormat(s string) (TextFormat, error) {
+	if s == "" {
+		return TextDetailed, nil
+	}
+	f := TextFormat(strings.ToLower(s))
+	for _, tf := range TextFormats {
+		if f == tf {
+			return f, nil
+		}
+	}
+	return "", errcat.User.Newf("invalid format %q, must be one of %s", s, TextFormatNames())
+}
+
+// TextFormatNames returns a string that lists the names of the valid text formats, separated by '|'.
+func TextFormatNames() string {
+	names := make([]string, len(TextFormats))
+	for i, tf := range TextFormats {
+		names[i] = string(tf)
+	}
+	return strings.Join(names, "|")
+}
+
+// Table writes the given header and rows to w as columns that are separated by at least two spaces.
+// Empty cells are written as "-", so that each row has the same number of fields when split on spaces.
+func Table(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	writeRow := func(row []string) error {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			if c == "" {
+				c = "-"
+			}
+			cells[i] = c
+		}
+		_, err := io.WriteString(tw, strings.Join(cells, "\t")+"\n")
+		return err
+	}
+	if err := writeRow(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writeRow(row); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// Names writes the given names to w, one per line.
+func Names(w io.Writer, names []string) error {
+	for _, n := range names {
+		if _, err := io.WriteString(w, n+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
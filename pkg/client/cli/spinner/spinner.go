@@ -1,6 +1,10 @@
 package spinner
 
-import "context"
+import (
+	"context"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+)
 
 type Spinner interface {
 	Helper()
@@ -29,16 +33,35 @@ type Provider interface {
 	New(string) Spinner
 }
 
-type key struct{}
+type (
+	key      struct{}
+	plainKey struct{}
+)
 
 func WithProvider(ctx context.Context, sp Provider) context.Context {
 	return context.WithValue(ctx, key{}, sp)
 }
 
-// New configures a new spinner with the default values displaying the job message.
+// WithPlain returns a context that requests plain output, i.e. that New returns a no-op spinner and that
+// progress is printed as plain lines. This is used when the global --no-spinner flag is given.
+func WithPlain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, plainKey{}, true)
+}
+
+// Plain returns true if plain output has been requested, either using WithPlain or by setting the
+// NO_COLOR environment variable to a non-empty value.
+func Plain(ctx context.Context) bool {
+	if p, ok := ctx.Value(plainKey{}).(bool); ok && p {
+		return true
+	}
+	return dos.Getenv(ctx, "NO_COLOR") != ""
+}
+
+// New configures a new spinner with the default values displaying the job message. The spinner
+// is a no-op when no Provider has been configured, or when Plain returns true.
 func New(ctx context.Context, job string) Spinner {
 	sp, ok := ctx.Value(key{}).(Provider)
-	if !ok {
+	if !ok || Plain(ctx) {
 		return noop{}
 	}
 	spin := sp.New(job)
@@ -359,6 +359,14 @@ type Timeouts struct {
 	PrivateFtpShutdown time.Duration `json:"ftpShutdown"`
 	// PrivateContainerShutdown max time to wait for a docker container to stop before forcing termination.
 	PrivateContainerShutdown time.Duration `json:"containerShutdown"`
+	// PrivateSFTPRetry is the interval between attempts to restart a failed sshfs mount.
+	PrivateSFTPRetry time.Duration `json:"sftpRetry"`
+	// PrivateSFTPRetryMax is the max time to keep retrying a failed sshfs mount. Zero means retry until the mount ends.
+	PrivateSFTPRetryMax time.Duration `json:"sftpRetryMax"`
+	// PrivateSessionIdle is how long a session without intercepts, ingests, or traffic may stay idle before the user daemon quits. Zero means never.
+	PrivateSessionIdle time.Duration `json:"sessionIdle"`
+	// PrivateKeepalive is the interval between the heartbeats that keep the session alive in the traffic-manager.
+	PrivateKeepalive time.Duration `json:"keepalive"`
 }
 
 type TimeoutID int
@@ -376,6 +384,10 @@ const (
 	TimeoutFtpReadWrite
 	TimeoutFtpShutdown
 	TimeoutContainerShutdown
+	TimeoutSFTPRetry
+	TimeoutSFTPRetryMax
+	TimeoutSessionIdle
+	TimeoutKeepalive
 )
 
 type timeoutContext struct {
@@ -424,6 +436,14 @@ func (t *Timeouts) Get(timeoutID TimeoutID) time.Duration {
 		timeoutVal = t.PrivateFtpShutdown
 	case TimeoutContainerShutdown:
 		timeoutVal = t.PrivateContainerShutdown
+	case TimeoutSFTPRetry:
+		timeoutVal = t.PrivateSFTPRetry
+	case TimeoutSFTPRetryMax:
+		timeoutVal = t.PrivateSFTPRetryMax
+	case TimeoutSessionIdle:
+		timeoutVal = t.PrivateSessionIdle
+	case TimeoutKeepalive:
+		timeoutVal = t.PrivateKeepalive
 	default:
 		panic("should not happen")
 	}
@@ -487,6 +507,18 @@ func (e timeoutError) Error() string {
 	case TimeoutContainerShutdown:
 		yamlName = "containerShutdown"
 		humanName = "Docker container shutdown grace period"
+	case TimeoutSFTPRetry:
+		yamlName = "sftpRetry"
+		humanName = "SFTP mount retry interval"
+	case TimeoutSFTPRetryMax:
+		yamlName = "sftpRetryMax"
+		humanName = "SFTP mount retry period"
+	case TimeoutSessionIdle:
+		yamlName = "sessionIdle"
+		humanName = "idle session"
+	case TimeoutKeepalive:
+		yamlName = "keepalive"
+		humanName = "session keepalive"
 	default:
 		panic("should not happen")
 	}
@@ -518,6 +550,10 @@ const (
 	defaultTimeoutsFtpReadWrite          = 1 * time.Minute
 	defaultTimeoutsFtpShutdown           = 2 * time.Minute
 	defaultTimeoutsContainerShutdown     = 0
+	defaultTimeoutsSFTPRetry             = 3 * time.Second
+	defaultTimeoutsSFTPRetryMax          = 0
+	defaultTimeoutsSessionIdle           = 0
+	defaultTimeoutsKeepalive             = 5 * time.Second
 	maxTimeoutsConnectivityCheck         = 5 * time.Second
 )
 
@@ -534,6 +570,10 @@ var defaultTimeouts = Timeouts{ //nolint:gochecknoglobals // constant
 	PrivateFtpReadWrite:          defaultTimeoutsFtpReadWrite,
 	PrivateFtpShutdown:           defaultTimeoutsFtpShutdown,
 	PrivateContainerShutdown:     defaultTimeoutsContainerShutdown,
+	PrivateSFTPRetry:             defaultTimeoutsSFTPRetry,
+	PrivateSFTPRetryMax:          defaultTimeoutsSFTPRetryMax,
+	PrivateSessionIdle:           defaultTimeoutsSessionIdle,
+	PrivateKeepalive:             defaultTimeoutsKeepalive,
 }
 
 func (t *Timeouts) defaults() DefaultsAware {
@@ -682,6 +722,14 @@ type Grpc struct {
 	// MaxReceiveSize is the maximum message size in bytes the client can receive in a gRPC call or stream message.
 	// Overrides the gRPC default of 4MB.
 	MaxReceiveSizeV resource.Quantity `json:"maxReceiveSize"`
+
+	// KeepaliveTime is the interval at which the client pings the traffic-manager when the connection has
+	// been idle, so that connections that are silently dropped are detected. Zero disables keepalive.
+	KeepaliveTime time.Duration `json:"keepaliveTime,omitempty"`
+
+	// KeepaliveTimeout is how long the client waits for a response to a keepalive ping before it closes
+	// the connection. Zero means that the gRPC default of 20 seconds is used.
+	KeepaliveTimeout time.Duration `json:"keepaliveTimeout,omitempty"`
 }
 
 func (g *Grpc) MaxReceiveSize() int64 {
@@ -697,11 +745,17 @@ func (g *Grpc) merge(o *Grpc) {
 	if !o.MaxReceiveSizeV.IsZero() {
 		g.MaxReceiveSizeV = o.MaxReceiveSizeV
 	}
+	if o.KeepaliveTime > 0 {
+		g.KeepaliveTime = o.KeepaliveTime
+	}
+	if o.KeepaliveTimeout > 0 {
+		g.KeepaliveTimeout = o.KeepaliveTimeout
+	}
 }
 
 // IsZero controls whether this element will be included in marshalled output.
 func (g *Grpc) IsZero() bool {
-	return g == nil || g.MaxReceiveSizeV.IsZero()
+	return g == nil || g.MaxReceiveSizeV.IsZero() && g.KeepaliveTime == 0 && g.KeepaliveTimeout == 0
 }
 
 type TelepresenceAPI struct {
@@ -744,9 +798,12 @@ func (tm *Telemount) UnmarshalJSONV2(in *jsontext.Decoder, opts json.Options) er
 	return json.UnmarshalDecode(in, &wp, opts)
 }
 
+const defaultForwardKeepAlive = 15 * time.Second
+
 var defaultIntercept = Intercept{ //nolint:gochecknoglobals // constant
 	AppProtocolStrategy: k8sapi.Http2Probe,
 	Telemount:           defaultTelemount,
+	ForwardKeepAlive:    defaultForwardKeepAlive,
 }
 
 type DockerImage struct {
@@ -762,6 +819,15 @@ type Intercept struct {
 	DefaultPort         int                        `json:"defaultPort"`
 	UseFtp              bool                       `json:"useFtp"`
 	Telemount           Telemount                  `json:"telemount,omitzero"`
+
+	// FuseAttrTimeout and FuseEntryTimeout control how long the FUSE kernel module caches file attributes and
+	// directory entries of a remote mount. Zero means that the defaults of the FUSE driver are used.
+	FuseAttrTimeout  time.Duration `json:"fuseAttrTimeout"`
+	FuseEntryTimeout time.Duration `json:"fuseEntryTimeout"`
+
+	// ForwardKeepAlive is the TCP keepalive period of connections that are port-forwarded to an intercepted pod.
+	// It defaults to 15 seconds. Zero means that the system default is used, and a negative value disables keepalive.
+	ForwardKeepAlive time.Duration `json:"forwardKeepAlive"`
 }
 
 func (ic *Intercept) defaults() DefaultsAware {
@@ -798,6 +864,24 @@ type Cluster struct {
 	ForceSPDY               bool     `json:"forceSPDY"`
 	AgentPortForward        bool     `json:"agentPortForward"`
 
+	// ManagerAddress is the host:port of a traffic-manager that is reachable without port-forward, e.g.
+	// through an ingress or a load balancer. The traffic-manager service is not looked up when it is set.
+	ManagerAddress string `json:"managerAddress"`
+
+	// ManagerTLS controls whether TLS is used when connecting to the ManagerAddress. TLS is also used
+	// when any of the certificate files below are set. It's a pointer so that an explicit false overrides
+	// a true when configurations are merged.
+	ManagerTLS *bool `json:"managerTLS"`
+
+	// ManagerCACert is the path to a PEM file with the CA certificates used to verify the traffic-manager
+	// at the ManagerAddress. The system's root CAs are used when it is empty.
+	ManagerCACert string `json:"managerCACert"`
+
+	// ManagerClientCert and ManagerClientKey are the paths to the PEM encoded certificate and key that
+	// the client presents to the traffic-manager at the ManagerAddress when using mutual TLS.
+	ManagerClientCert string `json:"managerClientCert"`
+	ManagerClientKey  string `json:"managerClientKey"`
+
 	// deprecated, use Routing.VirtualSubnet
 	OldVirtualIPSubnet string `json:"virtualIPSubnet"`
 }
@@ -816,6 +900,11 @@ func (cc *Cluster) defaults() DefaultsAware {
 	return &defaultCluster
 }
 
+// UseManagerTLS returns true when ManagerTLS is set to true.
+func (cc *Cluster) UseManagerTLS() bool {
+	return cc.ManagerTLS != nil && *cc.ManagerTLS
+}
+
 // merge merges this instance with the non-zero values of the given argument. The argument values take priority.
 func (cc *Cluster) merge(o *Cluster) {
 	mergeNonDefaults(cc, o)
@@ -843,10 +932,26 @@ type Routing struct {
 	Subnets                []netip.Prefix `json:"subnets,omitempty"`
 	AlsoProxy              []netip.Prefix `json:"alsoProxySubnets,omitempty"`
 	NeverProxy             []netip.Prefix `json:"neverProxySubnets,omitempty"`
+	NeverProxyHosts        []string       `json:"neverProxyHosts,omitempty"`
 	AllowConflicting       []netip.Prefix `json:"allowConflictingSubnets,omitempty"`
 	RecursionBlockDuration time.Duration  `json:"recursionBlockDuration,omitempty"`
 	VirtualSubnet          netip.Prefix   `json:"virtualSubnet"`
 	AutoResolveConflicts   bool           `json:"autoResolveConflicts"`
+	PersistVirtualIPs      bool           `json:"persistVirtualIPs,omitempty"`
+	Aggregate              bool           `json:"aggregate,omitempty"`
+
+	// VirtualIPProvider is the URL of an external IP address management that provides the virtual IPs. The
+	// virtual IPs are generated from the VirtualSubnet when it's empty.
+	VirtualIPProvider string `json:"virtualIPProvider,omitempty"`
+
+	// VirtualSubnets are the virtual subnets in use by the root daemon. This includes the VirtualSubnet
+	// and, when IPv6 addresses are translated and the VirtualSubnet is an IPv4 subnet, an automatically
+	// chosen IPv6 ULA subnet. Only set by the root daemon when it reports its configuration.
+	VirtualSubnets []netip.Prefix `json:"virtualSubnets,omitempty"`
+
+	// RoutedSubnets are the Subnets, each labeled with the reason why it is routed. Only set by the root
+	// daemon when it reports its configuration.
+	RoutedSubnets []RoutedSubnet `json:"routedSubnets,omitempty"`
 
 	// For backward compatibility.
 	OldAlsoProxy        []netip.Prefix `json:"alsoProxy,omitempty"`
@@ -854,6 +959,13 @@ type Routing struct {
 	OldAllowConflicting []netip.Prefix `json:"allowConflicting,omitempty"`
 }
 
+// RoutedSubnet is a subnet that is routed by the root daemon, together with the source of the subnet,
+// e.g. "pod", "service", "also-proxy", "virtual", "proxy-via", or "dns".
+type RoutedSubnet struct {
+	Subnet netip.Prefix `json:"subnet"`
+	Source string       `json:"source,omitempty"`
+}
+
 const defaultAutoResolveConflicts = true
 
 var defaultRouting = Routing{ //nolint:gochecknoglobals // constant
@@ -876,6 +988,9 @@ func (r *Routing) merge(o *Routing) {
 	} else if len(o.OldNeverProxy) > 0 {
 		r.NeverProxy = o.OldNeverProxy
 	}
+	if len(o.NeverProxyHosts) > 0 {
+		r.NeverProxyHosts = o.NeverProxyHosts
+	}
 	if len(o.AllowConflicting) > 0 {
 		r.AllowConflicting = o.AllowConflicting
 	} else if len(o.OldAllowConflicting) > 0 {
@@ -884,6 +999,12 @@ func (r *Routing) merge(o *Routing) {
 	if len(o.Subnets) > 0 {
 		r.Subnets = o.Subnets
 	}
+	if len(o.VirtualSubnets) > 0 {
+		r.VirtualSubnets = o.VirtualSubnets
+	}
+	if len(o.RoutedSubnets) > 0 {
+		r.RoutedSubnets = o.RoutedSubnets
+	}
 	if o.RecursionBlockDuration > 0 {
 		r.RecursionBlockDuration = o.RecursionBlockDuration
 	}
@@ -893,6 +1014,15 @@ func (r *Routing) merge(o *Routing) {
 	if o.AutoResolveConflicts != defaultAutoResolveConflicts { //nolint:gosimple // keep for the semantic clarity
 		r.AutoResolveConflicts = o.AutoResolveConflicts
 	}
+	if o.PersistVirtualIPs {
+		r.PersistVirtualIPs = true
+	}
+	if o.Aggregate {
+		r.Aggregate = true
+	}
+	if o.VirtualIPProvider != "" {
+		r.VirtualIPProvider = o.VirtualIPProvider
+	}
 }
 
 // IsZero controls whether this element will be included in marshalled output.
@@ -919,9 +1049,12 @@ func (d *DNS) Equal(o *DNS) bool {
 	}
 	return o.LocalIP == d.LocalIP &&
 		o.RemoteIP == d.RemoteIP &&
+		slices.Equal(o.Fallback, d.Fallback) &&
+		o.Redirect == d.Redirect &&
 		o.LookupTimeout == d.LookupTimeout &&
 		slices.Equal(o.IncludeSuffixes, d.IncludeSuffixes) &&
 		slices.Equal(o.ExcludeSuffixes, d.ExcludeSuffixes) &&
+		slices.Equal(o.BypassSuffixes, d.BypassSuffixes) &&
 		slices.Equal(o.Excludes, d.Excludes) &&
 		slices.Equal(o.Mappings, d.Mappings)
 }
@@ -1054,6 +1187,11 @@ func LoadConfig(c context.Context) (cfg Config, err error) {
 		if err != nil {
 			return err
 		}
+		if configEnvExpansion(c) {
+			if err = expandConfigEnv(c, fileConfig); err != nil {
+				return fmt.Errorf("%s: %w", fileName, err)
+			}
+		}
 		cfg.DestructiveMerge(fileConfig)
 		return nil
 	}
@@ -1078,18 +1216,74 @@ type RoutingSnake struct {
 	Subnets                []netip.Prefix `json:"subnets"`
 	AlsoProxy              []netip.Prefix `json:"also_proxy_subnets"`
 	NeverProxy             []netip.Prefix `json:"never_proxy_subnets"`
+	NeverProxyHosts        []string       `json:"never_proxy_hosts,omitempty"`
 	AllowConflicting       []netip.Prefix `json:"allow_conflicting_subnets"`
 	RecursionBlockDuration time.Duration  `json:"recursion_block_duration"`
 	VirtualSubnet          netip.Prefix   `json:"virtual_subnet"`
 	AutoResolveConflicts   bool           `json:"auto_resolve_conflicts"`
+	PersistVirtualIPs      bool           `json:"persist_virtual_ips,omitempty"`
+	Aggregate              bool           `json:"aggregate,omitempty"`
+	VirtualIPProvider      string         `json:"virtual_ip_provider,omitempty"`
+	VirtualSubnets         []netip.Prefix `json:"virtual_subnets,omitempty"`
+	RoutedSubnets          []RoutedSubnet `json:"routed_subnets,omitempty"`
+}
+
+// DNSRedirect controls how DNS queries are redirected to the local DNS server on Linux
+// systems that don't use systemd-resolved.
+type DNSRedirect string
+
+const (
+	// DNSRedirectIPTables redirects queries for the system's DNS server using an iptables NAT rule.
+	DNSRedirectIPTables DNSRedirect = "iptables"
+
+	// DNSRedirectTUN points the system resolver at the DNS IP that is routed to the TUN device.
+	DNSRedirectTUN DNSRedirect = "tun"
+)
+
+func (dr *DNSRedirect) UnmarshalJSONV2(in *jsontext.Decoder, opts json.Options) error {
+	var s string
+	if err := json.UnmarshalDecode(in, &s, opts); err != nil {
+		return err
+	}
+	switch r := DNSRedirect(s); r {
+	case "", DNSRedirectIPTables, DNSRedirectTUN:
+		*dr = r
+		return nil
+	default:
+		return fmt.Errorf("invalid DNS redirect %q, must be one of %q or %q", s, DNSRedirectIPTables, DNSRedirectTUN)
+	}
+}
+
+// DNSServers is a list of DNS server addresses. It can be unmarshalled from either a single
+// address or a list of addresses.
+type DNSServers []netip.Addr
+
+func (ds *DNSServers) UnmarshalJSONV2(in *jsontext.Decoder, opts json.Options) error {
+	if in.PeekKind() == '"' {
+		var addr netip.Addr
+		if err := json.UnmarshalDecode(in, &addr, opts); err != nil {
+			return err
+		}
+		*ds = DNSServers{addr}
+		return nil
+	}
+	var addrs []netip.Addr
+	if err := json.UnmarshalDecode(in, &addrs, opts); err != nil {
+		return err
+	}
+	*ds = addrs
+	return nil
 }
 
 type DNS struct {
 	Error           string        `json:"error"`
 	LocalIP         netip.Addr    `json:"localIP"`
 	RemoteIP        netip.Addr    `json:"remoteIP"`
+	Fallback        DNSServers    `json:"fallback"`
+	Redirect        DNSRedirect   `json:"redirect"`
 	IncludeSuffixes []string      `json:"includeSuffixes"`
 	ExcludeSuffixes []string      `json:"excludeSuffixes"`
+	BypassSuffixes  []string      `json:"bypassSuffixes"`
 	Excludes        []string      `json:"excludes"`
 	Mappings        DNSMappings   `json:"mappings"`
 	LookupTimeout   time.Duration `json:"lookupTimeout"`
@@ -1100,8 +1294,11 @@ type DNSSnake struct {
 	Error           string        `json:"error"`
 	LocalIP         netip.Addr    `json:"local_ip"`
 	RemoteIP        netip.Addr    `json:"remote_ip"`
+	Fallback        DNSServers    `json:"fallback"`
+	Redirect        DNSRedirect   `json:"redirect"`
 	IncludeSuffixes []string      `json:"include_suffixes"`
 	ExcludeSuffixes []string      `json:"exclude_suffixes"`
+	BypassSuffixes  []string      `json:"bypass_suffixes"`
 	Excludes        []string      `json:"excludes"`
 	Mappings        DNSMappings   `json:"mappings"`
 	LookupTimeout   time.Duration `json:"lookup_timeout"`
@@ -1133,8 +1330,11 @@ func (d *DNS) ToSnake() *DNSSnake {
 	return &DNSSnake{
 		LocalIP:         d.LocalIP,
 		RemoteIP:        d.RemoteIP,
+		Fallback:        d.Fallback,
+		Redirect:        d.Redirect,
 		ExcludeSuffixes: d.ExcludeSuffixes,
 		IncludeSuffixes: d.IncludeSuffixes,
+		BypassSuffixes:  d.BypassSuffixes,
 		Excludes:        d.Excludes,
 		Mappings:        d.Mappings,
 		LookupTimeout:   d.LookupTimeout,
@@ -1181,8 +1381,14 @@ func (r *Routing) ToSnake() *RoutingSnake {
 		Subnets:              r.Subnets,
 		AlsoProxy:            r.AlsoProxy,
 		NeverProxy:           r.NeverProxy,
+		NeverProxyHosts:      r.NeverProxyHosts,
 		AllowConflicting:     r.AllowConflicting,
 		AutoResolveConflicts: r.AutoResolveConflicts,
+		PersistVirtualIPs:    r.PersistVirtualIPs,
+		Aggregate:            r.Aggregate,
+		VirtualIPProvider:    r.VirtualIPProvider,
+		VirtualSubnets:       r.VirtualSubnets,
+		RoutedSubnets:        r.RoutedSubnets,
 	}
 }
 
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+)
+
+type configEnvExpansionKey struct{}
+
+// WithConfigEnvExpansion returns a context that makes LoadConfig expand references to environment variables in
+// the config.yml files that it reads. It must only be used by processes that run in the user's environment, i.e.
+// the CLI and the user daemon. The root daemon runs with the environment of root or sudo, and the configurations
+// of the traffic-manager and the cluster are never expanded.
+func WithConfigEnvExpansion(ctx context.Context) context.Context {
+	return context.WithValue(ctx, configEnvExpansionKey{}, true)
+}
+
+func configEnvExpansion(ctx context.Context) bool {
+	b, _ := ctx.Value(configEnvExpansionKey{}).(bool)
+	return b
+}
+
+// expandConfigEnv expands references to environment variables, written as ${VAR} or $VAR, in all string
+// values of the given configuration. A literal '$' is written as "$$". A reference to an environment
+// variable that isn't defined is an error.
+func expandConfigEnv(ctx context.Context, cfg Config) error {
+	return expandEnvValue(ctx, reflect.ValueOf(cfg), "")
+}
+
+func expandEnvValue(ctx context.Context, v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return expandEnvValue(ctx, v.Elem(), path)
+		}
+	case reflect.Struct:
+		vt := v.Type()
+		for i := 0; i < vt.NumField(); i++ {
+			f := vt.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			fp := path
+			if !f.Anonymous {
+				name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+				if name == "-" {
+					continue
+				}
+				if name == "" {
+					name = f.Name
+				}
+				fp = joinConfigPath(path, name)
+			}
+			if err := expandEnvValue(ctx, v.Field(i), fp); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := expandEnvValue(ctx, v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			break
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			s, err := expandEnv(ctx, iter.Value().String(), joinConfigPath(path, fmt.Sprint(iter.Key())))
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(s).Convert(v.Type().Elem()))
+		}
+	case reflect.String:
+		if v.CanSet() {
+			s, err := expandEnv(ctx, v.String(), path)
+			if err != nil {
+				return err
+			}
+			v.SetString(s)
+		}
+	default:
+	}
+	return nil
+}
+
+func expandEnv(ctx context.Context, s, path string) (string, error) {
+	if !strings.ContainsRune(s, '$') {
+		return s, nil
+	}
+	var err error
+	s = os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		value, ok := dos.LookupEnv(ctx, name)
+		if !ok && err == nil {
+			err = fmt.Errorf("%s: environment variable %q is not defined", path, name)
+		}
+		return value
+	})
+	return s, err
+}
+
+func joinConfigPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
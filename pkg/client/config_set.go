@@ -0,0 +1,119 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+	yaml3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// SetConfigValue returns a copy of the given YAML client configuration in which the value identified by
+// the given dotted key, e.g. "timeouts.intercept", has been set to the given value. The key must
+// identify a known configuration value. The value is typed according to what that configuration value
+// accepts, so it's written as a boolean, number, string, or, when it contains comma separated elements
+// and a list is expected, as a list. Comments in the given configuration are retained.
+func SetConfigValue(data []byte, key, value string) ([]byte, error) {
+	path := strings.Split(key, ".")
+	for _, p := range path {
+		if p == "" {
+			return nil, errcat.User.Newf("invalid config key %q", key)
+		}
+	}
+
+	var firstErr error
+	for _, vn := range valueCandidates(value) {
+		// Validate the value in isolation, so that problems elsewhere in the given configuration don't
+		// affect the outcome.
+		single, err := setConfigNode(nil, path, vn)
+		if err != nil {
+			return nil, err
+		}
+		js, err := yaml.YAMLToJSON(single)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = UnmarshalJSONConfig(js, true); err == nil {
+			return setConfigNode(data, path, vn)
+		}
+		var semanticErr *json.SemanticError
+		if errors.As(err, &semanticErr) && strings.Contains(semanticErr.Error(), "unknown name ") {
+			return nil, errcat.User.Newf("unknown config key %q", key)
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, errcat.User.Newf("invalid value %q for config key %q: %v", value, key, firstErr)
+}
+
+// valueCandidates returns the YAML nodes that the given value can be represented by, in order of preference.
+func valueCandidates(value string) []*yaml3.Node {
+	plain := &yaml3.Node{Kind: yaml3.ScalarNode, Value: value}
+	str := &yaml3.Node{Kind: yaml3.ScalarNode, Tag: "!!str", Value: value}
+	seq := &yaml3.Node{Kind: yaml3.SequenceNode, Tag: "!!seq"}
+	if value != "" {
+		for _, v := range strings.Split(value, ",") {
+			seq.Content = append(seq.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: strings.TrimSpace(v)})
+		}
+	}
+	return []*yaml3.Node{plain, str, seq}
+}
+
+// setConfigNode parses the given YAML data, sets the value at the given path, and returns the result.
+func setConfigNode(data []byte, path []string, value *yaml3.Node) ([]byte, error) {
+	var doc yaml3.Node
+	if err := yaml3.Unmarshal(data, &doc); err != nil {
+		return nil, errcat.Config.Newf("unable to parse the config: %v", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml3.Node{Kind: yaml3.DocumentNode, Content: []*yaml3.Node{{Kind: yaml3.MappingNode, Tag: "!!map"}}}
+	}
+	n := doc.Content[0]
+	if n.Kind != yaml3.MappingNode {
+		return nil, errcat.Config.New("the config is not a map")
+	}
+	last := len(path) - 1
+	for i, p := range path {
+		var child *yaml3.Node
+		for j := 0; j+1 < len(n.Content); j += 2 {
+			if n.Content[j].Value == p {
+				child = n.Content[j+1]
+				if i == last {
+					// Retain the comments of the replaced value.
+					value.HeadComment, value.LineComment, value.FootComment = child.HeadComment, child.LineComment, child.FootComment
+					n.Content[j+1] = value
+				}
+				break
+			}
+		}
+		if child == nil {
+			if i == last {
+				child = value
+			} else {
+				child = &yaml3.Node{Kind: yaml3.MappingNode, Tag: "!!map"}
+			}
+			n.Content = append(n.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: p}, child)
+		}
+		if i < last {
+			if child.Kind != yaml3.MappingNode {
+				return nil, errcat.User.Newf("config key %q is not a map", strings.Join(path[:i+1], "."))
+			}
+			n = child
+		}
+	}
+	var buf bytes.Buffer
+	enc := yaml3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
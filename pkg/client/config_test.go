@@ -37,6 +37,7 @@ logLevels:
 timeouts:
   clusterConnect: 25s
   proxyDial: 17s
+  keepalive: 30s
 logLevels:
   rootDaemon: trace
 images:
@@ -78,6 +79,7 @@ routing:
 	to := cfg.Timeouts()
 	assert.Equal(t, 25*time.Second, to.PrivateClusterConnect)      // from user
 	assert.Equal(t, 17*time.Second, to.PrivateProxyDial)           // from user
+	assert.Equal(t, 30*time.Second, to.Get(TimeoutKeepalive))      // from user
 	assert.Equal(t, time.Duration(0), to.PrivateConnectivityCheck) // from sys2
 
 	assert.Equal(t, logrus.DebugLevel, cfg.LogLevels().UserDaemon) // from sys2
@@ -180,3 +182,221 @@ logLevels:
 	require.NoError(t, err)
 	require.Equal(t, cfg.LogLevels().UserDaemon, logrus.DebugLevel)
 }
+
+func Test_ConfigUnmarshalDNSFallback(t *testing.T) {
+	ctx := dlog.NewTestContext(t, true)
+	cfg, err := ParseConfigYAML(ctx, "", []byte(`
+dns:
+  fallback: 10.0.0.1
+`))
+	require.NoError(t, err)
+	require.Equal(t, DNSServers{netip.MustParseAddr("10.0.0.1")}, cfg.DNS().Fallback)
+
+	cfg, err = ParseConfigYAML(ctx, "", []byte(`
+dns:
+  fallback:
+    - 10.0.0.1
+    - 10.0.0.2
+`))
+	require.NoError(t, err)
+	require.Equal(t, DNSServers{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2")}, cfg.DNS().Fallback)
+
+	cfgBytes, err := cfg.MarshalYAML()
+	require.NoError(t, err)
+	require.Equal(t, "dns:\n  fallback:\n  - 10.0.0.1\n  - 10.0.0.2\n", string(cfgBytes))
+}
+
+func Test_ConfigUnmarshalDNSRedirect(t *testing.T) {
+	ctx := dlog.NewTestContext(t, true)
+	cfg, err := ParseConfigYAML(ctx, "", []byte(`
+dns:
+  redirect: tun
+`))
+	require.NoError(t, err)
+	require.Equal(t, DNSRedirectTUN, cfg.DNS().Redirect)
+
+	_, err = ParseConfigYAML(ctx, "", []byte(`
+dns:
+  redirect: nftables
+`))
+	require.ErrorContains(t, err, `invalid DNS redirect "nftables"`)
+}
+
+func Test_ConfigUnmarshalInterceptForwardKeepAlive(t *testing.T) {
+	ctx := dlog.NewTestContext(t, true)
+	require.Equal(t, 15*time.Second, GetDefaultConfig().Intercept().ForwardKeepAlive)
+
+	cfg, err := ParseConfigYAML(ctx, "", []byte(`
+intercept:
+  forwardKeepAlive: 0s
+`))
+	require.NoError(t, err)
+	require.Zero(t, cfg.Intercept().ForwardKeepAlive)
+
+	cfg, err = ParseConfigYAML(ctx, "", []byte(`
+intercept:
+  forwardKeepAlive: -1s
+`))
+	require.NoError(t, err)
+	require.Equal(t, -time.Second, cfg.Intercept().ForwardKeepAlive)
+}
+
+func Test_ConfigMergeManagerTLS(t *testing.T) {
+	ctx := dlog.NewTestContext(t, true)
+	cfg, err := ParseConfigYAML(ctx, "", []byte(`
+cluster:
+  managerAddress: tm.example.com:443
+  managerTLS: true
+`))
+	require.NoError(t, err)
+	require.True(t, cfg.Cluster().UseManagerTLS())
+
+	// A config that doesn't set managerTLS leaves it intact.
+	nc := GetDefaultConfig()
+	nc.Cluster().ManagerAddress = "tm.example.com:443"
+	require.True(t, cfg.Merge(nc).Cluster().UseManagerTLS())
+
+	// An explicit false overrides it.
+	off := false
+	nc.Cluster().ManagerTLS = &off
+	require.False(t, cfg.Merge(nc).Cluster().UseManagerTLS())
+}
+
+func TestSetConfigValue(t *testing.T) {
+	in := []byte(`# Telepresence client config
+timeouts:
+  # Wait longer for intercepts
+  intercept: 30s
+`)
+	t.Run("timeout", func(t *testing.T) {
+		out, err := SetConfigValue(in, "timeouts.intercept", "2m")
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "# Telepresence client config")
+		assert.Contains(t, string(out), "# Wait longer for intercepts")
+		cfg, err := ParseConfigYAML(dlog.NewTestContext(t, false), "", out)
+		require.NoError(t, err)
+		assert.Equal(t, 2*time.Minute, cfg.Timeouts().PrivateIntercept)
+	})
+
+	t.Run("routing bool", func(t *testing.T) {
+		out, err := SetConfigValue(in, "routing.autoResolveConflicts", "true")
+		require.NoError(t, err)
+		cfg, err := ParseConfigYAML(dlog.NewTestContext(t, false), "", out)
+		require.NoError(t, err)
+		assert.True(t, cfg.Routing().AutoResolveConflicts)
+		assert.Equal(t, 30*time.Second, cfg.Timeouts().PrivateIntercept)
+	})
+
+	t.Run("subnet list", func(t *testing.T) {
+		out, err := SetConfigValue(nil, "routing.neverProxySubnets", "10.0.0.0/8,192.168.0.0/16")
+		require.NoError(t, err)
+		cfg, err := ParseConfigYAML(dlog.NewTestContext(t, false), "", out)
+		require.NoError(t, err)
+		assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")}, cfg.Routing().NeverProxy)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := SetConfigValue(in, "timeouts.noSuchTimeout", "2m")
+		require.ErrorContains(t, err, `unknown config key "timeouts.noSuchTimeout"`)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := SetConfigValue(in, "timeouts.intercept", "soon")
+		require.Error(t, err)
+	})
+}
+
+func TestValidateConfigYAML(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	errs := ValidateConfigYAML(ctx, "config.yml", []byte(`
+timeouts:
+  intercept: soon
+  helm: 1m
+routing:
+  alsoProxySubnets:
+    - 10.0.0.0/33
+  autoResolveConflicts: true
+logLevels:
+  noSuchDaemon: debug
+`))
+	require.Len(t, errs, 3)
+	assert.Equal(t, "logLevels.noSuchDaemon", errs[0].Path)
+	assert.EqualError(t, errs[0], "logLevels.noSuchDaemon: unknown key")
+	assert.Equal(t, "routing.alsoProxySubnets", errs[1].Path)
+	assert.Contains(t, errs[1].Expected, "netip.Prefix")
+	assert.Equal(t, "timeouts.intercept", errs[2].Path)
+	assert.Equal(t, "time.Duration", errs[2].Expected)
+
+	assert.Empty(t, ValidateConfigYAML(ctx, "config.yml", []byte(`
+timeouts:
+  intercept: 2m
+routing:
+  alsoProxySubnets:
+    - 10.0.0.0/8
+`)))
+}
+
+func TestExpandConfigEnv(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	env, err := LoadEnv()
+	require.NoError(t, err)
+	ctx = WithEnv(ctx, env)
+	ctx = filelocation.WithAppSystemConfigDirs(ctx, []string{})
+	t.Setenv("TP_TEST_NS", "tm-ns")
+	t.Setenv("TP_TEST_REGISTRY", "registry.example.com")
+
+	load := func(t *testing.T, ctx context.Context, yml string) (Config, error) {
+		tmp := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tmp, ConfigFile), []byte(yml), 0o600))
+		return LoadConfig(filelocation.WithAppUserConfigDir(ctx, tmp))
+	}
+
+	t.Run("defined", func(t *testing.T) {
+		cfg, err := load(t, WithConfigEnvExpansion(ctx), `
+cluster:
+  defaultManagerNamespace: ${TP_TEST_NS}
+images:
+  registry: $TP_TEST_REGISTRY/telepresence
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "tm-ns", cfg.Cluster().DefaultManagerNamespace)
+		assert.Equal(t, "registry.example.com/telepresence", cfg.Images().PrivateRegistry)
+	})
+
+	t.Run("undefined", func(t *testing.T) {
+		_, err := load(t, WithConfigEnvExpansion(ctx), `
+cluster:
+  defaultManagerNamespace: ${TP_TEST_UNDEFINED}
+`)
+		require.ErrorContains(t, err, `cluster.defaultManagerNamespace: environment variable "TP_TEST_UNDEFINED" is not defined`)
+	})
+
+	t.Run("escaped", func(t *testing.T) {
+		cfg, err := load(t, WithConfigEnvExpansion(ctx), `
+cluster:
+  defaultManagerNamespace: $${TP_TEST_NS}
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "${TP_TEST_NS}", cfg.Cluster().DefaultManagerNamespace)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		// The root daemon loads the config.yml without expansion, because it doesn't run in the user's environment.
+		cfg, err := load(t, ctx, `
+cluster:
+  defaultManagerNamespace: ${TP_TEST_UNDEFINED}
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "${TP_TEST_UNDEFINED}", cfg.Cluster().DefaultManagerNamespace)
+	})
+
+	t.Run("parsed YAML", func(t *testing.T) {
+		// The YAML of the traffic-manager and the cluster is never expanded.
+		cfg, err := ParseConfigYAML(WithConfigEnvExpansion(ctx), "config.yml", []byte(`
+cluster:
+  defaultManagerNamespace: ${TP_TEST_NS}
+`))
+		require.NoError(t, err)
+		assert.Equal(t, "${TP_TEST_NS}", cfg.Cluster().DefaultManagerNamespace)
+	})
+}
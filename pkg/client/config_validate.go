@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigError describes a problem with one value in a client configuration.
+type ConfigError struct {
+	// Path is the dotted path to the value, e.g. "timeouts.intercept". It is empty when the problem
+	// concerns the configuration as a whole.
+	Path string
+
+	// Expected is the name of the type that the value should have, if known.
+	Expected string
+
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	var sb strings.Builder
+	if e.Path != "" {
+		sb.WriteString(e.Path)
+		sb.WriteString(": ")
+	}
+	if e.Expected != "" {
+		sb.WriteString("expected ")
+		sb.WriteString(e.Expected)
+		sb.WriteString(": ")
+	}
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateConfigYAML parses the given YAML client configuration in the same way as when the configuration
+// is loaded, and returns all problems that are found. Each value is validated in isolation, so that one
+// erroneous value doesn't hide the problems of another. An empty result means that the configuration is
+// valid.
+func ValidateConfigYAML(ctx context.Context, path string, data []byte) []*ConfigError {
+	js, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return []*ConfigError{{Err: err}}
+	}
+	if _, err = UnmarshalJSONConfig(js, true); err != nil {
+		var top map[string]jsontext.Value
+		if err := json.Unmarshal(js, &top); err != nil {
+			return []*ConfigError{{Err: err}}
+		}
+		return validateMembers(nil, top)
+	}
+	cfg, err := ParseConfigYAML(ctx, path, data)
+	if err == nil && configEnvExpansion(ctx) {
+		err = expandConfigEnv(ctx, cfg)
+	}
+	if err == nil {
+		err = ValidateConfigFunc(ctx, cfg)
+	}
+	if err != nil {
+		return []*ConfigError{{Err: err}}
+	}
+	return nil
+}
+
+// validateMembers validates the given members of the object at the given path, in alphabetical order.
+func validateMembers(path []string, members map[string]jsontext.Value) []*ConfigError {
+	var errs []*ConfigError
+	keys := make([]string, 0, len(members))
+	for k := range members {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		errs = append(errs, validateValue(append(slices.Clip(path), k), members[k])...)
+	}
+	return errs
+}
+
+// validateValue validates the given value at the given path. Objects with erroneous content are
+// examined member by member, so that the error is attributed to the innermost value.
+func validateValue(path []string, value jsontext.Value) []*ConfigError {
+	err := validateIsolated(path, value)
+	if err == nil {
+		return nil
+	}
+	if value.Kind() == '{' && validateIsolated(path, jsontext.Value("{}")) == nil {
+		// The object itself is known, so examine its members.
+		var members map[string]jsontext.Value
+		if json.Unmarshal(value, &members) == nil {
+			if errs := validateMembers(path, members); len(errs) > 0 {
+				return errs
+			}
+		}
+	}
+	if isUnknownName(err) {
+		return []*ConfigError{{Path: strings.Join(path, "."), Err: errors.New("unknown key")}}
+	}
+	ce := &ConfigError{Path: strings.Join(path, "."), Err: err}
+	var semanticErr *json.SemanticError
+	if errors.As(err, &semanticErr) {
+		if semanticErr.GoType != nil {
+			ce.Expected = semanticErr.GoType.String()
+		}
+		if semanticErr.Err != nil {
+			ce.Err = semanticErr.Err
+		}
+	}
+	return []*ConfigError{ce}
+}
+
+// validateIsolated unmarshals a configuration that contains nothing but the given value at the given path.
+func validateIsolated(path []string, value jsontext.Value) error {
+	doc := []byte(value)
+	for i := len(path) - 1; i >= 0; i-- {
+		k, err := json.Marshal(path[i])
+		if err != nil {
+			return err
+		}
+		doc = []byte(fmt.Sprintf(`{%s:%s}`, k, doc))
+	}
+	_, err := UnmarshalJSONConfig(doc, true)
+	return err
+}
+
+func isUnknownName(err error) bool {
+	var semanticErr *json.SemanticError
+	return errors.As(err, &semanticErr) && strings.Contains(semanticErr.Error(), "unknown name ")
+}
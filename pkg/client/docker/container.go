@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	dockerClient "github.com/docker/docker/client"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
@@ -32,3 +35,70 @@ func StopContainer(ctx context.Context, nameOrID string) error {
 	dlog.Debugf(ctx, "Container %s stopped", nameOrID)
 	return nil
 }
+
+// PublisherLabel is the label of the containers that publish ports on behalf of a containerized daemon. Its
+// value is the name of the daemon container.
+const PublisherLabel = "telepresence.io/publisher"
+
+// publisherAPI is the part of the docker API that is needed to find and remove orphaned publishers.
+type publisherAPI interface {
+	ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error)
+	ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error)
+	ContainerRemove(ctx context.Context, container string, options container.RemoveOptions) error
+}
+
+// RemoveOrphanedPublishers removes all containers labeled with PublisherLabel whose daemon container is
+// no longer running. Such containers are normally killed when the port publishing ends, but they are left
+// behind if the CLI or the daemon dies abruptly.
+func RemoveOrphanedPublishers(ctx context.Context) {
+	cli, err := GetClient(ctx)
+	if err != nil {
+		dlog.Errorf(ctx, "unable to remove orphaned port publishers: %v", err)
+		return
+	}
+	removeOrphanedPublishers(ctx, cli)
+}
+
+func removeOrphanedPublishers(ctx context.Context, cli publisherAPI) {
+	cl, err := cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", PublisherLabel)),
+	})
+	if err != nil {
+		dlog.Errorf(ctx, "failed to list port publisher containers: %v", err)
+		return
+	}
+	daemonRunning := make(map[string]bool)
+	for _, cn := range cl {
+		daemonName := cn.Labels[PublisherLabel]
+		running, ok := daemonRunning[daemonName]
+		if !ok {
+			running = isContainerRunning(ctx, cli, daemonName)
+			daemonRunning[daemonName] = running
+		}
+		if running {
+			continue
+		}
+		dlog.Debugf(ctx, "Removing port publisher container %s of daemon %s", cn.ID, daemonName)
+		if err = cli.ContainerRemove(ctx, cn.ID, container.RemoveOptions{Force: true}); err != nil && !dockerClient.IsErrNotFound(err) {
+			dlog.Errorf(ctx, "failed to remove port publisher container %s: %v", cn.ID, err)
+		}
+	}
+}
+
+// isContainerRunning returns true if a container with the given name is running. A container that cannot be
+// inspected for other reasons than that it doesn't exist is considered running.
+func isContainerRunning(ctx context.Context, cli publisherAPI, name string) bool {
+	if name == "" {
+		return false
+	}
+	cj, err := cli.ContainerInspect(ctx, name)
+	if err != nil {
+		if dockerClient.IsErrNotFound(err) {
+			return false
+		}
+		dlog.Errorf(ctx, "container inspect on %s failed: %v", name, err)
+		return true
+	}
+	return cj.ContainerJSONBase != nil && cj.State != nil && cj.State.Running
+}
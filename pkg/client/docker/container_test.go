@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/datawire/dlib/dlog"
+)
+
+type fakePublisherAPI struct {
+	containers []types.Container
+
+	// daemons maps the names of existing daemon containers to their running state.
+	daemons map[string]bool
+
+	removed []string
+}
+
+func (f *fakePublisherAPI) ContainerList(_ context.Context, options container.ListOptions) ([]types.Container, error) {
+	var cl []types.Container
+	for _, cn := range f.containers {
+		match := true
+		for _, label := range options.Filters.Get("label") {
+			if _, ok := cn.Labels[label]; !ok {
+				match = false
+			}
+		}
+		if match {
+			cl = append(cl, cn)
+		}
+	}
+	return cl, nil
+}
+
+func (f *fakePublisherAPI) ContainerInspect(_ context.Context, name string) (types.ContainerJSON, error) {
+	running, ok := f.daemons[name]
+	if !ok {
+		return types.ContainerJSON{}, errdefs.NotFound(fmt.Errorf("no such container: %s", name))
+	}
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+		Name:  "/" + name,
+		State: &types.ContainerState{Running: running},
+	}}, nil
+}
+
+func (f *fakePublisherAPI) ContainerRemove(_ context.Context, id string, _ container.RemoveOptions) error {
+	f.removed = append(f.removed, id)
+	return nil
+}
+
+func Test_removeOrphanedPublishers(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	api := &fakePublisherAPI{
+		containers: []types.Container{
+			{ID: "pub-alive-1", Labels: map[string]string{PublisherLabel: "tp-alive"}},
+			{ID: "pub-alive-2", Labels: map[string]string{PublisherLabel: "tp-alive"}},
+			{ID: "pub-gone", Labels: map[string]string{PublisherLabel: "tp-gone"}},
+			{ID: "pub-stopped", Labels: map[string]string{PublisherLabel: "tp-stopped"}},
+			{ID: "unrelated", Labels: map[string]string{"other": "tp-gone"}},
+		},
+		daemons: map[string]bool{
+			"tp-alive":   true,
+			"tp-stopped": false,
+		},
+	}
+	removeOrphanedPublishers(ctx, api)
+	assert.ElementsMatch(t, []string{"pub-gone", "pub-stopped"}, api.removed)
+}
@@ -3,6 +3,8 @@ package docker
 import (
 	"context"
 	"fmt"
+	"net/netip"
+	"net/url"
 	"strings"
 	"sync"
 
@@ -53,3 +55,37 @@ func GetClient(ctx context.Context) (*client.Client, error) {
 	}
 	panic("docker client not initialized")
 }
+
+// IsRemoteHost returns true if the given docker host, in the format used by DOCKER_HOST, refers to a docker daemon
+// on another machine. Unix sockets, named pipes, and addresses on the loopback interface are considered local.
+func IsRemoteHost(host string) bool {
+	u, err := url.Parse(host)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "", "unix", "npipe", "fd":
+		return false
+	}
+	hn := u.Hostname()
+	if hn == "" || strings.EqualFold(hn, "localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(hn); err == nil && addr.IsLoopback() {
+		return false
+	}
+	return true
+}
+
+// RemoteHost returns the host of the docker daemon that is used by the current docker context, or DOCKER_HOST,
+// when that daemon is remote. An empty string is returned when the daemon is local.
+func RemoteHost(ctx context.Context) (string, error) {
+	cli, err := GetClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	if host := cli.DaemonHost(); IsRemoteHost(host) {
+		return host, nil
+	}
+	return "", nil
+}
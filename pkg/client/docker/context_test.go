@@ -0,0 +1,31 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRemoteHost(t *testing.T) {
+	tests := []struct {
+		host   string
+		remote bool
+	}{
+		{"", false},
+		{"unix:///var/run/docker.sock", false},
+		{"npipe:////./pipe/docker_engine", false},
+		{"tcp://localhost:2375", false},
+		{"tcp://127.0.0.1:2375", false},
+		{"tcp://[::1]:2376", false},
+		{"ssh://user@localhost", false},
+		{"tcp://192.168.1.20:2376", true},
+		{"tcp://docker.example.com:2376", true},
+		{"ssh://user@build-host", true},
+		{"https://docker.example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.remote, IsRemoteHost(tt.host))
+		})
+	}
+}
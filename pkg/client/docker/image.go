@@ -9,16 +9,35 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/docker/docker/api/types/image"
+	dockerClient "github.com/docker/docker/client"
+
 	"github.com/telepresenceio/telepresence/v2/pkg/proc"
 )
 
 // BuildImage builds an image from source. Stdout is silenced during those operations. The
 // image ID is returned.
 func BuildImage(ctx context.Context, context string, buildArgs []string) (string, error) {
+	args, err := buildCommandArgs(context, buildArgs)
+	if err != nil {
+		return "", err
+	}
+	cmd := proc.StdCommand(ctx, "docker", args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// buildCommandArgs returns the arguments for the "docker build" that builds an image from the given context,
+// which is either a directory or a Dockerfile, using the given build arguments.
+func buildCommandArgs(context string, buildArgs []string) ([]string, error) {
 	args := append([]string{"build", "--quiet"}, buildArgs...)
 	st, err := os.Stat(context)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if st.Mode().IsRegular() {
 		var fn string
@@ -28,34 +47,69 @@ func BuildImage(ctx context.Context, context string, buildArgs []string) (string
 		} else {
 			fn, err = filepath.Abs(context)
 			if err != nil {
-				return "", err
+				return nil, err
 			}
 		}
 		context = dir
 		args = append(args, "--file", fn)
 	}
-	cmd := proc.StdCommand(ctx, "docker", append(args, context)...)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	if err := cmd.Run(); err != nil {
-		return "", err
+	return append(args, context), nil
+}
+
+// ImageLabels returns the ID and the labels of the local image with the given reference. An empty ID is
+// returned when no such image exists.
+func ImageLabels(ctx context.Context, image string) (string, map[string]string, error) {
+	cli, err := GetClient(ctx)
+	if err != nil {
+		return "", nil, err
 	}
-	return strings.TrimSpace(out.String()), nil
+	ii, _, err := cli.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		if dockerClient.IsErrNotFound(err) {
+			err = nil
+		}
+		return "", nil, err
+	}
+	var labels map[string]string
+	if ii.Config != nil {
+		labels = ii.Config.Labels
+	}
+	return ii.ID, labels, nil
+}
+
+// RemoveImage removes the local image with the given ID. An image that is used by a container isn't removed.
+func RemoveImage(ctx context.Context, id string) error {
+	cli, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = cli.ImageRemove(ctx, id, image.RemoveOptions{PruneChildren: true})
+	return err
 }
 
 // PullImage checks if the given image exists locally by doing docker image inspect. A docker pull is
 // performed if no local image is found. Stdout is silenced during those operations.
 func PullImage(ctx context.Context, image string) error {
+	return PullImageForPlatform(ctx, image, "")
+}
+
+// PullImageForPlatform is like PullImage, but ensures that the image is for the given platform in the form
+// os/arch[/variant], unless the platform is empty.
+func PullImageForPlatform(ctx context.Context, image, platform string) error {
 	cli, err := GetClient(ctx)
 	if err != nil {
 		return err
 	}
-	_, _, err = cli.ImageInspectWithRaw(ctx, image)
-	if err == nil {
+	ii, _, err := cli.ImageInspectWithRaw(ctx, image)
+	if err == nil && (platform == "" || imagePlatformMatches(ii.Os, ii.Architecture, ii.Variant, platform)) {
 		// Image exists in the local cache, so don't bother pulling it.
 		return nil
 	}
-	cmd := proc.StdCommand(ctx, "docker", "pull", image)
+	args := []string{"pull"}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+	cmd := proc.StdCommand(ctx, "docker", append(args, image)...)
 	// Docker run will put the pull logs in stderr, but docker pull will put them in stdout.
 	// We discard them here, so they don't spam the user. They'll get errors through stderr if it comes to it.
 	cmd.Stdout = io.Discard
@@ -72,3 +126,13 @@ func PullImage(ctx context.Context, image string) error {
 
 	return nil
 }
+
+// imagePlatformMatches returns true if the given OS, architecture, and variant of an image match the given
+// platform in the form os/arch[/variant].
+func imagePlatformMatches(imgOS, arch, variant, platform string) bool {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || parts[0] != imgOS || parts[1] != arch {
+		return false
+	}
+	return len(parts) < 3 || parts[2] == variant
+}
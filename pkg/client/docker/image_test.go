@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_imagePlatformMatches(t *testing.T) {
+	tests := []struct {
+		os, arch, variant string
+		platform          string
+		want              bool
+	}{
+		{"linux", "amd64", "", "linux/amd64", true},
+		{"linux", "arm64", "v8", "linux/arm64", true},
+		{"linux", "arm64", "v8", "linux/arm64/v8", true},
+		{"linux", "arm", "v6", "linux/arm/v7", false},
+		{"linux", "arm64", "", "linux/amd64", false},
+		{"windows", "amd64", "", "linux/amd64", false},
+		{"linux", "amd64", "", "amd64", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			assert.Equal(t, tt.want, imagePlatformMatches(tt.os, tt.arch, tt.variant, tt.platform))
+		})
+	}
+}
+
+func Test_buildCommandArgs(t *testing.T) {
+	dir := t.TempDir()
+	dockerfile := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, os.WriteFile(dockerfile, []byte("FROM alpine\n"), 0o644))
+	opts := []string{"--tag=mytag", "--build-arg", "VERSION=1.2", "--secret", "id=npmrc,src=/home/me/.npmrc"}
+
+	args, err := buildCommandArgs(dir, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"build", "--quiet",
+		"--tag=mytag",
+		"--build-arg", "VERSION=1.2",
+		"--secret", "id=npmrc,src=/home/me/.npmrc",
+		dir,
+	}, args)
+
+	args, err = buildCommandArgs(dockerfile, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--file", dockerfile, dir}, args[len(args)-3:])
+
+	_, err = buildCommandArgs(filepath.Join(dir, "missing"), opts)
+	assert.Error(t, err)
+}
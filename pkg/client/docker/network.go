@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/netip"
 	"strings"
 
 	"github.com/docker/docker/api/types/network"
@@ -114,3 +115,22 @@ func EnsureNetwork(ctx context.Context, name string) error {
 	}
 	return err
 }
+
+// GetNetworkSubnets returns the subnets of the network with the given name.
+func GetNetworkSubnets(ctx context.Context, name string) ([]netip.Prefix, error) {
+	cli, err := GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := cli.NetworkInspect(ctx, name, network.InspectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("docker network inspect failed: %w", err)
+	}
+	subnets := make([]netip.Prefix, 0, len(resource.IPAM.Config))
+	for _, cfg := range resource.IPAM.Config {
+		if pfx, err := netip.ParsePrefix(cfg.Subnet); err == nil {
+			subnets = append(subnets, pfx)
+		}
+	}
+	return subnets, nil
+}
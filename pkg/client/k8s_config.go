@@ -82,6 +82,10 @@ type dnsConfig struct {
 	// a lookup. Includes have higher priority than excludes.
 	IncludeSuffixes []string `json:"include-suffixes,omitempty"`
 
+	// BypassSuffixes are suffixes for which the DNS resolver will never consult the cluster.
+	// Queries for matching names are sent directly to the fallback resolver.
+	BypassSuffixes []string `json:"bypass-suffixes,omitempty"`
+
 	// Excludes are a list of hostname that the DNS resolver will not resolve even if they exist.
 	Excludes []string `json:"excludes,omitempty"`
 
@@ -123,6 +127,9 @@ func (ke *kubeconfigExtension) asConfig() Config {
 		if len(keDns.IncludeSuffixes) > 0 {
 			dns.IncludeSuffixes = keDns.IncludeSuffixes
 		}
+		if len(keDns.BypassSuffixes) > 0 {
+			dns.BypassSuffixes = keDns.BypassSuffixes
+		}
 		if len(keDns.Mappings) > 0 {
 			dns.Mappings = keDns.Mappings
 		}
@@ -3,12 +3,15 @@ package k8sclient
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	v1 "k8s.io/api/authorization/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/datawire/k8sapi/pkg/k8sapi"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
 func CanI(ctx context.Context, ra *v1.ResourceAttributes) (bool, error) {
@@ -54,3 +57,61 @@ func CanPortForward(ctx context.Context, namespace string) bool {
 	})
 	return err == nil && ok
 }
+
+// MissingPermissions returns the subset of the given resource attributes that this client is not
+// permitted to use.
+func MissingPermissions(ctx context.Context, ras []*v1.ResourceAttributes) ([]*v1.ResourceAttributes, error) {
+	var missing []*v1.ResourceAttributes
+	for _, ra := range ras {
+		ok, err := CanI(ctx, ra)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			missing = append(missing, ra)
+		}
+	}
+	return missing, nil
+}
+
+// MissingPermissionsError returns a user error that lists the missing permissions needed to perform
+// the given action in the given namespace, and the rules of a Role that would grant them.
+func MissingPermissionsError(action, namespace string, missing []*v1.ResourceAttributes) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "insufficient permissions to %s in namespace %q. The following permissions are missing:\n", action, namespace)
+	type ruleKey struct {
+		group    string
+		resource string
+	}
+	var keys []ruleKey
+	verbs := make(map[ruleKey][]string)
+	for _, ra := range missing {
+		resource := ra.Resource
+		if ra.Subresource != "" {
+			resource += "/" + ra.Subresource
+		}
+		if ra.Group == "" {
+			fmt.Fprintf(&sb, "  %s %s\n", ra.Verb, resource)
+		} else {
+			fmt.Fprintf(&sb, "  %s %s.%s\n", ra.Verb, resource, ra.Group)
+		}
+		k := ruleKey{group: ra.Group, resource: resource}
+		if _, ok := verbs[k]; !ok {
+			keys = append(keys, k)
+		}
+		verbs[k] = append(verbs[k], ra.Verb)
+	}
+	fmt.Fprintf(&sb, "Please ask your cluster administrator for a Role in namespace %q with the following rules:\n", namespace)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "  - apiGroups: [%q]\n    resources: [%q]\n    verbs: [%s]\n", k.group, k.resource, quoteJoin(verbs[k]))
+	}
+	return errcat.User.New(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+func quoteJoin(ss []string) string {
+	qs := make([]string, len(ss))
+	for i, s := range ss {
+		qs[i] = strconv.Quote(s)
+	}
+	return strings.Join(qs, ", ")
+}
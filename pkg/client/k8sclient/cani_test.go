@@ -0,0 +1,58 @@
+package k8sclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	auth "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+func TestMissingPermissions(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	cs.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*auth.SelfSubjectAccessReview)
+		ra := review.Spec.ResourceAttributes
+		review.Status.Allowed = !(ra.Verb == "create" && ra.Resource == "pods" && ra.Subresource == "portforward")
+		return true, review, nil
+	})
+	ctx := k8sapi.WithK8sInterface(dlog.NewTestContext(t, false), cs)
+
+	ras := []*auth.ResourceAttributes{
+		{Namespace: "blue", Verb: "get", Resource: "pods"},
+		{Namespace: "blue", Verb: "create", Resource: "pods", Subresource: "portforward"},
+	}
+	missing, err := MissingPermissions(ctx, ras)
+	require.NoError(t, err)
+	require.Equal(t, ras[1:], missing)
+
+	err = MissingPermissionsError("intercept", "blue", missing)
+	assert.Equal(t, errcat.User, errcat.GetCategory(err))
+	assert.Equal(t, `insufficient permissions to intercept in namespace "blue". The following permissions are missing:
+  create pods/portforward
+Please ask your cluster administrator for a Role in namespace "blue" with the following rules:
+  - apiGroups: [""]
+    resources: ["pods/portforward"]
+    verbs: ["create"]`, err.Error())
+}
+
+func TestMissingPermissionsError_GroupsVerbs(t *testing.T) {
+	err := MissingPermissionsError("ingest", "blue", []*auth.ResourceAttributes{
+		{Namespace: "blue", Verb: "get", Resource: "deployments", Group: "apps"},
+		{Namespace: "blue", Verb: "list", Resource: "deployments", Group: "apps"},
+	})
+	assert.Equal(t, `insufficient permissions to ingest in namespace "blue". The following permissions are missing:
+  get deployments.apps
+  list deployments.apps
+Please ask your cluster administrator for a Role in namespace "blue" with the following rules:
+  - apiGroups: ["apps"]
+    resources: ["deployments"]
+    verbs: ["get", "list"]`, err.Error())
+}
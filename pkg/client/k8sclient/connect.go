@@ -2,13 +2,20 @@ package k8sclient
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/blang/semver/v4"
 	"github.com/cenkalti/backoff/v4"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/datawire/dlib/dlog"
@@ -16,11 +23,13 @@ import (
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/portforward"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
+// ConnectToManager connects to the traffic-manager using a port-forward to the traffic-manager service in the
+// given namespace, or, when the cluster.managerAddress is configured, directly to that address.
 func ConnectToManager(ctx context.Context, namespace string) (*grpc.ClientConn, manager.ManagerClient, *manager.VersionInfo2, error) {
-	grpcAddr := net.JoinHostPort("svc/traffic-manager."+namespace, "api")
-	conn, err := dialClusterGRPC(ctx, grpcAddr)
+	conn, err := dialManager(ctx, namespace)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -29,8 +38,27 @@ func ConnectToManager(ctx context.Context, namespace string) (*grpc.ClientConn,
 	if err != nil {
 		err = client.CheckTimeout(ctx, fmt.Errorf("dial manager: %w", err))
 		conn.Close()
+		return nil, nil, nil, err
 	}
-	return conn, mClient, vi, err
+	if opts := managerDialOptions(ctx, vi); len(opts) > 0 {
+		// The version is known now, so the connection is replaced with one that uses the keepalive options.
+		kConn, err := dialManager(ctx, namespace, opts...)
+		conn.Close()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		conn = kConn
+		mClient = manager.NewManagerClient(conn)
+	}
+	return conn, mClient, vi, nil
+}
+
+func dialManager(ctx context.Context, namespace string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if cc := client.GetConfig(ctx).Cluster(); cc.ManagerAddress != "" {
+		dlog.Debugf(ctx, "Connecting to traffic-manager at %s", cc.ManagerAddress)
+		return dialManagerAddress(cc, opts...)
+	}
+	return dialClusterGRPC(ctx, net.JoinHostPort("svc/traffic-manager."+namespace, "api"), opts...)
 }
 
 type versionAPI interface {
@@ -56,10 +84,99 @@ func ConnectToAgent(
 	return conn, mClient, vi, err
 }
 
-func dialClusterGRPC(ctx context.Context, address string) (*grpc.ClientConn, error) {
-	return grpc.NewClient(portforward.K8sPFScheme+":///"+address, grpc.WithContextDialer(portforward.Dialer(ctx)),
+func dialClusterGRPC(ctx context.Context, address string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return grpc.NewClient(portforward.K8sPFScheme+":///"+address, append([]grpc.DialOption{
+		grpc.WithContextDialer(portforward.Dialer(ctx)),
 		grpc.WithResolvers(portforward.NewResolver(ctx)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)...)
+}
+
+// dialManagerAddress creates a client connection to the traffic-manager at the cluster's ManagerAddress,
+// bypassing port-forward. The connection uses TLS when it's enabled in the given config, and the
+// certificates are loaded before dialing so that a misconfiguration is reported right away.
+func dialManagerAddress(cc *client.Cluster, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	tc, err := managerTLSConfig(cc)
+	if err != nil {
+		return nil, err
+	}
+	creds := insecure.NewCredentials()
+	if tc != nil {
+		creds = credentials.NewTLS(tc)
+	}
+	return grpc.NewClient(cc.ManagerAddress, append(opts, grpc.WithTransportCredentials(creds))...)
+}
+
+// minKeepaliveTime is the default minimum time between keepalive pings that a traffic-manager permits. A client
+// that pings more often is disconnected with a GOAWAY "too_many_pings".
+const minKeepaliveTime = 10 * time.Second
+
+// managerDialOptions returns the options that are specific to connections to the traffic-manager with the
+// given version.
+func managerDialOptions(ctx context.Context, vi *manager.VersionInfo2) []grpc.DialOption {
+	if kp, ok := keepaliveParams(ctx); ok {
+		if permitsKeepalive(vi) {
+			return []grpc.DialOption{grpc.WithKeepaliveParams(kp)}
+		}
+		dlog.Warnf(ctx, "%s %s doesn't permit keepalive pings, so grpc.keepaliveTime is ignored", vi.Name, vi.Version)
+	}
+	return nil
+}
+
+// permitsKeepalive returns true if the traffic-manager with the given version permits keepalive pings from clients.
+// Older versions use the gRPC default, which disconnects clients that ping more often than every 5 minutes.
+func permitsKeepalive(vi *manager.VersionInfo2) bool {
+	v, err := semver.Parse(strings.TrimPrefix(vi.Version, "v"))
+	return err == nil && (v.Major > 2 || v.Major == 2 && v.Minor >= 22)
+}
+
+// keepaliveParams returns the keepalive parameters configured in grpc.keepaliveTime and grpc.keepaliveTimeout,
+// and false when keepalive is disabled. The time is never less than the minimum that a traffic-manager permits
+// by default.
+func keepaliveParams(ctx context.Context) (keepalive.ClientParameters, bool) {
+	g := client.GetConfig(ctx).Grpc()
+	if g.KeepaliveTime <= 0 {
+		return keepalive.ClientParameters{}, false
+	}
+	return keepalive.ClientParameters{
+		Time:                max(g.KeepaliveTime, minKeepaliveTime),
+		Timeout:             g.KeepaliveTimeout,
+		PermitWithoutStream: true,
+	}, true
+}
+
+// managerTLSConfig returns the TLS config to use when connecting to the cluster's ManagerAddress, or nil
+// when TLS isn't enabled. TLS is enabled when ManagerTLS is true, or when any of the certificates are
+// configured. The ManagerClientCert and ManagerClientKey enable mutual TLS, and must be used together.
+func managerTLSConfig(cc *client.Cluster) (*tls.Config, error) {
+	if !cc.UseManagerTLS() && cc.ManagerCACert == "" && cc.ManagerClientCert == "" && cc.ManagerClientKey == "" {
+		return nil, nil
+	}
+	tc := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cc.ManagerCACert != "" {
+		pem, err := os.ReadFile(cc.ManagerCACert)
+		if err != nil {
+			return nil, errcat.Config.Newf("unable to read cluster.managerCACert: %v", err)
+		}
+		tc.RootCAs = x509.NewCertPool()
+		if !tc.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errcat.Config.Newf("cluster.managerCACert %q contains no PEM encoded certificates", cc.ManagerCACert)
+		}
+	}
+	switch {
+	case cc.ManagerClientCert == "" && cc.ManagerClientKey == "":
+	case cc.ManagerClientCert == "":
+		return nil, errcat.Config.New("cluster.managerClientKey is set but cluster.managerClientCert is not")
+	case cc.ManagerClientKey == "":
+		return nil, errcat.Config.New("cluster.managerClientCert is set but cluster.managerClientKey is not")
+	default:
+		cert, err := tls.LoadX509KeyPair(cc.ManagerClientCert, cc.ManagerClientKey)
+		if err != nil {
+			return nil, errcat.Config.Newf("unable to load cluster.managerClientCert and cluster.managerClientKey: %v", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	return tc, nil
 }
 
 func getVersion(ctx context.Context, gc versionAPI) (*manager.VersionInfo2, error) {
@@ -0,0 +1,135 @@
+package k8sclient
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+type stubManager struct {
+	manager.UnimplementedManagerServer
+}
+
+func (stubManager) Version(context.Context, *empty.Empty) (*manager.VersionInfo2, error) {
+	return &manager.VersionInfo2{Name: "stub-manager", Version: "v2.22.0"}, nil
+}
+
+func TestConnectToManagerAddress(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	manager.RegisterManagerServer(srv, stubManager{})
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	defer srv.Stop()
+
+	cfg := client.GetDefaultConfig()
+	cfg.Cluster().ManagerAddress = l.Addr().String()
+	ctx := client.WithConfig(dlog.NewTestContext(t, false), cfg)
+
+	// The namespace is ignored and no port-forward is attempted when the address is configured.
+	conn, _, vi, err := ConnectToManager(ctx, "no-such-namespace")
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "stub-manager", vi.Name)
+	assert.Equal(t, "v2.22.0", vi.Version)
+}
+
+func TestConnectToManagerAddressMisconfiguredTLS(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+	missing := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name    string
+		cluster client.Cluster
+		wantErr string
+	}{
+		{
+			name:    "missing CA",
+			cluster: client.Cluster{ManagerCACert: missing},
+			wantErr: "unable to read cluster.managerCACert",
+		},
+		{
+			name:    "invalid CA",
+			cluster: client.Cluster{ManagerCACert: notPEM},
+			wantErr: "contains no PEM encoded certificates",
+		},
+		{
+			name:    "cert without key",
+			cluster: client.Cluster{ManagerClientCert: notPEM},
+			wantErr: "cluster.managerClientKey is not",
+		},
+		{
+			name:    "key without cert",
+			cluster: client.Cluster{ManagerClientKey: notPEM},
+			wantErr: "cluster.managerClientCert is not",
+		},
+		{
+			name:    "missing client cert",
+			cluster: client.Cluster{ManagerClientCert: missing, ManagerClientKey: missing},
+			wantErr: "unable to load cluster.managerClientCert and cluster.managerClientKey",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := client.GetDefaultConfig()
+			cc := cfg.Cluster()
+			cc.ManagerAddress = "127.0.0.1:1"
+			cc.ManagerCACert = tt.cluster.ManagerCACert
+			cc.ManagerClientCert = tt.cluster.ManagerClientCert
+			cc.ManagerClientKey = tt.cluster.ManagerClientKey
+			ctx := client.WithConfig(dlog.NewTestContext(t, false), cfg)
+
+			// The error is returned before dialing, so there's no need for a server, and no timeout.
+			_, _, _, err := ConnectToManager(ctx, "no-such-namespace")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, errcat.Config, errcat.GetCategory(err))
+		})
+	}
+}
+
+func TestManagerDialOptionsKeepalive(t *testing.T) {
+	ctx := client.WithConfig(dlog.NewTestContext(t, false), client.GetDefaultConfig())
+	vi := &manager.VersionInfo2{Name: "Traffic Manager", Version: "v2.22.0"}
+	_, ok := keepaliveParams(ctx)
+	assert.False(t, ok)
+	assert.Empty(t, managerDialOptions(ctx, vi))
+
+	cfg := client.GetDefaultConfig()
+	cfg.Grpc().KeepaliveTime = 30 * time.Second
+	cfg.Grpc().KeepaliveTimeout = 5 * time.Second
+	ctx = client.WithConfig(ctx, cfg)
+	kp, ok := keepaliveParams(ctx)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, kp.Time)
+	assert.Equal(t, 5*time.Second, kp.Timeout)
+	assert.True(t, kp.PermitWithoutStream)
+	assert.Len(t, managerDialOptions(ctx, vi), 1)
+
+	// A traffic-manager that doesn't permit pings gets none.
+	assert.Empty(t, managerDialOptions(ctx, &manager.VersionInfo2{Name: "Traffic Manager", Version: "v2.21.3"}))
+
+	// The time is clamped to the minimum that the traffic-manager permits.
+	cfg.Grpc().KeepaliveTime = time.Second
+	ctx = client.WithConfig(ctx, cfg)
+	kp, ok = keepaliveParams(ctx)
+	require.True(t, ok)
+	assert.Equal(t, minKeepaliveTime, kp.Time)
+}
@@ -63,6 +63,19 @@ func (rotateDaily) RotateNow(rf *RotatingFile, _ int) bool {
 	return dtime.Now().In(bt.Location()).Day() != rf.BirthTime().Day()
 }
 
+type rotateBySize int64
+
+// RotateBySize returns a strategy that will ensure that the file is rotated if it is of non-zero size and
+// a call to Write() would make it grow beyond the given size.
+func RotateBySize(maxSize int64) RotationStrategy {
+	return rotateBySize(maxSize)
+}
+
+func (r rotateBySize) RotateNow(rf *RotatingFile, writeSize int) bool {
+	sz := rf.Size()
+	return sz > 0 && sz+int64(writeSize) > int64(r)
+}
+
 type RotatingFile struct {
 	ctx         context.Context
 	fileMode    fs.FileMode
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
 
 	"github.com/datawire/dlib/dgroup"
 	"github.com/datawire/dlib/dlog"
@@ -15,13 +16,18 @@ import (
 )
 
 type bridgeMounter struct {
+	mountStatus
+	localAddress  string
 	localPort     uint16
 	sessionID     string
 	managerClient manager.ManagerClient
 }
 
-func NewBridgeMounter(sessionID string, managerClient manager.ManagerClient, localPort uint16) Mounter {
+// NewBridgeMounter returns a Mounter that listens to the given local address and port, and forwards
+// connections to the remote SFTP server. All interfaces are used when the localAddress is empty.
+func NewBridgeMounter(sessionID string, managerClient manager.ManagerClient, localAddress string, localPort uint16) Mounter {
 	return &bridgeMounter{
+		localAddress:  localAddress,
 		localPort:     localPort,
 		sessionID:     sessionID,
 		managerClient: managerClient,
@@ -30,18 +36,19 @@ func NewBridgeMounter(sessionID string, managerClient manager.ManagerClient, loc
 
 func (m *bridgeMounter) Start(ctx context.Context, _, _, _, _ string, podIP net.IP, port uint16, _ bool) error {
 	ctx = dgroup.WithGoroutineName(ctx, iputil.JoinIpPort(podIP, port))
-	lc := &net.ListenConfig{}
-	la := fmt.Sprintf(":%d", m.localPort)
-	l, err := lc.Listen(ctx, "tcp", la)
+	l, err := m.listen(ctx)
 	if err != nil {
+		m.setDisconnected(err)
 		return err
 	}
-	dlog.Debugf(ctx, "Remote mount bridge listening at %s, will forward to %s", la, iputil.JoinIpPort(podIP, port))
+	m.setConnected()
+	dlog.Debugf(ctx, "Remote mount bridge listening at %s, will forward to %s", l.Addr(), iputil.JoinIpPort(podIP, port))
 	go func() {
 		for {
 			conn, err := l.Accept()
 			if err != nil {
 				dlog.Errorf(ctx, "mount listener failed: %v", err)
+				m.setDisconnected(err)
 				return
 			}
 			if ctx.Err() != nil {
@@ -57,6 +64,12 @@ func (m *bridgeMounter) Start(ctx context.Context, _, _, _, _ string, podIP net.
 	return nil
 }
 
+// listen creates the listener that the bridge accepts connections on.
+func (m *bridgeMounter) listen(ctx context.Context) (net.Listener, error) {
+	lc := &net.ListenConfig{}
+	return lc.Listen(ctx, "tcp", net.JoinHostPort(m.localAddress, strconv.Itoa(int(m.localPort))))
+}
+
 func (m *bridgeMounter) dispatchToTunnel(ctx context.Context, conn net.Conn, podIP net.IP, port uint16) error {
 	tcpAddr, ok := conn.LocalAddr().(*net.TCPAddr)
 	if !ok {
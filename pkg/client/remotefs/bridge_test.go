@@ -0,0 +1,33 @@
+package remotefs
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+)
+
+func Test_bridgeMounterListen(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+
+	t.Run("loopback", func(t *testing.T) {
+		m := NewBridgeMounter("session", nil, "127.0.0.1", 0).(*bridgeMounter)
+		l, err := m.listen(ctx)
+		require.NoError(t, err)
+		defer l.Close()
+		addr := l.Addr().(*net.TCPAddr)
+		assert.True(t, addr.IP.Equal(net.IPv4(127, 0, 0, 1)), "bound to %s", addr.IP)
+		assert.NotZero(t, addr.Port)
+	})
+
+	t.Run("all interfaces", func(t *testing.T) {
+		m := NewBridgeMounter("session", nil, "", 0).(*bridgeMounter)
+		l, err := m.listen(ctx)
+		require.NoError(t, err)
+		defer l.Close()
+		assert.True(t, l.Addr().(*net.TCPAddr).IP.IsUnspecified())
+	})
+}
@@ -2,6 +2,7 @@ package remotefs
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/netip"
 	"strings"
@@ -18,9 +19,15 @@ import (
 )
 
 type ftpMounter struct {
+	sync.Mutex
+	mountStatus
 	client  rpc.FuseFTPClient
-	id      *rpc.MountIdentifier
 	iceptWG *sync.WaitGroup
+
+	// id and clientMountPoint identify the FUSE host once it has been mounted. The host is reused
+	// by subsequent calls to Start for the same container.
+	id               *rpc.MountIdentifier
+	clientMountPoint string
 }
 
 func NewFTPMounter(client rpc.FuseFTPClient, iceptWG *sync.WaitGroup) Mounter {
@@ -35,6 +42,8 @@ func (m *ftpMounter) Start(ctx context.Context, workload, container, clientMount
 	if ro {
 		roTxt = " read-only"
 	}
+	m.Lock()
+	defer m.Unlock()
 	if m.id == nil {
 		cfg := client.GetConfig(ctx)
 		dlog.Infof(ctx, "Mounting FTP file system for container %s[%s] (address %s)%s at %q", workload, container, addr, roTxt, clientMountPoint)
@@ -53,9 +62,12 @@ func (m *ftpMounter) Start(ctx context.Context, workload, container, clientMount
 		})
 		cancel()
 		if err != nil {
+			m.setDisconnected(err)
 			return err
 		}
+		m.setConnected()
 		m.id = mountId
+		m.clientMountPoint = clientMountPoint
 
 		// Ensure unmount when intercept context is cancelled
 		m.iceptWG.Add(1)
@@ -68,11 +80,16 @@ func (m *ftpMounter) Start(ctx context.Context, workload, container, clientMount
 			if _, err = m.client.Unmount(ctx, m.id); err != nil {
 				dlog.Errorf(ctx, "Unmount of %s failed: %v", clientMountPoint, err)
 			}
+			m.setDisconnected(err)
 		}()
 		dlog.Infof(ctx, "File system for container %s[%s] (address %s) successfully mounted%s at %q", workload, container, addr, roTxt, clientMountPoint)
 		return nil
 	}
 
+	if clientMountPoint != m.clientMountPoint {
+		return fmt.Errorf("FTP file system for container %s[%s] is already mounted at %q", workload, container, m.clientMountPoint)
+	}
+
 	// Assign a new address to the FTP client. This kills any open connections but leaves the FUSE driver intact
 	dlog.Infof(ctx, "Switching remote address to %s for FTP file system for workload container %s[%s] at %q", addr, workload, container, clientMountPoint)
 	_, err := m.client.SetFtpServer(ctx, &rpc.SetFtpServerRequest{
@@ -82,5 +99,10 @@ func (m *ftpMounter) Start(ctx context.Context, workload, container, clientMount
 		},
 		Id: m.id,
 	})
+	if err != nil {
+		m.setDisconnected(err)
+	} else {
+		m.setConnected()
+	}
 	return err
 }
@@ -0,0 +1,74 @@
+package remotefs
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/go-fuseftp/rpc"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+)
+
+type fakeFuseFTPClient struct {
+	rpc.FuseFTPClient
+	sync.Mutex
+	mounts        int
+	setFtpServers int
+	unmounts      int
+}
+
+func (f *fakeFuseFTPClient) Mount(context.Context, *rpc.MountRequest, ...grpc.CallOption) (*rpc.MountIdentifier, error) {
+	f.Lock()
+	f.mounts++
+	f.Unlock()
+	return &rpc.MountIdentifier{}, nil
+}
+
+func (f *fakeFuseFTPClient) SetFtpServer(context.Context, *rpc.SetFtpServerRequest, ...grpc.CallOption) (*emptypb.Empty, error) {
+	f.Lock()
+	f.setFtpServers++
+	f.Unlock()
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeFuseFTPClient) Unmount(context.Context, *rpc.MountIdentifier, ...grpc.CallOption) (*emptypb.Empty, error) {
+	f.Lock()
+	f.unmounts++
+	f.Unlock()
+	return &emptypb.Empty{}, nil
+}
+
+func Test_ftpMounter_reuse(t *testing.T) {
+	ctx := client.WithConfig(dlog.NewTestContext(t, false), client.GetDefaultConfig())
+	ctx, cancel := context.WithCancel(ctx)
+	fc := &fakeFuseFTPClient{}
+	wg := &sync.WaitGroup{}
+	m := NewFTPMounter(fc, wg)
+
+	podIP := net.ParseIP("10.1.2.3").To4()
+	otherPodIP := net.ParseIP("10.1.2.4").To4()
+	wgs := sync.WaitGroup{}
+	for range 3 {
+		wgs.Add(1)
+		go func() {
+			defer wgs.Done()
+			assert.NoError(t, m.Start(ctx, "echo", "echo", "/tmp/mnt", "/tel_app_mounts", podIP, 8021, true))
+		}()
+	}
+	wgs.Wait()
+	require.NoError(t, m.Start(ctx, "echo", "echo", "/tmp/mnt", "/tel_app_mounts", otherPodIP, 8021, true))
+	assert.Error(t, m.Start(ctx, "echo", "echo", "/tmp/other", "/tel_app_mounts", podIP, 8021, true))
+
+	cancel()
+	wg.Wait()
+	assert.Equal(t, 1, fc.mounts)
+	assert.Equal(t, 3, fc.setFtpServers)
+	assert.Equal(t, 1, fc.unmounts)
+}
@@ -3,6 +3,8 @@ package remotefs
 import (
 	"context"
 	"net"
+	"sync"
+	"time"
 )
 
 // A Mounter is responsible for mounting a remote filesystem in a local directory or drive letter.
@@ -11,4 +13,71 @@ type Mounter interface {
 	// given ty clientMountPoint. The podIP and port is the address to the remote FTP or SFTP server.
 	// The id is just used for logging purposes.
 	Start(ctx context.Context, workload, container, clientMountPoint, mountPoint string, podIP net.IP, port uint16, ro bool) error
+
+	// Status returns the current status of the mount.
+	Status() Status
+}
+
+// Status describes the live state of a Mounter.
+type Status struct {
+	// Connected is true when the mount is established.
+	Connected bool
+
+	// LastError is the last error that the mount encountered, or nil if no error has been encountered.
+	LastError error
+}
+
+// mountStatus is embedded by the mounters to keep track of their Status.
+type mountStatus struct {
+	statusLock sync.Mutex
+	status     Status
+}
+
+func (s *mountStatus) Status() Status {
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+	return s.status
+}
+
+// setConnected marks the mount as connected.
+func (s *mountStatus) setConnected() {
+	s.statusLock.Lock()
+	s.status.Connected = true
+	s.statusLock.Unlock()
+}
+
+// mountPollInterval is the interval at which setConnectedWhenMounted checks the mount point.
+const mountPollInterval = 100 * time.Millisecond
+
+// setConnectedWhenMounted checks the given mount point until the mounted function reports that it is mounted,
+// and then marks the mount as connected. It returns when that happens or when the context is cancelled. The
+// mount is never marked as connected after the context is cancelled.
+func (s *mountStatus) setConnectedWhenMounted(ctx context.Context, mountPoint string, mounted func(string) bool) {
+	ticker := time.NewTicker(mountPollInterval)
+	defer ticker.Stop()
+	for {
+		if mounted(mountPoint) {
+			s.statusLock.Lock()
+			if ctx.Err() == nil {
+				s.status.Connected = true
+			}
+			s.statusLock.Unlock()
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setDisconnected marks the mount as disconnected, and retains the given error unless it is nil.
+func (s *mountStatus) setDisconnected(err error) {
+	s.statusLock.Lock()
+	s.status.Connected = false
+	if err != nil {
+		s.status.LastError = err
+	}
+	s.statusLock.Unlock()
 }
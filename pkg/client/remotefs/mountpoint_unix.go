@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package remotefs
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// isMountPoint returns true if a file system is mounted at the given directory, i.e. if the directory
+// resides on a different device than its parent.
+func isMountPoint(dir string) bool {
+	var st, pst unix.Stat_t
+	if unix.Stat(dir, &st) != nil || unix.Stat(filepath.Dir(dir), &pst) != nil {
+		return false
+	}
+	return st.Dev != pst.Dev
+}
@@ -0,0 +1,12 @@
+package remotefs
+
+import (
+	"os"
+)
+
+// isMountPoint returns true if a file system is mounted at the given drive letter or directory. The
+// sshfs-win program creates the drive letter or directory when the mount is established.
+func isMountPoint(dir string) bool {
+	_, err := os.Stat(dir)
+	return err == nil
+}
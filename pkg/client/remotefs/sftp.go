@@ -4,28 +4,41 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/netip"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 
-	"github.com/datawire/dlib/dexec"
 	"github.com/datawire/dlib/dgroup"
 	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
 	"github.com/telepresenceio/telepresence/v2/pkg/dpipe"
 	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
 	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+	"github.com/telepresenceio/telepresence/v2/pkg/routing"
 )
 
 type sftpMounter struct {
 	sync.Mutex
+	mountStatus
 	iceptWG *sync.WaitGroup
 	podWG   *sync.WaitGroup
+
+	// retryBackOff returns the backoff that controls how a failed sshfs mount is retried.
+	retryBackOff func(context.Context) backoff.BackOffContext
 }
 
 func NewSFTPMounter(iceptWG, podWG *sync.WaitGroup) Mounter {
-	return &sftpMounter{iceptWG: iceptWG, podWG: podWG}
+	return &sftpMounter{
+		iceptWG: iceptWG,
+		podWG:   podWG,
+		retryBackOff: func(ctx context.Context) backoff.BackOffContext {
+			return sftpRetryBackOff(ctx, backoff.SystemClock)
+		},
+	}
 }
 
 func (m *sftpMounter) Start(ctx context.Context, workload, container, clientMountPoint, mountPoint string, podIP net.IP, port uint16, ro bool) error {
@@ -51,75 +64,189 @@ func (m *sftpMounter) Start(ctx context.Context, workload, container, clientMoun
 				time.Sleep(time.Second)
 
 				// sshfs sometimes leave the mount point in a bad state. This will clean it up
-				ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
-				defer cancel()
-				var umount *dexec.Cmd
-				if runtime.GOOS == "darwin" {
-					umount = proc.CommandContext(ctx, "umount", "-f", clientMountPoint)
-				} else {
-					umount = proc.CommandContext(ctx, "fusermount", "-uz", clientMountPoint)
-				}
-				umount.DisableLogging = true
-				_ = umount.Run()
+				unmountSSHFS(context.WithoutCancel(ctx), runtime.GOOS, clientMountPoint, runQuietly)
 			}()
 		}
 
 		// Retry mount in case it gets disconnected
-		bc := backoff.WithContext(backoff.NewConstantBackOff(3*time.Second), ctx)
+		bc := m.retryBackOff(ctx)
+		exe, args := sshfsCommand(ctx, clientMountPoint, mountPoint, podIP, port, ro)
 		err := backoff.Retry(func() error {
-			sshfsArgs := []string{
-				"-F", "none", // don't load the user's config file
-				"-f", // foreground operation
-
-				// connection settings
-				"-C", // compression
-				"-oConnectTimeout=10",
-
-				// mount directives
-				"-o", "follow_symlinks",
-				"-o", "allow_root", // needed to make --docker-run work as docker runs as root
-			}
-			if ro {
-				sshfsArgs = append(sshfsArgs, "-o", "ro")
-			}
-
-			useIPv6 := len(podIP) == 16
-			if useIPv6 {
-				// Must use stdin/stdout because sshfs is not capable of connecting with IPv6
-				sshfsArgs = append(sshfsArgs,
-					"-o", "slave",
-					fmt.Sprintf("localhost:%s", mountPoint),
-					clientMountPoint, // where to mount it
-				)
-			} else {
-				sshfsArgs = append(sshfsArgs,
-					"-o", fmt.Sprintf("directport=%d", port),
-					fmt.Sprintf("%s:%s", podIP.String(), mountPoint), // what to mount
-					clientMountPoint, // where to mount it
-				)
-			}
-
-			exe := "sshfs"
-			if runtime.GOOS == "windows" {
-				// Use sshfs-win to launch the sshfs
-				sshfsArgs = append([]string{"cmd", "-ouid=-1", "-ogid=-1"}, sshfsArgs...)
-				exe = "sshfs-win"
-			}
-			var err error
-			if useIPv6 {
-				var conn net.Conn
-				if conn, err = net.Dial("tcp6", iputil.JoinIpPort(podIP, port)); err == nil {
-					defer conn.Close()
-					err = dpipe.DPipe(ctx, conn, exe, sshfsArgs...)
-				}
-			} else {
-				err = proc.Run(ctx, nil, exe, sshfsArgs...)
-			}
+			// sshfs runs in the foreground for as long as the mount is established, but the mount
+			// isn't connected until sshfs has mounted the remote file system.
+			mCtx, cancel := context.WithCancel(ctx)
+			go m.setConnectedWhenMounted(mCtx, clientMountPoint, isMountPoint)
+			err := runSSHFS(ctx, exe, args, podIP, port)
+			cancel()
+			m.setDisconnected(err)
 			return err
 		}, bc)
-		if err != nil {
-			dlog.Error(ctx, err)
+		if err != nil && ctx.Err() == nil {
+			dlog.Errorf(ctx, "Giving up on SFTP mount of container %s[%s] (pod %s) at %q: %v", workload, container, podIP, clientMountPoint, err)
 		}
 	}()
 	return nil
 }
+
+// minSFTPRetry is the shortest interval between attempts to restart a failed sshfs mount. It prevents that a
+// zero, or very small, sftpRetry timeout makes the retries spin.
+const minSFTPRetry = 100 * time.Millisecond
+
+// sftpRetryBackOff returns the backoff that controls how a failed sshfs mount is retried. The retries
+// use the interval given by the sftpRetry timeout, but never less than minSFTPRetry, and end when the
+// context is cancelled, or when the sftpRetryMax timeout has elapsed, unless it is zero. The elapsed
+// time is measured using the given clock.
+func sftpRetryBackOff(ctx context.Context, clock backoff.Clock) backoff.BackOffContext {
+	tos := client.GetConfig(ctx).Timeouts()
+	interval := max(tos.Get(client.TimeoutSFTPRetry), minSFTPRetry)
+	b := &backoff.ExponentialBackOff{
+		InitialInterval:     interval,
+		RandomizationFactor: 0,
+		Multiplier:          1,
+		MaxInterval:         interval,
+		MaxElapsedTime:      tos.Get(client.TimeoutSFTPRetryMax),
+		Stop:                backoff.Stop,
+		Clock:               clock,
+	}
+	b.Reset()
+	return backoff.WithContext(b, ctx)
+}
+
+// commandRunner runs a command and waits for it to complete.
+type commandRunner func(ctx context.Context, exe string, args ...string) error
+
+// runQuietly is a commandRunner that doesn't log the command or its output.
+func runQuietly(ctx context.Context, exe string, args ...string) error {
+	cmd := proc.CommandContext(ctx, exe, args...)
+	cmd.DisableLogging = true
+	return cmd.Run()
+}
+
+// unmountSSHFS unmounts the given mount point, and falls back to a forced, or lazy, unmount when the
+// normal unmount fails. Each attempt is given one second to complete.
+func unmountSSHFS(ctx context.Context, goos, clientMountPoint string, run commandRunner) {
+	var cmds [][]string
+	if goos == "darwin" {
+		cmds = [][]string{
+			{"umount", "-f", clientMountPoint},
+			{"diskutil", "unmount", "force", clientMountPoint},
+		}
+	} else {
+		cmds = [][]string{
+			{"fusermount", "-uz", clientMountPoint},
+			{"umount", "-l", clientMountPoint},
+		}
+	}
+	var err error
+	for _, cmd := range cmds {
+		cmdLine := strings.Join(cmd, " ")
+		tCtx, cancel := context.WithTimeout(ctx, time.Second)
+		err = run(tCtx, cmd[0], cmd[1:]...)
+		cancel()
+		if err == nil {
+			dlog.Infof(ctx, "Unmounted %q using %s", clientMountPoint, cmdLine)
+			return
+		}
+		dlog.Debugf(ctx, "%s failed: %v", cmdLine, err)
+	}
+	dlog.Debugf(ctx, "Unable to unmount %q, it might not have been mounted", clientMountPoint)
+}
+
+// sshfsCommand returns the executable and the arguments used when starting sshfs to mount the given
+// mountPoint of the pod with the given IP at clientMountPoint.
+func sshfsCommand(ctx context.Context, clientMountPoint, mountPoint string, podIP net.IP, port uint16, ro bool) (string, []string) {
+	args := sshfsArgs(ctx, clientMountPoint, mountPoint, podIP, port, ro)
+	if runtime.GOOS == "windows" {
+		// Use sshfs-win to launch the sshfs
+		return "sshfs-win", append([]string{"cmd", "-ouid=-1", "-ogid=-1"}, args...)
+	}
+	return "sshfs", args
+}
+
+// runSSHFS runs sshfs until it exits. The sshfs program is not capable of connecting to an IPv6
+// address, so when the pod IP is IPv6, the connection is established here and passed to sshfs on
+// its stdin/stdout. A link-local IPv6 address is dialed using the interface that routes it as its zone.
+func runSSHFS(ctx context.Context, exe string, args []string, podIP net.IP, port uint16) error {
+	if podIP.To4() != nil {
+		return proc.Run(ctx, nil, exe, args...)
+	}
+	zone := ""
+	if podIP.IsLinkLocalUnicast() {
+		rt, err := routing.GetRoutingTable(ctx)
+		if err != nil {
+			return err
+		}
+		if zone = linkLocalZone(rt, podIP); zone == "" {
+			return backoff.Permanent(fmt.Errorf("unable to find the interface that routes link-local address %s", podIP))
+		}
+	}
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp6", iputil.JoinIpZonePort(podIP, zone, port))
+	if err != nil {
+		return err
+	}
+	return dpipe.DPipe(ctx, conn, exe, args...)
+}
+
+// linkLocalZone returns the name of the interface of the most specific of the given routes that routes the
+// given link-local address, or an empty string when no such route exists. A link-local address must be dialed
+// with that name as its zone.
+func linkLocalZone(routes []*routing.Route, ip net.IP) string {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return ""
+	}
+	addr = addr.Unmap()
+	var best *routing.Route
+	for _, r := range routes {
+		if r.Interface != nil && r.Routes(addr) && (best == nil || r.RoutedNet.Bits() > best.RoutedNet.Bits()) {
+			best = r
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Interface.Name
+}
+
+// sshfsArgs returns the arguments used when starting sshfs to mount the given mountPoint of the pod with the
+// given IP at clientMountPoint.
+func sshfsArgs(ctx context.Context, clientMountPoint, mountPoint string, podIP net.IP, port uint16, ro bool) []string {
+	args := []string{
+		"-F", "none", // don't load the user's config file
+		"-f", // foreground operation
+
+		// connection settings
+		"-C", // compression
+		"-oConnectTimeout=10",
+
+		// mount directives
+		"-o", "follow_symlinks",
+		"-o", "allow_root", // needed to make --docker-run work as docker runs as root
+	}
+	if ro {
+		args = append(args, "-o", "ro")
+	}
+	ic := client.GetConfig(ctx).Intercept()
+	if ic.FuseAttrTimeout > 0 {
+		args = append(args, "-o", fmt.Sprintf("attr_timeout=%g", ic.FuseAttrTimeout.Seconds()))
+	}
+	if ic.FuseEntryTimeout > 0 {
+		args = append(args, "-o", fmt.Sprintf("entry_timeout=%g", ic.FuseEntryTimeout.Seconds()))
+	}
+
+	if podIP.To4() == nil {
+		// Must use stdin/stdout because sshfs is not capable of connecting with IPv6
+		args = append(args,
+			"-o", "slave",
+			fmt.Sprintf("localhost:%s", mountPoint),
+			clientMountPoint, // where to mount it
+		)
+	} else {
+		args = append(args,
+			"-o", fmt.Sprintf("directport=%d", port),
+			fmt.Sprintf("%s:%s", podIP.String(), mountPoint), // what to mount
+			clientMountPoint, // where to mount it
+		)
+	}
+	return args
+}
@@ -0,0 +1,245 @@
+package remotefs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/routing"
+)
+
+func Test_sshfsArgs(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	podIP := net.ParseIP("10.1.2.3").To4()
+
+	t.Run("default timeouts", func(t *testing.T) {
+		ctx := client.WithConfig(ctx, client.GetDefaultConfig())
+		args := sshfsArgs(ctx, "/tmp/mnt", "/tel_app_mounts", podIP, 8022, false)
+		for _, arg := range args {
+			assert.NotContains(t, arg, "attr_timeout")
+			assert.NotContains(t, arg, "entry_timeout")
+		}
+		assert.Equal(t, []string{"-o", "directport=8022", "10.1.2.3:/tel_app_mounts", "/tmp/mnt"}, args[len(args)-4:])
+	})
+
+	t.Run("configured timeouts", func(t *testing.T) {
+		cfg := client.GetDefaultConfig()
+		cfg.Intercept().FuseAttrTimeout = 500 * time.Millisecond
+		cfg.Intercept().FuseEntryTimeout = 30 * time.Second
+		ctx := client.WithConfig(ctx, cfg)
+		args := sshfsArgs(ctx, "/tmp/mnt", "/tel_app_mounts", podIP, 8022, true)
+		assert.Subset(t, args, []string{"ro", "attr_timeout=0.5", "entry_timeout=30"})
+	})
+}
+
+// fakeClock is a backoff.Clock that only advances when told to.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func Test_sftpRetryBackOff(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+
+	// waitAll returns the intervals of the given backoff until it stops, advancing the clock by each interval.
+	waitAll := func(bc backoff.BackOff, clock *fakeClock) []time.Duration {
+		var waits []time.Duration
+		for next := bc.NextBackOff(); next != backoff.Stop; next = bc.NextBackOff() {
+			waits = append(waits, next)
+			clock.now = clock.now.Add(next)
+		}
+		return waits
+	}
+
+	t.Run("default", func(t *testing.T) {
+		bc := sftpRetryBackOff(client.WithConfig(ctx, client.GetDefaultConfig()), &fakeClock{})
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, 3*time.Second, bc.NextBackOff())
+		}
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		cfg := client.GetDefaultConfig()
+		cfg.Timeouts().PrivateSFTPRetry = 200 * time.Millisecond
+		cfg.Timeouts().PrivateSFTPRetryMax = time.Second
+		clock := &fakeClock{}
+		waits := waitAll(sftpRetryBackOff(client.WithConfig(ctx, cfg), clock), clock)
+		assert.Equal(t, []time.Duration{
+			200 * time.Millisecond,
+			200 * time.Millisecond,
+			200 * time.Millisecond,
+			200 * time.Millisecond,
+			200 * time.Millisecond,
+		}, waits)
+	})
+
+	t.Run("zero interval", func(t *testing.T) {
+		cfg := client.GetDefaultConfig()
+		cfg.Timeouts().PrivateSFTPRetry = 0
+		cfg.Timeouts().PrivateSFTPRetryMax = 300 * time.Millisecond
+		clock := &fakeClock{}
+		waits := waitAll(sftpRetryBackOff(client.WithConfig(ctx, cfg), clock), clock)
+		assert.Equal(t, []time.Duration{minSFTPRetry, minSFTPRetry, minSFTPRetry}, waits)
+	})
+}
+
+func Test_sshfsArgsIPv6(t *testing.T) {
+	ctx := client.WithConfig(dlog.NewTestContext(t, false), client.GetDefaultConfig())
+
+	t.Run("IPv6 uses slave mode", func(t *testing.T) {
+		args := sshfsArgs(ctx, "/tmp/mnt", "/tel_app_mounts", net.ParseIP("fd00::1"), 8022, true)
+		assert.Subset(t, args, []string{"ro", "slave"})
+		assert.NotContains(t, args, "directport=8022")
+		assert.Equal(t, []string{"-o", "ro", "-o", "slave", "localhost:/tel_app_mounts", "/tmp/mnt"}, args[len(args)-6:])
+	})
+
+	t.Run("IPv4 in IPv6 form uses directport", func(t *testing.T) {
+		args := sshfsArgs(ctx, "/tmp/mnt", "/tel_app_mounts", net.ParseIP("10.1.2.3"), 8022, true)
+		assert.Equal(t, []string{"-o", "ro", "-o", "directport=8022", "10.1.2.3:/tel_app_mounts", "/tmp/mnt"}, args[len(args)-6:])
+	})
+}
+
+func Test_linkLocalZone(t *testing.T) {
+	routes := []*routing.Route{
+		{RoutedNet: netip.MustParsePrefix("::/0"), Interface: &net.Interface{Name: "eth0"}, Default: true},
+		{RoutedNet: netip.MustParsePrefix("fe80::/64"), Interface: &net.Interface{Name: "eth1"}},
+		{RoutedNet: netip.MustParsePrefix("fe80::/10"), Interface: &net.Interface{Name: "tel0"}},
+	}
+	assert.Equal(t, "eth1", linkLocalZone(routes, net.ParseIP("fe80::1")))
+	assert.Equal(t, "tel0", linkLocalZone(routes, net.ParseIP("fe80:0:0:1::1")))
+	assert.Equal(t, "", linkLocalZone(routes[:0], net.ParseIP("fe80::1")))
+}
+
+func Test_unmountSSHFS(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+
+	// fakeRunner records the commands that it runs, and fails the ones that are listed in failing.
+	type fakeRunner struct {
+		ran     []string
+		failing map[string]bool
+	}
+	newRunner := func(failing ...string) (*fakeRunner, commandRunner) {
+		fr := &fakeRunner{failing: make(map[string]bool)}
+		for _, f := range failing {
+			fr.failing[f] = true
+		}
+		return fr, func(ctx context.Context, exe string, args ...string) error {
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			fr.ran = append(fr.ran, strings.Join(append([]string{exe}, args...), " "))
+			if fr.failing[exe] {
+				return errors.New("exit status 1")
+			}
+			return nil
+		}
+	}
+
+	t.Run("linux primary succeeds", func(t *testing.T) {
+		fr, run := newRunner()
+		unmountSSHFS(ctx, "linux", "/tmp/mnt", run)
+		assert.Equal(t, []string{"fusermount -uz /tmp/mnt"}, fr.ran)
+	})
+
+	t.Run("linux falls back to lazy umount", func(t *testing.T) {
+		fr, run := newRunner("fusermount")
+		unmountSSHFS(ctx, "linux", "/tmp/mnt", run)
+		assert.Equal(t, []string{"fusermount -uz /tmp/mnt", "umount -l /tmp/mnt"}, fr.ran)
+	})
+
+	t.Run("darwin falls back to diskutil", func(t *testing.T) {
+		fr, run := newRunner("umount", "diskutil")
+		unmountSSHFS(ctx, "darwin", "/tmp/mnt", run)
+		assert.Equal(t, []string{"umount -f /tmp/mnt", "diskutil unmount force /tmp/mnt"}, fr.ran)
+	})
+}
+
+func Test_sftpMounterStatus(t *testing.T) {
+	ctx := client.WithConfig(dlog.NewTestContext(t, false), client.GetDefaultConfig())
+
+	// Obtain a port that nothing listens to.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := uint16(l.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, l.Close())
+
+	var iceptWG, podWG sync.WaitGroup
+	retries := 0
+	m := &sftpMounter{
+		iceptWG: &iceptWG,
+		podWG:   &podWG,
+		retryBackOff: func(ctx context.Context) backoff.BackOffContext {
+			// Retry twice without waiting, and count the retries.
+			return backoff.WithContext(backoff.WithMaxRetries(backOffFunc(func() time.Duration {
+				retries++
+				return 0
+			}), 2), ctx)
+		},
+	}
+	assert.Equal(t, Status{}, m.Status())
+	require.NoError(t, m.Start(ctx, "wl", "ct", t.TempDir(), "/tel_app_mounts", net.ParseIP("127.0.0.1"), port, true))
+	iceptWG.Wait()
+
+	st := m.Status()
+	assert.False(t, st.Connected)
+	assert.Error(t, st.LastError)
+	assert.Equal(t, 2, retries)
+}
+
+// backOffFunc is a backoff.BackOff that returns the result of calling itself.
+type backOffFunc func() time.Duration
+
+func (f backOffFunc) NextBackOff() time.Duration {
+	return f()
+}
+
+func (f backOffFunc) Reset() {}
+
+func Test_setConnectedWhenMounted(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	lastErr := errors.New("connection refused")
+
+	t.Run("connected once mounted", func(t *testing.T) {
+		var ms mountStatus
+		ms.setDisconnected(lastErr)
+		checks := 0
+		ms.setConnectedWhenMounted(ctx, "/tmp/mnt", func(dir string) bool {
+			assert.Equal(t, "/tmp/mnt", dir)
+			checks++
+			assert.False(t, ms.Status().Connected)
+			return checks == 3
+		})
+		assert.Equal(t, 3, checks)
+		assert.Equal(t, Status{Connected: true, LastError: lastErr}, ms.Status())
+	})
+
+	t.Run("never mounted", func(t *testing.T) {
+		var ms mountStatus
+		ms.setDisconnected(lastErr)
+		ctx, cancel := context.WithTimeout(ctx, 3*mountPollInterval)
+		defer cancel()
+		ms.setConnectedWhenMounted(ctx, "/tmp/mnt", func(string) bool { return false })
+		assert.Equal(t, Status{LastError: lastErr}, ms.Status())
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		var ms mountStatus
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		ms.setConnectedWhenMounted(ctx, "/tmp/mnt", func(string) bool { return true })
+		assert.False(t, ms.Status().Connected)
+	})
+}
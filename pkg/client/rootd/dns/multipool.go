@@ -0,0 +1,82 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// multiPool is a FallbackPool that delegates to a list of pools, one for each fallback DNS server. An exchange
+// is first attempted using the currently preferred pool. If that fails, the remaining pools are tried in order,
+// and the first one that succeeds becomes the preferred pool for subsequent exchanges.
+type multiPool struct {
+	pools     []FallbackPool
+	preferred atomic.Int32
+}
+
+// NewMultiPool returns a FallbackPool that fails over between the given pools. The given pool is returned
+// as is when there's only one.
+func NewMultiPool(pools ...FallbackPool) FallbackPool {
+	if len(pools) == 1 {
+		return pools[0]
+	}
+	return &multiPool{pools: pools}
+}
+
+// NewConnPools creates one ConnPool for each of the given addresses and returns a FallbackPool that
+// fails over between them in the given order.
+func NewConnPools(addrs []netip.Addr, poolSize int) (FallbackPool, error) {
+	pools := make([]FallbackPool, 0, len(addrs))
+	for _, addr := range addrs {
+		pool, err := NewConnPool(addr, poolSize)
+		if err != nil {
+			for _, p := range pools {
+				p.Close()
+			}
+			return nil, err
+		}
+		pools = append(pools, pool)
+	}
+	return NewMultiPool(pools...), nil
+}
+
+func (mp *multiPool) Exchange(ctx context.Context, client *dns.Client, msg *dns.Msg) (r *dns.Msg, rtt time.Duration, err error) {
+	n := int32(len(mp.pools))
+	start := mp.preferred.Load()
+	for i := int32(0); i < n; i++ {
+		idx := (start + i) % n
+		if r, rtt, err = mp.pools[idx].Exchange(ctx, client, msg); err == nil {
+			if idx != start {
+				mp.preferred.CompareAndSwap(start, idx)
+			}
+			return r, rtt, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return r, rtt, err
+}
+
+// RemoteAddr returns the address of the currently preferred DNS server.
+func (mp *multiPool) RemoteAddr() netip.Addr {
+	return mp.pools[mp.preferred.Load()].RemoteAddr()
+}
+
+func (mp *multiPool) LocalAddrs() []*net.UDPAddr {
+	var addrs []*net.UDPAddr
+	for _, p := range mp.pools {
+		addrs = append(addrs, p.LocalAddrs()...)
+	}
+	return addrs
+}
+
+func (mp *multiPool) Close() {
+	for _, p := range mp.pools {
+		p.Close()
+	}
+}
@@ -0,0 +1,107 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPool is a FallbackPool that either answers all queries or times out on all of them.
+type stubPool struct {
+	addr      netip.Addr
+	down      bool
+	exchanges int
+	closed    bool
+}
+
+func (p *stubPool) Exchange(_ context.Context, _ *dns.Client, r *dns.Msg) (*dns.Msg, time.Duration, error) {
+	p.exchanges++
+	if p.down {
+		return nil, 0, &net.OpError{Op: "read", Net: "udp", Err: os.ErrDeadlineExceeded}
+	}
+	msg := new(dns.Msg)
+	msg.SetReply(r)
+	return msg, time.Millisecond, nil
+}
+
+func (p *stubPool) RemoteAddr() netip.Addr {
+	return p.addr
+}
+
+func (p *stubPool) LocalAddrs() []*net.UDPAddr {
+	return []*net.UDPAddr{{IP: net.IP{127, 0, 0, 1}, Port: 50000 + int(p.addr.As4()[3])}}
+}
+
+func (p *stubPool) Close() {
+	p.closed = true
+}
+
+func TestMultiPool_FirstFallbackDown(t *testing.T) {
+	first := &stubPool{addr: netip.MustParseAddr("10.0.0.1"), down: true}
+	second := &stubPool{addr: netip.MustParseAddr("10.0.0.2")}
+	third := &stubPool{addr: netip.MustParseAddr("10.0.0.3")}
+	pool := NewMultiPool(first, second, third)
+	assert.Len(t, pool.LocalAddrs(), 3)
+
+	ctx := context.Background()
+	dc := &dns.Client{Net: "udp", Timeout: time.Second}
+	q := new(dns.Msg)
+	q.SetQuestion("example.com.", dns.TypeA)
+
+	r, _, err := pool.Exchange(ctx, dc, q)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, 1, first.exchanges)
+	assert.Equal(t, 1, second.exchanges)
+	assert.Equal(t, 0, third.exchanges)
+
+	// The second server is now preferred, so the first one isn't consulted again.
+	assert.Equal(t, second.addr, pool.RemoteAddr())
+	_, _, err = pool.Exchange(ctx, dc, q)
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.exchanges)
+	assert.Equal(t, 2, second.exchanges)
+
+	// Fail over again when the preferred server goes down, wrapping around if needed.
+	second.down = true
+	first.down = false
+	_, _, err = pool.Exchange(ctx, dc, q)
+	require.NoError(t, err)
+	assert.Equal(t, 3, second.exchanges)
+	assert.Equal(t, 1, third.exchanges)
+	assert.Equal(t, third.addr, pool.RemoteAddr())
+
+	pool.Close()
+	assert.True(t, first.closed)
+	assert.True(t, second.closed)
+	assert.True(t, third.closed)
+}
+
+func TestMultiPool_AllFallbacksDown(t *testing.T) {
+	first := &stubPool{addr: netip.MustParseAddr("10.0.0.1"), down: true}
+	second := &stubPool{addr: netip.MustParseAddr("10.0.0.2"), down: true}
+	pool := NewMultiPool(first, second)
+
+	q := new(dns.Msg)
+	q.SetQuestion("example.com.", dns.TypeA)
+	_, _, err := pool.Exchange(context.Background(), &dns.Client{Net: "udp"}, q)
+	require.Error(t, err)
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+	assert.Equal(t, 1, first.exchanges)
+	assert.Equal(t, 1, second.exchanges)
+	assert.Equal(t, first.addr, pool.RemoteAddr())
+}
+
+func TestNewMultiPool_Single(t *testing.T) {
+	single := &stubPool{addr: netip.MustParseAddr("10.0.0.1")}
+	assert.Same(t, single, NewMultiPool(single))
+}
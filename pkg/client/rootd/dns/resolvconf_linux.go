@@ -0,0 +1,83 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/filelocation"
+)
+
+// resolvConfBackupFile is the name of the file in the root daemon's cache dir that retains the original
+// resolv.conf while DNS is redirected via the TUN device. It is used to restore the resolv.conf when the
+// daemon starts after it was terminated before it could restore it.
+const resolvConfBackupFile = "resolv-conf-backup.json"
+
+type resolvConfBackup struct {
+	// FileName is the name of the file that was redirected
+	FileName string `json:"fileName"`
+
+	// Original is the content of the file before it was redirected
+	Original string `json:"original"`
+
+	// Redirected is the content that the file was given when it was redirected
+	Redirected string `json:"redirected"`
+}
+
+func resolvConfBackupPath(c context.Context) string {
+	return filepath.Join(filelocation.AppUserCacheDir(c), resolvConfBackupFile)
+}
+
+// RestoreResolvConf restores a resolv.conf that is still redirected via the TUN device because the daemon
+// was terminated before it could restore it.
+func RestoreResolvConf(c context.Context) {
+	if err := restoreResolvConf(c, resolvConfBackupPath(c)); err != nil {
+		dlog.Errorf(c, "failed to restore %s: %v", resolvConf, err)
+	}
+}
+
+// saveResolvConfBackup writes the given backup to the given file.
+func saveResolvConfBackup(backupFile string, b *resolvConfBackup) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(backupFile), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(backupFile, data, 0o600)
+}
+
+// restoreResolvConf restores the file of the backup found in the given backupFile, and then removes the
+// backup. The file is left untouched when it no longer has the redirected content, because that means
+// that someone else has edited it. It's not an error if the backupFile doesn't exist.
+func restoreResolvConf(c context.Context, backupFile string) error {
+	data, err := os.ReadFile(backupFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return err
+	}
+	var b resolvConfBackup
+	if err = json.Unmarshal(data, &b); err != nil {
+		_ = os.Remove(backupFile)
+		return err
+	}
+	current, err := os.ReadFile(b.FileName)
+	switch {
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return err
+	case err == nil && string(current) == b.Redirected:
+		dlog.Debugf(c, "Restoring %s", b.FileName)
+		if err = os.WriteFile(b.FileName, []byte(b.Original), 0o644); err != nil {
+			return err
+		}
+	default:
+		dlog.Warnf(c, "%s was changed after DNS was redirected to the TUN device, so it will not be restored", b.FileName)
+	}
+	return os.Remove(backupFile)
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package dns
+
+import (
+	"context"
+)
+
+// RestoreResolvConf is a no-op. The resolv.conf is only redirected on Linux.
+func RestoreResolvConf(context.Context) {}
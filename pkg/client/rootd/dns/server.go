@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/netip"
 	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -82,6 +83,10 @@ type Server struct {
 	// intended for this resolver and will get reapplied when passing things on to the fallback resolver.
 	dropSuffixes []string
 
+	// Lower case domains, without leading or trailing dots, that are never sent to the cluster. Queries for
+	// names that are equal to, or subdomains of, these domains are dispatched directly to the fallback resolver.
+	bypassSuffixes []string
+
 	// routes are typically namespaces, accessible using <service-name>.<namespace-name>.
 	routes map[string]struct{}
 
@@ -160,6 +165,7 @@ func NewServer(config *client.DNS, clusterLookup Resolver) *Server {
 		routes:         make(map[string]struct{}),
 		domains:        make(map[string]struct{}),
 		dropSuffixes:   []string{tel2SubDomainDot},
+		bypassSuffixes: normalizeBypassSuffixes(config.BypassSuffixes),
 		search:         []string{tel2SubDomain},
 		nsAndDomainsCh: make(chan nsAndDomains, 5),
 		clusterDomain:  defaultClusterDomain,
@@ -284,6 +290,35 @@ func (s *Server) isExcluded(name string) bool {
 	return false
 }
 
+// normalizeBypassSuffixes returns the given suffixes in lower case and stripped from wildcard
+// prefixes and leading or trailing dots, so that "*.Corp.Example.com." becomes "corp.example.com".
+func normalizeBypassSuffixes(sfxs []string) []string {
+	if len(sfxs) == 0 {
+		return nil
+	}
+	ns := make([]string, 0, len(sfxs))
+	for _, sfx := range sfxs {
+		sfx = strings.Trim(strings.TrimPrefix(strings.ToLower(sfx), "*"), ".")
+		if sfx != "" {
+			ns = append(ns, sfx)
+		}
+	}
+	return ns
+}
+
+// isBypassed returns true if the given lower case name is equal to, or a subdomain of, a
+// configured bypass-suffix.
+func (s *Server) isBypassed(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	for _, sfx := range s.bypassSuffixes {
+		if name == sfx || strings.HasSuffix(name, "."+sfx) {
+			dlog.Debugf(s.ctx, "Cluster DNS bypassed by bypass-suffix %q for name %q", sfx, name)
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Server) isDomainExcluded(name string) bool {
 	return slices.Contains(s.ExcludeSuffixes, "."+name)
 }
@@ -393,6 +428,21 @@ func (s *Server) SetTopLevelDomainsAndSearchPath(ctx context.Context, domains []
 	}
 }
 
+// SearchPaths returns the search paths and drop suffixes that are currently in use by the resolver, along
+// with the top level domains that are routed to the cluster.
+func (s *Server) SearchPaths() (search, dropSuffixes, routes []string) {
+	s.RLock()
+	defer s.RUnlock()
+	search = slices.Clone(s.search)
+	dropSuffixes = slices.Clone(s.dropSuffixes)
+	routes = make([]string, 0, len(s.routes))
+	for route := range s.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return search, dropSuffixes, routes
+}
+
 func (s *Server) purgeRecordsFromCache(keyName string) {
 	keyName = strings.TrimSuffix(keyName, ".") + "."
 	for _, qType := range []uint16{dns.TypeA, dns.TypeAAAA} {
@@ -453,6 +503,26 @@ func newLocalUDPListener(c context.Context) (net.PacketConn, error) {
 	return lc.ListenPacket(c, "udp", "127.0.0.1:0")
 }
 
+func (s *Server) setRoutesAndSearch(das nsAndDomains) {
+	routes := make(map[string]struct{}, len(das.domains))
+	for _, domain := range das.domains {
+		if domain != "" && !s.isDomainExcluded(domain) {
+			routes[domain] = struct{}{}
+		}
+	}
+	if !s.isDomainExcluded("svc") {
+		routes["svc"] = struct{}{}
+	}
+	s.Lock()
+	s.routes = routes
+
+	// The connected namespace must be included as a search path for the cases
+	// where it's up to the traffic-manager to resolve. It cannot resolve a single
+	// label name intended for other namespaces.
+	s.search = []string{tel2SubDomain, das.namespace}
+	s.Unlock()
+}
+
 func (s *Server) processSearchPaths(g *dgroup.Group, processor func(context.Context, vif.Device) error, dev vif.Device) {
 	g.Go("SearchPaths", func(c context.Context) error {
 		s.performRecursionCheck(c)
@@ -474,25 +544,7 @@ func (s *Server) processSearchPaths(g *dgroup.Group, processor func(context.Cont
 					continue
 				}
 				prevDas = das
-
-				routes := make(map[string]struct{}, len(das.domains))
-				for _, domain := range das.domains {
-					if domain != "" && !s.isDomainExcluded(domain) {
-						routes[domain] = struct{}{}
-					}
-				}
-				if !s.isDomainExcluded("svc") {
-					routes["svc"] = struct{}{}
-				}
-				s.Lock()
-				s.routes = routes
-
-				// The connected namespace must be included as a search path for the cases
-				// where it's up to the traffic-manager to resolve. It cannot resolve a single
-				// label name intended for other namespaces.
-				s.search = []string{tel2SubDomain, das.namespace}
-				s.Unlock()
-
+				s.setRoutesAndSearch(das)
 				if err := processor(c, dev); err != nil {
 					return err
 				}
@@ -830,6 +882,18 @@ func (s *Server) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		}
 	}
 
+	if s.isBypassed(q.Name) {
+		// The cluster must never be consulted for this name.
+		if s.fallbackPool == nil {
+			msg.SetRcode(r, dns.RcodeNameError)
+			return
+		}
+		q.Name = origName
+		pfx = func() string { return fmt.Sprintf("(%s) ", s.fallbackPool.RemoteAddr()) }
+		msg, txt = s.fallbackExchange(c, msg, r)
+		return
+	}
+
 	var answer dnsproxy.RRs
 	var rCode int
 	var err error
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"net/netip"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/datawire/dlib/dgroup"
 	"github.com/datawire/dlib/dlog"
 	"github.com/datawire/dlib/dtime"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
 	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
 	"github.com/telepresenceio/telepresence/v2/pkg/forwarder"
 	"github.com/telepresenceio/telepresence/v2/pkg/proc"
@@ -29,12 +31,14 @@ const (
 	recursionTestTimeout = 200 * time.Millisecond
 )
 
+const resolvConf = "/etc/resolv.conf"
+
 var errResolveDNotConfigured = errors.New("resolved not configured")
 
 func (s *Server) Worker(c context.Context, dev vif.Device, configureDNS func(netip.Addr, *net.UDPAddr)) error {
 	if proc.RunningInContainer() {
 		// Don't bother with systemd-resolved when running in a docker container
-		return s.runOverridingServer(c, dev)
+		return s.runOverridingServer(c, dev, configureDNS)
 	}
 
 	err := s.tryResolveD(dgroup.WithGoroutineName(c, "/resolved"), dev, configureDNS)
@@ -42,15 +46,15 @@ func (s *Server) Worker(c context.Context, dev vif.Device, configureDNS func(net
 		err = nil
 		if c.Err() == nil {
 			dlog.Info(c, "Unable to use systemd-resolved, falling back to local server")
-			err = s.runOverridingServer(dgroup.WithGoroutineName(c, "/legacy"), dev)
+			err = s.runOverridingServer(dgroup.WithGoroutineName(c, "/legacy"), dev, configureDNS)
 		}
 	}
 	return err
 }
 
-func (s *Server) runOverridingServer(c context.Context, dev vif.Device) error {
+func (s *Server) runOverridingServer(c context.Context, dev vif.Device, configureDNS func(netip.Addr, *net.UDPAddr)) error {
 	if !s.LocalIP.IsValid() {
-		rf, err := dnsproxy.ReadResolveFile("/etc/resolv.conf")
+		rf, err := dnsproxy.ReadResolveFile(resolvConf)
 		if err != nil {
 			return err
 		}
@@ -86,6 +90,11 @@ func (s *Server) runOverridingServer(c context.Context, dev vif.Device) error {
 	if !s.LocalIP.IsValid() {
 		return errors.New("couldn't determine dns ip from /etc/resolv.conf")
 	}
+	fallback := s.Fallback
+	if len(fallback) == 0 {
+		fallback = []netip.Addr{s.LocalIP}
+	}
+	dlog.Infof(c, "Using fallback DNS servers %v", fallback)
 
 	listeners, err := s.dnsListeners(c)
 	if err != nil {
@@ -97,10 +106,10 @@ func (s *Server) runOverridingServer(c context.Context, dev vif.Device) error {
 	}
 	dlog.Debugf(c, "Bootstrapping local DNS server on port %d", dnsResolverAddr.Port)
 
-	// Create the connection pool later used for fallback. We need to create this before the firewall
-	// rule because the rule must exclude the local address of this connection in order to
-	// let it reach the original destination and not cause an endless loop.
-	pool, err := NewConnPool(s.LocalIP, 10)
+	// Create the connection pools later used for fallback. We need to create these before the firewall
+	// rule because the rule must exclude the local addresses of their connections in order to
+	// let them reach the original destination and not cause an endless loop.
+	pool, err := NewConnPools(fallback, 10)
 	if err != nil {
 		return err
 	}
@@ -144,6 +153,27 @@ func (s *Server) runOverridingServer(c context.Context, dev vif.Device) error {
 		})
 	}
 
+	if s.Redirect == client.DNSRedirectTUN {
+		g.Go("TUN-redirect", func(c context.Context) error {
+			select {
+			case <-c.Done():
+			case <-serverStarted:
+				restore, err := s.redirectViaTUN(c, resolvConf, resolvConfBackupPath(c), dnsResolverAddr, configureDNS)
+				if err != nil {
+					return err
+				}
+				defer func() {
+					restore(context.WithoutCancel(c))
+					s.flushDNS()
+				}()
+				s.flushDNS()
+				<-serverDone // Stay alive until DNS server is done
+			}
+			return nil
+		})
+		return g.Wait()
+	}
+
 	g.Go("NAT-redirect", func(c context.Context) error {
 		select {
 		case <-c.Done():
@@ -176,6 +206,74 @@ func (s *Server) dnsListeners(c context.Context) ([]net.PacketConn, error) {
 	return []net.PacketConn{listener}, nil
 }
 
+// redirectViaTUN is an alternative to routeDNS for hosts where iptables cannot be used. Instead of
+// rewriting packets sent to the system's DNS server, it makes the TUN device intercept queries sent to
+// the cluster DNS IP (which is routed to the TUN device like any other cluster subnet) and points the
+// system resolver at that IP by rewriting the given resolv.conf file. The tel2-search domain is added
+// in front of the file's search domains. The fallback servers remain reachable using the host's regular
+// routes.
+//
+// The original content is saved in the given backupFile before the resolv.conf is rewritten, so that it
+// can be restored by RestoreResolvConf should the daemon terminate without restoring it. The returned
+// function restores the original resolv.conf, unless it has been edited by someone else.
+func (s *Server) redirectViaTUN(
+	c context.Context,
+	fileName string,
+	backupFile string,
+	dnsResolverAddr *net.UDPAddr,
+	configureDNS func(netip.Addr, *net.UDPAddr),
+) (func(context.Context), error) {
+	s.RLock()
+	dnsIP := s.RemoteIP
+	s.RUnlock()
+	if !dnsIP.IsValid() {
+		return nil, fmt.Errorf("dns redirect %q requires a cluster DNS IP that is routed to the TUN device", client.DNSRedirectTUN)
+	}
+
+	// A backup that is still present was left by a daemon that didn't terminate gracefully.
+	if err := restoreResolvConf(c, backupFile); err != nil {
+		return nil, err
+	}
+	orig, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	rf, err := dnsproxy.ReadResolveFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	rf.Nameservers = []string{dnsIP.String()}
+	search := []string{tel2SubDomain}
+	for _, sp := range rf.Search {
+		if sp != tel2SubDomain {
+			search = append(search, sp)
+		}
+	}
+	rf.Search = search
+	redirected := rf.String()
+	if err = saveResolvConfBackup(backupFile, &resolvConfBackup{
+		FileName:   fileName,
+		Original:   string(orig),
+		Redirected: redirected,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save backup of %s: %w", fileName, err)
+	}
+
+	configureDNS(dnsIP, dnsResolverAddr)
+	dlog.Infof(c, "Redirecting DNS to %s using the TUN device", dnsIP)
+	if err = os.WriteFile(fileName, []byte(redirected), 0o644); err != nil {
+		configureDNS(netip.Addr{}, nil)
+		_ = os.Remove(backupFile)
+		return nil, err
+	}
+	return func(c context.Context) {
+		if err := restoreResolvConf(c, backupFile); err != nil {
+			dlog.Errorf(c, "failed to restore %s: %v", fileName, err)
+		}
+		configureDNS(netip.Addr{}, nil)
+	}, nil
+}
+
 // runNatTableCmd runs "iptables -t nat ...".
 func runNatTableCmd(c context.Context, args ...string) error {
 	// We specifically don't want to use the cancellation of 'ctx' here, because we don't ever
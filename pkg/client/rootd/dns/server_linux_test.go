@@ -0,0 +1,154 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
+)
+
+func TestServer_RedirectViaTUN(t *testing.T) {
+	ctx, cancel := context.WithCancel(dlog.NewTestContext(t, false))
+	defer cancel()
+
+	origContent := "nameserver 192.168.1.1\nsearch example.com\n"
+	dir := t.TempDir()
+	resolvFile := filepath.Join(dir, "resolv.conf")
+	backupFile := filepath.Join(dir, "cache", resolvConfBackupFile)
+	require.NoError(t, os.WriteFile(resolvFile, []byte(origContent), 0o644))
+
+	dnsIP := netip.MustParseAddr("10.96.0.2")
+	s := NewServer(&client.DNS{
+		Redirect:        client.DNSRedirectTUN,
+		IncludeSuffixes: []string{".cluster.test"},
+	}, nil)
+	s.SetClusterDNS(nil, dnsIP)
+	s.recursive = recursionNotDetected
+
+	listener, err := newLocalUDPListener(ctx)
+	require.NoError(t, err)
+	listenerAddr, err := splitToUDPAddr(listener.LocalAddr())
+	require.NoError(t, err)
+
+	answerIP := net.IP{10, 1, 2, 3}
+	resolve := func(_ context.Context, q *dns.Question) (dnsproxy.RRs, int, error) {
+		return dnsproxy.RRs{&dns.A{
+			Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET},
+			A:   answerIP,
+		}}, dns.RcodeSuccess, nil
+	}
+	started := make(chan struct{})
+	go func() {
+		_ = s.Run(ctx, started, []net.PacketConn{listener}, &fakeFallbackPool{}, resolve)
+	}()
+	<-started
+
+	// The configured IP and local address are what the TUN device's stream creator uses when it
+	// intercepts DNS queries.
+	var tunDNSIP netip.Addr
+	var tunLocalAddr *net.UDPAddr
+	configureDNS := func(ip netip.Addr, addr *net.UDPAddr) {
+		tunDNSIP = ip
+		tunLocalAddr = addr
+	}
+	restore, err := s.redirectViaTUN(ctx, resolvFile, backupFile, listenerAddr, configureDNS)
+	require.NoError(t, err)
+
+	rf, err := dnsproxy.ReadResolveFile(resolvFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dnsIP.String()}, rf.Nameservers)
+	assert.Equal(t, []string{tel2SubDomain, "example.com"}, rf.Search)
+	assert.FileExists(t, backupFile)
+	assert.Equal(t, dnsIP, tunDNSIP)
+	require.NotNil(t, tunLocalAddr)
+
+	// Send the query to the address that the TUN device would forward it to.
+	q := new(dns.Msg)
+	q.SetQuestion("echo.cluster.test.", dns.TypeA)
+	dc := &dns.Client{Net: "udp", Timeout: 2 * time.Second}
+	r, _, err := dc.ExchangeContext(ctx, q, tunLocalAddr.String())
+	require.NoError(t, err)
+	require.Equal(t, dns.RcodeSuccess, r.Rcode)
+	require.Len(t, r.Answer, 1)
+	assert.True(t, answerIP.Equal(r.Answer[0].(*dns.A).A))
+
+	restore(ctx)
+	content, err := os.ReadFile(resolvFile)
+	require.NoError(t, err)
+	assert.Equal(t, origContent, string(content))
+	assert.NoFileExists(t, backupFile)
+	assert.False(t, tunDNSIP.IsValid())
+	assert.Nil(t, tunLocalAddr)
+}
+
+func TestServer_RedirectViaTUN_Restore(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	origContent := "nameserver 192.168.1.1\nsearch example.com\n"
+	redirect := func(t *testing.T) (string, string, func(context.Context)) {
+		dir := t.TempDir()
+		resolvFile := filepath.Join(dir, "resolv.conf")
+		backupFile := filepath.Join(dir, resolvConfBackupFile)
+		require.NoError(t, os.WriteFile(resolvFile, []byte(origContent), 0o644))
+		s := NewServer(&client.DNS{Redirect: client.DNSRedirectTUN}, nil)
+		s.SetClusterDNS(nil, netip.MustParseAddr("10.96.0.2"))
+		restore, err := s.redirectViaTUN(ctx, resolvFile, backupFile, &net.UDPAddr{}, func(netip.Addr, *net.UDPAddr) {})
+		require.NoError(t, err)
+		return resolvFile, backupFile, restore
+	}
+
+	t.Run("after daemon restart", func(t *testing.T) {
+		// The daemon terminates without calling restore, and the next daemon finds the backup.
+		resolvFile, backupFile, _ := redirect(t)
+		require.NoError(t, restoreResolvConf(ctx, backupFile))
+		content, err := os.ReadFile(resolvFile)
+		require.NoError(t, err)
+		assert.Equal(t, origContent, string(content))
+		assert.NoFileExists(t, backupFile)
+	})
+
+	t.Run("on next redirect", func(t *testing.T) {
+		resolvFile, backupFile, _ := redirect(t)
+		s := NewServer(&client.DNS{Redirect: client.DNSRedirectTUN}, nil)
+		s.SetClusterDNS(nil, netip.MustParseAddr("10.96.0.2"))
+		restore, err := s.redirectViaTUN(ctx, resolvFile, backupFile, &net.UDPAddr{}, func(netip.Addr, *net.UDPAddr) {})
+		require.NoError(t, err)
+		restore(ctx)
+		content, err := os.ReadFile(resolvFile)
+		require.NoError(t, err)
+		assert.Equal(t, origContent, string(content))
+	})
+
+	t.Run("not when edited by someone else", func(t *testing.T) {
+		resolvFile, backupFile, restore := redirect(t)
+		editedContent := "nameserver 192.168.1.2\n"
+		require.NoError(t, os.WriteFile(resolvFile, []byte(editedContent), 0o644))
+		restore(ctx)
+		content, err := os.ReadFile(resolvFile)
+		require.NoError(t, err)
+		assert.Equal(t, editedContent, string(content))
+		assert.NoFileExists(t, backupFile)
+	})
+
+	t.Run("without backup", func(t *testing.T) {
+		assert.NoError(t, restoreResolvConf(ctx, filepath.Join(t.TempDir(), resolvConfBackupFile)))
+	})
+}
+
+func TestServer_RedirectViaTUN_NoDNSIP(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	s := NewServer(&client.DNS{Redirect: client.DNSRedirectTUN}, nil)
+	dir := t.TempDir()
+	_, err := s.redirectViaTUN(ctx, filepath.Join(dir, "resolv.conf"), filepath.Join(dir, resolvConfBackupFile), &net.UDPAddr{}, func(netip.Addr, *net.UDPAddr) {})
+	require.Error(t, err)
+}
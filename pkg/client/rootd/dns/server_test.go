@@ -1,15 +1,22 @@
 package dns
 
 import (
+	"context"
+	"net"
+	"net/netip"
 	"testing"
 	"time"
 
 	"github.com/miekg/dns"
 	"github.com/puzpuzpuz/xsync/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/datawire/dlib/dlog"
 	rpc "github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
 )
 
 type suiteServer struct {
@@ -130,3 +137,112 @@ func (s *suiteServer) TestIsExcluded() {
 func TestServerTestSuite(t *testing.T) {
 	suite.Run(t, new(suiteServer))
 }
+
+type fakeFallbackPool struct {
+	queries []string
+}
+
+func (p *fakeFallbackPool) Exchange(_ context.Context, _ *dns.Client, r *dns.Msg) (*dns.Msg, time.Duration, error) {
+	q := r.Question[0]
+	p.queries = append(p.queries, q.Name)
+	msg := new(dns.Msg)
+	msg.SetReply(r)
+	msg.Answer = []dns.RR{&dns.A{
+		Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET},
+		A:   net.IP{10, 0, 0, 1},
+	}}
+	return msg, 0, nil
+}
+
+func (p *fakeFallbackPool) RemoteAddr() netip.Addr {
+	return netip.AddrFrom4([4]byte{10, 0, 0, 53})
+}
+
+func (p *fakeFallbackPool) LocalAddrs() []*net.UDPAddr {
+	return nil
+}
+
+func (p *fakeFallbackPool) Close() {}
+
+type fakeResponseWriter struct {
+	dns.ResponseWriter
+	msg *dns.Msg
+}
+
+func (w *fakeResponseWriter) WriteMsg(msg *dns.Msg) error {
+	w.msg = msg
+	return nil
+}
+
+func TestServer_BypassSuffixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		bypassed bool
+	}{
+		{
+			name:     "exact match",
+			query:    "corp.example.com.",
+			bypassed: true,
+		},
+		{
+			name:     "subdomain match",
+			query:    "Build.Corp.Example.COM.",
+			bypassed: true,
+		},
+		{
+			name:     "non-match",
+			query:    "notcorp.example.com.",
+			bypassed: false,
+		},
+		{
+			name:     "single label",
+			query:    "corp.",
+			bypassed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := dlog.NewTestContext(t, false)
+			var clusterQueries []string
+			s := NewServer(&client.DNS{
+				BypassSuffixes:  []string{"*.Corp.example.com."},
+				IncludeSuffixes: []string{".com"},
+			}, nil)
+			s.ctx = ctx
+			s.recursive = recursionNotDetected
+			s.fallbackPool = &fakeFallbackPool{}
+			s.resolve = func(_ context.Context, q *dns.Question) (dnsproxy.RRs, int, error) {
+				clusterQueries = append(clusterQueries, q.Name)
+				return nil, dns.RcodeNameError, nil
+			}
+			assert.Equal(t, tt.bypassed, s.isBypassed(dns.CanonicalName(tt.query)))
+
+			w := &fakeResponseWriter{}
+			r := new(dns.Msg)
+			r.SetQuestion(tt.query, dns.TypeA)
+			s.ServeDNS(w, r)
+			require.NotNil(t, w.msg)
+			fallbackQueries := s.fallbackPool.(*fakeFallbackPool).queries
+			if tt.bypassed {
+				assert.Empty(t, clusterQueries)
+				assert.Equal(t, []string{tt.query}, fallbackQueries)
+				assert.Equal(t, dns.RcodeSuccess, w.msg.Rcode)
+			} else {
+				assert.Equal(t, []string{dns.CanonicalName(tt.query)}, clusterQueries)
+			}
+		})
+	}
+}
+
+func TestServer_SearchPaths(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	s := NewServer(&client.DNS{ExcludeSuffixes: []string{".com"}}, nil)
+	s.SetTopLevelDomainsAndSearchPath(ctx, []string{"cluster.local", "blue", "green"}, "blue")
+	s.setRoutesAndSearch(<-s.nsAndDomainsCh)
+
+	search, dropSuffixes, routes := s.SearchPaths()
+	assert.Equal(t, []string{tel2SubDomain, "blue"}, search)
+	assert.Equal(t, []string{tel2SubDomainDot}, dropSuffixes)
+	assert.Equal(t, []string{"blue", "cluster.local", "green", "svc"}, routes)
+}
@@ -34,31 +34,43 @@ func (s *Server) Worker(c context.Context, dev vif.Device, configureDNS func(net
 
 	var pool FallbackPool
 	if client.GetConfig(c).OSSpecific().Network.DNSWithFallback {
-		// Create the connection pool later used for fallback.
-		dnsServers, err := getDNSServerList()
-		if err != nil {
-			dlog.Warnf(c, "Failed to get DNS servers: %v", err)
-		} else {
-			for _, dnsServer := range dnsServers {
+		// Create the connection pools later used for fallback. Unless fallback servers are configured, the first
+		// viable system DNS server is used.
+		dnsServers := s.Fallback
+		useFirst := len(dnsServers) == 0
+		if useFirst {
+			dnsServerList, err := getDNSServerList()
+			if err != nil {
+				dlog.Warnf(c, "Failed to get DNS servers: %v", err)
+			}
+			for _, dnsServer := range dnsServerList {
 				addr, err := netip.ParseAddr(dnsServer)
 				if err != nil {
 					dlog.Warn(c, err)
 					continue
 				}
-				p, err := NewConnPool(addr, 10)
-				if err == nil {
-					dlog.Infof(c, "Using fallback DNS server: %s", dnsServer)
-					pool = p
-					break
-				}
+				dnsServers = append(dnsServers, addr)
+			}
+		}
+		var pools []FallbackPool
+		for _, addr := range dnsServers {
+			p, err := NewConnPool(addr, 10)
+			if err != nil {
 				dlog.Warn(c, err)
+				continue
 			}
-			if pool == nil {
-				dlog.Warnf(c, "No viable fallback DNS server found")
-			} else {
-				defer pool.Close()
+			dlog.Infof(c, "Using fallback DNS server: %s", addr)
+			pools = append(pools, p)
+			if useFirst {
+				break
 			}
 		}
+		if len(pools) == 0 {
+			dlog.Warnf(c, "No viable fallback DNS server found")
+		} else {
+			pool = NewMultiPool(pools...)
+			defer pool.Close()
+		}
 	}
 
 	// Start local DNS server
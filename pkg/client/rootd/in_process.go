@@ -121,6 +121,26 @@ func (rd *InProcSession) WaitForAgentIP(ctx context.Context, request *rpc.WaitFo
 	return rd.waitForAgentIP(ctx, request)
 }
 
+func (rd *InProcSession) GetVirtualIPs(context.Context, *empty.Empty, ...grpc.CallOption) (*rpc.VirtualIPs, error) {
+	return rd.getVirtualIPs(), nil
+}
+
+func (rd *InProcSession) GetRouteConflicts(context.Context, *empty.Empty, ...grpc.CallOption) (*rpc.RouteConflicts, error) {
+	return rd.getRouteConflicts(), nil
+}
+
+func (rd *InProcSession) GetTrafficActivity(context.Context, *empty.Empty, ...grpc.CallOption) (*rpc.TrafficActivity, error) {
+	return rd.getTrafficActivity(), nil
+}
+
+func (rd *InProcSession) UpdateRouting(ctx context.Context, in *rpc.UpdateRoutingRequest, _ ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, rd.updateRouting(ctx, in)
+}
+
+func (rd *InProcSession) GetDNSSearchPaths(context.Context, *empty.Empty, ...grpc.CallOption) (*rpc.DNSSearchPaths, error) {
+	return rd.getDNSSearchPaths(), nil
+}
+
 // NewInProcSession returns a root daemon session suitable to use in-process (from the user daemon) and is primarily intended for
 // when the user daemon runs in a docker container with NET_ADMIN capabilities.
 func NewInProcSession(
@@ -129,9 +149,10 @@ func NewInProcSession(
 	mc manager.ManagerClient,
 	ver semver.Version,
 	isPodDaemon bool,
+	opts ...SessionOption,
 ) (context.Context, *InProcSession, error) {
 	ctx, cancel := context.WithCancel(ctx)
-	ctx, session, err := newSession(ctx, mi, &userdToManagerShortcut{mc}, ver, isPodDaemon)
+	ctx, session, err := newSession(ctx, mi, &userdToManagerShortcut{mc}, ver, isPodDaemon, opts...)
 	if err != nil {
 		cancel()
 		return ctx, nil, err
@@ -0,0 +1,94 @@
+package rootd
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/datawire/dlib/dlog"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/subnet"
+)
+
+// updateRouting adds and removes also-proxy and never-proxy subnets, and then updates the routes using
+// the last cluster info received from the traffic-manager. The previous subnets are restored if the
+// routes cannot be updated.
+func (s *Session) updateRouting(ctx context.Context, req *rpc.UpdateRoutingRequest) error {
+	addAlso, err := parseSubnets("also-proxy", req.AddAlsoProxy)
+	if err == nil {
+		addAlso, err = validateSubnets("also-proxy", addAlso, s.alsoProxyVia)
+	}
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	addNever, err := parseSubnets("never-proxy", req.AddNeverProxy)
+	if err == nil {
+		addNever, err = validateSubnets("never-proxy", addNever, nope)
+	}
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	removeAlso, err := parseSubnets("also-proxy", req.RemoveAlsoProxy)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	removeNever, err := parseSubnets("never-proxy", req.RemoveNeverProxy)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.routingLock.Lock()
+	defer s.routingLock.Unlock()
+	if s.clusterInfo == nil {
+		return status.Error(codes.Unavailable, "the network of the session has not been configured yet")
+	}
+	if s.tunVif != nil && s.vipGenerator == nil && !client.GetConfig(ctx).Routing().AutoResolveConflicts {
+		if err = s.tunVif.Router.ValidateRoutes(ctx, addAlso); err != nil {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+
+	oldAlso, oldNever := s.alsoProxySubnets, s.neverProxySubnets
+	oldRemovedAlso, oldRemovedNever := s.removedAlsoProxy, s.removedNeverProxy
+	s.alsoProxySubnets = subnet.Unique(append(withoutSubnets(oldAlso, removeAlso), addAlso...))
+	s.neverProxySubnets = subnet.Unique(append(withoutSubnets(oldNever, removeNever), addNever...))
+
+	// Remember the removals, so that they aren't undone by the next cluster info from the traffic-manager.
+	s.removedAlsoProxy = append(withoutSubnets(oldRemovedAlso, slices.Concat(addAlso, removeAlso)), removeAlso...)
+	s.removedNeverProxy = append(withoutSubnets(oldRemovedNever, slices.Concat(addNever, removeNever)), removeNever...)
+	dlog.Infof(ctx, "also-proxy subnets %v", s.alsoProxySubnets)
+	dlog.Infof(ctx, "never-proxy subnets %v", s.neverProxySubnets)
+	if err = s.onClusterInfo(ctx, s.clusterInfo); err != nil {
+		s.alsoProxySubnets, s.neverProxySubnets = oldAlso, oldNever
+		s.removedAlsoProxy, s.removedNeverProxy = oldRemovedAlso, oldRemovedNever
+		if rErr := s.onClusterInfo(ctx, s.clusterInfo); rErr != nil {
+			dlog.Errorf(ctx, "failed to restore routes: %v", rErr)
+		}
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return nil
+}
+
+func parseSubnets(name string, cidrs []string) ([]netip.Prefix, error) {
+	sns := make([]netip.Prefix, len(cidrs))
+	for i, cidr := range cidrs {
+		sn, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s subnet %q: %w", name, cidr, err)
+		}
+		sns[i] = sn.Masked()
+	}
+	return sns, nil
+}
+
+// withoutSubnets returns a copy of the given subnets that doesn't contain any of the removed subnets.
+func withoutSubnets(subnets, removed []netip.Prefix) []netip.Prefix {
+	return slices.DeleteFunc(slices.Clone(subnets), func(sn netip.Prefix) bool {
+		return slices.Contains(removed, sn)
+	})
+}
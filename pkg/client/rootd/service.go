@@ -25,6 +25,8 @@ import (
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/logging"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/rootd/dns"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/rootd/vip"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/scout"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/socket"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
@@ -52,6 +54,7 @@ func GetNewServiceFunc(ctx context.Context) NewServiceFunc {
 
 const (
 	ProcessName         = "daemon"
+	vipStoreFile        = "virtual-ips.json"
 	titleName           = "Daemon"
 	pprofFlag           = "pprof"
 	metritonDisableFlag = "disable-metriton"
@@ -283,6 +286,45 @@ func (s *Service) WaitForAgentIP(ctx context.Context, request *rpc.WaitForAgentI
 	return rsp, err
 }
 
+func (s *Service) GetVirtualIPs(ctx context.Context, _ *emptypb.Empty) (result *rpc.VirtualIPs, err error) {
+	err = s.WithSession(func(ctx context.Context, session *Session) error {
+		result = session.getVirtualIPs()
+		return nil
+	})
+	return result, err
+}
+
+func (s *Service) GetRouteConflicts(ctx context.Context, _ *emptypb.Empty) (result *rpc.RouteConflicts, err error) {
+	err = s.WithSession(func(ctx context.Context, session *Session) error {
+		result = session.getRouteConflicts()
+		return nil
+	})
+	return result, err
+}
+
+func (s *Service) GetTrafficActivity(ctx context.Context, _ *emptypb.Empty) (result *rpc.TrafficActivity, err error) {
+	err = s.WithSession(func(ctx context.Context, session *Session) error {
+		result = session.getTrafficActivity()
+		return nil
+	})
+	return result, err
+}
+
+func (s *Service) UpdateRouting(ctx context.Context, req *rpc.UpdateRoutingRequest) (*emptypb.Empty, error) {
+	err := s.WithSession(func(ctx context.Context, session *Session) error {
+		return session.updateRouting(ctx, req)
+	})
+	return &emptypb.Empty{}, err
+}
+
+func (s *Service) GetDNSSearchPaths(ctx context.Context, _ *emptypb.Empty) (result *rpc.DNSSearchPaths, err error) {
+	err = s.WithSession(func(ctx context.Context, session *Session) error {
+		result = session.getDNSSearchPaths()
+		return nil
+	})
+	return result, err
+}
+
 func (s *Service) SetLogLevel(ctx context.Context, request *manager.LogLevelRequest) (*emptypb.Empty, error) {
 	duration := time.Duration(0)
 	if request.Duration != nil {
@@ -429,6 +471,20 @@ func (s *Service) serveGrpc(c context.Context, l net.Listener) error {
 	return err
 }
 
+// withVIPStore returns a context with the store that retains virtual IP assignments between sessions.
+// The store is file-backed when the routing.persistVirtualIPs config is set, so that assignments
+// survive a restart of the daemon.
+func withVIPStore(c context.Context, cfg client.Config) context.Context {
+	if cfg.Routing().PersistVirtualIPs {
+		store, err := vip.NewFileStore(c, filepath.Join(filelocation.AppUserCacheDir(c), vipStoreFile))
+		if err == nil {
+			return vip.WithStore(c, store)
+		}
+		dlog.Errorf(c, "unable to load persisted virtual IPs: %v", err)
+	}
+	return vip.WithStore(c, vip.NewMemoryStore())
+}
+
 // run is the main function when executing as the daemon.
 func run(cmd *cobra.Command, args []string) error {
 	if !proc.IsAdmin() {
@@ -472,6 +528,13 @@ func run(cmd *cobra.Command, args []string) error {
 	dlog.Infof(c, "PID is %d", os.Getpid())
 	dlog.Info(c, "")
 
+	c = withVIPStore(c, cfg)
+	defer func() {
+		if err := vip.GetStore(c).Close(); err != nil {
+			dlog.Errorf(c, "failed to persist virtual IPs: %v", err)
+		}
+	}()
+
 	// Listen on domain unix domain socket or windows named pipe. The listener must be opened
 	// before other tasks because the CLI client will only wait for a short period of time for
 	// the socket/pipe to appear before it gives up.
@@ -490,6 +553,7 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	vif.InitLogger(c)
+	dns.RestoreResolvConf(c)
 
 	g := dgroup.NewGroup(c, dgroup.GroupConfig{
 		SoftShutdownTimeout:  2 * time.Second,
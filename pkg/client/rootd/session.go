@@ -1,6 +1,7 @@
 package rootd
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"errors"
@@ -26,6 +27,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	empty "google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
@@ -48,6 +50,8 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+	"github.com/telepresenceio/telepresence/v2/pkg/routing"
 	"github.com/telepresenceio/telepresence/v2/pkg/slice"
 	"github.com/telepresenceio/telepresence/v2/pkg/subnet"
 	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
@@ -57,11 +61,15 @@ import (
 type agentSubnet struct {
 	netip.Prefix
 	workload string
+
+	// port, when non-zero, is the only destination port that is routed to the workload.
+	port uint16
 }
 
 type agentVIP struct {
 	workload      string
 	destinationIP netip.Addr
+	port          uint16
 }
 
 // Session resolves DNS names and routes outbound traffic that is centered around a TUN device. The router is
@@ -148,6 +156,25 @@ type Session struct {
 	// vipGenerator generates virtual IPs for a given range.
 	vipGenerator vip.Generator
 
+	// vip6Generator generates virtual IPs for translated IPv6 addresses when the vipGenerator
+	// range is an IPv4 subnet. Its range is a randomly chosen IPv6 ULA subnet.
+	vip6Generator vip.Generator
+
+	// vipStore retains the virtual IPs assigned to remote IPs so that a remote IP keeps its
+	// virtual IP when the session is recreated.
+	vipStore vip.Store
+
+	// vipProvider, when set, replaces the vipGenerator and vipStore as the source of virtual IPs.
+	vipProvider vip.LocalIPProvider
+
+	// routeConflicts are the conflicts between routed subnets and existing routes that were found
+	// the last time the routes were validated. It is nil when no conflicts were found.
+	routeConflicts atomic.Pointer[[]vif.RouteConflict]
+
+	// lastTrafficActivity is the time, in Unix nanoseconds, when data was last sent or received on a
+	// tunnel to the cluster, or zero if no data has been sent or received.
+	lastTrafficActivity atomic.Int64
+
 	// closing is set during shutdown and can have the values:
 	//   0 = running
 	//   1 = closing
@@ -188,6 +215,18 @@ type Session struct {
 	// vifReady is closed when the virtual network interface has been configured.
 	vifReady chan error
 
+	// routingLock serializes the updates of the routes that are caused by cluster info changes
+	// and by calls to updateRouting.
+	routingLock sync.Mutex
+
+	// clusterInfo is the last cluster info received from the traffic-manager.
+	clusterInfo *manager.ClusterInfo
+
+	// removedAlsoProxy and removedNeverProxy are the subnets that have been removed using updateRouting. They
+	// are not added again when the traffic-manager reports them in its cluster info.
+	removedAlsoProxy  []netip.Prefix
+	removedNeverProxy []netip.Prefix
+
 	// done is closed when the session ends
 	done               chan struct{}
 	subnetViaWorkloads []*rpc.SubnetViaWorkload
@@ -322,6 +361,18 @@ func connectToUserDaemon(c context.Context) (*grpc.ClientConn, connector.Manager
 	return conn, mc, mgrVer, nil
 }
 
+// SessionOption configures a Session when it's created.
+type SessionOption func(*Session)
+
+// WithLocalIPProvider returns a SessionOption that makes the session obtain its virtual IPs from the given provider
+// instead of generating them, which makes it possible to integrate with an external IP address management. The
+// provider must return IPs from within the virtual subnet.
+func WithLocalIPProvider(provider vip.LocalIPProvider) SessionOption {
+	return func(s *Session) {
+		s.vipProvider = provider
+	}
+}
+
 // NewSession returns a new properly initialized session object.
 func NewSession(c context.Context, mi *rpc.NetworkConfig) (context.Context, *Session, error) {
 	dlog.Info(c, "-- Starting new session")
@@ -348,7 +399,14 @@ func NewSession(c context.Context, mi *rpc.NetworkConfig) (context.Context, *Ses
 
 func nope() bool { return false }
 
-func newSession(c context.Context, mi *rpc.NetworkConfig, mc connector.ManagerProxyClient, ver semver.Version, isPodDaemon bool) (context.Context, *Session, error) {
+func newSession(
+	c context.Context,
+	mi *rpc.NetworkConfig,
+	mc connector.ManagerProxyClient,
+	ver semver.Version,
+	isPodDaemon bool,
+	opts ...SessionOption,
+) (context.Context, *Session, error) {
 	dlog.Debugf(c, "Creating session with id %v", mi.Session)
 	s := &Session{
 		handlers:              tunnel.NewPool(),
@@ -366,16 +424,38 @@ func newSession(c context.Context, mi *rpc.NetworkConfig, mc connector.ManagerPr
 		localTranslationTable: xsync.NewMapOf[netip.Addr, netip.Addr](),
 		virtualIPs:            xsync.NewMapOf[netip.Addr, agentVIP](),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
 	cfg := client.GetConfig(c)
 	rt := cfg.Routing()
 	var err error
+	if s.vipProvider == nil && rt.VirtualIPProvider != "" {
+		if s.vipProvider, err = vip.NewHTTPProvider(rt.VirtualIPProvider); err != nil {
+			return c, nil, errcat.Config.New(err)
+		}
+	}
+	if err = validateProxyViaPorts(s.subnetViaWorkloads, runtime.GOOS, proc.RunningInContainer()); err != nil {
+		return c, nil, err
+	}
 	s.alsoProxySubnets, err = validateSubnets("also-proxy", rt.AlsoProxy, s.alsoProxyVia)
 	if err != nil {
 		return c, nil, err
 	}
 	dlog.Infof(c, "also-proxy subnets %v", s.alsoProxySubnets)
 
-	s.neverProxySubnets, err = validateSubnets("never-proxy", rt.NeverProxy, nope)
+	neverProxy := rt.NeverProxy
+	if len(rt.NeverProxyHosts) > 0 {
+		// The hosts are resolved using the host's resolver each time a session is created, so a
+		// reconnect will pick up any changes in their addresses.
+		hostSubnets, err := subnet.ResolveHosts(c, lookupNeverProxyHost, rt.NeverProxyHosts)
+		if err != nil {
+			dlog.Warnf(c, "never-proxy hosts: %v", err)
+		}
+		dlog.Infof(c, "never-proxy hosts %v resolved to %v", rt.NeverProxyHosts, hostSubnets)
+		neverProxy = append(slices.Clone(neverProxy), hostSubnets...)
+	}
+	s.neverProxySubnets, err = validateSubnets("never-proxy", neverProxy, nope)
 	if err != nil {
 		return c, nil, err
 	}
@@ -387,6 +467,10 @@ func newSession(c context.Context, mi *rpc.NetworkConfig, mc connector.ManagerPr
 	}
 	dlog.Infof(c, "allow-conflicting subnets %v", s.allowConflictingSubnets)
 
+	if s.vipStore = vip.GetStore(c); s.vipStore == nil {
+		s.vipStore = vip.NewMemoryStore()
+	}
+
 	s.dnsServer = dns.NewServer(cfg.DNS(), s.clusterLookup)
 	s.SetTopLevelDomains(c, nil)
 	return c, s, nil
@@ -443,33 +527,50 @@ func (s *Session) clusterLookup(ctx context.Context, q *dns2.Question) (dnsproxy
 }
 
 func (s *Session) GetLocalIP(ctx context.Context, destinationIP netip.Addr) (netip.Addr, error) {
-	var err error
-	va, ok := s.localTranslationTable.Compute(destinationIP, func(existing netip.Addr, loaded bool) (netip.Addr, bool) {
-		if loaded {
-			return existing, false
-		}
-		for _, sn := range s.localTranslationSubnets {
-			if sn.Contains(destinationIP) {
-				var nip netip.Addr
-				nip, err = s.nextVirtualIP(sn.workload, destinationIP)
-				return nip, err != nil
-			}
+	if va, ok := s.localTranslationTable.Load(destinationIP); ok {
+		dlog.Debugf(ctx, "using VIP %q for resolved IP %q", va, destinationIP)
+		return va, nil
+	}
+	for _, sn := range s.localTranslationSubnets {
+		if !sn.Contains(destinationIP) {
+			continue
+		}
+		// The virtual IP is obtained without holding a lock on the translation table, because a provider
+		// might need to make a network call to get it.
+		nip, err := s.nextVirtualIP(ctx, sn.workload, sn.port, destinationIP)
+		if err != nil {
+			return destinationIP, err
+		}
+		va, loaded := s.localTranslationTable.LoadOrStore(destinationIP, nip)
+		if loaded && va != nip {
+			// A concurrent lookup of the same IP got there first.
+			s.virtualIPs.Delete(nip)
 		}
-		return netip.Addr{}, true
-	})
-	if ok {
 		dlog.Debugf(ctx, "using VIP %q for resolved IP %q", va, destinationIP)
-		destinationIP = va
+		return va, nil
 	}
-	return destinationIP, err
+	return destinationIP, nil
 }
 
-func (s *Session) nextVirtualIP(workload string, destinationIP netip.Addr) (netip.Addr, error) {
-	va, err := s.vipGenerator.Next()
+func (s *Session) nextVirtualIP(ctx context.Context, workload string, port uint16, destinationIP netip.Addr) (netip.Addr, error) {
+	gen := s.vipGenerator
+	if destinationIP.Is6() && s.vip6Generator != nil {
+		gen = s.vip6Generator
+	}
+	var va netip.Addr
+	var err error
+	if s.vipProvider != nil {
+		va, err = s.vipProvider.GetLocalIP(ctx, destinationIP)
+		if err == nil && !gen.Subnet().Contains(va) {
+			err = fmt.Errorf("virtual IP %s for %s is not in the virtual subnet %s", va, destinationIP, gen.Subnet())
+		}
+	} else {
+		va, err = vip.Assign(gen, s.vipStore, destinationIP)
+	}
 	if err != nil {
 		return va, err
 	}
-	s.virtualIPs.Store(va, agentVIP{workload: workload, destinationIP: destinationIP})
+	s.virtualIPs.Store(va, agentVIP{workload: workload, destinationIP: destinationIP, port: port})
 	return va, nil
 }
 
@@ -480,8 +581,14 @@ func (s *Session) getNetworkConfig(ctx context.Context) *rpc.NetworkConfig {
 		curSubnets := s.tunVif.Router.GetRoutedSubnets()
 		r.Subnets = make([]netip.Prefix, len(curSubnets))
 		copy(r.Subnets, curSubnets)
+		rss := s.tunVif.Router.GetRoutedSubnetSources()
+		r.RoutedSubnets = make([]client.RoutedSubnet, len(rss))
+		for i, rs := range rss {
+			r.RoutedSubnets[i] = client.RoutedSubnet{Subnet: rs.Subnet, Source: string(rs.Source)}
+		}
 	} else {
 		r.Subnets = nil
+		r.RoutedSubnets = nil
 	}
 	if len(s.effectiveNeverProxy) > 0 {
 		r.NeverProxy = make([]netip.Prefix, len(s.effectiveNeverProxy))
@@ -501,6 +608,13 @@ func (s *Session) getNetworkConfig(ctx context.Context) *rpc.NetworkConfig {
 	} else {
 		r.AllowConflicting = nil
 	}
+	r.VirtualSubnets = nil
+	if s.vipGenerator != nil {
+		r.VirtualSubnets = append(r.VirtualSubnets, s.vipGenerator.Subnet())
+	}
+	if s.vip6Generator != nil {
+		r.VirtualSubnets = append(r.VirtualSubnets, s.vip6Generator.Subnet())
+	}
 	d := mc.DNS()
 	if s.dnsLocalAddr != nil {
 		d.LocalIP, _ = netip.AddrFromSlice(s.dnsLocalAddr.IP)
@@ -605,25 +719,9 @@ func (s *Session) watchClusterInfo(ctx context.Context) error {
 				}
 				break
 			}
-			if err = s.readAdditionalRouting(ctx, mgrInfo); err != nil {
+			if err = s.handleClusterInfo(ctx, mgrInfo); err != nil {
 				return err
 			}
-			select {
-			case <-s.vifReady:
-				if err := s.onClusterInfo(ctx, mgrInfo); err != nil {
-					if !errors.Is(err, context.Canceled) {
-						dlog.Error(ctx, err)
-					}
-					return err
-				}
-			default:
-				if err = s.onFirstClusterInfo(ctx, mgrInfo); err != nil {
-					if !errors.Is(err, context.Canceled) {
-						dlog.Error(ctx, err)
-					}
-					return err
-				}
-			}
 		}
 		dtime.SleepWithContext(ctx, backoff)
 		backoff *= 2
@@ -634,6 +732,25 @@ func (s *Session) watchClusterInfo(ctx context.Context) error {
 	return nil
 }
 
+func (s *Session) handleClusterInfo(ctx context.Context, mgrInfo *manager.ClusterInfo) (err error) {
+	s.routingLock.Lock()
+	defer s.routingLock.Unlock()
+	if err = s.readAdditionalRouting(ctx, mgrInfo); err != nil {
+		return err
+	}
+	s.clusterInfo = mgrInfo
+	select {
+	case <-s.vifReady:
+		err = s.onClusterInfo(ctx, mgrInfo)
+	default:
+		err = s.onFirstClusterInfo(ctx, mgrInfo)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		dlog.Error(ctx, err)
+	}
+	return err
+}
+
 // createSubnetForDNSOnly will find a random IPv4 subnet that isn't currently routed and
 // attach the DNS server to that subnet.
 func (s *Session) createSubnetForDNSOnly(ctx context.Context, mgrInfo *manager.ClusterInfo) {
@@ -762,6 +879,25 @@ func (s *Session) onClusterInfo(ctx context.Context, mgrInfo *manager.ClusterInf
 		subnets = append(subnets, s.vipGenerator.Subnet())
 		dlog.Debugf(ctx, "Adding VIP subnet %q to TUN-device", s.vipGenerator.Subnet().String())
 		s.consolidateProxyViaWorkloads(ctx)
+		if s.vip6Generator == nil && s.translatesIPv6IntoIPv4() {
+			sn, err := s.randomVIP6Subnet(ctx, subnets)
+			if err != nil {
+				return err
+			}
+			dlog.Infof(ctx, "Using automatically selected IPv6 ULA subnet %s for IPv6 virtual IPs", sn)
+			s.vip6Generator = vip.NewGenerator(sn)
+		}
+		vipSubnets := []netip.Prefix{s.vipGenerator.Subnet()}
+		if s.vip6Generator != nil {
+			vipSubnets = append(vipSubnets, s.vip6Generator.Subnet())
+			subnets = append(subnets, s.vip6Generator.Subnet())
+			dlog.Debugf(ctx, "Adding IPv6 VIP subnet %q to TUN-device", s.vip6Generator.Subnet().String())
+		}
+		// Assignments made in subnets that this session doesn't use, e.g. a random IPv6 subnet selected by
+		// an earlier session, will never be used again.
+		if err := s.vipStore.Retain(vipSubnets...); err != nil {
+			return err
+		}
 	}
 
 	if !s.alsoProxyVia() {
@@ -818,15 +954,24 @@ func (s *Session) onClusterInfo(ctx context.Context, mgrInfo *manager.ClusterInf
 	}
 	rt := s.tunVif.Router
 	rt.UpdateWhitelist(s.allowConflictingSubnets)
+	rt.SetSubnetSources(s.subnetSources())
+	rt.SetAggregate(client.GetConfig(ctx).Routing().Aggregate)
 
-	err = rt.ValidateRoutes(ctx, proxy)
+	conflicts, err := rt.DescribeConflicts(ctx, proxy)
 	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		// Stored when returning, so that the conflicts aren't cleared by the recursive call below, which
+		// no longer sees them once they are translated.
+		defer s.routeConflicts.Store(&conflicts)
+		err = vif.RouteConflictsError(conflicts)
 		if s.vipGenerator != nil || !client.GetConfig(ctx).Routing().AutoResolveConflicts {
 			return err
 		}
-		// Check each subnet and add a translation for those that conflict.
+		// Add a translation for each subnet that conflicts.
 		for _, pp := range proxy {
-			if routeConflict := rt.ValidateRoutes(ctx, []netip.Prefix{pp}); routeConflict != nil {
+			if slices.ContainsFunc(conflicts, func(c vif.RouteConflict) bool { return c.Subnet == pp }) {
 				dlog.Infof(ctx, "Translating IPs in conflicting subnet %s to the virtual subnet", pp)
 				s.subnetViaWorkloads = append(s.subnetViaWorkloads, &rpc.SubnetViaWorkload{
 					Subnet:   pp.String(),
@@ -840,11 +985,41 @@ func (s *Session) onClusterInfo(ctx context.Context, mgrInfo *manager.ClusterInf
 		}
 		return s.onClusterInfo(ctx, mgrInfo)
 	}
+	s.routeConflicts.Store(nil)
 
 	dlog.Debugf(ctx, "UpdatinRoutes %s, %s, %s", proxy, s.effectiveNeverProxy, neverProxyOverrides)
 	return rt.UpdateRoutes(ctx, proxy, s.effectiveNeverProxy, neverProxyOverrides)
 }
 
+// subnetSources returns the reason why each of the subnets that onClusterInfo might route is routed.
+func (s *Session) subnetSources() map[netip.Prefix]vif.SubnetSource {
+	sources := make(map[netip.Prefix]vif.SubnetSource)
+	add := func(src vif.SubnetSource, sns ...netip.Prefix) {
+		for _, sn := range sns {
+			if _, ok := sources[sn]; !ok && sn.IsValid() {
+				sources[sn] = src
+			}
+		}
+	}
+	// The virtual subnets are labeled "proxy-via" when they hold the virtual IPs of subnets that are routed via a
+	// workload, and "virtual" when all the subnets are just translated locally.
+	vs := vif.SubnetSourceVirtual
+	if slices.ContainsFunc(s.localTranslationSubnets, func(as agentSubnet) bool { return as.workload != "" }) {
+		vs = vif.SubnetSourceProxyVia
+	}
+	if s.vipGenerator != nil {
+		add(vs, s.vipGenerator.Subnet())
+	}
+	if s.vip6Generator != nil {
+		add(vs, s.vip6Generator.Subnet())
+	}
+	add(vif.SubnetSourceService, s.serviceSubnet)
+	add(vif.SubnetSourcePod, s.podSubnets...)
+	add(vif.SubnetSourceAlsoProxy, s.alsoProxySubnets...)
+	add(vif.SubnetSourceDNS, s.dnsServerSubnet)
+	return sources
+}
+
 func computeNeverProxyOverrides(ctx context.Context, subnets, nvp []netip.Prefix) (proxy, neverProxy, neverProxyOverrides []netip.Prefix) {
 	neverProxy = slices.DeleteFunc(slices.Clone(nvp), func(nps netip.Prefix) bool {
 		for _, ds := range subnets {
@@ -875,6 +1050,9 @@ func computeNeverProxyOverrides(ctx context.Context, subnets, nvp []netip.Prefix
 	return subnet.Unique(proxy), neverProxy, neverProxyOverrides
 }
 
+// lookupNeverProxyHost resolves the hosts given with --never-proxy-host.
+var lookupNeverProxyHost subnet.LookupFunc = net.DefaultResolver.LookupNetIP //nolint:gochecknoglobals // can be replaced
+
 func validateSubnets(name string, ns []netip.Prefix, allowLoopback func() bool) ([]netip.Prefix, error) {
 	ns = subnet.Unique(ns)
 	rs := make([]netip.Prefix, 0, len(ns))
@@ -903,14 +1081,14 @@ func (s *Session) readAdditionalRouting(ctx context.Context, mgrInfo *manager.Cl
 		if err != nil {
 			return err
 		}
-		s.alsoProxySubnets = subnet.Unique(append(s.alsoProxySubnets, sns...))
+		s.alsoProxySubnets = subnet.Unique(append(s.alsoProxySubnets, withoutSubnets(sns, s.removedAlsoProxy)...))
 		dlog.Infof(ctx, "also-proxy subnets %v", s.alsoProxySubnets)
 
 		sns, err = validateSubnets("never-proxy", iputil.RPCsToPrefixes(r.NeverProxySubnets), nope)
 		if err != nil {
 			return err
 		}
-		s.neverProxySubnets = subnet.Unique(append(s.neverProxySubnets, sns...))
+		s.neverProxySubnets = subnet.Unique(append(s.neverProxySubnets, withoutSubnets(sns, s.removedNeverProxy)...))
 		dlog.Infof(ctx, "never-proxy subnets %v", s.neverProxySubnets)
 
 		sns, err = validateSubnets("allow-conflicting", iputil.RPCsToPrefixes(r.AllowConflictingSubnets), nope)
@@ -1167,6 +1345,10 @@ func (s *Session) activateProxyViaWorkloads(ctx context.Context) error {
 		if s.agentClients == nil {
 			return errcat.User.Newf("Agent port-forwards are disabled. Client is not permitted to do proxy-via %s", wlName)
 		}
+		if _, err := netip.ParseAddr(wlName); err == nil {
+			// A pod IP is used directly as the proxy endpoint. Its pod must already have a traffic-agent.
+			continue
+		}
 		dlog.Debugf(ctx, "Ensuring proxy-via agent in %s", wlName)
 		_, err := s.managerClient.EnsureAgent(ctx, &manager.EnsureAgentRequest{
 			Session: s.session,
@@ -1184,29 +1366,82 @@ func (s *Session) activateProxyViaWorkloads(ctx context.Context) error {
 	return nil
 }
 
+// translatesIPv6IntoIPv4 returns true if the virtual subnet is an IPv4 subnet and at least one
+// of the local translation subnets is an IPv6 subnet.
+func (s *Session) translatesIPv6IntoIPv4() bool {
+	if !s.vipGenerator.Subnet().Addr().Is4() {
+		return false
+	}
+	for _, sn := range s.localTranslationSubnets {
+		if sn.Addr().Is6() {
+			return true
+		}
+	}
+	return false
+}
+
+// randomVIP6Subnet returns a random IPv6 ULA subnet that doesn't overlap with the given subnets,
+// the never-proxy subnets, or any subnet that is currently routed by the host.
+func (s *Session) randomVIP6Subnet(ctx context.Context, subnets []netip.Prefix) (netip.Prefix, error) {
+	avoid := slices.Concat(subnets, s.neverProxySubnets)
+	rt, err := routing.GetRoutingTable(ctx)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	for _, r := range rt {
+		avoid = append(avoid, r.RoutedNet)
+	}
+	return subnet.RandomULAPrefix(avoid)
+}
+
+// validateProxyViaPorts returns an error when a proxy-via uses the port form on a platform where connections to the
+// other ports can't be dialed locally using the original destination IP. That's the case when the daemon runs in a
+// container, because the connections would then be dialed from within the container, and for loopback addresses
+// other than 127.0.0.1 on macOS, because only that address is assigned to its loopback interface.
+func validateProxyViaPorts(svs []*rpc.SubnetViaWorkload, goos string, inContainer bool) error {
+	for _, sv := range svs {
+		if sv.Port == 0 {
+			continue
+		}
+		if inContainer {
+			return errcat.User.Newf("proxy-via %s:%d: a port cannot be used when the daemon runs in a container", sv.Subnet, sv.Port)
+		}
+		if goos == "darwin" {
+			sn, err := netip.ParsePrefix(sv.Subnet)
+			if err == nil && sn.Overlaps(netip.MustParsePrefix("127.0.0.0/8")) && sn.Masked() != netip.MustParsePrefix("127.0.0.1/32") {
+				return errcat.User.Newf("proxy-via %s:%d: a port can only be used with the loopback address 127.0.0.1/32 on macOS",
+					sv.Subnet, sv.Port)
+			}
+		}
+	}
+	return nil
+}
+
 func (s *Session) consolidateProxyViaWorkloads(ctx context.Context) []string {
-	desiredVips := make(map[string][]netip.Prefix)
+	desiredVips := make(map[string][]agentSubnet)
 	snCount := 0
+	add := func(wl string, port uint16, sns ...netip.Prefix) {
+		for _, sn := range sns {
+			desiredVips[wl] = append(desiredVips[wl], agentSubnet{Prefix: sn, port: port})
+		}
+		snCount += len(sns)
+	}
 	for _, pvx := range s.subnetViaWorkloads {
 		switch pvx.Subnet {
 		case "also":
-			desiredVips[pvx.Workload] = append(desiredVips[pvx.Workload], s.alsoProxySubnets...)
-			snCount += len(s.alsoProxySubnets)
+			add(pvx.Workload, 0, s.alsoProxySubnets...)
 		case "pods":
-			desiredVips[pvx.Workload] = append(desiredVips[pvx.Workload], s.podSubnets...)
-			snCount += len(s.podSubnets)
+			add(pvx.Workload, 0, s.podSubnets...)
 		case "service":
 			if s.serviceSubnet.IsValid() {
-				desiredVips[pvx.Workload] = append(desiredVips[pvx.Workload], s.serviceSubnet)
-				snCount++
+				add(pvx.Workload, 0, s.serviceSubnet)
 			}
 		default:
 			sn, err := netip.ParsePrefix(pvx.Subnet)
 			if err != nil {
 				dlog.Warnf(ctx, "unable to parse proxy-via subnet %s", pvx.Subnet)
 			} else {
-				desiredVips[pvx.Workload] = append(desiredVips[pvx.Workload], sn)
-				snCount++
+				add(pvx.Workload, uint16(pvx.Port), sn)
 			}
 		}
 	}
@@ -1220,7 +1455,8 @@ func (s *Session) consolidateProxyViaWorkloads(ctx context.Context) []string {
 			wlNames = append(wlNames, wlName)
 		}
 		for _, sn := range sns {
-			lcs = append(lcs, agentSubnet{Prefix: sn, workload: wlName})
+			sn.workload = wlName
+			lcs = append(lcs, sn)
 		}
 	}
 	s.localTranslationSubnets = lcs
@@ -1243,11 +1479,20 @@ func (s *Session) waitForProxyViaWorkloads(ctx context.Context) error {
 			ws = slice.AppendUnique(ws, svw.Workload)
 		}
 	}
+	for _, wl := range ws {
+		if ip, err := netip.ParseAddr(wl); err == nil && len(s.podSubnets) > 0 && !slices.ContainsFunc(s.podSubnets, func(sn netip.Prefix) bool { return sn.Contains(ip) }) {
+			return errcat.User.Newf("proxy-via pod IP %s is not in any of the cluster's pod subnets %v", ip, s.podSubnets)
+		}
+	}
 	for _, wl := range ws {
 		s.agentClients.SetProxyVia(wl)
 		dlog.Debugf(ctx, "Waiting for proxy-via agent in %s", wl)
 		go func(wl string) {
-			waitCh <- s.agentClients.WaitForWorkload(ctx, to, wl)
+			if ip, err := netip.ParseAddr(wl); err == nil {
+				waitCh <- s.agentClients.WaitForIP(ctx, to, ip)
+			} else {
+				waitCh <- s.agentClients.WaitForWorkload(ctx, to, wl)
+			}
 		}(wl)
 	}
 	for _, wl := range ws {
@@ -1281,6 +1526,70 @@ func (s *Session) translateEnvIPs(ctx context.Context, environment *rpc.Environm
 	return environment
 }
 
+// getVirtualIPs returns the current translations between remote IPs and virtual IPs, sorted by virtual IP.
+func (s *Session) getVirtualIPs() *rpc.VirtualIPs {
+	var vips []*rpc.VirtualIP
+	s.virtualIPs.Range(func(va netip.Addr, av agentVIP) bool {
+		vi := &rpc.VirtualIP{
+			RemoteIp:  av.destinationIP.AsSlice(),
+			VirtualIp: va.AsSlice(),
+			Workload:  av.workload,
+		}
+		for _, gen := range []vip.Generator{s.vipGenerator, s.vip6Generator} {
+			if gen != nil && gen.Subnet().Contains(va) {
+				vi.Subnet = gen.Subnet().String()
+				break
+			}
+		}
+		vips = append(vips, vi)
+		return true
+	})
+	slices.SortFunc(vips, func(a, b *rpc.VirtualIP) int {
+		return bytes.Compare(a.VirtualIp, b.VirtualIp)
+	})
+	return &rpc.VirtualIPs{VirtualIps: vips}
+}
+
+func (s *Session) getTrafficActivity() *rpc.TrafficActivity {
+	ta := &rpc.TrafficActivity{}
+	if t := s.lastTrafficActivity.Load(); t != 0 {
+		ta.LastActivity = timestamppb.New(time.Unix(0, t))
+	}
+	return ta
+}
+
+func (s *Session) getRouteConflicts() *rpc.RouteConflicts {
+	cp := s.routeConflicts.Load()
+	if cp == nil {
+		return &rpc.RouteConflicts{}
+	}
+	conflicts := *cp
+	rcs := make([]*rpc.RouteConflict, len(conflicts))
+	for i, c := range conflicts {
+		rc := &rpc.RouteConflict{
+			Subnet: c.Subnet.String(),
+			Route:  c.Route.RoutedNet.String(),
+		}
+		if c.Route.Interface != nil {
+			rc.Interface = c.Route.Interface.Name
+		}
+		if c.Route.Gateway.IsValid() {
+			rc.Gateway = c.Route.Gateway.String()
+		}
+		rcs[i] = rc
+	}
+	return &rpc.RouteConflicts{RouteConflicts: rcs}
+}
+
+func (s *Session) getDNSSearchPaths() *rpc.DNSSearchPaths {
+	search, dropSuffixes, routes := s.dnsServer.SearchPaths()
+	return &rpc.DNSSearchPaths{
+		SearchPaths:  search,
+		DropSuffixes: dropSuffixes,
+		Routes:       routes,
+	}
+}
+
 func (s *Session) MapsIPv4() bool {
 	for _, p := range s.localTranslationSubnets {
 		if p.Addr().Is4() {
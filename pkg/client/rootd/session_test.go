@@ -0,0 +1,276 @@
+package rootd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/rootd/vip"
+	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
+	"github.com/telepresenceio/telepresence/v2/pkg/vif"
+)
+
+// stubProvider returns predetermined IPs, like an external IP address management would.
+type stubProvider map[netip.Addr]netip.Addr
+
+func (s stubProvider) MapsIPv4() bool {
+	return true
+}
+
+func (s stubProvider) MapsIPv6() bool {
+	return false
+}
+
+func (s stubProvider) GetLocalIP(_ context.Context, remoteIP netip.Addr) (netip.Addr, error) {
+	if lip, ok := s[remoteIP]; ok {
+		return lip, nil
+	}
+	return remoteIP, nil
+}
+
+func TestSession_WithLocalIPProvider(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	ctx = client.WithConfig(ctx, client.GetDefaultConfig())
+	provider := stubProvider{
+		netip.MustParseAddr("10.110.210.8"): netip.MustParseAddr("100.156.200.42"),
+		netip.MustParseAddr("10.110.210.9"): netip.MustParseAddr("192.168.1.3"),
+	}
+	ctx, s, err := newSession(ctx, &rpc.NetworkConfig{}, nil, semver.Version{}, false, WithLocalIPProvider(provider))
+	require.NoError(t, err)
+	s.vipGenerator = vip.NewGenerator(netip.MustParsePrefix("100.156.200.0/24"))
+
+	va, err := s.nextVirtualIP(ctx, "echo", 80, netip.MustParseAddr("10.110.210.8"))
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("100.156.200.42"), va)
+	assert.Equal(t, []netip.Addr{va}, virtualIPsOf(s))
+
+	// IPs that the provider returns must be in the virtual subnet.
+	_, err = s.nextVirtualIP(ctx, "echo", 80, netip.MustParseAddr("10.110.210.9"))
+	assert.ErrorContains(t, err, "is not in the virtual subnet")
+	assert.Equal(t, []netip.Addr{va}, virtualIPsOf(s))
+}
+
+// racingProvider stores a translation for the IP that it's asked for before it returns, like a concurrent lookup
+// of the same IP would.
+type racingProvider struct {
+	stubProvider
+	s *Session
+}
+
+func (r *racingProvider) GetLocalIP(ctx context.Context, remoteIP netip.Addr) (netip.Addr, error) {
+	r.s.localTranslationTable.Store(remoteIP, netip.MustParseAddr("100.156.200.7"))
+	return r.stubProvider.GetLocalIP(ctx, remoteIP)
+}
+
+func TestSession_GetLocalIP(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	ctx = client.WithConfig(ctx, client.GetDefaultConfig())
+	rp := &racingProvider{stubProvider: stubProvider{
+		netip.MustParseAddr("10.110.210.8"): netip.MustParseAddr("100.156.200.42"),
+	}}
+	ctx, s, err := newSession(ctx, &rpc.NetworkConfig{}, nil, semver.Version{}, false, WithLocalIPProvider(rp))
+	require.NoError(t, err)
+	rp.s = s
+	s.vipGenerator = vip.NewGenerator(netip.MustParsePrefix("100.156.200.0/24"))
+	s.localTranslationSubnets = []agentSubnet{{Prefix: netip.MustParsePrefix("10.110.210.0/24"), workload: "echo"}}
+
+	// The provider is called without a lock on the translation table, so it doesn't deadlock, and the
+	// translation that was stored first wins.
+	va, err := s.GetLocalIP(ctx, netip.MustParseAddr("10.110.210.8"))
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("100.156.200.7"), va)
+	assert.Empty(t, virtualIPsOf(s))
+
+	// IPs outside the translated subnets are returned as is.
+	ip := netip.MustParseAddr("10.110.211.8")
+	va, err = s.GetLocalIP(ctx, ip)
+	require.NoError(t, err)
+	assert.Equal(t, ip, va)
+}
+
+func TestSession_virtualIPProviderConfig(t *testing.T) {
+	// The external IP address management hands out IPs in the order that they are requested.
+	var mu sync.Mutex
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requested = append(requested, r.URL.Query().Get("ip"))
+		if r.URL.Query().Get("ip") == "10.110.210.9" {
+			http.Error(w, "exhausted", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "100.156.200.%d\n", 100+len(requested))
+	}))
+	defer srv.Close()
+
+	ctx := dlog.NewTestContext(t, false)
+	cfg := client.GetDefaultConfig()
+	cfg.Routing().VirtualIPProvider = srv.URL
+	ctx = client.WithConfig(ctx, cfg)
+	ctx, s, err := newSession(ctx, &rpc.NetworkConfig{}, nil, semver.Version{}, false)
+	require.NoError(t, err)
+	s.vipGenerator = vip.NewGenerator(netip.MustParsePrefix("100.156.200.0/24"))
+
+	va, err := s.nextVirtualIP(ctx, "echo", 80, netip.MustParseAddr("10.110.210.8"))
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("100.156.200.101"), va)
+	assert.Equal(t, []netip.Addr{va}, virtualIPsOf(s))
+
+	_, err = s.nextVirtualIP(ctx, "echo", 80, netip.MustParseAddr("10.110.210.9"))
+	assert.ErrorContains(t, err, "503 Service Unavailable")
+	assert.Equal(t, []netip.Addr{va}, virtualIPsOf(s))
+	mu.Lock()
+	assert.Equal(t, []string{"10.110.210.8", "10.110.210.9"}, requested)
+	mu.Unlock()
+
+	cfg.Routing().VirtualIPProvider = "ftp://ipam.example.com"
+	_, _, err = newSession(ctx, &rpc.NetworkConfig{}, nil, semver.Version{}, false)
+	assert.ErrorContains(t, err, "scheme must be http or https")
+}
+
+func TestSession_withoutLocalIPProvider(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	ctx = client.WithConfig(ctx, client.GetDefaultConfig())
+	ctx, s, err := newSession(ctx, &rpc.NetworkConfig{}, nil, semver.Version{}, false)
+	require.NoError(t, err)
+	s.vipGenerator = vip.NewGenerator(netip.MustParsePrefix("100.156.200.0/24"))
+
+	va, err := s.nextVirtualIP(ctx, "echo", 80, netip.MustParseAddr("10.110.210.8"))
+	require.NoError(t, err)
+	assert.True(t, s.vipGenerator.Subnet().Contains(va))
+}
+
+func TestSession_subnetSources(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	ctx = client.WithConfig(ctx, client.GetDefaultConfig())
+	_, s, err := newSession(ctx, &rpc.NetworkConfig{}, nil, semver.Version{}, false)
+	require.NoError(t, err)
+	vs := netip.MustParsePrefix("211.55.48.0/20")
+	svc := netip.MustParsePrefix("10.96.0.0/16")
+	s.serviceSubnet = svc
+	s.vipGenerator = vip.NewGenerator(vs)
+
+	// Subnets that are just translated locally.
+	s.localTranslationSubnets = []agentSubnet{{Prefix: netip.MustParsePrefix("10.1.0.0/16")}}
+	assert.Equal(t, map[netip.Prefix]vif.SubnetSource{
+		vs:  vif.SubnetSourceVirtual,
+		svc: vif.SubnetSourceService,
+	}, s.subnetSources())
+
+	// Subnets that are routed via a workload.
+	s.localTranslationSubnets = append(s.localTranslationSubnets, agentSubnet{Prefix: netip.MustParsePrefix("10.2.0.0/16"), workload: "echo"})
+	assert.Equal(t, map[netip.Prefix]vif.SubnetSource{
+		vs:  vif.SubnetSourceProxyVia,
+		svc: vif.SubnetSourceService,
+	}, s.subnetSources())
+}
+
+func TestSession_readAdditionalRoutingKeepsRemovals(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	ctx = client.WithConfig(ctx, client.GetDefaultConfig())
+	_, s, err := newSession(ctx, &rpc.NetworkConfig{}, nil, semver.Version{}, false)
+	require.NoError(t, err)
+
+	also1 := netip.MustParsePrefix("192.168.4.0/24")
+	also2 := netip.MustParsePrefix("192.168.5.0/24")
+	never := netip.MustParsePrefix("10.1.2.0/24")
+	mgrInfo := &manager.ClusterInfo{Routing: &manager.Routing{
+		AlsoProxySubnets:  iputil.PrefixesToRPC([]netip.Prefix{also1, also2}),
+		NeverProxySubnets: iputil.PrefixesToRPC([]netip.Prefix{never}),
+	}}
+	require.NoError(t, s.readAdditionalRouting(ctx, mgrInfo))
+	assert.ElementsMatch(t, []netip.Prefix{also1, also2}, s.alsoProxySubnets)
+	assert.Contains(t, s.neverProxySubnets, never)
+
+	// Subnets that were removed at runtime aren't added again by the next cluster info.
+	s.alsoProxySubnets = withoutSubnets(s.alsoProxySubnets, []netip.Prefix{also2})
+	s.neverProxySubnets = withoutSubnets(s.neverProxySubnets, []netip.Prefix{never})
+	s.removedAlsoProxy = []netip.Prefix{also2}
+	s.removedNeverProxy = []netip.Prefix{never}
+	require.NoError(t, s.readAdditionalRouting(ctx, mgrInfo))
+	assert.Equal(t, []netip.Prefix{also1}, s.alsoProxySubnets)
+	assert.NotContains(t, s.neverProxySubnets, never)
+}
+
+func virtualIPsOf(s *Session) []netip.Addr {
+	var vas []netip.Addr
+	s.virtualIPs.Range(func(va netip.Addr, _ agentVIP) bool {
+		vas = append(vas, va)
+		return true
+	})
+	return vas
+}
+
+func Test_validateProxyViaPorts(t *testing.T) {
+	tests := []struct {
+		name        string
+		sv          *rpc.SubnetViaWorkload
+		goos        string
+		inContainer bool
+		wantErr     string
+	}{
+		{
+			name:        "no port in container",
+			sv:          &rpc.SubnetViaWorkload{Subnet: "10.0.0.0/24", Workload: "echo"},
+			goos:        "linux",
+			inContainer: true,
+		},
+		{
+			name: "port",
+			sv:   &rpc.SubnetViaWorkload{Subnet: "10.0.0.0/24", Workload: "echo", Port: 8080},
+			goos: "linux",
+		},
+		{
+			name:        "port in container",
+			sv:          &rpc.SubnetViaWorkload{Subnet: "10.0.0.0/24", Workload: "echo", Port: 8080},
+			goos:        "linux",
+			inContainer: true,
+			wantErr:     "a port cannot be used when the daemon runs in a container",
+		},
+		{
+			name: "loopback subnet on linux",
+			sv:   &rpc.SubnetViaWorkload{Subnet: "127.0.0.0/8", Workload: "echo", Port: 8080},
+			goos: "linux",
+		},
+		{
+			name: "127.0.0.1 on macOS",
+			sv:   &rpc.SubnetViaWorkload{Subnet: "127.0.0.1/32", Workload: "echo", Port: 8080},
+			goos: "darwin",
+		},
+		{
+			name:    "other loopback address on macOS",
+			sv:      &rpc.SubnetViaWorkload{Subnet: "127.0.0.2/32", Workload: "echo", Port: 8080},
+			goos:    "darwin",
+			wantErr: "a port can only be used with the loopback address 127.0.0.1/32 on macOS",
+		},
+		{
+			name:    "loopback subnet on macOS",
+			sv:      &rpc.SubnetViaWorkload{Subnet: "127.0.0.0/8", Workload: "echo", Port: 8080},
+			goos:    "darwin",
+			wantErr: "a port can only be used with the loopback address 127.0.0.1/32 on macOS",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateProxyViaPorts([]*rpc.SubnetViaWorkload{tt.sv}, tt.goos, tt.inContainer)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
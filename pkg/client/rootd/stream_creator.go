@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"net/netip"
+	"sync/atomic"
 	"time"
 
 	"github.com/puzpuzpuz/xsync/v3"
@@ -76,6 +77,15 @@ func (s *Session) streamCreator(ctx context.Context) tunnel.StreamCreator {
 		var tp tunnel.Provider
 		if a, ok := s.getAgentVIP(destAddr); ok {
 			// s.agentClients is never nil when agentVIPs are used.
+			if a.port != 0 && a.port != id.DestinationPort() {
+				// Only connections to a.port are routed to the workload. This one is dialed locally
+				// using the original destination IP.
+				id = tunnel.NewConnID(p, id.Source(), a.destinationIP.AsSlice(), id.SourcePort(), id.DestinationPort())
+				dlog.Debugf(c, "Dialing proxy-via %s locally for id %s", a.workload, id)
+				from, to := tunnel.NewPipe(id, s.session.SessionId)
+				tunnel.NewDialer(to, func() {}, nil, nil).Start(c)
+				return from, nil
+			}
 			if a.workload != "" {
 				tp = s.agentClients.GetWorkloadClient(a.workload)
 				if tp == nil {
@@ -107,8 +117,34 @@ func (s *Session) streamCreator(ctx context.Context) tunnel.StreamCreator {
 		}
 
 		tc := client.GetConfig(c).Timeouts()
-		return tunnel.NewClientStream(c, ct, id, s.session.SessionId, tc.Get(client.TimeoutRoundtripLatency), tc.Get(client.TimeoutEndpointDial))
+		st, err := tunnel.NewClientStream(c, ct, id, s.session.SessionId, tc.Get(client.TimeoutRoundtripLatency), tc.Get(client.TimeoutEndpointDial))
+		if err != nil {
+			return nil, err
+		}
+		return &activityStream{Stream: st, lastActivity: &s.lastTrafficActivity}, nil
+	}
+}
+
+// activityStream records the time when a message was last sent or received on the wrapped Stream.
+type activityStream struct {
+	tunnel.Stream
+	lastActivity *atomic.Int64
+}
+
+func (as *activityStream) Receive(ctx context.Context) (tunnel.Message, error) {
+	m, err := as.Stream.Receive(ctx)
+	if err == nil {
+		as.lastActivity.Store(time.Now().UnixNano())
 	}
+	return m, err
+}
+
+func (as *activityStream) Send(ctx context.Context, m tunnel.Message) error {
+	err := as.Stream.Send(ctx, m)
+	if err == nil {
+		as.lastActivity.Store(time.Now().UnixNano())
+	}
+	return err
 }
 
 func (s *Session) getAgentVIP(dest netip.Addr) (a agentVIP, ok bool) {
@@ -0,0 +1,70 @@
+package vip
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// httpProviderTimeout is the maximum time to wait for the external IP address management to respond.
+const httpProviderTimeout = 5 * time.Second
+
+type httpProvider struct {
+	url    *url.URL
+	client *http.Client
+}
+
+// NewHTTPProvider returns a LocalIPProvider that obtains the virtual IP of a remote IP from an external IP
+// address management, by sending a GET request to the given URL with the remote IP in the "ip" query parameter.
+// The response body must contain the virtual IP.
+func NewHTTPProvider(providerURL string) (LocalIPProvider, error) {
+	u, err := url.Parse(providerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid virtual IP provider URL %q: %w", providerURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid virtual IP provider URL %q: scheme must be http or https", providerURL)
+	}
+	return &httpProvider{url: u, client: &http.Client{Timeout: httpProviderTimeout}}, nil
+}
+
+func (p *httpProvider) MapsIPv4() bool {
+	return true
+}
+
+func (p *httpProvider) MapsIPv6() bool {
+	return true
+}
+
+func (p *httpProvider) GetLocalIP(ctx context.Context, remoteIP netip.Addr) (netip.Addr, error) {
+	u := *p.url
+	q := u.Query()
+	q.Set("ip", remoteIP.String())
+	u.RawQuery = q.Encode()
+	rq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	rs, err := p.client.Do(rq)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("unable to get virtual IP for %s: %w", remoteIP, err)
+	}
+	defer rs.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(rs.Body, 256))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("unable to get virtual IP for %s: %w", remoteIP, err)
+	}
+	if rs.StatusCode != http.StatusOK {
+		return netip.Addr{}, fmt.Errorf("unable to get virtual IP for %s: %s", remoteIP, rs.Status)
+	}
+	va, err := netip.ParseAddr(strings.TrimSpace(string(body)))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid virtual IP for %s: %w", remoteIP, err)
+	}
+	return va, nil
+}
@@ -0,0 +1,239 @@
+package vip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-json-experiment/json"
+
+	"github.com/datawire/dlib/dlog"
+)
+
+// Store keeps track of the virtual IPs that have been assigned to remote IPs. The assignments are
+// scoped to a virtual subnet, so that a change of subnet starts out with a clean slate.
+type Store interface {
+	// Lookup returns the virtual IP assigned to the given remote IP within the given subnet.
+	Lookup(subnet netip.Prefix, remoteIP netip.Addr) (netip.Addr, bool)
+
+	// Assigned returns true if the given virtual IP has been assigned to a remote IP within the given subnet.
+	Assigned(subnet netip.Prefix, virtualIP netip.Addr) bool
+
+	// Save assigns the given virtual IP to the given remote IP within the given subnet.
+	Save(subnet netip.Prefix, remoteIP, virtualIP netip.Addr) error
+
+	// Retain evicts the assignments of all subnets except the given ones. It's called when a session
+	// starts using its virtual subnets, so that the assignments of subnets that are no longer in use
+	// don't accumulate.
+	Retain(subnets ...netip.Prefix) error
+
+	// Close writes assignments that haven't been persisted yet, if any.
+	Close() error
+}
+
+type storeKey struct{}
+
+// WithStore returns a context that carries the given Store.
+func WithStore(ctx context.Context, store Store) context.Context {
+	return context.WithValue(ctx, storeKey{}, store)
+}
+
+// GetStore returns the Store carried by the given context, or nil if no Store has been set.
+func GetStore(ctx context.Context) Store {
+	if s, ok := ctx.Value(storeKey{}).(Store); ok {
+		return s
+	}
+	return nil
+}
+
+// Assign returns the virtual IP that has been assigned to the given remote IP in the subnet of
+// the given generator. A new virtual IP is generated and saved in the store when no such IP
+// exists. Generated IPs that are already assigned to other remote IPs are skipped.
+func Assign(gen Generator, store Store, remoteIP netip.Addr) (netip.Addr, error) {
+	sn := gen.Subnet()
+	if va, ok := store.Lookup(sn, remoteIP); ok && sn.Contains(va) {
+		return va, nil
+	}
+	for {
+		va, err := gen.Next()
+		if err != nil {
+			return va, err
+		}
+		if !store.Assigned(sn, va) {
+			return va, store.Save(sn, remoteIP, va)
+		}
+	}
+}
+
+// subnetMappings maps remote IPs to virtual IPs within a subnet.
+type subnetMappings map[netip.Addr]netip.Addr
+
+type memoryStore struct {
+	sync.RWMutex
+	subnets map[netip.Prefix]subnetMappings
+}
+
+// NewMemoryStore returns a Store that retains its assignments for the lifetime of the process.
+func NewMemoryStore() Store {
+	return &memoryStore{subnets: make(map[netip.Prefix]subnetMappings)}
+}
+
+func (m *memoryStore) Lookup(subnet netip.Prefix, remoteIP netip.Addr) (netip.Addr, bool) {
+	m.RLock()
+	defer m.RUnlock()
+	va, ok := m.subnets[subnet][remoteIP]
+	return va, ok
+}
+
+func (m *memoryStore) Assigned(subnet netip.Prefix, virtualIP netip.Addr) bool {
+	m.RLock()
+	defer m.RUnlock()
+	for _, va := range m.subnets[subnet] {
+		if va == virtualIP {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memoryStore) Save(subnet netip.Prefix, remoteIP, virtualIP netip.Addr) error {
+	m.Lock()
+	m.save(subnet, remoteIP, virtualIP)
+	m.Unlock()
+	return nil
+}
+
+func (m *memoryStore) save(subnet netip.Prefix, remoteIP, virtualIP netip.Addr) {
+	sm, ok := m.subnets[subnet]
+	if !ok {
+		sm = make(subnetMappings)
+		m.subnets[subnet] = sm
+	}
+	sm[remoteIP] = virtualIP
+}
+
+func (m *memoryStore) Retain(subnets ...netip.Prefix) error {
+	m.Lock()
+	m.retain(subnets)
+	m.Unlock()
+	return nil
+}
+
+// retain evicts the subnets that aren't in the given list, and returns true if any subnet was evicted.
+func (m *memoryStore) retain(subnets []netip.Prefix) bool {
+	evicted := false
+	for sn := range m.subnets {
+		if !slices.Contains(subnets, sn) {
+			delete(m.subnets, sn)
+			evicted = true
+		}
+	}
+	return evicted
+}
+
+func (m *memoryStore) Close() error {
+	return nil
+}
+
+// flushDelay is the time that a fileStore waits before it writes its file after a change, so that the
+// assignments of a burst of lookups are written at once.
+const flushDelay = time.Second
+
+type fileStore struct {
+	memoryStore
+	ctx   context.Context
+	path  string
+	timer *time.Timer
+	dirty bool
+}
+
+// NewFileStore returns a Store that is backed by the given file. Existing assignments are loaded
+// from the file. Changes are written to the file after a short delay, so that a burst of new
+// assignments results in one write.
+func NewFileStore(ctx context.Context, path string) (Store, error) {
+	fs := &fileStore{
+		memoryStore: memoryStore{subnets: make(map[netip.Prefix]subnetMappings)},
+		ctx:         ctx,
+		path:        path,
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err = json.Unmarshal(data, &fs.subnets); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON from file %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (f *fileStore) Save(subnet netip.Prefix, remoteIP, virtualIP netip.Addr) error {
+	f.Lock()
+	f.save(subnet, remoteIP, virtualIP)
+	f.scheduleFlush()
+	f.Unlock()
+	return nil
+}
+
+func (f *fileStore) Retain(subnets ...netip.Prefix) error {
+	f.Lock()
+	if f.retain(subnets) {
+		f.scheduleFlush()
+	}
+	f.Unlock()
+	return nil
+}
+
+func (f *fileStore) Close() error {
+	f.Lock()
+	defer f.Unlock()
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+	return f.write()
+}
+
+// scheduleFlush ensures that the file is written within the flushDelay. Must be called with the lock held.
+func (f *fileStore) scheduleFlush() {
+	f.dirty = true
+	if f.timer == nil {
+		f.timer = time.AfterFunc(flushDelay, f.flush)
+	}
+}
+
+func (f *fileStore) flush() {
+	f.Lock()
+	defer f.Unlock()
+	f.timer = nil
+	if err := f.write(); err != nil {
+		dlog.Errorf(f.ctx, "failed to persist virtual IPs: %v", err)
+	}
+}
+
+// write writes all assignments to the file, unless they are unchanged since the last write. Must be called
+// with the lock held.
+func (f *fileStore) write() error {
+	if !f.dirty {
+		return nil
+	}
+	data, err := json.Marshal(f.subnets, json.Deterministic(true))
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	if err = os.WriteFile(f.path, data, 0o600); err != nil {
+		return err
+	}
+	f.dirty = false
+	return nil
+}
@@ -0,0 +1,133 @@
+package vip
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+)
+
+func TestAssign_MemoryStore(t *testing.T) {
+	sn := netip.MustParsePrefix("211.55.48.0/24")
+	store := NewMemoryStore()
+	a1 := netip.MustParseAddr("10.0.0.1")
+	a2 := netip.MustParseAddr("10.0.0.2")
+
+	v1, err := Assign(NewGenerator(sn), store, a1)
+	require.NoError(t, err)
+	v2, err := Assign(NewGenerator(sn), store, a2)
+	require.NoError(t, err)
+	assert.NotEqual(t, v1, v2, "a fresh generator must not reuse an assigned virtual IP")
+
+	v, err := Assign(NewGenerator(sn), store, a1)
+	require.NoError(t, err)
+	assert.Equal(t, v1, v)
+
+	// Assignments are scoped to the virtual subnet.
+	other := netip.MustParsePrefix("246.246.0.0/16")
+	v, err = Assign(NewGenerator(other), store, a1)
+	require.NoError(t, err)
+	assert.True(t, other.Contains(v))
+}
+
+func TestAssign_FileStoreRestart(t *testing.T) {
+	sn := netip.MustParsePrefix("fd00:1234::/64")
+	path := filepath.Join(t.TempDir(), "state", "virtual-ips.json")
+	remotes := []netip.Addr{
+		netip.MustParseAddr("2001:db8::1"),
+		netip.MustParseAddr("2001:db8::2"),
+		netip.MustParseAddr("2001:db8::3"),
+	}
+
+	ctx := dlog.NewTestContext(t, false)
+	store, err := NewFileStore(ctx, path)
+	require.NoError(t, err)
+	gen := NewGenerator(sn)
+	assigned := make(map[netip.Addr]netip.Addr)
+	for _, ra := range remotes[:2] {
+		va, err := Assign(gen, store, ra)
+		require.NoError(t, err)
+		assigned[ra] = va
+	}
+
+	// The assignments are written when the store is closed, or after a short delay.
+	require.NoError(t, store.Close())
+
+	// Simulate a daemon restart, where both the store and the generator are recreated, and
+	// where the remote IPs are requested in a different order.
+	store, err = NewFileStore(ctx, path)
+	require.NoError(t, err)
+	gen = NewGenerator(sn)
+	v3, err := Assign(gen, store, remotes[2])
+	require.NoError(t, err)
+	for _, ra := range remotes[:2] {
+		va, err := Assign(gen, store, ra)
+		require.NoError(t, err)
+		assert.Equal(t, assigned[ra], va)
+		assert.NotEqual(t, v3, va)
+	}
+}
+
+func TestFileStore_batchedWrites(t *testing.T) {
+	sn := netip.MustParsePrefix("211.55.48.0/24")
+	path := filepath.Join(t.TempDir(), "virtual-ips.json")
+	store, err := NewFileStore(dlog.NewTestContext(t, false), path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	gen := NewGenerator(sn)
+	for _, ra := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		_, err = Assign(gen, store, netip.MustParseAddr(ra))
+		require.NoError(t, err)
+	}
+	// Nothing is written until the flush delay has passed, and then everything is written at once.
+	assert.NoFileExists(t, path)
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, 5*flushDelay, flushDelay/10)
+	reloaded, err := NewFileStore(dlog.NewTestContext(t, false), path)
+	require.NoError(t, err)
+	_, ok := reloaded.Lookup(sn, netip.MustParseAddr("10.0.0.3"))
+	assert.True(t, ok)
+}
+
+func TestStore_Retain(t *testing.T) {
+	sn1 := netip.MustParsePrefix("211.55.48.0/24")
+	sn2 := netip.MustParsePrefix("fd00:1234::/64")
+	a1 := netip.MustParseAddr("10.0.0.1")
+	a2 := netip.MustParseAddr("2001:db8::1")
+	path := filepath.Join(t.TempDir(), "virtual-ips.json")
+	fs, err := NewFileStore(dlog.NewTestContext(t, false), path)
+	require.NoError(t, err)
+
+	for name, store := range map[string]Store{"memory": NewMemoryStore(), "file": fs} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Assign(NewGenerator(sn1), store, a1)
+			require.NoError(t, err)
+			_, err = Assign(NewGenerator(sn2), store, a2)
+			require.NoError(t, err)
+
+			// A session that only uses the first subnet evicts the second.
+			require.NoError(t, store.Retain(sn1))
+			_, ok := store.Lookup(sn1, a1)
+			assert.True(t, ok)
+			_, ok = store.Lookup(sn2, a2)
+			assert.False(t, ok)
+			require.NoError(t, store.Close())
+		})
+	}
+
+	// The eviction is persisted.
+	reloaded, err := NewFileStore(dlog.NewTestContext(t, false), path)
+	require.NoError(t, err)
+	_, ok := reloaded.Lookup(sn1, a1)
+	assert.True(t, ok)
+	_, ok = reloaded.Lookup(sn2, a2)
+	assert.False(t, ok)
+}
@@ -13,6 +13,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 
@@ -138,23 +139,57 @@ func (s *service) Disconnect(ctx context.Context, ex *empty.Empty) (*empty.Empty
 	return &empty.Empty{}, nil
 }
 
-func (s *service) Status(ctx context.Context, ex *empty.Empty) (result *rpc.ConnectInfo, err error) {
+func (s *service) Status(ctx context.Context, _ *empty.Empty) (result *rpc.ConnectInfo, err error) {
 	s.LogCall(ctx, "Status", func(c context.Context) {
-		s.sessionLock.RLock()
-		defer s.sessionLock.RUnlock()
-		if s.session == nil {
-			result = &rpc.ConnectInfo{Error: rpc.ConnectInfo_DISCONNECTED}
-			_ = s.withRootDaemon(c, func(c context.Context, dc daemon.DaemonClient) error {
-				result.DaemonStatus, err = dc.Status(c, ex)
-				return nil
-			})
-		} else {
-			result = s.session.Status(s.sessionContext)
-		}
+		result, err = s.status(c)
 	})
 	return
 }
 
+func (s *service) WatchStatus(_ *empty.Empty, stream rpc.Connector_WatchStatusServer) error {
+	ctx := s.callCtx(stream.Context(), "WatchStatus")
+	dlog.Debug(ctx, "called")
+	defer dlog.Debug(ctx, "returned")
+
+	// Subscribe before the first status is sent so that no change is lost.
+	changed, unsubscribe := s.subscribeStatus()
+	defer unsubscribe()
+
+	var last *rpc.ConnectInfo
+	for {
+		ci, err := s.status(ctx)
+		if err != nil {
+			return err
+		}
+		if !proto.Equal(last, ci) {
+			if err = stream.Send(ci); err != nil {
+				return err
+			}
+			last = ci
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+func (s *service) status(c context.Context) (result *rpc.ConnectInfo, err error) {
+	s.sessionLock.RLock()
+	defer s.sessionLock.RUnlock()
+	if s.session == nil {
+		result = &rpc.ConnectInfo{Error: rpc.ConnectInfo_DISCONNECTED}
+		_ = s.withRootDaemon(c, func(c context.Context, dc daemon.DaemonClient) error {
+			result.DaemonStatus, err = dc.Status(c, &empty.Empty{})
+			return nil
+		})
+	} else {
+		result = s.session.Status(s.sessionContext)
+	}
+	return result, err
+}
+
 // isMultiPortIntercept checks if the intercept is one of several active intercepts on the same workload.
 // If it is, then the first returned value will be true and the second will indicate if those intercepts are
 // on different services. Otherwise, this function returns false, false.
@@ -324,6 +359,9 @@ func (s *service) RemoveInterceptor(ctx context.Context, interceptor *rpc.Interc
 func (s *service) List(c context.Context, lr *rpc.ListRequest) (result *rpc.WorkloadInfoSnapshot, err error) {
 	err = s.WithSession(c, "List", func(c context.Context, session userd.Session) error {
 		result, err = session.WorkloadInfoSnapshot(c, []string{lr.Namespace}, lr.Filter)
+		if err == nil && lr.LabelSelector != "" {
+			result, err = session.SelectWorkloads(c, result, lr.LabelSelector)
+		}
 		return err
 	})
 	return
@@ -363,6 +401,20 @@ func (s *service) WatchWorkloads(wr *rpc.WatchWorkloadsRequest, stream rpc.Conne
 	return session.WatchWorkloads(sessionCtx, wr, stream)
 }
 
+func (s *service) WatchInterceptTraffic(tr *rpc.InterceptTrafficRequest, stream rpc.Connector_WatchInterceptTrafficServer) error {
+	var sessionCtx context.Context
+	var session userd.Session
+
+	err := s.WithSession(stream.Context(), "WatchInterceptTraffic", func(c context.Context, s userd.Session) error {
+		session, sessionCtx = s, c
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return session.WatchInterceptTraffic(sessionCtx, tr, stream)
+}
+
 func (s *service) Uninstall(c context.Context, ur *rpc.UninstallRequest) (result *common.Result, err error) {
 	err = s.WithSession(c, "Uninstall", func(c context.Context, session userd.Session) error {
 		result, err = session.Uninstall(c, ur)
@@ -600,6 +652,57 @@ func (s *service) SetDNSMappings(ctx context.Context, req *daemon.SetDNSMappings
 	return &empty.Empty{}, err
 }
 
+func (s *service) GetVirtualIPs(ctx context.Context, _ *emptypb.Empty) (result *daemon.VirtualIPs, err error) {
+	err = s.WithSession(ctx, "GetVirtualIPs", func(ctx context.Context, session userd.Session) error {
+		result, err = session.RootDaemon().GetVirtualIPs(ctx, &emptypb.Empty{})
+		return err
+	})
+	return result, err
+}
+
+func (s *service) GetRouteConflicts(ctx context.Context, _ *emptypb.Empty) (result *daemon.RouteConflicts, err error) {
+	err = s.WithSession(ctx, "GetRouteConflicts", func(ctx context.Context, session userd.Session) error {
+		result, err = session.RootDaemon().GetRouteConflicts(ctx, &emptypb.Empty{})
+		return err
+	})
+	return result, err
+}
+
+func (s *service) UpdateRouting(ctx context.Context, req *daemon.UpdateRoutingRequest) (*emptypb.Empty, error) {
+	err := s.WithSession(ctx, "UpdateRouting", func(ctx context.Context, session userd.Session) error {
+		_, err := session.RootDaemon().UpdateRouting(ctx, req)
+		return err
+	})
+	if err == nil {
+		s.self.NotifyStatusChanged()
+	}
+	return &empty.Empty{}, err
+}
+
+func (s *service) GetDNSSearchPaths(ctx context.Context, _ *emptypb.Empty) (result *daemon.DNSSearchPaths, err error) {
+	err = s.WithSession(ctx, "GetDNSSearchPaths", func(ctx context.Context, session userd.Session) error {
+		result, err = session.RootDaemon().GetDNSSearchPaths(ctx, &emptypb.Empty{})
+		return err
+	})
+	return result, err
+}
+
+func (s *service) GetInterceptHolders(ctx context.Context, request *rpc.InterceptHoldersRequest) (result *rpc.InterceptHolders, err error) {
+	err = s.WithSession(ctx, "GetInterceptHolders", func(ctx context.Context, session userd.Session) error {
+		result, err = session.GetInterceptHolders(ctx, request)
+		return err
+	})
+	return result, err
+}
+
+func (s *service) ListIngests(ctx context.Context, _ *emptypb.Empty) (response *rpc.IngestInfoSnapshot, err error) {
+	err = s.WithSession(ctx, "ListIngests", func(ctx context.Context, session userd.Session) error {
+		response = session.ListIngests()
+		return nil
+	})
+	return response, err
+}
+
 func (s *service) Ingest(ctx context.Context, request *rpc.IngestRequest) (response *rpc.IngestInfo, err error) {
 	err = s.WithSession(ctx, "Ingest", func(ctx context.Context, session userd.Session) error {
 		response, err = session.Ingest(ctx, request)
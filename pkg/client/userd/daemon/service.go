@@ -12,6 +12,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -74,6 +75,10 @@ type service struct {
 	sessionQuitting int32 // atomic boolean. True if non-zero.
 	sessionLock     sync.RWMutex
 
+	// statusSubscribers are notified when the connection status might have changed.
+	statusLock        sync.Mutex
+	statusSubscribers map[uuid.UUID]chan struct{}
+
 	// These are used to communicate between the various goroutines.
 	connectRequest  chan userd.ConnectRequest // server-grpc.connect() -> connectWorker
 	connectResponse chan *rpc.ConnectInfo     // connectWorker -> server-grpc.connect()
@@ -137,6 +142,37 @@ func (s *service) FuseFTPMgr() remotefs.FuseFTPManager {
 	return s.fuseFtpMgr
 }
 
+// NotifyStatusChanged tells the WatchStatus streams that the connection status might have changed.
+func (s *service) NotifyStatusChanged() {
+	s.statusLock.Lock()
+	for _, ch := range s.statusSubscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+	s.statusLock.Unlock()
+}
+
+// subscribeStatus returns a channel that receives a notification each time NotifyStatusChanged is
+// called, and a function that ends the subscription.
+func (s *service) subscribeStatus() (<-chan struct{}, func()) {
+	id := uuid.New()
+	// Buffered so that a notification that arrives while a status is sent isn't lost.
+	ch := make(chan struct{}, 1)
+	s.statusLock.Lock()
+	if s.statusSubscribers == nil {
+		s.statusSubscribers = make(map[uuid.UUID]chan struct{})
+	}
+	s.statusSubscribers[id] = ch
+	s.statusLock.Unlock()
+	return ch, func() {
+		s.statusLock.Lock()
+		delete(s.statusSubscribers, id)
+		s.statusLock.Unlock()
+	}
+}
+
 func (s *service) RootSessionInProcess() bool {
 	return s.rootSessionInProc
 }
@@ -291,6 +327,7 @@ func (s *service) startSession(parentCtx context.Context, cr userd.ConnectReques
 		cancel()
 		<-session.Done()
 	}
+	s.self.NotifyStatusChanged()
 
 	// Run the session asynchronously. We must be able to respond to connect (with UpdateStatus) while
 	// the session is running. The s.sessionCancel is called from Disconnect
@@ -302,6 +339,7 @@ func (s *service) startSession(parentCtx context.Context, cr userd.ConnectReques
 			s.session = nil
 			s.sessionCancel = nil
 			s.sessionLock.Unlock()
+			s.self.NotifyStatusChanged()
 			_ = client.ReloadDaemonLogLevel(parentCtx, false)
 			wg.Done()
 		}()
@@ -316,7 +354,6 @@ func (s *service) startSession(parentCtx context.Context, cr userd.ConnectReques
 				}
 				return
 			}
-
 			dlog.Error(ctx, err)
 		}
 		if s.rootSessionInProc {
@@ -374,6 +411,7 @@ func (s *service) cancelSession() {
 	s.sessionCancel = nil
 	atomic.StoreInt32(&s.sessionQuitting, 0)
 	s.sessionLock.Unlock()
+	s.self.NotifyStatusChanged()
 }
 
 // run is the main function when executing as the connector.
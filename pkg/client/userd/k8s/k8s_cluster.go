@@ -221,12 +221,14 @@ func ConnectCluster(c context.Context, cr *rpc.ConnectRequest, config *client.Ku
 	if err != nil {
 		return c, nil, fmt.Errorf("failed to parse extra allow conflicting subnets: %w", err)
 	}
-	if len(extraAlsoProxy)+len(extraNeverProxy)+len(extraAllow) > 0 {
+	extraNeverProxyHosts := cr.GetNeverProxyHosts()
+	if len(extraAlsoProxy)+len(extraNeverProxy)+len(extraNeverProxyHosts)+len(extraAllow) > 0 {
 		cfg := client.GetConfig(c).Merge(client.GetDefaultConfig())
 		rt := cfg.Routing()
 		rt.AllowConflicting = append(rt.AllowConflicting, extraAllow...)
 		rt.AlsoProxy = append(rt.AlsoProxy, extraAlsoProxy...)
 		rt.NeverProxy = append(rt.NeverProxy, extraNeverProxy...)
+		rt.NeverProxyHosts = append(rt.NeverProxyHosts, extraNeverProxyHosts...)
 		c = client.WithConfig(c, cfg)
 	}
 
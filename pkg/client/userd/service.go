@@ -35,6 +35,9 @@ type Service interface {
 	// FuseFTPMgr returns the manager responsible for creating a client that can connect to the FuseFTP service.
 	FuseFTPMgr() remotefs.FuseFTPManager
 
+	// NotifyStatusChanged tells the clients that watch the connection status that it might have changed.
+	NotifyStatusChanged()
+
 	RootSessionInProcess() bool
 	WithSession(context.Context, string, func(context.Context, Session) error) error
 
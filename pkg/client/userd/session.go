@@ -29,6 +29,11 @@ type WatchWorkloadsStream interface {
 	Context() context.Context
 }
 
+type WatchInterceptTrafficStream interface {
+	Send(*rpc.InterceptTrafficEntry) error
+	Context() context.Context
+}
+
 type InterceptInfo interface {
 	InterceptResult() *rpc.InterceptResult
 	PreparedIntercept() *manager.PreparedIntercept
@@ -74,6 +79,9 @@ type Session interface {
 
 	WatchWorkloads(context.Context, *rpc.WatchWorkloadsRequest, WatchWorkloadsStream) error
 	WorkloadInfoSnapshot(context.Context, []string, rpc.ListRequest_Filter) (*rpc.WorkloadInfoSnapshot, error)
+	SelectWorkloads(context.Context, *rpc.WorkloadInfoSnapshot, string) (*rpc.WorkloadInfoSnapshot, error)
+	WatchInterceptTraffic(context.Context, *rpc.InterceptTrafficRequest, WatchInterceptTrafficStream) error
+	GetInterceptHolders(context.Context, *rpc.InterceptHoldersRequest) (*rpc.InterceptHolders, error)
 
 	GetCurrentNamespaces(forClientAccess bool) []string
 	ActualNamespace(string) string
@@ -96,6 +104,7 @@ type Session interface {
 	Done() <-chan struct{}
 	Ingest(context.Context, *rpc.IngestRequest) (*rpc.IngestInfo, error)
 	GetIngest(*rpc.IngestIdentifier) (*rpc.IngestInfo, error)
+	ListIngests() *rpc.IngestInfoSnapshot
 	LeaveIngest(context.Context, *rpc.IngestIdentifier) (*rpc.IngestInfo, error)
 }
 
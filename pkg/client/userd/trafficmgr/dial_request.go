@@ -16,5 +16,6 @@ func (s *session) _dialRequestWatcher(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	ctx = tunnel.WithDialObserver(ctx, s.observeDial)
 	return tunnel.DialWaitLoop(ctx, tunnel.ManagerProvider(s.managerClient), dialerStream, s.sessionInfo.SessionId)
 }
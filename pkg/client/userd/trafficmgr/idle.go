@@ -0,0 +1,104 @@
+package trafficmgr
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/datawire/dlib/dlog"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/userd"
+)
+
+// touch records that the session is active.
+func (s *session) touch() {
+	s.touchAt(time.Now())
+}
+
+// touchAt records that the session was active at the given time, unless a later activity has been recorded.
+func (s *session) touchAt(t time.Time) {
+	tn := t.UnixNano()
+	for {
+		last := s.lastActivity.Load()
+		if tn <= last || s.lastActivity.CompareAndSwap(last, tn) {
+			return
+		}
+	}
+}
+
+// busy returns true when the session has intercepts or ingests, or is waiting for an intercept to arrive.
+func (s *session) busy() bool {
+	s.currentInterceptsLock.Lock()
+	busy := len(s.currentIntercepts) > 0 || len(s.interceptWaiters) > 0
+	s.currentInterceptsLock.Unlock()
+	return busy || s.currentIngests.Size() > 0
+}
+
+// lastTrafficActivity returns the last time that traffic flowed through the tunnels of the root daemon, or the
+// zero time when that is unknown.
+func (s *session) lastTrafficActivity(ctx context.Context) time.Time {
+	ta, err := s.rootDaemon.GetTrafficActivity(ctx, &empty.Empty{})
+	if err != nil {
+		if status.Code(err) != codes.Unimplemented {
+			dlog.Debugf(ctx, "unable to get traffic activity from root daemon: %v", err)
+		}
+		return time.Time{}
+	}
+	if ta.LastActivity == nil {
+		return time.Time{}
+	}
+	return ta.LastActivity.AsTime()
+}
+
+// idleLoop quits the user daemon, using the same path as "telepresence quit", when the session has been idle for
+// longer than the sessionIdle timeout. The loop returns immediately when the timeout is zero.
+func (s *session) idleLoop(ctx context.Context) error {
+	timeout := client.GetConfig(ctx).Timeouts().Get(client.TimeoutSessionIdle)
+	if timeout <= 0 {
+		return nil
+	}
+	if idle, ok := s.waitUntilIdle(ctx, timeout, s.lastTrafficActivity); ok {
+		dlog.Infof(ctx, "Quitting session that has been idle for %s", idle.Truncate(time.Second))
+		var cs rpc.ConnectorServer
+		userd.GetService(ctx).As(&cs)
+		// Quit waits for the session to end, and this loop is part of the session, so it must be called
+		// asynchronously.
+		go func() {
+			_, _ = cs.Quit(context.WithoutCancel(ctx), &empty.Empty{})
+		}()
+	}
+	return nil
+}
+
+// waitUntilIdle returns the idle time and true when the session has been idle for longer than the given timeout, or
+// false when the context is cancelled before that happens. A session is idle when it has no intercepts or ingests.
+// It is considered active while it is busy, when intercepts or ingests are created, when connections are dialed on
+// behalf of an intercept, when Remain is called, and when traffic flows through the tunnels to the cluster.
+func (s *session) waitUntilIdle(ctx context.Context, timeout time.Duration, lastTraffic func(context.Context) time.Time) (time.Duration, bool) {
+	s.touch()
+	ticker := time.NewTicker(timeout / 10)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, false
+		case <-ticker.C:
+			if s.busy() {
+				s.touch()
+				continue
+			}
+			if time.Since(time.Unix(0, s.lastActivity.Load())) < timeout {
+				continue
+			}
+			// Only ask for the traffic when the session would otherwise be idle.
+			s.touchAt(lastTraffic(ctx))
+			if idle := time.Since(time.Unix(0, s.lastActivity.Load())); idle >= timeout {
+				return idle, true
+			}
+		}
+	}
+}
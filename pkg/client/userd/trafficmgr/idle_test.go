@@ -0,0 +1,100 @@
+package trafficmgr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+)
+
+func Test_idleLoop(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	cfg := client.GetDefaultConfig()
+	cfg.Timeouts().PrivateSessionIdle = 0
+	s := newMountTestSession()
+	assert.NoError(t, s.idleLoop(client.WithConfig(ctx, cfg)))
+}
+
+func Test_waitUntilIdle(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	noTraffic := func(context.Context) time.Time { return time.Time{} }
+
+	type result struct {
+		idle time.Duration
+		ok   bool
+	}
+	runWait := func(ctx context.Context, s *session, lastTraffic func(context.Context) time.Time) <-chan result {
+		ch := make(chan result, 1)
+		go func() {
+			idle, ok := s.waitUntilIdle(ctx, timeout, lastTraffic)
+			ch <- result{idle, ok}
+		}()
+		return ch
+	}
+
+	t.Run("ends idle session", func(t *testing.T) {
+		s := newMountTestSession()
+		start := time.Now()
+		select {
+		case r := <-runWait(dlog.NewTestContext(t, false), s, noTraffic):
+			assert.True(t, r.ok)
+			assert.GreaterOrEqual(t, r.idle, timeout)
+			assert.GreaterOrEqual(t, time.Since(start), timeout)
+		case <-time.After(2 * time.Second):
+			t.Fatal("idle session was not ended")
+		}
+	})
+
+	t.Run("keeps session with intercept", func(t *testing.T) {
+		s := newMountTestSession()
+		s.addTestIntercept("echo", "", 0)
+		ctx, cancel := context.WithTimeout(dlog.NewTestContext(t, false), 5*timeout)
+		defer cancel()
+		assert.False(t, (<-runWait(ctx, s, noTraffic)).ok)
+	})
+
+	t.Run("keeps session with traffic", func(t *testing.T) {
+		s := newMountTestSession()
+		ctx, cancel := context.WithTimeout(dlog.NewTestContext(t, false), 5*timeout)
+		defer cancel()
+		assert.False(t, (<-runWait(ctx, s, func(context.Context) time.Time { return time.Now() })).ok)
+	})
+
+	t.Run("ends session once traffic stops", func(t *testing.T) {
+		s := newMountTestSession()
+		// The traffic ends after twice the timeout.
+		trafficEnd := time.Now().Add(2 * timeout)
+		lastTraffic := func(context.Context) time.Time {
+			if now := time.Now(); now.Before(trafficEnd) {
+				return now
+			}
+			return trafficEnd
+		}
+		select {
+		case r := <-runWait(dlog.NewTestContext(t, false), s, lastTraffic):
+			assert.True(t, r.ok)
+		case <-time.After(2 * time.Second):
+			t.Fatal("idle session was not ended")
+		}
+	})
+
+	t.Run("ends session once the intercept is gone", func(t *testing.T) {
+		s := newMountTestSession()
+		s.addTestIntercept("echo", "", 0)
+		ch := runWait(dlog.NewTestContext(t, false), s, noTraffic)
+		time.Sleep(3 * timeout)
+		s.currentInterceptsLock.Lock()
+		delete(s.currentIntercepts, "echo")
+		s.currentInterceptsLock.Unlock()
+		select {
+		case r := <-ch:
+			assert.True(t, r.ok)
+		case <-time.After(2 * time.Second):
+			t.Fatal("idle session was not ended")
+		}
+	})
+}
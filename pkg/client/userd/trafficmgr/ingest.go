@@ -1,8 +1,12 @@
 package trafficmgr
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -11,7 +15,9 @@ import (
 	rpc "github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/rpc/v2/daemon"
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/remotefs"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/userd"
 )
 
 type ingestKey struct {
@@ -26,31 +32,37 @@ func (ik ingestKey) String() string {
 type ingest struct {
 	*manager.AgentInfo
 	ingestKey
-	ctx              context.Context
-	cancel           context.CancelFunc
-	localMountPoint  string
-	localMountPort   int32
-	localPorts       []string
-	handlerContainer string
-	pid              int
-	mounter          remotefs.Mounter
+	ctx               context.Context
+	cancel            context.CancelFunc
+	localMountPoint   string
+	localMountPort    int32
+	localMountAddress string
+	localPorts        []string
+	readOnly          bool
+	handlerContainer  string
+	pid               int
+	mounter           atomic.Pointer[remotefs.Mounter]
+
+	// started is set once the mounts and port-forwards of this ingest have been started.
+	started atomic.Bool
 }
 
 func (ig *ingest) podAccess(rd daemon.DaemonClient) *podAccess {
 	ni := ig.Containers[ig.container]
 	pa := &podAccess{
-		ctx:              ig.ctx,
-		localPorts:       ig.localPorts,
-		workload:         ig.workload,
-		container:        ig.container,
-		podIP:            ig.PodIp,
-		sftpPort:         ig.SftpPort,
-		ftpPort:          ig.FtpPort,
-		mountPoint:       ni.MountPoint,
-		clientMountPoint: ig.localMountPoint,
-		localMountPort:   ig.localMountPort,
-		mounter:          &ig.mounter,
-		readOnly:         true,
+		ctx:               ig.ctx,
+		localPorts:        ig.localPorts,
+		workload:          ig.workload,
+		container:         ig.container,
+		podIP:             ig.PodIp,
+		sftpPort:          ig.SftpPort,
+		ftpPort:           ig.FtpPort,
+		mountPoint:        ni.MountPoint,
+		clientMountPoint:  ig.localMountPoint,
+		localMountPort:    ig.localMountPort,
+		localMountAddress: ig.localMountAddress,
+		mounter:           &ig.mounter,
+		readOnly:          ig.readOnly,
 	}
 	if err := pa.ensureAccess(ig.ctx, rd); err != nil {
 		dlog.Error(ig.ctx, err)
@@ -65,12 +77,15 @@ func (ig *ingest) response() *rpc.IngestInfo {
 		WorkloadKind:     ig.Kind,
 		Container:        ig.container,
 		PodIp:            ig.PodIp,
+		PodName:          ig.PodName,
 		SftpPort:         ig.SftpPort,
 		FtpPort:          ig.FtpPort,
 		MountPoint:       cn.MountPoint,
 		ClientMountPoint: ig.localMountPoint,
 		Environment:      cn.Environment,
+		MountWritable:    !ig.readOnly,
 	}
+	ii.MountStatus = mountStatus(&ig.mounter)
 	if ig.handlerContainer != "" {
 		if ii.Environment == nil {
 			ii.Environment = make(map[string]string, 1)
@@ -92,6 +107,19 @@ func (s *session) getSingleContainerName(ai *manager.AgentInfo) (name string, er
 	return name, err
 }
 
+// resolveContainerName returns the name of the container in the given agent that matches the given name, which
+// may be a glob.
+func resolveContainerName(ai *manager.AgentInfo, name string) (string, error) {
+	cn, err := agentconfig.MatchContainerName(name, slices.Collect(maps.Keys(ai.Containers)))
+	if err != nil {
+		return "", err
+	}
+	if cn == "" {
+		return "", fmt.Errorf("workload %s has no container named %s", ai.Name, name)
+	}
+	return cn, nil
+}
+
 func (s *session) validateAgentForIngest(ai *manager.AgentInfo) error {
 	if len(ai.Containers) == 0 {
 		return status.Error(codes.Unimplemented, fmt.Sprintf("traffic-manager %s have no support for ingest", s.managerVersion))
@@ -99,9 +127,15 @@ func (s *session) validateAgentForIngest(ai *manager.AgentInfo) error {
 	return nil
 }
 
-func (s *session) getCurrentAgent(name string) *manager.AgentInfo {
-	for _, ai := range s.getCurrentAgents() {
-		if ai.Name == name {
+func (s *session) getCurrentAgent(name, podName string) *manager.AgentInfo {
+	return findAgent(s.getCurrentAgents(), name, podName)
+}
+
+// findAgent returns the first agent of the named workload, or the agent of the given pod of that
+// workload when podName isn't empty.
+func findAgent(ais []*manager.AgentInfo, name, podName string) *manager.AgentInfo {
+	for _, ai := range ais {
+		if ai.Name == name && (podName == "" || ai.PodName == podName) {
 			return ai
 		}
 	}
@@ -109,21 +143,27 @@ func (s *session) getCurrentAgent(name string) *manager.AgentInfo {
 }
 
 func (s *session) Ingest(ctx context.Context, rq *rpc.IngestRequest) (ir *rpc.IngestInfo, err error) {
+	s.touch()
 	id := rq.Identifier
 	ik := ingestKey{
 		workload:  id.WorkloadName,
 		container: id.ContainerName,
 	}
-	ai := s.getCurrentAgent(ik.workload)
+	ai := s.getCurrentAgent(ik.workload, id.PodName)
 
 	if ai != nil {
 		if ik.container == "" {
 			ik.container, err = s.getSingleContainerName(ai)
-			if err != nil {
-				return nil, err
-			}
+		} else {
+			ik.container, err = resolveContainerName(ai, ik.container)
+		}
+		if err != nil {
+			return nil, err
 		}
 		if ig, loaded := s.currentIngests.Load(ik); loaded {
+			if id.PodName != "" && ig.PodName != id.PodName {
+				return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("%s is already ingested from pod %s", ik, ig.PodName))
+			}
 			return ig.response(), nil
 		}
 	}
@@ -133,13 +173,21 @@ func (s *session) Ingest(ctx context.Context, rq *rpc.IngestRequest) (ir *rpc.In
 		return nil, err
 	}
 
+	if err = checkWorkloadAccess(ctx, "ingest", s.Namespace); err != nil {
+		return nil, err
+	}
+
 	if ai == nil {
 		var as *manager.AgentInfoSnapshot
 		as, err = s.managerClient.EnsureAgent(ctx, &manager.EnsureAgentRequest{Session: s.sessionInfo, Name: ik.workload})
 		if err != nil {
 			return nil, err
 		}
-		ai = as.Agents[0]
+		if id.PodName == "" {
+			ai = as.Agents[0]
+		} else if ai = findAgent(as.Agents, ik.workload, id.PodName); ai == nil {
+			return nil, status.Error(codes.NotFound, fmt.Sprintf("workload %s has no pod named %s with a traffic-agent", ik.workload, id.PodName))
+		}
 	}
 	if err = s.validateAgentForIngest(ai); err != nil {
 		return nil, err
@@ -150,8 +198,8 @@ func (s *session) Ingest(ctx context.Context, rq *rpc.IngestRequest) (ir *rpc.In
 		if err != nil {
 			return nil, err
 		}
-	} else if _, ok := ai.Containers[ik.container]; !ok {
-		return nil, fmt.Errorf("workload %s has no container named %s", ik.workload, ik.container)
+	} else if ik.container, err = resolveContainerName(ai, ik.container); err != nil {
+		return nil, err
 	}
 
 	err = s.translateContainerEnv(ctx, ai, ik.container)
@@ -166,23 +214,38 @@ func (s *session) Ingest(ctx context.Context, rq *rpc.IngestRequest) (ir *rpc.In
 			dlog.Debugf(ctx, "Cancelling ingest %s", ik)
 			cancel()
 			s.ingestTracker.cancelContainer(ik.workload, ik.container)
+			userd.GetService(ctx).NotifyStatusChanged()
 		}
 		return &ingest{
-			ingestKey:       ik,
-			AgentInfo:       ai,
-			ctx:             ctx,
-			cancel:          cancelIngest,
-			localMountPoint: rq.MountPoint,
-			localMountPort:  rq.LocalMountPort,
-			localPorts:      rq.LocalPorts,
+			ingestKey:         ik,
+			AgentInfo:         ai,
+			ctx:               ctx,
+			cancel:            cancelIngest,
+			localMountPoint:   rq.MountPoint,
+			localMountPort:    rq.LocalMountPort,
+			localMountAddress: rq.LocalMountAddress,
+			localPorts:        rq.LocalPorts,
+			readOnly:          !rq.MountWritable,
 		}
 	})
 	if !loaded {
-		s.ingestTracker.initialStart(ig.podAccess(s.rootDaemon))
+		userd.GetService(ctx).NotifyStatusChanged()
 	}
+	s.startIngest(ig)
 	return ig.response(), nil
 }
 
+// startIngest starts the mounts and port-forwards of the given ingest, unless they have already been started.
+// A repeated ingest of the same container must not start the mounter of the existing ingest again, because
+// that would establish a new mount. Returns true if the ingest was started by this call.
+func (s *session) startIngest(ig *ingest) bool {
+	if !ig.started.CompareAndSwap(false, true) {
+		return false
+	}
+	s.ingestTracker.initialStart(ig.podAccess(s.rootDaemon))
+	return true
+}
+
 func (s *session) translateContainerEnv(ctx context.Context, ai *manager.AgentInfo, container string) error {
 	cn, ok := ai.Containers[container]
 	if !ok {
@@ -243,6 +306,17 @@ func (s *session) GetIngest(rq *rpc.IngestIdentifier) (ii *rpc.IngestInfo, err e
 	return ig.response(), nil
 }
 
+func (s *session) ListIngests() *rpc.IngestInfoSnapshot {
+	ingests := s.getCurrentIngests()
+	slices.SortFunc(ingests, func(a, b *rpc.IngestInfo) int {
+		if c := cmp.Compare(a.Workload, b.Workload); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Container, b.Container)
+	})
+	return &rpc.IngestInfoSnapshot{Ingests: ingests}
+}
+
 func (s *session) LeaveIngest(c context.Context, rq *rpc.IngestIdentifier) (ii *rpc.IngestInfo, err error) {
 	ig, err := s.getIngest(rq)
 	if err != nil {
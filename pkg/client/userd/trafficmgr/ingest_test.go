@@ -0,0 +1,76 @@
+package trafficmgr
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/remotefs"
+)
+
+type countingMounter struct {
+	starts atomic.Int32
+}
+
+func (m *countingMounter) Start(context.Context, string, string, string, string, net.IP, uint16, bool) error {
+	m.starts.Add(1)
+	return nil
+}
+
+func (m *countingMounter) Status() remotefs.Status {
+	return remotefs.Status{}
+}
+
+func Test_startIngest(t *testing.T) {
+	cfg := client.GetDefaultConfig()
+	cfg.Cluster().AgentPortForward = false
+	ctx, cancel := context.WithCancel(client.WithConfig(dlog.NewTestContext(t, false), cfg))
+	defer cancel()
+
+	s := newMountTestSession()
+	s.ingestTracker = newPodAccessTracker()
+	ik := ingestKey{workload: "echo", container: "echo"}
+	ig := &ingest{
+		AgentInfo: &manager.AgentInfo{
+			Name:       "echo",
+			PodIp:      "10.1.2.3",
+			SftpPort:   8022,
+			Containers: map[string]*manager.AgentInfo_ContainerInfo{"echo": {MountPoint: "/tel_app_mounts/echo"}},
+		},
+		ingestKey:       ik,
+		ctx:             ctx,
+		localMountPoint: "/tmp/echo",
+	}
+
+	// The mounter is normally created by the first start. It's stored up front here so that its starts can be counted.
+	m := &countingMounter{}
+	var rm remotefs.Mounter = m
+	ig.mounter.Store(&rm)
+
+	var started atomic.Int32
+	wg := sync.WaitGroup{}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.startIngest(ig) {
+				started.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), started.Load())
+	assert.Equal(t, int32(1), m.starts.Load())
+
+	// Starting it again after the pod access has been cancelled doesn't start the mounter either.
+	s.ingestTracker.cancelContainer(ik.workload, ik.container)
+	assert.False(t, s.startIngest(ig))
+	assert.Equal(t, int32(1), m.starts.Load())
+}
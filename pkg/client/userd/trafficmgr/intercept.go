@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	grpcCodes "google.golang.org/grpc/codes"
@@ -30,6 +31,7 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/matcher"
 	"github.com/telepresenceio/telepresence/v2/pkg/proc"
 	"github.com/telepresenceio/telepresence/v2/pkg/restapi"
+	"github.com/telepresenceio/telepresence/v2/pkg/workload"
 )
 
 // intercept tracks the life-cycle of an intercept, dictated by the intercepts
@@ -60,11 +62,14 @@ type intercept struct {
 	handlerContainer string
 
 	// The mounter of the remote file system.
-	remotefs.Mounter
+	mounter atomic.Pointer[remotefs.Mounter]
 
 	// Use bridged ftp/sftp mount through this local port
 	localMountPort int32
 
+	// The address that the localMountPort is bound to. All interfaces are used when empty.
+	localMountAddress string
+
 	// Mount read-only
 	readOnly bool
 }
@@ -88,6 +93,9 @@ type awaitIntercept struct {
 	// the mount to take place in a host
 	mountPort int32
 
+	// mountAddress is the address that the mountPort is bound to
+	mountAddress string
+
 	readOnly bool
 	waitCh   chan<- interceptResult
 }
@@ -106,18 +114,19 @@ func (ic *intercept) localPorts() []string {
 
 func (ic *intercept) podAccess(rd daemon.DaemonClient) *podAccess {
 	pa := &podAccess{
-		ctx:              ic.ctx,
-		localPorts:       ic.localPorts(),
-		workload:         ic.Spec.Agent,
-		podIP:            ic.PodIp,
-		container:        ic.Spec.ContainerName,
-		sftpPort:         ic.SftpPort,
-		ftpPort:          ic.FtpPort,
-		mountPoint:       ic.MountPoint,
-		clientMountPoint: ic.ClientMountPoint,
-		localMountPort:   ic.localMountPort,
-		readOnly:         ic.readOnly,
-		mounter:          &ic.Mounter,
+		ctx:               ic.ctx,
+		localPorts:        ic.localPorts(),
+		workload:          ic.Spec.Agent,
+		podIP:             ic.PodIp,
+		container:         ic.Spec.ContainerName,
+		sftpPort:          ic.SftpPort,
+		ftpPort:           ic.FtpPort,
+		mountPoint:        ic.MountPoint,
+		clientMountPoint:  ic.ClientMountPoint,
+		localMountPort:    ic.localMountPort,
+		localMountAddress: ic.localMountAddress,
+		readOnly:          ic.readOnly,
+		mounter:           &ic.mounter,
 	}
 	if err := pa.ensureAccess(ic.ctx, rd); err != nil {
 		dlog.Error(ic.ctx, err)
@@ -260,6 +269,7 @@ func (s *session) setCurrentIntercepts(ctx context.Context, iis []*manager.Inter
 			if aw, ok := s.interceptWaiters[ii.Spec.Name]; ok {
 				ic.ClientMountPoint = aw.mountPoint
 				ic.localMountPort = aw.mountPort
+				ic.localMountAddress = aw.mountAddress
 				ic.readOnly = aw.readOnly
 			}
 		}
@@ -283,6 +293,7 @@ func (s *session) setCurrentIntercepts(ctx context.Context, iis []*manager.Inter
 	}
 	s.currentIntercepts = intercepts
 	s.reconcileAPIServers(ctx)
+	userd.GetService(ctx).NotifyStatusChanged()
 }
 
 func InterceptError(tp common.InterceptError, err error) *rpc.InterceptResult {
@@ -325,7 +336,7 @@ func (s *session) ensureNoInterceptConflict(ir *rpc.CreateInterceptRequest) *rpc
 	if err != nil {
 		return &rpc.InterceptResult{
 			Error:         common.InterceptError_MOUNT_POINT_BUSY,
-			ErrorText:     err.Error(),
+			ErrorText:     grpcStatus.Convert(err).Message(),
 			ErrorCategory: int32(errcat.User),
 		}
 	}
@@ -366,6 +377,9 @@ func (s *session) CanIntercept(c context.Context, ir *rpc.CreateInterceptRequest
 	if spec.Agent == "" {
 		return nil, nil
 	}
+	if err := checkWorkloadAccess(c, "intercept", spec.Namespace); err != nil {
+		return nil, InterceptError(common.InterceptError_PERMISSION_DENIED, err)
+	}
 
 	mgrIr := &manager.CreateInterceptRequest{
 		Session:       s.SessionInfo(),
@@ -406,6 +420,7 @@ func (s *session) NewCreateInterceptRequest(spec *manager.InterceptSpec) *manage
 
 // AddIntercept adds one intercept.
 func (s *session) AddIntercept(c context.Context, ir *rpc.CreateInterceptRequest) *rpc.InterceptResult {
+	s.touch()
 	self := s.self
 	iInfo, result := self.CanIntercept(c, ir)
 	if result != nil {
@@ -441,6 +456,10 @@ func (s *session) AddIntercept(c context.Context, ir *rpc.CreateInterceptRequest
 	}
 	spec.Protocol = pi.Protocol
 	spec.ContainerPort = pi.ContainerPort
+	if spec.ContainerName != "" && pi.ContainerName != "" {
+		// The given container name may be a glob.
+		spec.ContainerName = pi.ContainerName
+	}
 	result = iInfo.InterceptResult()
 
 	spec.ServiceUid = result.ServiceUid
@@ -458,10 +477,11 @@ func (s *session) AddIntercept(c context.Context, ir *rpc.CreateInterceptRequest
 	waitCh := make(chan interceptResult, 2) // Need a buffer because reply can come before we're reading the channel,
 	s.currentInterceptsLock.Lock()
 	s.interceptWaiters[spec.Name] = &awaitIntercept{
-		mountPoint: ir.MountPoint,
-		mountPort:  ir.LocalMountPort,
-		readOnly:   ir.MountReadOnly,
-		waitCh:     waitCh,
+		mountPoint:   ir.MountPoint,
+		mountPort:    ir.LocalMountPort,
+		mountAddress: ir.LocalMountAddress,
+		readOnly:     ir.MountReadOnly,
+		waitCh:       waitCh,
 	}
 	s.currentInterceptsLock.Unlock()
 	defer func() {
@@ -526,12 +546,52 @@ func (s *session) AddIntercept(c context.Context, ir *rpc.CreateInterceptRequest
 				return InterceptError(common.InterceptError_INTERNAL, client.CheckTimeout(c, err))
 			}
 			result.InterceptInfo.Environment = env.Env
+			if w := s.pausedWarning(c, result.WorkloadKind, spec.Agent, spec.Namespace); w != "" {
+				result.Warnings = append(result.Warnings, w)
+			}
 			success = true // Prevent removal in deferred function
+			if d := ir.Duration.AsDuration(); d > 0 {
+				s.leaveAfter(ic, d)
+			}
 			return result
 		}
 	}
 }
 
+// pausedWarning returns a warning if the given workload is a Rollout that is paused, or an empty string if it isn't.
+func (s *session) pausedWarning(ctx context.Context, kind, name, namespace string) string {
+	if rpcKind(kind) != manager.WorkloadInfo_ROLLOUT {
+		return ""
+	}
+	s.ensureWatchers(ctx, []string{namespace})
+	s.workloadsLock.Lock()
+	info, ok := s.workloads[namespace][workloadInfoKey{kind: manager.WorkloadInfo_ROLLOUT, name: name}]
+	s.workloadsLock.Unlock()
+	if ok && info.state == workload.StatePaused {
+		return fmt.Sprintf("Rollout %s.%s is paused. Its pods will not be updated until it is resumed", name, namespace)
+	}
+	return ""
+}
+
+// leaveAfter removes the given intercept, and stops its handler, when the given duration has elapsed. Nothing
+// happens if the intercept ends before that.
+func (s *session) leaveAfter(ic *intercept, d time.Duration) {
+	go func() {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ic.ctx.Done():
+		case <-timer.C:
+			ctx := context.WithoutCancel(ic.ctx)
+			name := ic.Spec.Name
+			dlog.Infof(ctx, "Leaving intercept %s because its duration of %s has elapsed", name, d)
+			if err := s.self.RemoveIntercept(ctx, name); err != nil {
+				dlog.Errorf(ctx, "failed to remove intercept %s: %v", name, err)
+			}
+		}
+	}()
+}
+
 func (s *session) InterceptProlog(context.Context, *manager.CreateInterceptRequest) *rpc.InterceptResult {
 	return nil
 }
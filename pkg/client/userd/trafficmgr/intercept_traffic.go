@@ -0,0 +1,173 @@
+package trafficmgr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/datawire/dlib/dlog"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/userd"
+	"github.com/telepresenceio/telepresence/v2/pkg/ipproto"
+	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
+	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
+)
+
+// trafficSubscriberBufferSize is the number of entries that can be queued for a slow subscriber.
+// Entries are dropped when the buffer is full.
+const trafficSubscriberBufferSize = 64
+
+type trafficSubscriber struct {
+	intercept string
+	ch        chan *rpc.InterceptTrafficEntry
+}
+
+// WatchInterceptTraffic streams a summary of each connection that is dialed on behalf of the
+// given intercept. The stream ends when the intercept ends or when the caller cancels.
+func (s *session) WatchInterceptTraffic(c context.Context, tr *rpc.InterceptTrafficRequest, stream userd.WatchInterceptTrafficStream) error {
+	ic := s.getInterceptByName(tr.Name)
+	if ic == nil {
+		return status.Error(codes.NotFound, fmt.Sprintf("intercept %s doesn't exist", tr.Name))
+	}
+
+	id := uuid.New()
+	ch := make(chan *rpc.InterceptTrafficEntry, trafficSubscriberBufferSize)
+	s.trafficLock.Lock()
+	if s.trafficSubscribers == nil {
+		s.trafficSubscribers = make(map[uuid.UUID]*trafficSubscriber)
+	}
+	s.trafficSubscribers[id] = &trafficSubscriber{intercept: tr.Name, ch: ch}
+	s.trafficLock.Unlock()
+
+	defer func() {
+		s.trafficLock.Lock()
+		delete(s.trafficSubscribers, id)
+		s.trafficLock.Unlock()
+	}()
+
+	for {
+		select {
+		case <-c.Done():
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		case <-ic.ctx.Done():
+			return nil
+		case e := <-ch:
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// observeDial is a tunnel.DialObserver that dispatches a summary of the dialed connection to
+// all subscribers of the intercept that the connection targets.
+func (s *session) observeDial(ctx context.Context, ds *tunnel.DialSummary) {
+	s.touch()
+	s.trafficLock.Lock()
+	hasSubscribers := len(s.trafficSubscribers) > 0
+	s.trafficLock.Unlock()
+	if !hasSubscribers {
+		return
+	}
+
+	// The current intercepts are copied before the trafficLock is taken again, so that the lock of the
+	// intercepts is never acquired while the trafficLock is held.
+	var entry *rpc.InterceptTrafficEntry
+	for _, ic := range s.getCurrentIntercepts() {
+		if ic.Disposition == manager.InterceptDispositionType_ACTIVE && interceptTargets(ic.Spec, ds.ID) {
+			entry = newInterceptTrafficEntry(ic.Spec.Name, ds)
+			break
+		}
+	}
+	if entry == nil {
+		return
+	}
+
+	s.trafficLock.Lock()
+	defer s.trafficLock.Unlock()
+	for _, sub := range s.trafficSubscribers {
+		if sub.intercept == entry.Intercept {
+			select {
+			case sub.ch <- entry:
+			default:
+				dlog.Debugf(ctx, "dropping traffic entry for intercept %s, subscriber is too slow", entry.Intercept)
+			}
+		}
+	}
+}
+
+// interceptTargets returns true if the destination of the given connection is the target of the given intercept.
+func interceptTargets(spec *manager.InterceptSpec, id tunnel.ConnID) bool {
+	if int32(id.DestinationPort()) != spec.TargetPort {
+		return false
+	}
+	targetIP := iputil.Parse(spec.TargetHost)
+	return targetIP == nil || targetIP.Equal(id.Destination())
+}
+
+func newInterceptTrafficEntry(name string, ds *tunnel.DialSummary) *rpc.InterceptTrafficEntry {
+	id := ds.ID
+	e := &rpc.InterceptTrafficEntry{
+		Intercept:     name,
+		Protocol:      ipproto.String(id.Protocol()),
+		Source:        iputil.JoinIpPort(id.Source(), id.SourcePort()),
+		Destination:   iputil.JoinIpPort(id.Destination(), id.DestinationPort()),
+		StartTime:     timestamppb.New(ds.Start),
+		Duration:      durationpb.New(ds.Duration),
+		BytesReceived: ds.IngressBytes,
+		BytesSent:     ds.EgressBytes,
+	}
+	if method, path, ok := parseHTTPRequestLine(ds.IngressHead); ok {
+		e.HttpMethod = method
+		e.HttpPath = path
+		e.HttpStatus, _ = parseHTTPStatusLine(ds.EgressHead)
+	}
+	return e
+}
+
+// firstLine returns the first line of the given data, or false if the data doesn't contain a complete line.
+func firstLine(data []byte) (string, bool) {
+	line, _, ok := bytes.Cut(data, []byte("\r\n"))
+	return string(line), ok
+}
+
+// parseHTTPRequestLine returns the method and path of an HTTP/1.x request line, e.g. "GET /index.html HTTP/1.1".
+func parseHTTPRequestLine(data []byte) (method, path string, ok bool) {
+	line, ok := firstLine(data)
+	if !ok {
+		return "", "", false
+	}
+	fields := strings.Split(line, " ")
+	if len(fields) != 3 || fields[0] == "" || fields[1] == "" || !strings.HasPrefix(fields[2], "HTTP/1.") {
+		return "", "", false
+	}
+	return fields[0], fields[1], true
+}
+
+// parseHTTPStatusLine returns the status code of an HTTP/1.x status line, e.g. "HTTP/1.1 200 OK".
+func parseHTTPStatusLine(data []byte) (int32, bool) {
+	line, ok := firstLine(data)
+	if !ok {
+		return 0, false
+	}
+	fields := strings.SplitN(line, " ", 3)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "HTTP/1.") || len(fields[1]) != 3 {
+		return 0, false
+	}
+	code, err := strconv.Atoi(fields[1])
+	if err != nil || code < 100 {
+		return 0, false
+	}
+	return int32(code), true
+}
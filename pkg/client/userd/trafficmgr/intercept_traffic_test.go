@@ -0,0 +1,287 @@
+package trafficmgr
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/datawire/dlib/dlog"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/ipproto"
+	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
+	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
+)
+
+type trafficStream struct {
+	ctx     context.Context
+	entries chan *rpc.InterceptTrafficEntry
+}
+
+func (ts *trafficStream) Send(e *rpc.InterceptTrafficEntry) error {
+	ts.entries <- e
+	return nil
+}
+
+func (ts *trafficStream) Context() context.Context {
+	return ts.ctx
+}
+
+func Test_observeDial(t *testing.T) {
+	ctx, cancel := context.WithCancel(dlog.NewTestContext(t, false))
+	defer cancel()
+
+	s := newMountTestSession()
+	icCtx, icCancel := context.WithCancel(ctx)
+	defer icCancel()
+	s.currentIntercepts["echo"] = &intercept{
+		InterceptInfo: &manager.InterceptInfo{
+			Id:          "id-echo",
+			Spec:        &manager.InterceptSpec{Name: "echo", TargetHost: "127.0.0.1", TargetPort: 8080},
+			Disposition: manager.InterceptDispositionType_ACTIVE,
+		},
+		ctx:    icCtx,
+		cancel: icCancel,
+	}
+
+	stream := &trafficStream{ctx: ctx, entries: make(chan *rpc.InterceptTrafficEntry, trafficSubscriberBufferSize)}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.WatchInterceptTraffic(ctx, &rpc.InterceptTrafficRequest{Name: "echo"}, stream)
+	}()
+
+	src := net.ParseIP("10.0.0.1")
+	dst := net.ParseIP("127.0.0.1")
+	other := &tunnel.DialSummary{ID: tunnel.NewConnID(ipproto.TCP, src, dst, 34567, 9090)}
+	ds := &tunnel.DialSummary{
+		ID:           tunnel.NewConnID(ipproto.TCP, src, dst, 34567, 8080),
+		Start:        time.Now(),
+		Duration:     time.Second,
+		IngressBytes: 10,
+		EgressBytes:  20,
+	}
+
+	// The subscriber is registered asynchronously, so the summary is observed until it arrives.
+	var entry *rpc.InterceptTrafficEntry
+	require.Eventually(t, func() bool {
+		s.observeDial(ctx, other)
+		s.observeDial(ctx, ds)
+		select {
+		case entry = <-stream.entries:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "echo", entry.Intercept)
+	assert.Equal(t, "tcp", entry.Protocol)
+	assert.Equal(t, "10.0.0.1:34567", entry.Source)
+	assert.Equal(t, "127.0.0.1:8080", entry.Destination)
+	assert.Equal(t, time.Second, entry.Duration.AsDuration())
+	assert.Equal(t, uint64(10), entry.BytesReceived)
+	assert.Equal(t, uint64(20), entry.BytesSent)
+
+	// The stream ends when the intercept ends.
+	icCancel()
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("traffic stream didn't end with the intercept")
+	}
+}
+
+// tunnelPipe is the client side of a traffic-manager Tunnel that is connected to a server side in the test.
+type tunnelPipe struct {
+	grpc.ClientStream
+	ctx       context.Context
+	cToS      chan *manager.TunnelMessage
+	sToC      chan *manager.TunnelMessage
+	closeOnce sync.Once
+}
+
+func newTunnelPipe(ctx context.Context) *tunnelPipe {
+	return &tunnelPipe{ctx: ctx, cToS: make(chan *manager.TunnelMessage, 10), sToC: make(chan *manager.TunnelMessage, 10)}
+}
+
+func pipeSend(ctx context.Context, ch chan<- *manager.TunnelMessage, m *manager.TunnelMessage) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case ch <- m:
+		return nil
+	}
+}
+
+func pipeRecv(ctx context.Context, ch <-chan *manager.TunnelMessage) (*manager.TunnelMessage, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case m, ok := <-ch:
+		if !ok {
+			return nil, net.ErrClosed
+		}
+		return m, nil
+	}
+}
+
+func (tp *tunnelPipe) Send(m *manager.TunnelMessage) error {
+	return pipeSend(tp.ctx, tp.cToS, m)
+}
+
+func (tp *tunnelPipe) Recv() (*manager.TunnelMessage, error) {
+	return pipeRecv(tp.ctx, tp.sToC)
+}
+
+func (tp *tunnelPipe) CloseSend() error {
+	tp.closeOnce.Do(func() { close(tp.cToS) })
+	return nil
+}
+
+// tunnelPipeServer is the server side of a tunnelPipe.
+type tunnelPipeServer struct {
+	*tunnelPipe
+}
+
+func (ts tunnelPipeServer) Send(m *manager.TunnelMessage) error {
+	return pipeSend(ts.ctx, ts.sToC, m)
+}
+
+func (ts tunnelPipeServer) Recv() (*manager.TunnelMessage, error) {
+	return pipeRecv(ts.ctx, ts.cToS)
+}
+
+type dialStream struct {
+	grpc.ClientStream
+	ctx   context.Context
+	dials <-chan *manager.DialRequest
+}
+
+func (ds *dialStream) Recv() (*manager.DialRequest, error) {
+	select {
+	case <-ds.ctx.Done():
+		return nil, ds.ctx.Err()
+	case dr := <-ds.dials:
+		return dr, nil
+	}
+}
+
+// dialTestManager is a traffic-manager that sends dial requests and hands out the Tunnels that are
+// created for them.
+type dialTestManager struct {
+	manager.ManagerClient
+	dials   chan *manager.DialRequest
+	tunnels chan *tunnelPipe
+}
+
+func (m *dialTestManager) WatchDial(ctx context.Context, _ *manager.SessionInfo, _ ...grpc.CallOption) (grpc.ServerStreamingClient[manager.DialRequest], error) {
+	return &dialStream{ctx: ctx, dials: m.dials}, nil
+}
+
+func (m *dialTestManager) Tunnel(ctx context.Context, _ ...grpc.CallOption) (grpc.BidiStreamingClient[manager.TunnelMessage, manager.TunnelMessage], error) {
+	tp := newTunnelPipe(ctx)
+	m.tunnels <- tp
+	return tp, nil
+}
+
+func Test_interceptTraffic(t *testing.T) {
+	ctx, cancel := context.WithTimeout(dlog.NewTestContext(t, false), 10*time.Second)
+	defer cancel()
+
+	// The local handler of the intercept.
+	handler := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created " + r.URL.Path))
+	}))
+	defer handler.Close()
+	hAddr := handler.Listener.Addr().(*net.TCPAddr)
+
+	mgr := &dialTestManager{dials: make(chan *manager.DialRequest), tunnels: make(chan *tunnelPipe, 1)}
+	s := newMountTestSession()
+	s.managerClient = mgr
+	s.sessionInfo = &manager.SessionInfo{SessionId: "session-1"}
+	icCtx, icCancel := context.WithCancel(ctx)
+	defer icCancel()
+	s.currentIntercepts["echo"] = &intercept{
+		InterceptInfo: &manager.InterceptInfo{
+			Id:          "id-echo",
+			Spec:        &manager.InterceptSpec{Name: "echo", TargetHost: "127.0.0.1", TargetPort: int32(hAddr.Port)},
+			Disposition: manager.InterceptDispositionType_ACTIVE,
+		},
+		ctx:    icCtx,
+		cancel: icCancel,
+	}
+
+	stream := &trafficStream{ctx: ctx, entries: make(chan *rpc.InterceptTrafficEntry, trafficSubscriberBufferSize)}
+	go func() {
+		_ = s.WatchInterceptTraffic(ctx, &rpc.InterceptTrafficRequest{Name: "echo"}, stream)
+	}()
+	require.Eventually(t, func() bool {
+		s.trafficLock.Lock()
+		defer s.trafficLock.Unlock()
+		return len(s.trafficSubscribers) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	go func() {
+		_ = s._dialRequestWatcher(ctx)
+	}()
+
+	// The traffic-manager asks the session to dial the handler, and then sends a request through the tunnel.
+	id := tunnel.NewConnID(ipproto.TCP, iputil.Parse("10.0.0.1"), hAddr.IP, 34567, uint16(hAddr.Port))
+	mgr.dials <- &manager.DialRequest{ConnId: []byte(id)}
+	tp := <-mgr.tunnels
+	server, err := tunnel.NewServerStream(ctx, tunnelPipeServer{tp})
+	require.NoError(t, err)
+
+	request := []byte("POST /items HTTP/1.1\r\nHost: echo\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+	require.NoError(t, server.Send(ctx, tunnel.NewMessage(tunnel.Normal, request)))
+	var response []byte
+	incoming, _ := tunnel.ReadLoop(ctx, server, nil)
+	for m := range incoming {
+		if m.Code() == tunnel.Normal {
+			response = append(response, m.Payload()...)
+		}
+	}
+	require.NoError(t, server.CloseSend(ctx))
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for traffic entry")
+	case entry := <-stream.entries:
+		assert.Equal(t, "echo", entry.Intercept)
+		assert.Equal(t, "10.0.0.1:34567", entry.Source)
+		assert.Equal(t, uint64(len(request)), entry.BytesReceived)
+		assert.Equal(t, uint64(len(response)), entry.BytesSent)
+		assert.Equal(t, "POST", entry.HttpMethod)
+		assert.Equal(t, "/items", entry.HttpPath)
+		assert.Equal(t, int32(http.StatusCreated), entry.HttpStatus)
+	}
+}
+
+func Test_parseHTTP(t *testing.T) {
+	method, path, ok := parseHTTPRequestLine([]byte("GET /api/items?x=1 HTTP/1.1\r\nHost: echo\r\n"))
+	assert.True(t, ok)
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/api/items?x=1", path)
+
+	_, _, ok = parseHTTPRequestLine([]byte("GET /api/items HTTP/1.1"))
+	assert.False(t, ok, "incomplete line")
+	_, _, ok = parseHTTPRequestLine([]byte("\x16\x03\x01\x02\x00\r\n"))
+	assert.False(t, ok, "not HTTP")
+
+	status, ok := parseHTTPStatusLine([]byte("HTTP/1.1 404 Not Found\r\n"))
+	assert.True(t, ok)
+	assert.Equal(t, int32(404), status)
+
+	_, ok = parseHTTPStatusLine([]byte("HTTP/1.1 4x4 Bad\r\n"))
+	assert.False(t, ok)
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/go-fuseftp/rpc"
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/remotefs"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/userd"
@@ -51,18 +53,20 @@ func (pa *podAccess) startMount(ctx context.Context, iceptWG, podWG *sync.WaitGr
 		port = pa.sftpPort
 	}
 
-	m := *pa.mounter
-	if m == nil {
+	var m remotefs.Mounter
+	if mp := pa.mounter.Load(); mp != nil {
+		m = *mp
+	} else {
 		switch {
 		case pa.localMountPort != 0:
 			session := userd.GetSession(ctx)
-			m = remotefs.NewBridgeMounter(session.SessionInfo().SessionId, session.ManagerClient(), uint16(pa.localMountPort))
+			m = remotefs.NewBridgeMounter(session.SessionInfo().SessionId, session.ManagerClient(), pa.localMountAddress, uint16(pa.localMountPort))
 		case useFtp:
 			m = remotefs.NewFTPMounter(fuseftp, iceptWG)
 		default:
 			m = remotefs.NewSFTPMounter(iceptWG, podWG)
 		}
-		*pa.mounter = m
+		pa.mounter.Store(&m)
 	}
 	err := m.Start(mountCtx, pa.workload, pa.container, pa.clientMountPoint, pa.mountPoint, iputil.Parse(pa.podIP), uint16(port), pa.readOnly)
 	if err != nil && ctx.Err() == nil {
@@ -70,36 +74,67 @@ func (pa *podAccess) startMount(ctx context.Context, iceptWG, podWG *sync.WaitGr
 	}
 }
 
-func (s *session) ensureNoMountConflict(localMountPoint string, localMountPort int32) (err error) {
-	if localMountPoint == "" && localMountPort == 0 {
+// mountStatus returns the status of the given mounter, or nil when no mount has been started.
+func mountStatus(mp *atomic.Pointer[remotefs.Mounter]) *connector.MountStatus {
+	m := mp.Load()
+	if m == nil {
 		return nil
 	}
+	st := (*m).Status()
+	ms := &connector.MountStatus{Connected: st.Connected}
+	if st.LastError != nil {
+		ms.Error = st.LastError.Error()
+	}
+	return ms
+}
+
+// mountHolder is an intercept or an ingest that holds a local mount point and/or a local mount port.
+type mountHolder struct {
+	kind       string
+	name       string
+	mountPoint string
+	mountPort  int32
+}
+
+// mountHolders returns the mount holders of all intercepts and ingests in this session, including the
+// intercepts that have been created but not yet arrived from the traffic-manager.
+func (s *session) mountHolders() []mountHolder {
+	var mhs []mountHolder
 	s.currentInterceptsLock.Lock()
 	for _, ic := range s.currentIntercepts {
-		if localMountPoint != "" && ic.ClientMountPoint == localMountPoint {
-			err = status.Error(codes.AlreadyExists, fmt.Sprintf("mount point %s already in use by intercept %s", localMountPoint, ic.Spec.Name))
-			break
-		}
-		if localMountPort != 0 && ic.localMountPort == localMountPort {
-			err = status.Error(codes.AlreadyExists, fmt.Sprintf("mount port %d already in use by intercept %s", localMountPort, ic.Spec.Name))
-			break
-		}
+		mhs = append(mhs, mountHolder{kind: "intercept", name: ic.Spec.Name, mountPoint: ic.ClientMountPoint, mountPort: ic.localMountPort})
 	}
-	s.currentInterceptsLock.Unlock()
-	if err != nil {
-		return err
+	for name, aw := range s.interceptWaiters {
+		mhs = append(mhs, mountHolder{kind: "intercept", name: name, mountPoint: aw.mountPoint, mountPort: aw.mountPort})
 	}
+	s.currentInterceptsLock.Unlock()
 
 	s.currentIngests.Range(func(key ingestKey, ig *ingest) bool {
-		if localMountPoint != "" && ig.localMountPoint == localMountPoint {
-			err = status.Error(codes.AlreadyExists, fmt.Sprintf("mount point %s already in use by ingest %s", localMountPoint, key))
-			return false
-		}
-		if localMountPort != 0 && ig.localMountPort == localMountPort {
-			err = status.Error(codes.AlreadyExists, fmt.Sprintf("mount port %d already in use by ingest %s", localMountPort, key))
-			return false
-		}
+		mhs = append(mhs, mountHolder{kind: "ingest", name: key.String(), mountPoint: ig.localMountPoint, mountPort: ig.localMountPort})
 		return true
 	})
-	return err
+	return mhs
+}
+
+// findMountConflict returns an AlreadyExists error that names the first of the given holders that
+// uses the given local mount point or local mount port, or nil when there is no such holder.
+func findMountConflict(localMountPoint string, localMountPort int32, mhs []mountHolder) error {
+	for _, mh := range mhs {
+		if localMountPoint != "" && mh.mountPoint == localMountPoint {
+			return status.Error(codes.AlreadyExists, fmt.Sprintf("mount point %s already in use by %s %s", localMountPoint, mh.kind, mh.name))
+		}
+		if localMountPort != 0 && mh.mountPort == localMountPort {
+			return status.Error(codes.AlreadyExists, fmt.Sprintf("mount port %d already in use by %s %s", localMountPort, mh.kind, mh.name))
+		}
+	}
+	return nil
+}
+
+// ensureNoMountConflict checks that the given local mount point and local mount port aren't used by
+// any intercept or ingest in this session.
+func (s *session) ensureNoMountConflict(localMountPoint string, localMountPort int32) error {
+	if localMountPoint == "" && localMountPort == 0 {
+		return nil
+	}
+	return findMountConflict(localMountPoint, localMountPort, s.mountHolders())
 }
@@ -0,0 +1,91 @@
+package trafficmgr
+
+import (
+	"testing"
+
+	"github.com/puzpuzpuz/xsync/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/common"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+func newMountTestSession() *session {
+	return &session{
+		currentIntercepts: make(map[string]*intercept),
+		interceptWaiters:  make(map[string]*awaitIntercept),
+		currentIngests:    xsync.NewMapOf[ingestKey, *ingest](),
+	}
+}
+
+func (s *session) addTestIntercept(name, mountPoint string, mountPort int32) {
+	s.currentIntercepts[name] = &intercept{
+		InterceptInfo: &manager.InterceptInfo{
+			Id:               "id-" + name,
+			Spec:             &manager.InterceptSpec{Name: name},
+			ClientMountPoint: mountPoint,
+		},
+		localMountPort: mountPort,
+	}
+}
+
+func (s *session) addTestIngest(workload, container, mountPoint string, mountPort int32) {
+	ik := ingestKey{workload: workload, container: container}
+	s.currentIngests.Store(ik, &ingest{ingestKey: ik, localMountPoint: mountPoint, localMountPort: mountPort})
+}
+
+func requireMountConflict(t *testing.T, err error, msg string) {
+	t.Helper()
+	require.Error(t, err)
+	st := status.Convert(err)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+	assert.Equal(t, msg, st.Message())
+}
+
+func Test_ensureNoMountConflict(t *testing.T) {
+	t.Run("intercept then ingest", func(t *testing.T) {
+		s := newMountTestSession()
+		s.addTestIntercept("echo", "/tmp/echo", 0)
+		requireMountConflict(t, s.ensureNoMountConflict("/tmp/echo", 0), "mount point /tmp/echo already in use by intercept echo")
+		assert.NoError(t, s.ensureNoMountConflict("/tmp/other", 0))
+	})
+
+	t.Run("awaited intercept then ingest", func(t *testing.T) {
+		s := newMountTestSession()
+		s.interceptWaiters["echo"] = &awaitIntercept{mountPoint: "/tmp/echo"}
+		requireMountConflict(t, s.ensureNoMountConflict("/tmp/echo", 0), "mount point /tmp/echo already in use by intercept echo")
+	})
+
+	t.Run("ingest then intercept", func(t *testing.T) {
+		s := newMountTestSession()
+		s.addTestIngest("echo", "echo-container", "/tmp/echo", 0)
+		requireMountConflict(t, s.ensureNoMountConflict("/tmp/echo", 0), "mount point /tmp/echo already in use by ingest echo[echo-container]")
+
+		ir := s.ensureNoInterceptConflict(&rpc.CreateInterceptRequest{
+			Spec:       &manager.InterceptSpec{Name: "other"},
+			MountPoint: "/tmp/echo",
+		})
+		require.NotNil(t, ir)
+		assert.Equal(t, common.InterceptError_MOUNT_POINT_BUSY, ir.Error)
+		assert.Equal(t, "mount point /tmp/echo already in use by ingest echo[echo-container]", ir.ErrorText)
+	})
+
+	t.Run("port collision", func(t *testing.T) {
+		s := newMountTestSession()
+		s.addTestIntercept("echo", "", 8022)
+		s.addTestIngest("hello", "hello", "", 8023)
+		requireMountConflict(t, s.ensureNoMountConflict("", 8022), "mount port 8022 already in use by intercept echo")
+		requireMountConflict(t, s.ensureNoMountConflict("", 8023), "mount port 8023 already in use by ingest hello[hello]")
+		assert.NoError(t, s.ensureNoMountConflict("", 8024))
+	})
+
+	t.Run("nothing to mount", func(t *testing.T) {
+		s := newMountTestSession()
+		s.addTestIntercept("echo", "", 0)
+		assert.NoError(t, s.ensureNoMountConflict("", 0))
+	})
+}
@@ -0,0 +1,52 @@
+package trafficmgr
+
+import (
+	"context"
+
+	auth "k8s.io/api/authorization/v1"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/k8sclient"
+)
+
+// workloadAccessPermissions returns the permissions that the client needs in the given namespace
+// in order to ingest or intercept a workload in it. The traffic-manager performs all workload
+// modifications, so the client only needs permissions to reach the traffic-agent.
+func workloadAccessPermissions(ctx context.Context, namespace string) []*auth.ResourceAttributes {
+	if !client.GetConfig(ctx).Cluster().AgentPortForward {
+		return nil
+	}
+	return []*auth.ResourceAttributes{
+		{
+			Namespace: namespace,
+			Verb:      "get",
+			Resource:  "pods",
+		},
+		{
+			Namespace:   namespace,
+			Verb:        "create",
+			Resource:    "pods",
+			Subresource: "portforward",
+		},
+	}
+}
+
+// checkWorkloadAccess verifies that the client has the permissions needed to perform the given
+// action in the given namespace, and returns an error that lists the missing permissions if it
+// doesn't. The check is skipped if the permissions cannot be determined.
+func checkWorkloadAccess(ctx context.Context, action, namespace string) error {
+	ras := workloadAccessPermissions(ctx, namespace)
+	if len(ras) == 0 {
+		return nil
+	}
+	missing, err := k8sclient.MissingPermissions(ctx, ras)
+	if err != nil {
+		dlog.Debugf(ctx, "skipping permission check for %s: %v", action, err)
+		return nil
+	}
+	if len(missing) > 0 {
+		return k8sclient.MissingPermissionsError(action, namespace, missing)
+	}
+	return nil
+}
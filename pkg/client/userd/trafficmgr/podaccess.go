@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/netip"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -39,12 +40,16 @@ type podAccess struct {
 	clientMountPoint string
 
 	// Pointer to the mounter of the remote file system. The mounter
-	// is maintained in the ingest or intercept structure
-	mounter *remotefs.Mounter
+	// is maintained in the ingest or intercept structure, and it is
+	// read concurrently when the status of the mount is reported.
+	mounter *atomic.Pointer[remotefs.Mounter]
 
 	// Use bridged ftp/sftp mount through this local port
 	localMountPort int32
 
+	// The address that the localMountPort is bound to. All interfaces are used when empty.
+	localMountAddress string
+
 	// Mount read-only
 	readOnly bool
 }
@@ -140,6 +145,7 @@ func (pa *podAccess) workerPortForward(ctx context.Context, port string, wg *syn
 		return
 	}
 	f := forwarder.NewInterceptor(addr, pa.podIP, pp.Port)
+	f.SetKeepAlive(client.GetConfig(ctx).Intercept().ForwardKeepAlive)
 	err = f.Serve(ctx, nil)
 	if err != nil && ctx.Err() == nil {
 		dlog.Errorf(ctx, "port-forwarder failed with %v", err)
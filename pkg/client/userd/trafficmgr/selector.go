@@ -0,0 +1,135 @@
+package trafficmgr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/datawire/dlib/dlog"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// validateLabelSelector returns an error when the given label selector is invalid, or when the traffic-manager
+// cannot filter workloads using label selectors.
+func (s *session) validateLabelSelector(selector string) error {
+	if v := s.managerVersion; v.Major < 2 || v.Major == 2 && v.Minor < 22 {
+		return status.Error(codes.Unimplemented, fmt.Sprintf("traffic-manager %s have no support for label selectors", v))
+	}
+	if _, err := labels.Parse(selector); err != nil {
+		return errcat.User.Newf("invalid label selector %q: %w", selector, err)
+	}
+	return nil
+}
+
+// workloadSelection contains the workloads that the traffic-manager reports as matching a label selector. The
+// selector is only evaluated by the traffic-manager.
+type workloadSelection struct {
+	sync.Mutex
+	selected map[string]map[workloadInfoKey]struct{}
+}
+
+// update adds the workloads of the given delta to the selection of the given namespace, or removes them when
+// they are deleted or no longer match.
+func (ws *workloadSelection) update(namespace string, delta *manager.WorkloadEventsDelta) {
+	ws.Lock()
+	defer ws.Unlock()
+	selected, ok := ws.selected[namespace]
+	if !ok {
+		selected = make(map[workloadInfoKey]struct{})
+		ws.selected[namespace] = selected
+	}
+	for _, we := range delta.GetEvents() {
+		w := we.Workload
+		if we.Type == manager.WorkloadEvent_DELETED {
+			// The kind is unknown when the deletion was discovered using the agent of the workload.
+			for key := range selected {
+				if key.name == w.Name {
+					delete(selected, key)
+				}
+			}
+		} else {
+			selected[workloadInfoKey{kind: w.Kind, name: w.Name}] = struct{}{}
+		}
+	}
+}
+
+// filter returns a snapshot with the workloads of the given snapshot that are selected.
+func (ws *workloadSelection) filter(snapshot *rpc.WorkloadInfoSnapshot) *rpc.WorkloadInfoSnapshot {
+	ws.Lock()
+	defer ws.Unlock()
+	wls := make([]*rpc.WorkloadInfo, 0, len(snapshot.Workloads))
+	for _, wl := range snapshot.Workloads {
+		if _, ok := ws.selected[wl.Namespace][workloadInfoKey{kind: rpcKind(wl.WorkloadResourceType), name: wl.Name}]; ok {
+			wls = append(wls, wl)
+		}
+	}
+	return &rpc.WorkloadInfoSnapshot{Workloads: wls}
+}
+
+// selectWorkloads asks the traffic-manager to watch the workloads in the given namespaces that match the given
+// label selector, and returns a selection once the initial snapshot of each namespace has arrived. When changed
+// is non-nil, the selection is kept up to date, and changed is called after each update, until the context is
+// cancelled.
+func (s *session) selectWorkloads(ctx context.Context, namespaces []string, selector string, changed func()) (*workloadSelection, error) {
+	if err := s.validateLabelSelector(selector); err != nil {
+		return nil, err
+	}
+	ws := &workloadSelection{selected: make(map[string]map[workloadInfoKey]struct{}, len(namespaces))}
+	for _, ns := range namespaces {
+		wlc, err := s.managerClient.WatchWorkloads(ctx, &manager.WorkloadEventsRequest{
+			SessionInfo:   s.sessionInfo,
+			Namespace:     ns,
+			LabelSelector: selector,
+		})
+		if err != nil {
+			return nil, err
+		}
+		delta, err := wlc.Recv()
+		if err != nil {
+			return nil, err
+		}
+		ws.update(ns, delta)
+		if changed == nil {
+			continue
+		}
+		go func() {
+			for {
+				delta, err := wlc.Recv()
+				if err != nil {
+					if ctx.Err() == nil {
+						dlog.Errorf(ctx, "failed to watch workloads in namespace %s that match %q: %v", ns, selector, err)
+					}
+					return
+				}
+				ws.update(ns, delta)
+				changed()
+			}
+		}()
+	}
+	return ws, nil
+}
+
+// SelectWorkloads returns a snapshot with the workloads of the given snapshot that the traffic-manager reports as
+// matching the given label selector.
+func (s *session) SelectWorkloads(ctx context.Context, snapshot *rpc.WorkloadInfoSnapshot, selector string) (*rpc.WorkloadInfoSnapshot, error) {
+	var nss []string
+	for _, wl := range snapshot.Workloads {
+		if !slices.Contains(nss, wl.Namespace) {
+			nss = append(nss, wl.Namespace)
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ws, err := s.selectWorkloads(ctx, nss, selector, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ws.filter(snapshot), nil
+}
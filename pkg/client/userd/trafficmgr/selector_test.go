@@ -0,0 +1,39 @@
+package trafficmgr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+func Test_workloadSelection(t *testing.T) {
+	ws := &workloadSelection{selected: make(map[string]map[workloadInfoKey]struct{})}
+	snapshot := &rpc.WorkloadInfoSnapshot{Workloads: []*rpc.WorkloadInfo{
+		{Name: "echo", Namespace: "default", WorkloadResourceType: "Deployment"},
+		{Name: "echo", Namespace: "other", WorkloadResourceType: "Deployment"},
+		{Name: "hello", Namespace: "default", WorkloadResourceType: "StatefulSet"},
+	}}
+	names := func() []string {
+		var ns []string
+		for _, wl := range ws.filter(snapshot).Workloads {
+			ns = append(ns, wl.Name+"."+wl.Namespace)
+		}
+		return ns
+	}
+
+	ws.update("default", &manager.WorkloadEventsDelta{Events: []*manager.WorkloadEvent{
+		{Workload: &manager.WorkloadInfo{Name: "echo", Kind: manager.WorkloadInfo_DEPLOYMENT}},
+		{Workload: &manager.WorkloadInfo{Name: "hello", Kind: manager.WorkloadInfo_STATEFULSET}},
+	}})
+	ws.update("other", &manager.WorkloadEventsDelta{})
+	assert.Equal(t, []string{"echo.default", "hello.default"}, names())
+
+	// A workload that no longer matches is reported as deleted by the traffic-manager.
+	ws.update("default", &manager.WorkloadEventsDelta{Events: []*manager.WorkloadEvent{
+		{Type: manager.WorkloadEvent_DELETED, Workload: &manager.WorkloadInfo{Name: "echo"}},
+	}})
+	assert.Equal(t, []string{"hello.default"}, names())
+}
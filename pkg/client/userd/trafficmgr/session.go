@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/blang/semver/v4"
@@ -80,7 +82,8 @@ type workloadInfo struct {
 	uid              types.UID
 	state            workload.State
 	agentState       manager.WorkloadInfo_AgentState
-	interceptClients []string
+	interceptClients []*manager.WorkloadInfo_Intercept
+	agentOutdated    bool
 }
 
 type session struct {
@@ -116,6 +119,12 @@ type session struct {
 
 	workloadSubscribers map[uuid.UUID]chan struct{}
 
+	// trafficLock protects trafficSubscribers
+	trafficLock sync.Mutex
+
+	// trafficSubscribers are the clients that watch the traffic of an intercept.
+	trafficSubscribers map[uuid.UUID]*trafficSubscriber
+
 	// currentIngests is tracks the ingests that are active in this session.
 	currentIngests *xsync.MapOf[ingestKey, *ingest]
 
@@ -152,6 +161,9 @@ type session struct {
 	// done is closed when the session ends
 	done chan struct{}
 
+	// lastActivity is the time, in Unix nanoseconds, when the session last saw activity. See idleLoop.
+	lastActivity atomic.Int64
+
 	// Possibly extended version of the session. Use when calling interface methods.
 	self userd.Session
 }
@@ -214,6 +226,13 @@ func NewSession(
 	ctx = cluster.WithJoinedClientSetInterface(ctx)
 	scout.SetMetadatum(ctx, "cluster_id", cluster.GetClusterId(ctx))
 
+	if cr.ManagerAddress != "" {
+		nc := client.GetDefaultConfig()
+		nc.Cluster().ManagerAddress = cr.ManagerAddress
+		nc.Cluster().ManagerTLS = cr.ManagerTls
+		ctx = client.WithConfig(ctx, client.GetConfig(ctx).Merge(nc))
+	}
+
 	dlog.Info(ctx, "Connecting to traffic manager...")
 	installID, err := client.InstallID(ctx)
 	if err != nil {
@@ -359,9 +378,11 @@ func connectMgr(
 	defer cancel()
 
 	mgrNs := k8s.GetManagerNamespace(ctx)
-	err := CheckTrafficManagerService(ctx, mgrNs)
-	if err != nil {
-		return nil, err
+	if client.GetConfig(ctx).Cluster().ManagerAddress == "" {
+		// The traffic-manager service is only used when the traffic-manager is reached using port-forward.
+		if err := CheckTrafficManagerService(ctx, mgrNs); err != nil {
+			return nil, err
+		}
 	}
 
 	conn, mClient, vi, err := k8sclient.ConnectToManager(ctx, mgrNs)
@@ -470,7 +491,15 @@ func (s *session) NewRemainRequest() *manager.RemainRequest {
 	return &manager.RemainRequest{Session: s.SessionInfo()}
 }
 
+// Remain tells the traffic-manager that the session is still alive. It counts as session activity, so unlike
+// the periodic keep-alive, it resets the idle timer.
 func (s *session) Remain(ctx context.Context) error {
+	s.touch()
+	return s.keepAlive(ctx)
+}
+
+// keepAlive tells the traffic-manager that the session is still alive.
+func (s *session) keepAlive(ctx context.Context) error {
 	self := s.self
 	ctx, cancel := client.GetConfig(ctx).Timeouts().TimeoutContext(ctx, client.TimeoutTrafficManagerAPI)
 	defer cancel()
@@ -548,6 +577,7 @@ func (s *session) StartServices(g *dgroup.Group) {
 	g.Go("agents", s.watchAgentsLoop)
 	g.Go("intercept-port-forward", s.watchInterceptsHandler)
 	g.Go("dial-request-watcher", s.dialRequestWatcher)
+	g.Go("idle", s.idleLoop)
 }
 
 func runWithRetry(ctx context.Context, f func(context.Context) error) error {
@@ -591,6 +621,7 @@ func (s *session) ApplyConfig(ctx context.Context) error {
 func (s *session) getInfosForWorkloads(
 	namespaces []string,
 	iMap map[string][]*manager.InterceptInfo,
+	mMap map[string]*rpc.MountStatus,
 	gMap map[string][]*rpc.IngestInfo,
 	sMap map[string]string,
 	filter rpc.ListRequest_Filter,
@@ -613,11 +644,21 @@ func (s *session) getInfosForWorkloads(
 		if wlInfo.InterceptInfos, ok = iMap[name]; !ok {
 			filterMatch &= ^rpc.ListRequest_INTERCEPTS
 		}
+		for _, ii := range wlInfo.InterceptInfos {
+			if ms, ok := mMap[ii.Id]; ok {
+				if wlInfo.InterceptMounts == nil {
+					wlInfo.InterceptMounts = make(map[string]*rpc.MountStatus)
+				}
+				wlInfo.InterceptMounts[ii.Id] = ms
+			}
+		}
 		if wlInfo.IngestInfos, ok = gMap[name]; !ok {
 			filterMatch &= ^rpc.ListRequest_INGESTS
 		}
 		if wlInfo.AgentVersion, ok = sMap[name]; !ok {
 			filterMatch &= ^rpc.ListRequest_INSTALLED_AGENTS
+		} else {
+			wlInfo.AgentVersionMismatch = info.agentOutdated
 		}
 		if filter != 0 && filter&filterMatch == 0 {
 			return
@@ -636,7 +677,8 @@ func (s *session) getInfosForWorkloads(
 
 func (s *session) WatchWorkloads(c context.Context, wr *rpc.WatchWorkloadsRequest, stream userd.WatchWorkloadsStream) error {
 	id := uuid.New()
-	ch := make(chan struct{})
+	// Buffered so that a notification that arrives while a snapshot is sent isn't lost.
+	ch := make(chan struct{}, 1)
 	s.workloadsLock.Lock()
 	if s.workloadSubscribers == nil {
 		s.workloadSubscribers = make(map[uuid.UUID]chan struct{})
@@ -650,11 +692,36 @@ func (s *session) WatchWorkloads(c context.Context, wr *rpc.WatchWorkloadsReques
 		s.workloadsLock.Unlock()
 	}()
 
+	var sel *workloadSelection
+	if wr.LabelSelector != "" {
+		var nss []string
+		for _, ns := range wr.Namespaces {
+			if ns = s.ActualNamespace(ns); ns != "" {
+				nss = append(nss, ns)
+			}
+		}
+		sc, cancel := context.WithCancel(c)
+		defer cancel()
+		var err error
+		sel, err = s.selectWorkloads(sc, nss, wr.LabelSelector, func() {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	send := func() error {
 		ws, err := s.WorkloadInfoSnapshot(c, wr.Namespaces, rpc.ListRequest_EVERYTHING)
 		if err != nil {
 			return err
 		}
+		if sel != nil {
+			ws = sel.filter(ws)
+		}
 		return stream.Send(ws)
 	}
 
@@ -674,6 +741,34 @@ func (s *session) WatchWorkloads(c context.Context, wr *rpc.WatchWorkloadsReques
 	}
 }
 
+// GetInterceptHolders returns the clients that currently intercept the given workload in the given namespace, or
+// in the connected namespace when no namespace is given. The holders are sorted by creation time.
+func (s *session) GetInterceptHolders(ctx context.Context, rq *rpc.InterceptHoldersRequest) (*rpc.InterceptHolders, error) {
+	ns := s.ActualNamespace(rq.Namespace)
+	if ns == "" {
+		return nil, errcat.User.Newf("namespace %s is not mapped", rq.Namespace)
+	}
+	s.ensureWatchers(ctx, []string{ns})
+
+	s.workloadsLock.Lock()
+	defer s.workloadsLock.Unlock()
+	found := false
+	var holders []*manager.WorkloadInfo_Intercept
+	for key, info := range s.workloads[ns] {
+		if key.name == rq.Workload {
+			found = true
+			holders = append(holders, info.interceptClients...)
+		}
+	}
+	if !found {
+		return nil, errcat.User.Newf("workload %s.%s not found", rq.Workload, ns)
+	}
+	slices.SortFunc(holders, func(a, b *manager.WorkloadInfo_Intercept) int {
+		return a.CreatedAt.AsTime().Compare(b.CreatedAt.AsTime())
+	})
+	return &rpc.InterceptHolders{Holders: holders}, nil
+}
+
 func (s *session) ensureWatchers(ctx context.Context,
 	namespaces []string,
 ) {
@@ -744,11 +839,15 @@ func (s *session) WorkloadInfoSnapshot(
 	}
 	s.ensureWatchers(ctx, nss)
 	iMap := make(map[string][]*manager.InterceptInfo, len(is))
+	mMap := make(map[string]*rpc.MountStatus, len(is))
 nextIs:
 	for _, i := range is {
 		for _, ns := range nss {
 			if i.Spec.Namespace == ns {
 				iMap[i.Spec.Agent] = append(iMap[i.Spec.Agent], i.InterceptInfo)
+				if ms := mountStatus(&i.mounter); ms != nil {
+					mMap[i.Id] = ms
+				}
 				continue nextIs
 			}
 		}
@@ -759,16 +858,14 @@ nextIs:
 		return true
 	})
 
-	workloadInfos := s.getInfosForWorkloads(nss, iMap, gMap, sMap, filter)
+	workloadInfos := s.getInfosForWorkloads(nss, iMap, mMap, gMap, sMap, filter)
 	return &rpc.WorkloadInfoSnapshot{Workloads: workloadInfos}, nil
 }
 
 var ErrSessionExpired = errors.New("session expired")
 
 func (s *session) remainLoop(c context.Context) error {
-	ticker := time.NewTicker(5 * time.Second)
 	defer func() {
-		ticker.Stop()
 		c = dcontext.WithoutCancel(c)
 		c, cancel := context.WithTimeout(c, 3*time.Second)
 		defer cancel()
@@ -782,13 +879,29 @@ func (s *session) remainLoop(c context.Context) error {
 		}
 		s.managerConn.Close()
 	}()
+	ticker := time.NewTicker(KeepaliveInterval(c))
+	defer ticker.Stop()
+	return remainEvery(c, ticker.C, s.keepAlive)
+}
 
+// KeepaliveInterval returns the interval between the calls that keep a session with the traffic-manager alive. It's
+// the configured timeouts.keepalive, or its default when the configured value isn't positive.
+func KeepaliveInterval(c context.Context) time.Duration {
+	interval := client.GetConfig(c).Timeouts().Get(client.TimeoutKeepalive)
+	if interval <= 0 {
+		interval = client.GetDefaultConfig().Timeouts().Get(client.TimeoutKeepalive)
+	}
+	return interval
+}
+
+// remainEvery calls remain on each tick until the context is cancelled or remain returns an error.
+func remainEvery(c context.Context, tick <-chan time.Time, remain func(context.Context) error) error {
 	for {
 		select {
 		case <-c.Done():
 			return nil
-		case <-ticker.C:
-			if err := s.self.Remain(c); err != nil {
+		case <-tick:
+			if err := remain(c); err != nil {
 				return err
 			}
 		}
@@ -915,19 +1028,22 @@ func (s *session) Uninstall(ctx context.Context, ur *rpc.UninstallRequest) (*com
 		return err
 	}
 
+	if ur.UninstallType != rpc.UninstallRequest_NAMED_AGENTS && ur.UninstallType != rpc.UninstallRequest_ALL_AGENTS {
+		return nil, status.Error(codes.InvalidArgument, "invalid uninstall request")
+	}
+	nss, err := s.uninstallNamespaces(ur)
+	if err != nil {
+		return errcat.ToResult(err), nil
+	}
+	if ur.DryRun {
+		return uninstallDryRun(ur, nss, loadAgentConfigMap)
+	}
+
 	// Removal of agents requested. We need the agents ConfigMap in order to do that.
 	// This removal is deliberately done in the client instead of the traffic-manager so that RBAC can be configured
 	// to prevent the clients from doing it.
 	if ur.UninstallType == rpc.UninstallRequest_NAMED_AGENTS {
-		// must have a valid namespace in order to uninstall named agents
-		if ur.Namespace == "" {
-			ur.Namespace = s.Namespace
-		}
-		namespace := s.ActualNamespace(ur.Namespace)
-		if namespace == "" {
-			// namespace is not mapped
-			return errcat.ToResult(errcat.User.Newf("namespace %s is not mapped", ur.Namespace)), nil
-		}
+		namespace := nss[0]
 		cm, err := loadAgentConfigMap(namespace)
 		if err != nil || cm == nil {
 			return errcat.ToResult(err), nil
@@ -951,10 +1067,6 @@ func (s *session) Uninstall(ctx context.Context, ur *rpc.UninstallRequest) (*com
 		}
 		return errcat.ToResult(nil), nil
 	}
-	if ur.UninstallType != rpc.UninstallRequest_ALL_AGENTS {
-		return nil, status.Error(codes.InvalidArgument, "invalid uninstall request")
-	}
-
 	_ = s.ClearIngestsAndIntercepts(ctx)
 	clearAgentsConfigMap := func(ns string) error {
 		cm, err := loadAgentConfigMap(ns)
@@ -971,26 +1083,62 @@ func (s *session) Uninstall(ctx context.Context, ur *rpc.UninstallRequest) (*com
 		return nil
 	}
 
-	if ur.Namespace != "" {
-		if ur.Namespace == "" {
-			ur.Namespace = s.Namespace
+	for _, ns := range nss {
+		err := clearAgentsConfigMap(ns)
+		if err != nil {
+			return errcat.ToResult(err), nil
+		}
+	}
+	return errcat.ToResult(nil), nil
+}
+
+// uninstallNamespaces returns the namespaces that the given UninstallRequest applies to. Named agents must be in
+// a mapped namespace, which defaults to the connected namespace. All agents are uninstalled from the given namespace,
+// or from all mapped namespaces when no namespace is given.
+func (s *session) uninstallNamespaces(ur *rpc.UninstallRequest) ([]string, error) {
+	ns := ur.Namespace
+	if ns == "" {
+		if ur.UninstallType == rpc.UninstallRequest_ALL_AGENTS {
+			return s.GetCurrentNamespaces(true), nil
 		}
-		namespace := s.ActualNamespace(ur.Namespace)
-		if namespace == "" {
-			// namespace is not mapped
-			return errcat.ToResult(errcat.User.Newf("namespace %s is not mapped", ur.Namespace)), nil
+		ns = s.Namespace
+	}
+	namespace := s.ActualNamespace(ns)
+	if namespace == "" {
+		// namespace is not mapped
+		return nil, errcat.User.Newf("namespace %s is not mapped", ns)
+	}
+	return []string{namespace}, nil
+}
+
+// uninstallDryRun returns a Result with the JSON encoded names of the workloads with installed agents that
+// the given UninstallRequest would remove from the agents ConfigMaps in the given namespaces.
+func uninstallDryRun(
+	ur *rpc.UninstallRequest,
+	nss []string,
+	loadAgentConfigMap func(string) (*core.ConfigMap, error),
+) (*common.Result, error) {
+	names := []string{}
+	for _, ns := range nss {
+		cm, err := loadAgentConfigMap(ns)
+		if err != nil {
+			return errcat.ToResult(err), nil
 		}
-		return errcat.ToResult(clearAgentsConfigMap(namespace)), nil
-	} else {
-		// Load all effected configmaps
-		for _, ns := range s.GetCurrentNamespaces(true) {
-			err := clearAgentsConfigMap(ns)
-			if err != nil {
-				return errcat.ToResult(err), nil
+		if cm == nil {
+			continue
+		}
+		for name := range cm.Data {
+			if ur.UninstallType == rpc.UninstallRequest_ALL_AGENTS || slices.Contains(ur.Agents, name) {
+				names = append(names, name)
 			}
 		}
 	}
-	return errcat.ToResult(nil), nil
+	sort.Strings(names)
+	data, err := json.Marshal(names)
+	if err != nil {
+		return errcat.ToResult(err), nil
+	}
+	return &common.Result{Data: data}, nil
 }
 
 func (s *session) getNetworkInfo(ctx context.Context, cr *rpc.ConnectRequest) *rootdRpc.NetworkConfig {
@@ -1239,19 +1387,13 @@ func (s *session) workloadsWatcher(ctx context.Context, namespace string, synced
 				dlog.Debugf(ctx, "Deleting workload %s/%s.%s", key.kind, key.name, namespace)
 				delete(workloads, key)
 			} else {
-				var clients []string
-				if lc := len(w.InterceptClients); lc > 0 {
-					clients = make([]string, lc)
-					for i, ic := range w.InterceptClients {
-						clients[i] = ic.Client
-					}
-				}
 				dlog.Debugf(ctx, "Adding workload %s/%s.%s", key.kind, key.name, namespace)
 				workloads[key] = workloadInfo{
 					uid:              types.UID(w.Uid),
 					state:            workload.StateFromRPC(w.State),
 					agentState:       w.AgentState,
-					interceptClients: clients,
+					interceptClients: w.InterceptClients,
+					agentOutdated:    w.AgentVersionMismatch,
 				}
 			}
 		}
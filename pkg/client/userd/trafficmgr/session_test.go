@@ -0,0 +1,57 @@
+package trafficmgr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+)
+
+func Test_remainEvery(t *testing.T) {
+	t.Run("calls remain on each tick", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(dlog.NewTestContext(t, false))
+		tick := make(chan time.Time)
+		calls := make(chan struct{})
+		done := make(chan error, 1)
+		go func() {
+			done <- remainEvery(ctx, tick, func(context.Context) error {
+				calls <- struct{}{}
+				return nil
+			})
+		}()
+		for range 3 {
+			tick <- time.Now()
+			<-calls
+		}
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("returns the remain error", func(t *testing.T) {
+		tick := make(chan time.Time, 1)
+		tick <- time.Now()
+		err := remainEvery(dlog.NewTestContext(t, false), tick, func(context.Context) error {
+			return ErrSessionExpired
+		})
+		assert.ErrorIs(t, err, ErrSessionExpired)
+	})
+}
+
+func TestKeepaliveInterval(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	def := client.GetDefaultConfig().Timeouts().Get(client.TimeoutKeepalive)
+
+	cfg := client.GetDefaultConfig()
+	assert.Equal(t, def, KeepaliveInterval(client.WithConfig(ctx, cfg)))
+
+	cfg.Timeouts().PrivateKeepalive = 30 * time.Second
+	assert.Equal(t, 30*time.Second, KeepaliveInterval(client.WithConfig(ctx, cfg)))
+
+	cfg.Timeouts().PrivateKeepalive = 0
+	assert.Equal(t, def, KeepaliveInterval(client.WithConfig(ctx, cfg)))
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
@@ -20,6 +21,7 @@ type Interceptor interface {
 	InterceptInfo() *restapi.InterceptInfo
 	Serve(context.Context, chan<- net.Addr) error
 	SetIntercepting(*manager.InterceptInfo)
+	SetKeepAlive(time.Duration)
 	SetStreamProvider(tunnel.ClientStreamProvider)
 	Target() (string, uint16)
 }
@@ -37,6 +39,10 @@ type interceptor struct {
 	targetPort     uint16
 	streamProvider tunnel.ClientStreamProvider
 
+	// keepAlive is the TCP keepalive period used for forwarded connections. Zero means
+	// that the system default is used, and a negative value disables keepalive.
+	keepAlive time.Duration
+
 	intercept *manager.InterceptInfo
 }
 
@@ -57,6 +63,14 @@ func (f *interceptor) SetStreamProvider(streamProvider tunnel.ClientStreamProvid
 	f.mu.Unlock()
 }
 
+// SetKeepAlive sets the TCP keepalive period of connections that are forwarded after this call.
+// It has no effect on UDP forwarders.
+func (f *interceptor) SetKeepAlive(keepAlive time.Duration) {
+	f.mu.Lock()
+	f.keepAlive = keepAlive
+	f.mu.Unlock()
+}
+
 func (f *interceptor) Close() error {
 	f.lCancel()
 	return nil
@@ -92,7 +92,11 @@ func (f *tcp) forwardConn(clientConn *net.TCPConn) error {
 	targetHost := f.targetHost
 	targetPort := f.targetPort
 	intercept := f.intercept
+	keepAlive := f.keepAlive
 	f.mu.Unlock()
+	if err := setKeepAlive(clientConn, keepAlive); err != nil {
+		dlog.Debugf(ctx, "unable to set keepalive on %s: %v", clientConn.RemoteAddr(), err)
+	}
 	if intercept != nil {
 		return f.interceptConn(ctx, clientConn, intercept)
 	}
@@ -114,6 +118,9 @@ func (f *tcp) forwardConn(clientConn *net.TCPConn) error {
 		return fmt.Errorf("error on dial: %w", err)
 	}
 	defer targetConn.Close()
+	if err := setKeepAlive(targetConn, keepAlive); err != nil {
+		dlog.Debugf(ctx, "unable to set keepalive on %s: %v", targetAddr, err)
+	}
 
 	done := make(chan struct{})
 
@@ -184,3 +191,21 @@ func (f *tcp) interceptConn(ctx context.Context, conn net.Conn, iCept *manager.I
 	})
 	return nil
 }
+
+// setKeepAlive configures TCP keepalive on the given connection so that connections that go
+// half-open, e.g. across a NAT, are detected and closed. A zero period leaves the system default
+// in place, and a negative period disables keepalive.
+func setKeepAlive(conn *net.TCPConn, period time.Duration) error {
+	switch {
+	case period == 0:
+		return nil
+	case period < 0:
+		return conn.SetKeepAlive(false)
+	default:
+		return conn.SetKeepAliveConfig(net.KeepAliveConfig{
+			Enable:   true,
+			Idle:     period,
+			Interval: period,
+		})
+	}
+}
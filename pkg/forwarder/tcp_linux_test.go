@@ -0,0 +1,56 @@
+package forwarder
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func getSockOpt(t *testing.T, conn *net.TCPConn, level, opt int) int {
+	t.Helper()
+	rc, err := conn.SyscallConn()
+	require.NoError(t, err)
+	var value int
+	var optErr error
+	require.NoError(t, rc.Control(func(fd uintptr) {
+		value, optErr = unix.GetsockoptInt(int(fd), level, opt)
+	}))
+	require.NoError(t, optErr)
+	return value
+}
+
+func dialLoopback(t *testing.T) *net.TCPConn {
+	t.Helper()
+	l, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	conn, err := net.DialTCP("tcp", nil, l.Addr().(*net.TCPAddr))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func Test_setKeepAlive(t *testing.T) {
+	t.Run("period", func(t *testing.T) {
+		conn := dialLoopback(t)
+		require.NoError(t, setKeepAlive(conn, 7*time.Second))
+		assert.Equal(t, 1, getSockOpt(t, conn, unix.SOL_SOCKET, unix.SO_KEEPALIVE))
+		assert.Equal(t, 7, getSockOpt(t, conn, unix.IPPROTO_TCP, unix.TCP_KEEPIDLE))
+		assert.Equal(t, 7, getSockOpt(t, conn, unix.IPPROTO_TCP, unix.TCP_KEEPINTVL))
+	})
+	t.Run("disabled", func(t *testing.T) {
+		conn := dialLoopback(t)
+		require.NoError(t, setKeepAlive(conn, -1))
+		assert.Equal(t, 0, getSockOpt(t, conn, unix.SOL_SOCKET, unix.SO_KEEPALIVE))
+	})
+	t.Run("default", func(t *testing.T) {
+		conn := dialLoopback(t)
+		idle := getSockOpt(t, conn, unix.IPPROTO_TCP, unix.TCP_KEEPIDLE)
+		require.NoError(t, setKeepAlive(conn, 0))
+		assert.Equal(t, idle, getSockOpt(t, conn, unix.IPPROTO_TCP, unix.TCP_KEEPIDLE))
+	})
+}
@@ -5,13 +5,26 @@ import (
 	"strconv"
 )
 
+// JoinIpPort returns the "host:port" form of the given IP and port. IPv6 addresses are enclosed
+// in brackets.
 func JoinIpPort(ip net.IP, port uint16) string {
+	return JoinIpZonePort(ip, "", port)
+}
+
+// JoinIpZonePort is like JoinIpPort but adds the given zone to IPv6 addresses, e.g. "[fe80::1%eth0]:22".
+// A link-local IPv6 address is only reachable through a specific interface, and must therefore have a
+// zone to be dialed. The zone is ignored for IPv4 addresses.
+func JoinIpZonePort(ip net.IP, zone string, port uint16) string {
 	ps := strconv.Itoa(int(port))
 	if ip4 := ip.To4(); ip4 != nil {
 		return ip4.String() + ":" + ps
 	}
 	if ip16 := ip.To16(); ip16 != nil {
-		return "[" + ip16.String() + "]:" + ps
+		host := ip16.String()
+		if zone != "" {
+			host += "%" + zone
+		}
+		return "[" + host + "]:" + ps
 	}
 	return ":" + ps
 }
@@ -0,0 +1,26 @@
+package iputil
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinIpPort(t *testing.T) {
+	assert.Equal(t, "10.1.2.3:22", JoinIpPort(net.ParseIP("10.1.2.3"), 22))
+	assert.Equal(t, "[fd00::1]:22", JoinIpPort(net.ParseIP("fd00::1"), 22))
+	assert.Equal(t, ":22", JoinIpPort(nil, 22))
+}
+
+func TestJoinIpZonePort(t *testing.T) {
+	assert.Equal(t, "[fe80::1%eth0]:22", JoinIpZonePort(net.ParseIP("fe80::1"), "eth0", 22))
+	assert.Equal(t, "[fe80::1]:22", JoinIpZonePort(net.ParseIP("fe80::1"), "", 22))
+	assert.Equal(t, "10.1.2.3:22", JoinIpZonePort(net.ParseIP("10.1.2.3"), "eth0", 22))
+
+	// The result can be split and resolved by the net package.
+	addr, err := net.ResolveTCPAddr("tcp6", JoinIpZonePort(net.ParseIP("fe80::1"), "eth0", 22))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "eth0", addr.Zone)
+	}
+}
@@ -2,6 +2,7 @@ package proc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec" //nolint:depguard // We want no logging and no soft-context signal handling
@@ -69,13 +70,48 @@ func Wait(ctx context.Context, cancel context.CancelFunc, cmd *dexec.Cmd) error
 		return fmt.Errorf("%s: %w", shellquote.ShellString(cmd.Path, cmd.Args), err)
 	}
 
-	exitCode := s.ExitCode()
-	if exitCode != 0 {
-		return fmt.Errorf("%s: exited with %d", shellquote.ShellString(cmd.Path, cmd.Args), exitCode)
+	if !s.Success() {
+		return fmt.Errorf("%s: %w", shellquote.ShellString(cmd.Path, cmd.Args), &exec.ExitError{ProcessState: s})
 	}
 	return nil
 }
 
+// HandlerExitError is the error of an intercept or ingest handler, or of a docker-run container, that exited
+// with a non-zero exit code. The CLI exits with that same code.
+type HandlerExitError struct {
+	error
+	ExitCode int
+}
+
+func (e *HandlerExitError) Unwrap() error {
+	return e.error
+}
+
+// HandlerExit returns a HandlerExitError when the given error is caused by a process that exited with a
+// non-zero exit code. Other errors are returned unchanged.
+func HandlerExit(err error) error {
+	var ee *exec.ExitError
+	if errors.As(err, &ee) && ee.ExitCode() > 0 {
+		return &HandlerExitError{error: err, ExitCode: ee.ExitCode()}
+	}
+	return err
+}
+
+// Terminated returns true when the given error is caused by a process that was terminated using Terminate, or
+// killed, or by a docker run whose container was stopped or killed.
+func Terminated(err error) bool {
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		return false
+	}
+	switch ee.ExitCode() {
+	case 128 + 9, 128 + 15:
+		// A docker run exits with 128 + the number of the signal that ended its container.
+		return true
+	}
+	return terminated(ee.ProcessState)
+}
+
 // CreateNewProcessGroup ensures that the process uses a process group of its own to prevent
 // it getting affected by <ctrl-c> in the terminal.
 func CreateNewProcessGroup(cmd *exec.Cmd) {
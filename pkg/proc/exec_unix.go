@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec" //nolint:depguard // We want no logging and no soft-context signal handling
+	"syscall" //nolint:depguard // We specifically need "syscall.WaitStatus" rather than "unix.WaitStatus" for os.ProcessState.Sys().
 
 	"golang.org/x/sys/unix"
 
@@ -62,6 +63,11 @@ func terminate(p *os.Process) error {
 	return p.Signal(unix.SIGTERM)
 }
 
+func terminated(ps *os.ProcessState) bool {
+	ws, ok := ps.Sys().(syscall.WaitStatus)
+	return ok && ws.Signaled() && (ws.Signal() == unix.SIGTERM || ws.Signal() == unix.SIGKILL)
+}
+
 func createNewProcessGroup(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &unix.SysProcAttr{
 		Setpgid: true,
@@ -80,6 +80,11 @@ func terminate(p *os.Process) error {
 	return p.Kill()
 }
 
+func terminated(ps *os.ProcessState) bool {
+	// Terminate kills the process, and a killed process exits with exit code 1.
+	return ps.ExitCode() == 1
+}
+
 const peSize = uint32(unsafe.Sizeof(windows.ProcessEntry32{}))
 
 type processInfo struct {
@@ -2,9 +2,13 @@
 package subnet
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"net"
 	"net/netip"
+	"slices"
 	"sort"
 )
 
@@ -101,6 +105,55 @@ func Unique(subnets []netip.Prefix) []netip.Prefix {
 	return subnets[:ln]
 }
 
+// Aggregate merges the given subnets into as few subnets as possible without changing the set of
+// addresses that they cover. Subnets that are covered by other subnets are dropped, and two subnets
+// that are the two halves of a larger subnet are replaced by that subnet. Two halves are never merged
+// when the resulting subnet overlaps one of the subnets to avoid. The given slice is not altered and
+// the returned slice is sorted.
+func Aggregate(subnets, avoid []netip.Prefix) []netip.Prefix {
+	result := make([]netip.Prefix, len(subnets))
+	for i, sn := range subnets {
+		result[i] = sn.Masked()
+	}
+	result = Unique(result)
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(result) && !merged; i++ {
+			a := result[i]
+			bits := a.Bits()
+			if bits == 0 {
+				continue
+			}
+			parent, _ := a.Addr().Prefix(bits - 1)
+			if slices.ContainsFunc(avoid, parent.Overlaps) {
+				continue
+			}
+			for j := i + 1; j < len(result); j++ {
+				b := result[j]
+				if b.Bits() == bits && b.Addr().Is4() == a.Addr().Is4() {
+					if bp, _ := b.Addr().Prefix(bits - 1); bp == parent {
+						result[i] = parent
+						result = slices.Delete(result, j, j+1)
+						merged = true
+						break
+					}
+				}
+			}
+		}
+		if merged {
+			// The new subnet may cover other subnets.
+			result = Unique(result)
+		}
+	}
+	slices.SortFunc(result, func(a, b netip.Prefix) int {
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c
+		}
+		return a.Bits() - b.Bits()
+	})
+	return result
+}
+
 // Partition returns two slices, the first containing the subnets for which the filter evaluates
 // to true, the second containing the rest.
 func Partition[T any](subnets []T, filter func(int, T) bool) (matched, notMatched []T) {
@@ -214,6 +267,51 @@ func RandomIPv4Prefix(bits int, avoid []netip.Prefix) (netip.Prefix, error) {
 	return netip.Prefix{}, fmt.Errorf("unable to find a free subnet")
 }
 
+// ULA is the IPv6 Unique Local Address range. See https://www.rfc-editor.org/rfc/rfc4193.
+var ULA = netip.MustParsePrefix("fc00::/7") //nolint:gochecknoglobals // constant
+
+// maxULAAttempts is the number of random ULA subnets that RandomULAPrefix will try before giving up.
+const maxULAAttempts = 64
+
+// RandomULAPrefix finds a random free IPv6 subnet with a 64-bit mask in the locally assigned half
+// (fd00::/8) of the Unique Local Address range. As with RandomIPv4Prefix, a subnet is considered free
+// if it doesn't overlap with any of the subnets returned by the net.InterfaceAddrs function or with
+// any of the subnets provided in the avoid parameter.
+func RandomULAPrefix(avoid []netip.Prefix) (netip.Prefix, error) {
+	as, err := net.InterfaceAddrs()
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	cidrs := make([]netip.Prefix, 0, len(as)+len(avoid))
+	for _, a := range as {
+		if cidr, err := netip.ParsePrefix(a.String()); err == nil {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	cidrs = append(cidrs, avoid...)
+
+	for i := 0; i < maxULAAttempts; i++ {
+		// 0xfd followed by a random 40-bit global ID and a random 16-bit subnet ID.
+		var ip [16]byte
+		ip[0] = 0xfd
+		if _, err = rand.Read(ip[1:8]); err != nil {
+			return netip.Prefix{}, err
+		}
+		sn := netip.PrefixFrom(netip.AddrFrom16(ip), 64)
+		inUse := false
+		for _, cidr := range cidrs {
+			if cidr.Overlaps(sn) {
+				inUse = true
+				break
+			}
+		}
+		if !inUse {
+			return sn, nil
+		}
+	}
+	return netip.Prefix{}, fmt.Errorf("unable to find a free IPv6 ULA subnet")
+}
+
 // IsHalfOfDefault route returns true if the given subnet covers half the address space with a /1 mask.
 func IsHalfOfDefault(n netip.Prefix) bool {
 	return n.Bits() == 1
@@ -229,3 +327,26 @@ func PrefixToIPNet(p netip.Prefix) *net.IPNet {
 		Mask: net.CIDRMask(p.Bits(), a.BitLen()),
 	}
 }
+
+// LookupFunc resolves a host into its IP addresses. It has the same signature as net.Resolver.LookupNetIP.
+type LookupFunc func(ctx context.Context, network, host string) ([]netip.Addr, error)
+
+// ResolveHosts resolves the given hosts using the given lookup function and returns a single address
+// prefix (/32 or /128) for each unique address found. Hosts that cannot be resolved are reported in
+// the returned error, but don't prevent the prefixes of the remaining hosts from being returned.
+func ResolveHosts(ctx context.Context, lookup LookupFunc, hosts []string) ([]netip.Prefix, error) {
+	var pfxs []netip.Prefix
+	var errs []error
+	for _, host := range hosts {
+		addrs, err := lookup(ctx, "ip", host)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to resolve host %q: %w", host, err))
+			continue
+		}
+		for _, addr := range addrs {
+			addr = addr.Unmap()
+			pfxs = append(pfxs, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return Unique(pfxs), errors.Join(errs...)
+}
@@ -2,9 +2,12 @@ package subnet
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"net/netip"
 	"os"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -128,3 +131,113 @@ func TestUnique(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregate(t *testing.T) {
+	pfx := func(ss ...string) []netip.Prefix {
+		ps := make([]netip.Prefix, len(ss))
+		for i, s := range ss {
+			ps[i] = netip.MustParsePrefix(s)
+		}
+		return ps
+	}
+	tests := []struct {
+		name    string
+		subnets []netip.Prefix
+		avoid   []netip.Prefix
+		want    []netip.Prefix
+	}{
+		{
+			name:    "Merges adjacent halves",
+			subnets: pfx("10.1.1.0/24", "10.1.0.0/24"),
+			want:    pfx("10.1.0.0/23"),
+		},
+		{
+			name:    "Merges repeatedly",
+			subnets: pfx("10.1.0.0/24", "10.1.1.0/24", "10.1.2.0/24", "10.1.3.0/24", "10.1.4.0/24"),
+			want:    pfx("10.1.0.0/22", "10.1.4.0/24"),
+		},
+		{
+			name:    "Drops contained and overlapping",
+			subnets: pfx("10.1.0.0/16", "10.1.2.0/24", "10.2.0.0/24", "10.2.0.128/25", "10.2.1.0/24"),
+			want:    pfx("10.1.0.0/16", "10.2.0.0/23"),
+		},
+		{
+			name:    "Does not merge non-sibling neighbours",
+			subnets: pfx("10.1.1.0/24", "10.1.2.0/24"),
+			want:    pfx("10.1.1.0/24", "10.1.2.0/24"),
+		},
+		{
+			name:    "Does not mix address families",
+			subnets: pfx("0.0.0.0/1", "128.0.0.0/1", "::/1", "8000::/1"),
+			want:    pfx("0.0.0.0/0", "::/0"),
+		},
+		{
+			name:    "Masks host bits",
+			subnets: pfx("10.1.0.17/24", "10.1.1.0/24"),
+			want:    pfx("10.1.0.0/23"),
+		},
+		{
+			name:    "Never proxy prevents merge",
+			subnets: pfx("10.1.0.0/24", "10.1.1.0/24", "10.1.2.0/24", "10.1.3.0/24"),
+			avoid:   pfx("10.1.3.5/32"),
+			want:    pfx("10.1.0.0/23", "10.1.2.0/24", "10.1.3.0/24"),
+		},
+		{
+			name:    "Never proxy outside of subnets doesn't prevent merge",
+			subnets: pfx("10.1.0.0/24", "10.1.1.0/24"),
+			avoid:   pfx("10.1.2.0/24"),
+			want:    pfx("10.1.0.0/23"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := slices.Clone(tt.subnets)
+			assert.Equal(t, tt.want, Aggregate(tt.subnets, tt.avoid))
+			assert.Equal(t, orig, tt.subnets)
+		})
+	}
+}
+
+func TestRandomULAPrefix(t *testing.T) {
+	// Avoid the lower half of fd00::/8 so that the chosen subnet must end up in the upper half.
+	avoid := []netip.Prefix{netip.MustParsePrefix("fd00::/9")}
+	for i := 0; i < 20; i++ {
+		sn, err := RandomULAPrefix(avoid)
+		require.NoError(t, err)
+		assert.Equal(t, 64, sn.Bits())
+		assert.True(t, ULA.Contains(sn.Addr()), "%s is not a ULA subnet", sn)
+		assert.Equal(t, sn.Masked(), sn)
+		for _, a := range avoid {
+			assert.False(t, a.Overlaps(sn), "%s overlaps %s", sn, a)
+		}
+	}
+
+	_, err := RandomULAPrefix([]netip.Prefix{ULA})
+	assert.Error(t, err)
+}
+
+func TestResolveHosts(t *testing.T) {
+	lookup := func(_ context.Context, network, host string) ([]netip.Addr, error) {
+		assert.Equal(t, "ip", network)
+		switch host {
+		case "proxy.corp.example.com":
+			return []netip.Addr{netip.MustParseAddr("10.20.30.40"), netip.MustParseAddr("fd00::1")}, nil
+		case "mapped.corp.example.com":
+			return []netip.Addr{netip.MustParseAddr("::ffff:10.20.30.41")}, nil
+		default:
+			return nil, errors.New("no such host")
+		}
+	}
+
+	pfxs, err := ResolveHosts(context.Background(), lookup, []string{"proxy.corp.example.com", "mapped.corp.example.com"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []netip.Prefix{
+		netip.MustParsePrefix("10.20.30.40/32"),
+		netip.MustParsePrefix("10.20.30.41/32"),
+		netip.MustParsePrefix("fd00::1/128"),
+	}, pfxs)
+
+	pfxs, err = ResolveHosts(context.Background(), lookup, []string{"unknown.example.com", "proxy.corp.example.com"})
+	require.ErrorContains(t, err, `"unknown.example.com"`)
+	assert.Len(t, pfxs, 2)
+}
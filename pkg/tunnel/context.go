@@ -16,3 +16,21 @@ func GetPool(ctx context.Context) *Pool {
 	}
 	return pool
 }
+
+type dialObserverKey struct{}
+
+// DialObserver is called when a connection that was dialed in response to a DialRequest has ended.
+type DialObserver func(ctx context.Context, summary *DialSummary)
+
+// WithDialObserver returns a context with the given DialObserver.
+func WithDialObserver(ctx context.Context, observer DialObserver) context.Context {
+	return context.WithValue(ctx, dialObserverKey{}, observer)
+}
+
+func getDialObserver(ctx context.Context) DialObserver {
+	observer, ok := ctx.Value(dialObserverKey{}).(DialObserver)
+	if !ok {
+		return nil
+	}
+	return observer
+}
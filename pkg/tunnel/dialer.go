@@ -359,7 +359,97 @@ func dialRespond(ctx context.Context, tunnelProvider Provider, dr *rpc.DialReque
 		cancel()
 		return
 	}
-	d := NewDialer(s, cancel, nil, nil)
+	observer := getDialObserver(ctx)
+	if observer == nil {
+		d := NewDialer(s, cancel, nil, nil)
+		d.Start(ctx)
+		<-d.Done()
+		return
+	}
+
+	ingressBytes := NewCounterProbe("FromPeerBytes")
+	egressBytes := NewCounterProbe("ToPeerBytes")
+	hs := &headStream{Stream: s}
+	start := time.Now()
+	d := NewDialer(hs, cancel, ingressBytes, egressBytes)
 	d.Start(ctx)
 	<-d.Done()
+	ingressHead, egressHead := hs.heads()
+	observer(ctx, &DialSummary{
+		ID:           id,
+		Start:        start,
+		Duration:     time.Since(start),
+		IngressBytes: ingressBytes.GetValue(),
+		EgressBytes:  egressBytes.GetValue(),
+		IngressHead:  ingressHead,
+		EgressHead:   egressHead,
+	})
+}
+
+// dialSummaryHeadSize is the maximum number of bytes that a DialSummary retains from the start of each direction.
+const dialSummaryHeadSize = 512
+
+// headStream retains the first bytes of the payloads that are received and sent on the wrapped Stream.
+type headStream struct {
+	Stream
+	sync.Mutex
+	ingress []byte
+	egress  []byte
+}
+
+func (hs *headStream) Receive(ctx context.Context) (Message, error) {
+	m, err := hs.Stream.Receive(ctx)
+	if err == nil && m.Code() == Normal {
+		hs.Lock()
+		hs.ingress = appendHead(hs.ingress, m.Payload())
+		hs.Unlock()
+	}
+	return m, err
+}
+
+func (hs *headStream) Send(ctx context.Context, m Message) error {
+	if m.Code() == Normal {
+		hs.Lock()
+		hs.egress = appendHead(hs.egress, m.Payload())
+		hs.Unlock()
+	}
+	return hs.Stream.Send(ctx, m)
+}
+
+func (hs *headStream) heads() (ingress, egress []byte) {
+	hs.Lock()
+	defer hs.Unlock()
+	return hs.ingress, hs.egress
+}
+
+// appendHead appends the given data to the head until it reaches the dialSummaryHeadSize.
+func appendHead(head, data []byte) []byte {
+	if n := dialSummaryHeadSize - len(head); n > 0 {
+		if len(data) > n {
+			data = data[:n]
+		}
+		head = append(head, data...)
+	}
+	return head
+}
+
+// DialSummary summarizes a connection that was dialed in response to a DialRequest.
+type DialSummary struct {
+	ID    ConnID
+	Start time.Time
+
+	// Duration is the time from when the dial was initiated until the connection ended.
+	Duration time.Duration
+
+	// IngressBytes is the number of bytes received from the peer and written to the dialed connection.
+	IngressBytes uint64
+
+	// EgressBytes is the number of bytes read from the dialed connection and sent to the peer.
+	EgressBytes uint64
+
+	// IngressHead contains the first bytes, at most 512, that were received from the peer.
+	IngressHead []byte
+
+	// EgressHead contains the first bytes, at most 512, that were sent to the peer.
+	EgressHead []byte
 }
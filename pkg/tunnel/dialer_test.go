@@ -0,0 +1,108 @@
+package tunnel
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/ipproto"
+	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
+)
+
+type testTunnelClient struct {
+	grpc.ClientStream
+	*clientSide
+}
+
+func (c testTunnelClient) CloseSend() error {
+	return c.clientSide.CloseSend()
+}
+
+type testProvider struct {
+	*bidi
+}
+
+func (p testProvider) Tunnel(context.Context, ...grpc.CallOption) (Client, error) {
+	return testTunnelClient{clientSide: &clientSide{p.bidi}}, nil
+}
+
+func TestDialRespond_Observer(t *testing.T) {
+	ctx, cancel := testContext(t, 10*time.Second)
+	defer cancel()
+
+	request := []byte("hello")
+	response := []byte("hello yourself")
+
+	// A local handler that reads a request, writes a response, and then closes the connection.
+	l, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, len(request))
+		if _, err = io.ReadFull(conn, buf); err == nil {
+			_, _ = conn.Write(response)
+		}
+	}()
+
+	lAddr := l.Addr().(*net.TCPAddr)
+	id := NewConnID(ipproto.TCP, iputil.Parse("192.168.0.1"), lAddr.IP, 1001, uint16(lAddr.Port))
+
+	summaries := make(chan *DialSummary, 1)
+	ctx = WithDialObserver(ctx, func(_ context.Context, ds *DialSummary) {
+		summaries <- ds
+	})
+
+	tunnel := newBidi(10, ctx.Done())
+	go dialRespond(ctx, testProvider{tunnel}, &manager.DialRequest{ConnId: []byte(id)}, uuid.New().String())
+
+	server, err := NewServerStream(ctx, tunnel.serverSide())
+	require.NoError(t, err)
+	require.NoError(t, server.Send(ctx, NewMessage(Normal, request)))
+
+	var received []byte
+	incoming, _ := ReadLoop(ctx, server, nil)
+	for m := range incoming {
+		if m.Code() == Normal {
+			received = append(received, m.Payload()...)
+		}
+	}
+	assert.Equal(t, response, received)
+	require.NoError(t, server.CloseSend(ctx))
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for dial summary")
+	case ds := <-summaries:
+		assert.Equal(t, id, ds.ID)
+		assert.Equal(t, uint64(len(request)), ds.IngressBytes)
+		assert.Equal(t, uint64(len(response)), ds.EgressBytes)
+		assert.Equal(t, request, ds.IngressHead)
+		assert.Equal(t, response, ds.EgressHead)
+		assert.False(t, ds.Start.IsZero())
+		assert.Positive(t, ds.Duration)
+	}
+}
+
+func TestAppendHead(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 300)
+	head := appendHead(nil, data)
+	assert.Len(t, head, 300)
+	head = appendHead(head, data)
+	assert.Len(t, head, dialSummaryHeadSize)
+	head = appendHead(head, data)
+	assert.Len(t, head, dialSummaryHeadSize)
+}
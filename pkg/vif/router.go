@@ -7,6 +7,7 @@ import (
 	"net/netip"
 	"runtime"
 	"slices"
+	"strings"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
@@ -25,6 +26,28 @@ type Router struct {
 	routedSubnets []netip.Prefix
 	// The subnets that are allowed to be routed even in the presence of conflicting routes
 	whitelistedSubnets []netip.Prefix
+	// Merge adjacent and contained subnets before they are routed
+	aggregate bool
+	// The reason why each subnet passed to UpdateRoutes is routed
+	sources map[netip.Prefix]SubnetSource
+}
+
+// SubnetSource describes why a subnet is routed.
+type SubnetSource string
+
+const (
+	SubnetSourceService   SubnetSource = "service"
+	SubnetSourcePod       SubnetSource = "pod"
+	SubnetSourceAlsoProxy SubnetSource = "also-proxy"
+	SubnetSourceVirtual   SubnetSource = "virtual"
+	SubnetSourceProxyVia  SubnetSource = "proxy-via"
+	SubnetSourceDNS       SubnetSource = "dns"
+)
+
+// RoutedSubnet is a subnet that is routed by the Router, labeled with the reason why it is routed.
+type RoutedSubnet struct {
+	Subnet netip.Prefix
+	Source SubnetSource
 }
 
 func NewRouter(device Device, table routing.Table) *Router {
@@ -35,17 +58,92 @@ func (rt *Router) GetRoutedSubnets() []netip.Prefix {
 	return rt.routedSubnets
 }
 
+// SetSubnetSources records why each of the subnets that will be passed to UpdateRoutes is routed.
+func (rt *Router) SetSubnetSources(sources map[netip.Prefix]SubnetSource) {
+	rt.sources = sources
+}
+
+// GetRoutedSubnetSources returns the subnets that are currently being routed, each labeled with its source.
+// A subnet that is the result of an aggregation is labeled with the sources of the subnets that it contains.
+func (rt *Router) GetRoutedSubnetSources() []RoutedSubnet {
+	rs := make([]RoutedSubnet, len(rt.routedSubnets))
+	for i, sn := range rt.routedSubnets {
+		rs[i] = RoutedSubnet{Subnet: sn, Source: rt.sourceOf(sn)}
+	}
+	return rs
+}
+
+func (rt *Router) sourceOf(sn netip.Prefix) SubnetSource {
+	if src, ok := rt.sources[sn]; ok {
+		return src
+	}
+	var srcs []string
+	for csn, src := range rt.sources {
+		if subnet.Covers(sn, csn) && !slices.Contains(srcs, string(src)) {
+			srcs = append(srcs, string(src))
+		}
+	}
+	slices.Sort(srcs)
+	return SubnetSource(strings.Join(srcs, ","))
+}
+
 func (rt *Router) UpdateWhitelist(whitelist []netip.Prefix) {
 	rt.whitelistedSubnets = whitelist
 }
 
+// SetAggregate controls whether the subnets passed to UpdateRoutes are merged into as few subnets as
+// possible before they are added to the device.
+func (rt *Router) SetAggregate(aggregate bool) {
+	rt.aggregate = aggregate
+}
+
+// RouteConflict describes an existing route that overlaps a subnet that is about to be routed.
+type RouteConflict struct {
+	// Subnet is the subnet that was requested.
+	Subnet netip.Prefix
+	// Route is the existing route that the subnet overlaps. It includes the interface and the gateway.
+	Route *routing.Route
+}
+
+func (c *RouteConflict) String() string {
+	return fmt.Sprintf("subnet %s overlaps with existing route %q", c.Subnet, c.Route)
+}
+
+// RouteConflictsError returns an error that describes all the given conflicts.
+func RouteConflictsError(conflicts []RouteConflict) error {
+	descs := make([]string, len(conflicts))
+	for i := range conflicts {
+		descs[i] = conflicts[i].String()
+	}
+	return errcat.Config.New(fmt.Sprintf(
+		"%s. Please see %s for more information",
+		strings.Join(descs, "; "), "https://www.telepresence.io/docs/reference/vpn",
+	))
+}
+
+// ValidateRoutes returns an error that describes the first conflict found by DescribeConflicts, if any.
 func (rt *Router) ValidateRoutes(ctx context.Context, routes []netip.Prefix) error {
+	conflicts, err := rt.DescribeConflicts(ctx, routes)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return RouteConflictsError(conflicts[:1])
+	}
+	return nil
+}
+
+// DescribeConflicts returns all conflicts between the given routes and the routes of the current routing table.
+func (rt *Router) DescribeConflicts(ctx context.Context, routes []netip.Prefix) ([]RouteConflict, error) {
 	// We need the entire table because we need to check for any overlaps, not just "is this IP already routed"
 	table, err := routing.GetRoutingTable(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return rt.describeConflicts(ctx, table, routes), nil
+}
 
+func (rt *Router) describeConflicts(ctx context.Context, table []*routing.Route, routes []netip.Prefix) (conflicts []RouteConflict) {
 	nonWhitelisted := slices.DeleteFunc(slices.Clone(routes), func(r netip.Prefix) bool {
 		for _, w := range rt.whitelistedSubnets {
 			if subnet.Covers(w, r) {
@@ -73,14 +171,11 @@ func (rt *Router) ValidateRoutes(ctx context.Context, routes []netip.Prefix) err
 		}
 		for _, r := range nonWhitelisted {
 			if tr.RoutedNet.Overlaps(r) {
-				return errcat.Config.New(fmt.Sprintf(
-					"subnet %s overlaps with existing route %q. Please see %s for more information",
-					r, tr, "https://www.telepresence.io/docs/reference/vpn",
-				))
+				conflicts = append(conflicts, RouteConflict{Subnet: r, Route: tr})
 			}
 		}
 	}
-	return nil
+	return conflicts
 }
 
 func (rt *Router) UpdateRoutes(ctx context.Context, pleaseProxy, dontProxy, dontProxyOverrides []netip.Prefix) error {
@@ -88,6 +183,15 @@ func (rt *Router) UpdateRoutes(ctx context.Context, pleaseProxy, dontProxy, dont
 	// that we're about to add.
 	rt.dropStaticOverrides(ctx)
 
+	if rt.aggregate {
+		// Subnets are never merged into a subnet that overlaps a never-proxied subnet.
+		aggregated := subnet.Aggregate(pleaseProxy, dontProxy)
+		if len(aggregated) < len(pleaseProxy) {
+			dlog.Debugf(ctx, "Aggregated %d subnets into %s", len(pleaseProxy), aggregated)
+		}
+		pleaseProxy = aggregated
+	}
+
 	// Remove all no longer desired subnets from the routedSubnets
 	var removed []netip.Prefix
 	rt.routedSubnets, removed = subnet.Partition(rt.routedSubnets, func(_ int, sn netip.Prefix) bool {
@@ -0,0 +1,78 @@
+package vif
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/routing"
+)
+
+type namedDevice struct {
+	Device
+	name string
+}
+
+func (d *namedDevice) Name() string {
+	return d.name
+}
+
+func TestRouter_describeConflicts(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	eth0 := &net.Interface{Index: 2, Name: "eth0"}
+	vpn0 := &net.Interface{Index: 3, Name: "vpn0"}
+	tun0 := &net.Interface{Index: 4, Name: "tun0"}
+	route := func(cidr, gw string, iface *net.Interface) *routing.Route {
+		r := &routing.Route{RoutedNet: netip.MustParsePrefix(cidr), LocalIP: netip.MustParseAddr("192.168.1.10"), Interface: iface}
+		if gw != "" {
+			r.Gateway = netip.MustParseAddr(gw)
+		}
+		return r
+	}
+	defaultRoute := route("0.0.0.0/0", "192.168.1.1", eth0)
+	defaultRoute.Default = true
+	lan := route("192.168.1.0/24", "", eth0)
+	vpnLow := route("10.0.0.0/16", "10.8.0.1", vpn0)
+	vpnHalf := route("128.0.0.0/1", "10.8.0.1", vpn0)
+	vpnHigh := route("10.1.2.0/24", "10.8.0.1", vpn0)
+	ours := route("10.96.0.0/16", "", tun0)
+	table := []*routing.Route{defaultRoute, lan, vpnLow, vpnHalf, vpnHigh, ours}
+
+	rt := NewRouter(&namedDevice{name: "tun0"}, nil)
+	pods := netip.MustParsePrefix("10.0.0.0/8")
+	svcs := netip.MustParsePrefix("10.96.0.0/16")
+	other := netip.MustParsePrefix("172.20.0.0/16")
+
+	t.Run("all conflicts", func(t *testing.T) {
+		conflicts := rt.describeConflicts(ctx, table, []netip.Prefix{pods, svcs, other})
+		require.Len(t, conflicts, 2)
+		assert.Equal(t, pods, conflicts[0].Subnet)
+		assert.Same(t, vpnLow, conflicts[0].Route)
+		assert.Equal(t, pods, conflicts[1].Subnet)
+		assert.Same(t, vpnHigh, conflicts[1].Route)
+		assert.Equal(t, "vpn0", conflicts[1].Route.Interface.Name)
+		assert.Equal(t, netip.MustParseAddr("10.8.0.1"), conflicts[1].Route.Gateway)
+	})
+
+	t.Run("no conflicts", func(t *testing.T) {
+		assert.Empty(t, rt.describeConflicts(ctx, table, []netip.Prefix{svcs, other}))
+	})
+
+	t.Run("whitelisted", func(t *testing.T) {
+		wrt := NewRouter(&namedDevice{name: "tun0"}, nil)
+		wrt.UpdateWhitelist([]netip.Prefix{pods})
+		assert.Empty(t, wrt.describeConflicts(ctx, table, []netip.Prefix{pods}))
+	})
+
+	t.Run("error describes all conflicts", func(t *testing.T) {
+		err := RouteConflictsError(rt.describeConflicts(ctx, table, []netip.Prefix{pods}))
+		assert.EqualError(t, err, ``+
+			`subnet 10.0.0.0/8 overlaps with existing route "10.0.0.0/16 via 192.168.1.10 dev vpn0, gw 10.8.0.1"; `+
+			`subnet 10.0.0.0/8 overlaps with existing route "10.1.2.0/24 via 192.168.1.10 dev vpn0, gw 10.8.0.1". `+
+			`Please see https://www.telepresence.io/docs/reference/vpn for more information`)
+	})
+}
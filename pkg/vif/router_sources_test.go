@@ -0,0 +1,58 @@
+package vif
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_GetRoutedSubnetSources(t *testing.T) {
+	pods := netip.MustParsePrefix("10.0.0.0/16")
+	svcs := netip.MustParsePrefix("10.96.0.0/16")
+	virtual := netip.MustParsePrefix("211.55.0.0/16")
+	proxyVia := netip.MustParsePrefix("fd7a:115c:a1e0::/64")
+	also1 := netip.MustParsePrefix("192.168.4.0/24")
+	also2 := netip.MustParsePrefix("192.168.5.0/24")
+	rt := NewRouter(&namedDevice{name: "tun0"}, nil)
+	rt.SetSubnetSources(map[netip.Prefix]SubnetSource{
+		pods:     SubnetSourcePod,
+		svcs:     SubnetSourceService,
+		virtual:  SubnetSourceVirtual,
+		proxyVia: SubnetSourceProxyVia,
+		also1:    SubnetSourceAlsoProxy,
+		also2:    SubnetSourceAlsoProxy,
+	})
+
+	t.Run("virtual is distinct from cluster", func(t *testing.T) {
+		rt.routedSubnets = []netip.Prefix{pods, svcs, virtual}
+		assert.Equal(t, []RoutedSubnet{
+			{Subnet: pods, Source: SubnetSourcePod},
+			{Subnet: svcs, Source: SubnetSourceService},
+			{Subnet: virtual, Source: SubnetSourceVirtual},
+		}, rt.GetRoutedSubnetSources())
+	})
+
+	t.Run("proxy-via is distinct from virtual", func(t *testing.T) {
+		rt.routedSubnets = []netip.Prefix{virtual, proxyVia}
+		assert.Equal(t, []RoutedSubnet{
+			{Subnet: virtual, Source: SubnetSourceVirtual},
+			{Subnet: proxyVia, Source: SubnetSourceProxyVia},
+		}, rt.GetRoutedSubnetSources())
+	})
+
+	t.Run("aggregated", func(t *testing.T) {
+		rt.routedSubnets = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.4.0/23")}
+		assert.Equal(t, []RoutedSubnet{
+			{Subnet: netip.MustParsePrefix("10.0.0.0/8"), Source: "pod,service"},
+			{Subnet: netip.MustParsePrefix("192.168.4.0/23"), Source: SubnetSourceAlsoProxy},
+		}, rt.GetRoutedSubnetSources())
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		rt.routedSubnets = []netip.Prefix{netip.MustParsePrefix("172.20.0.0/16")}
+		assert.Equal(t, []RoutedSubnet{
+			{Subnet: netip.MustParsePrefix("172.20.0.0/16")},
+		}, rt.GetRoutedSubnetSources())
+	})
+}
@@ -110,6 +110,30 @@ func (s *RoutingSuite) Test_RouteIsBlackListed() {
 	s.Require().NotEqual(device, route.Interface.Name)
 }
 
+func (s *RoutingSuite) Test_NeverProxyHostIsStaticOverride() {
+	ctx := context.Background()
+	cidrYes := getCidr(2, 0, 24)
+	cidrNo := getCidr(2, 4, 32)
+	oldRoute, err := routing.GetRoute(ctx, cidrNo)
+	s.Require().NoError(err)
+
+	// The router resolves the host using a stub resolver, which keeps the test independent of DNS.
+	host := "never-proxy.example.test"
+	device, routerCancel, err := s.runRouter(ctx, "@"+host+"="+cidrNo.Addr().String(), cidrYes.String(), "!"+host)
+	s.Require().NoError(err)
+	defer routerCancel()
+
+	// The address that the host resolved to is routed as before, while its neighbours are routed to the device.
+	route, err := routing.GetRoute(ctx, cidrNo)
+	s.Require().NoError(err)
+	s.Require().Equal(oldRoute.Interface.Name, route.Interface.Name, "Expected route %s got %s", oldRoute, route)
+	s.Require().NotEqual(device, route.Interface.Name)
+
+	route, err = routing.GetRoute(ctx, getCidr(2, 5, 32))
+	s.Require().NoError(err)
+	s.Require().Equal(device, route.Interface.Name, "Route %s is not for device %s", route, device)
+}
+
 func (s *RoutingSuite) Test_RoutingTable() {
 	ctx := context.Background()
 	cidr := getCidr(2, 0, 24)
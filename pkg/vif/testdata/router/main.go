@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/netip"
 	"os"
 	"strings"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/subnet"
 	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
 	"github.com/telepresenceio/telepresence/v2/pkg/vif"
 )
@@ -55,15 +57,44 @@ func main() {
 			os.Exit(1)
 		}
 	}()
+	// Arguments on the form @<host>=<ip> populate a stub resolver, so that hosts can be resolved without DNS.
+	hosts := make(map[string][]netip.Addr)
+	var args []string
+	for _, arg := range os.Args[1:] {
+		if entry, ok := strings.CutPrefix(arg, "@"); ok {
+			host, ip, _ := strings.Cut(entry, "=")
+			var addr netip.Addr
+			if addr, err = netip.ParseAddr(ip); err != nil {
+				return
+			}
+			hosts[host] = append(hosts[host], addr)
+		} else {
+			args = append(args, arg)
+		}
+	}
+	lookup := func(_ context.Context, _, host string) ([]netip.Addr, error) {
+		if addrs, ok := hosts[host]; ok {
+			return addrs, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
 	yesRoutes := []netip.Prefix{}
 	noRoutes := []netip.Prefix{}
 	whitelist := []netip.Prefix{}
-	for _, cidr := range os.Args[1:] {
+	for _, cidr := range args {
 		var ipnet netip.Prefix
 		if strings.HasPrefix(cidr, "!") {
 			if ipnet, err = netip.ParsePrefix(strings.TrimPrefix(cidr, "!")); err == nil {
 				fmt.Printf("Blacklisting route: %s\n", ipnet)
 				noRoutes = append(noRoutes, ipnet)
+			} else {
+				// Not a CIDR, so treat it as a host that is resolved into never-proxy subnets.
+				var hostNets []netip.Prefix
+				if hostNets, err = subnet.ResolveHosts(ctx, lookup, []string{strings.TrimPrefix(cidr, "!")}); err == nil {
+					fmt.Printf("Blacklisting host routes: %s\n", hostNets)
+					noRoutes = append(noRoutes, hostNets...)
+				}
 			}
 		} else if strings.HasPrefix(cidr, "+") {
 			if ipnet, err = netip.ParsePrefix(strings.TrimPrefix(cidr, "+")); err == nil {
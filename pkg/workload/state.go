@@ -18,6 +18,7 @@ const (
 	StateProgressing
 	StateAvailable
 	StateFailure
+	StatePaused
 )
 
 func deploymentState(d *appsv1.Deployment) State {
@@ -61,6 +62,10 @@ func statefulSetState(_ *appsv1.StatefulSet) State {
 }
 
 func rolloutSetState(r *argorollouts.Rollout) State {
+	// A paused rollout keeps its Progressing condition until it's resumed, so it must be checked first.
+	if r.Spec.Paused || len(r.Status.PauseConditions) > 0 || r.Status.Phase == argorollouts.RolloutPhasePaused {
+		return StatePaused
+	}
 	conds := r.Status.Conditions
 	sort.Slice(conds, func(i, j int) bool {
 		return conds[i].LastTransitionTime.Compare(conds[j].LastTransitionTime.Time) > 0
@@ -95,6 +100,8 @@ func (ws State) String() string {
 		return "Available"
 	case StateFailure:
 		return "Failure"
+	case StatePaused:
+		return "Paused"
 	default:
 		return "Unknown"
 	}
@@ -124,6 +131,8 @@ func StateFromRPC(s manager.WorkloadInfo_State) State {
 		return StateFailure
 	case manager.WorkloadInfo_PROGRESSING:
 		return StateProgressing
+	case manager.WorkloadInfo_PAUSED:
+		return StatePaused
 	default:
 		return StateUnknown
 	}
@@ -0,0 +1,83 @@
+package workload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argorollouts "github.com/datawire/argo-rollouts-go-client/pkg/apis/rollouts/v1alpha1"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+func TestRolloutState(t *testing.T) {
+	progressing := argorollouts.RolloutStatus{
+		Conditions: []argorollouts.RolloutCondition{{
+			Type:               argorollouts.RolloutProgressing,
+			Status:             core.ConditionTrue,
+			LastTransitionTime: meta.Now(),
+		}},
+	}
+	tests := []struct {
+		name   string
+		paused bool
+		status func(argorollouts.RolloutStatus) argorollouts.RolloutStatus
+		want   State
+	}{
+		{
+			name:   "progressing",
+			status: func(s argorollouts.RolloutStatus) argorollouts.RolloutStatus { return s },
+			want:   StateProgressing,
+		},
+		{
+			name:   "paused by spec",
+			paused: true,
+			status: func(s argorollouts.RolloutStatus) argorollouts.RolloutStatus { return s },
+			want:   StatePaused,
+		},
+		{
+			name: "paused by canary step",
+			status: func(s argorollouts.RolloutStatus) argorollouts.RolloutStatus {
+				s.PauseConditions = []argorollouts.PauseCondition{{Reason: argorollouts.PauseReasonCanaryPauseStep, StartTime: meta.Now()}}
+				s.ControllerPause = true
+				return s
+			},
+			want: StatePaused,
+		},
+		{
+			name: "paused phase",
+			status: func(s argorollouts.RolloutStatus) argorollouts.RolloutStatus {
+				s.Phase = argorollouts.RolloutPhasePaused
+				return s
+			},
+			want: StatePaused,
+		},
+		{
+			name: "resumed",
+			status: func(s argorollouts.RolloutStatus) argorollouts.RolloutStatus {
+				// The controllerPause remains true after a manual resume.
+				s.ControllerPause = true
+				return s
+			},
+			want: StateProgressing,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ro := &argorollouts.Rollout{
+				TypeMeta:   meta.TypeMeta{Kind: "Rollout"},
+				ObjectMeta: meta.ObjectMeta{Name: "echo", Namespace: "default"},
+				Spec:       argorollouts.RolloutSpec{Paused: tt.paused},
+				Status:     tt.status(*progressing.DeepCopy()),
+			}
+			assert.Equal(t, tt.want, GetWorkloadState(k8sapi.Rollout(ro)))
+		})
+	}
+}
+
+func TestStateFromRPC(t *testing.T) {
+	assert.Equal(t, StatePaused, StateFromRPC(manager.WorkloadInfo_PAUSED))
+	assert.Equal(t, "Paused", StatePaused.String())
+}
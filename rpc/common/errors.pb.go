@@ -43,6 +43,7 @@ const (
 	InterceptError_MOUNT_POINT_BUSY           InterceptError = 13
 	InterceptError_UNKNOWN_FLAG               InterceptError = 15
 	InterceptError_EXEC_CMD                   InterceptError = 16 // External exec command failed
+	InterceptError_PERMISSION_DENIED          InterceptError = 18 // The client lacks the RBAC permissions needed
 )
 
 // Enum value maps for InterceptError.
@@ -66,6 +67,7 @@ var (
 		13: "MOUNT_POINT_BUSY",
 		15: "UNKNOWN_FLAG",
 		16: "EXEC_CMD",
+		18: "PERMISSION_DENIED",
 	}
 	InterceptError_value = map[string]int32{
 		"UNSPECIFIED":                0,
@@ -86,6 +88,7 @@ var (
 		"MOUNT_POINT_BUSY":           13,
 		"UNKNOWN_FLAG":               15,
 		"EXEC_CMD":                   16,
+		"PERMISSION_DENIED":          18,
 	}
 )
 
@@ -242,7 +245,7 @@ var file_common_errors_proto_rawDesc = []byte{
 	0x04, 0x55, 0x53, 0x45, 0x52, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x4e, 0x46, 0x49,
 	0x47, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x4e, 0x4f, 0x5f, 0x44, 0x41, 0x45, 0x4d, 0x4f, 0x4e,
 	0x5f, 0x4c, 0x4f, 0x47, 0x53, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f,
-	0x57, 0x4e, 0x10, 0x04, 0x2a, 0xa0, 0x03, 0x0a, 0x0e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65,
+	0x57, 0x4e, 0x10, 0x04, 0x2a, 0xb7, 0x03, 0x0a, 0x0e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65,
 	0x70, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45,
 	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x4e, 0x54, 0x45,
 	0x52, 0x4e, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x4e, 0x4f, 0x5f, 0x43, 0x4f, 0x4e,
@@ -268,11 +271,12 @@ var file_common_errors_proto_rawDesc = []byte{
 	0x0c, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x4f, 0x55, 0x4e, 0x54, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54,
 	0x5f, 0x42, 0x55, 0x53, 0x59, 0x10, 0x0d, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x4e, 0x4b, 0x4e, 0x4f,
 	0x57, 0x4e, 0x5f, 0x46, 0x4c, 0x41, 0x47, 0x10, 0x0f, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x58, 0x45,
-	0x43, 0x5f, 0x43, 0x4d, 0x44, 0x10, 0x10, 0x42, 0x36, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x65, 0x6c, 0x65, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e,
-	0x63, 0x65, 0x69, 0x6f, 0x2f, 0x74, 0x65, 0x6c, 0x65, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63,
-	0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x32, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x43, 0x5f, 0x43, 0x4d, 0x44, 0x10, 0x10, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x45, 0x52, 0x4d, 0x49,
+	0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x4e, 0x49, 0x45, 0x44, 0x10, 0x12, 0x42, 0x36,
+	0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x65, 0x6c,
+	0x65, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x69, 0x6f, 0x2f, 0x74, 0x65, 0x6c, 0x65,
+	0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x32, 0x2f,
+	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -32,10 +32,12 @@ const (
 	Connector_Disconnect_FullMethodName              = "/telepresence.connector.Connector/Disconnect"
 	Connector_GetClusterSubnets_FullMethodName       = "/telepresence.connector.Connector/GetClusterSubnets"
 	Connector_Status_FullMethodName                  = "/telepresence.connector.Connector/Status"
+	Connector_WatchStatus_FullMethodName             = "/telepresence.connector.Connector/WatchStatus"
 	Connector_CanIntercept_FullMethodName            = "/telepresence.connector.Connector/CanIntercept"
 	Connector_Ingest_FullMethodName                  = "/telepresence.connector.Connector/Ingest"
 	Connector_GetIngest_FullMethodName               = "/telepresence.connector.Connector/GetIngest"
 	Connector_LeaveIngest_FullMethodName             = "/telepresence.connector.Connector/LeaveIngest"
+	Connector_ListIngests_FullMethodName             = "/telepresence.connector.Connector/ListIngests"
 	Connector_CreateIntercept_FullMethodName         = "/telepresence.connector.Connector/CreateIntercept"
 	Connector_RemoveIntercept_FullMethodName         = "/telepresence.connector.Connector/RemoveIntercept"
 	Connector_UpdateIntercept_FullMethodName         = "/telepresence.connector.Connector/UpdateIntercept"
@@ -54,6 +56,12 @@ const (
 	Connector_SetDNSExcludes_FullMethodName          = "/telepresence.connector.Connector/SetDNSExcludes"
 	Connector_SetDNSMappings_FullMethodName          = "/telepresence.connector.Connector/SetDNSMappings"
 	Connector_GetAgentConfig_FullMethodName          = "/telepresence.connector.Connector/GetAgentConfig"
+	Connector_WatchInterceptTraffic_FullMethodName   = "/telepresence.connector.Connector/WatchInterceptTraffic"
+	Connector_GetVirtualIPs_FullMethodName           = "/telepresence.connector.Connector/GetVirtualIPs"
+	Connector_GetRouteConflicts_FullMethodName       = "/telepresence.connector.Connector/GetRouteConflicts"
+	Connector_GetDNSSearchPaths_FullMethodName       = "/telepresence.connector.Connector/GetDNSSearchPaths"
+	Connector_UpdateRouting_FullMethodName           = "/telepresence.connector.Connector/UpdateRouting"
+	Connector_GetInterceptHolders_FullMethodName     = "/telepresence.connector.Connector/GetInterceptHolders"
 )
 
 // ConnectorClient is the client API for Connector service.
@@ -87,6 +95,9 @@ type ConnectorClient interface {
 	// Status returns the status of the current connection or DISCONNECTED
 	// if no connection has been established.
 	Status(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConnectInfo, error)
+	// WatchStatus streams the status of the current connection. A new status
+	// is sent initially and then each time the status changes.
+	WatchStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConnectInfo], error)
 	// Queries the connector whether it is possible to create the given intercept.
 	CanIntercept(ctx context.Context, in *CreateInterceptRequest, opts ...grpc.CallOption) (*InterceptResult, error)
 	// Starts an Ingest session.
@@ -95,6 +106,8 @@ type ConnectorClient interface {
 	GetIngest(ctx context.Context, in *IngestIdentifier, opts ...grpc.CallOption) (*IngestInfo, error)
 	// Ends an Ingest session.
 	LeaveIngest(ctx context.Context, in *IngestIdentifier, opts ...grpc.CallOption) (*IngestInfo, error)
+	// Lists the ongoing Ingest sessions.
+	ListIngests(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*IngestInfoSnapshot, error)
 	// Adds an intercept to a workload.  Requires having already called
 	// Connect.
 	CreateIntercept(ctx context.Context, in *CreateInterceptRequest, opts ...grpc.CallOption) (*InterceptResult, error)
@@ -137,6 +150,20 @@ type ConnectorClient interface {
 	SetDNSMappings(ctx context.Context, in *daemon.SetDNSMappingsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// GetAgentConfig returns the agent configuration for a specific workload.
 	GetAgentConfig(ctx context.Context, in *manager.AgentConfigRequest, opts ...grpc.CallOption) (*manager.AgentConfigResponse, error)
+	// WatchInterceptTraffic streams a summary of each connection that is routed to the
+	// local handler of the given intercept. A summary is sent when the connection ends.
+	WatchInterceptTraffic(ctx context.Context, in *InterceptTrafficRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InterceptTrafficEntry], error)
+	// GetVirtualIPs returns the current translations between remote IPs and virtual IPs.
+	GetVirtualIPs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*daemon.VirtualIPs, error)
+	// GetRouteConflicts returns the conflicts between the subnets that the session routes and existing routes.
+	GetRouteConflicts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*daemon.RouteConflicts, error)
+	// GetDNSSearchPaths returns the search paths and drop suffixes that are currently in use by the DNS resolver.
+	GetDNSSearchPaths(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*daemon.DNSSearchPaths, error)
+	// UpdateRouting adds or removes also-proxy and never-proxy subnets of the current session and
+	// updates the routes accordingly, without the need to reconnect.
+	UpdateRouting(ctx context.Context, in *daemon.UpdateRoutingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetInterceptHolders returns the clients that currently intercept the given workload.
+	GetInterceptHolders(ctx context.Context, in *InterceptHoldersRequest, opts ...grpc.CallOption) (*InterceptHolders, error)
 }
 
 type connectorClient struct {
@@ -237,6 +264,25 @@ func (c *connectorClient) Status(ctx context.Context, in *emptypb.Empty, opts ..
 	return out, nil
 }
 
+func (c *connectorClient) WatchStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConnectInfo], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Connector_ServiceDesc.Streams[0], Connector_WatchStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[emptypb.Empty, ConnectInfo]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connector_WatchStatusClient = grpc.ServerStreamingClient[ConnectInfo]
+
 func (c *connectorClient) CanIntercept(ctx context.Context, in *CreateInterceptRequest, opts ...grpc.CallOption) (*InterceptResult, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(InterceptResult)
@@ -277,6 +323,16 @@ func (c *connectorClient) LeaveIngest(ctx context.Context, in *IngestIdentifier,
 	return out, nil
 }
 
+func (c *connectorClient) ListIngests(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*IngestInfoSnapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IngestInfoSnapshot)
+	err := c.cc.Invoke(ctx, Connector_ListIngests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *connectorClient) CreateIntercept(ctx context.Context, in *CreateInterceptRequest, opts ...grpc.CallOption) (*InterceptResult, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(InterceptResult)
@@ -329,7 +385,7 @@ func (c *connectorClient) List(ctx context.Context, in *ListRequest, opts ...grp
 
 func (c *connectorClient) WatchWorkloads(ctx context.Context, in *WatchWorkloadsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WorkloadInfoSnapshot], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Connector_ServiceDesc.Streams[0], Connector_WatchWorkloads_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Connector_ServiceDesc.Streams[1], Connector_WatchWorkloads_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -466,6 +522,75 @@ func (c *connectorClient) GetAgentConfig(ctx context.Context, in *manager.AgentC
 	return out, nil
 }
 
+func (c *connectorClient) WatchInterceptTraffic(ctx context.Context, in *InterceptTrafficRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InterceptTrafficEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Connector_ServiceDesc.Streams[2], Connector_WatchInterceptTraffic_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[InterceptTrafficRequest, InterceptTrafficEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connector_WatchInterceptTrafficClient = grpc.ServerStreamingClient[InterceptTrafficEntry]
+
+func (c *connectorClient) GetVirtualIPs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*daemon.VirtualIPs, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(daemon.VirtualIPs)
+	err := c.cc.Invoke(ctx, Connector_GetVirtualIPs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectorClient) GetRouteConflicts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*daemon.RouteConflicts, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(daemon.RouteConflicts)
+	err := c.cc.Invoke(ctx, Connector_GetRouteConflicts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectorClient) GetDNSSearchPaths(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*daemon.DNSSearchPaths, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(daemon.DNSSearchPaths)
+	err := c.cc.Invoke(ctx, Connector_GetDNSSearchPaths_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectorClient) UpdateRouting(ctx context.Context, in *daemon.UpdateRoutingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Connector_UpdateRouting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectorClient) GetInterceptHolders(ctx context.Context, in *InterceptHoldersRequest, opts ...grpc.CallOption) (*InterceptHolders, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InterceptHolders)
+	err := c.cc.Invoke(ctx, Connector_GetInterceptHolders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConnectorServer is the server API for Connector service.
 // All implementations must embed UnimplementedConnectorServer
 // for forward compatibility.
@@ -497,6 +622,9 @@ type ConnectorServer interface {
 	// Status returns the status of the current connection or DISCONNECTED
 	// if no connection has been established.
 	Status(context.Context, *emptypb.Empty) (*ConnectInfo, error)
+	// WatchStatus streams the status of the current connection. A new status
+	// is sent initially and then each time the status changes.
+	WatchStatus(*emptypb.Empty, grpc.ServerStreamingServer[ConnectInfo]) error
 	// Queries the connector whether it is possible to create the given intercept.
 	CanIntercept(context.Context, *CreateInterceptRequest) (*InterceptResult, error)
 	// Starts an Ingest session.
@@ -505,6 +633,8 @@ type ConnectorServer interface {
 	GetIngest(context.Context, *IngestIdentifier) (*IngestInfo, error)
 	// Ends an Ingest session.
 	LeaveIngest(context.Context, *IngestIdentifier) (*IngestInfo, error)
+	// Lists the ongoing Ingest sessions.
+	ListIngests(context.Context, *emptypb.Empty) (*IngestInfoSnapshot, error)
 	// Adds an intercept to a workload.  Requires having already called
 	// Connect.
 	CreateIntercept(context.Context, *CreateInterceptRequest) (*InterceptResult, error)
@@ -547,6 +677,20 @@ type ConnectorServer interface {
 	SetDNSMappings(context.Context, *daemon.SetDNSMappingsRequest) (*emptypb.Empty, error)
 	// GetAgentConfig returns the agent configuration for a specific workload.
 	GetAgentConfig(context.Context, *manager.AgentConfigRequest) (*manager.AgentConfigResponse, error)
+	// WatchInterceptTraffic streams a summary of each connection that is routed to the
+	// local handler of the given intercept. A summary is sent when the connection ends.
+	WatchInterceptTraffic(*InterceptTrafficRequest, grpc.ServerStreamingServer[InterceptTrafficEntry]) error
+	// GetVirtualIPs returns the current translations between remote IPs and virtual IPs.
+	GetVirtualIPs(context.Context, *emptypb.Empty) (*daemon.VirtualIPs, error)
+	// GetRouteConflicts returns the conflicts between the subnets that the session routes and existing routes.
+	GetRouteConflicts(context.Context, *emptypb.Empty) (*daemon.RouteConflicts, error)
+	// GetDNSSearchPaths returns the search paths and drop suffixes that are currently in use by the DNS resolver.
+	GetDNSSearchPaths(context.Context, *emptypb.Empty) (*daemon.DNSSearchPaths, error)
+	// UpdateRouting adds or removes also-proxy and never-proxy subnets of the current session and
+	// updates the routes accordingly, without the need to reconnect.
+	UpdateRouting(context.Context, *daemon.UpdateRoutingRequest) (*emptypb.Empty, error)
+	// GetInterceptHolders returns the clients that currently intercept the given workload.
+	GetInterceptHolders(context.Context, *InterceptHoldersRequest) (*InterceptHolders, error)
 	mustEmbedUnimplementedConnectorServer()
 }
 
@@ -584,6 +728,9 @@ func (UnimplementedConnectorServer) GetClusterSubnets(context.Context, *emptypb.
 func (UnimplementedConnectorServer) Status(context.Context, *emptypb.Empty) (*ConnectInfo, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (UnimplementedConnectorServer) WatchStatus(*emptypb.Empty, grpc.ServerStreamingServer[ConnectInfo]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
 func (UnimplementedConnectorServer) CanIntercept(context.Context, *CreateInterceptRequest) (*InterceptResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CanIntercept not implemented")
 }
@@ -596,6 +743,9 @@ func (UnimplementedConnectorServer) GetIngest(context.Context, *IngestIdentifier
 func (UnimplementedConnectorServer) LeaveIngest(context.Context, *IngestIdentifier) (*IngestInfo, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LeaveIngest not implemented")
 }
+func (UnimplementedConnectorServer) ListIngests(context.Context, *emptypb.Empty) (*IngestInfoSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIngests not implemented")
+}
 func (UnimplementedConnectorServer) CreateIntercept(context.Context, *CreateInterceptRequest) (*InterceptResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateIntercept not implemented")
 }
@@ -650,6 +800,24 @@ func (UnimplementedConnectorServer) SetDNSMappings(context.Context, *daemon.SetD
 func (UnimplementedConnectorServer) GetAgentConfig(context.Context, *manager.AgentConfigRequest) (*manager.AgentConfigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAgentConfig not implemented")
 }
+func (UnimplementedConnectorServer) WatchInterceptTraffic(*InterceptTrafficRequest, grpc.ServerStreamingServer[InterceptTrafficEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchInterceptTraffic not implemented")
+}
+func (UnimplementedConnectorServer) GetVirtualIPs(context.Context, *emptypb.Empty) (*daemon.VirtualIPs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVirtualIPs not implemented")
+}
+func (UnimplementedConnectorServer) GetRouteConflicts(context.Context, *emptypb.Empty) (*daemon.RouteConflicts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRouteConflicts not implemented")
+}
+func (UnimplementedConnectorServer) GetDNSSearchPaths(context.Context, *emptypb.Empty) (*daemon.DNSSearchPaths, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDNSSearchPaths not implemented")
+}
+func (UnimplementedConnectorServer) UpdateRouting(context.Context, *daemon.UpdateRoutingRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRouting not implemented")
+}
+func (UnimplementedConnectorServer) GetInterceptHolders(context.Context, *InterceptHoldersRequest) (*InterceptHolders, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInterceptHolders not implemented")
+}
 func (UnimplementedConnectorServer) mustEmbedUnimplementedConnectorServer() {}
 func (UnimplementedConnectorServer) testEmbeddedByValue()                   {}
 
@@ -833,6 +1001,17 @@ func _Connector_Status_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Connector_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConnectorServer).WatchStatus(m, &grpc.GenericServerStream[emptypb.Empty, ConnectInfo]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connector_WatchStatusServer = grpc.ServerStreamingServer[ConnectInfo]
+
 func _Connector_CanIntercept_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateInterceptRequest)
 	if err := dec(in); err != nil {
@@ -905,6 +1084,24 @@ func _Connector_LeaveIngest_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Connector_ListIngests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorServer).ListIngests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connector_ListIngests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorServer).ListIngests(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Connector_CreateIntercept_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateInterceptRequest)
 	if err := dec(in); err != nil {
@@ -1222,6 +1419,107 @@ func _Connector_GetAgentConfig_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Connector_WatchInterceptTraffic_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InterceptTrafficRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConnectorServer).WatchInterceptTraffic(m, &grpc.GenericServerStream[InterceptTrafficRequest, InterceptTrafficEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connector_WatchInterceptTrafficServer = grpc.ServerStreamingServer[InterceptTrafficEntry]
+
+func _Connector_GetVirtualIPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorServer).GetVirtualIPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connector_GetVirtualIPs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorServer).GetVirtualIPs(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connector_GetRouteConflicts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorServer).GetRouteConflicts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connector_GetRouteConflicts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorServer).GetRouteConflicts(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connector_GetDNSSearchPaths_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorServer).GetDNSSearchPaths(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connector_GetDNSSearchPaths_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorServer).GetDNSSearchPaths(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connector_UpdateRouting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(daemon.UpdateRoutingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorServer).UpdateRouting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connector_UpdateRouting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorServer).UpdateRouting(ctx, req.(*daemon.UpdateRoutingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connector_GetInterceptHolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InterceptHoldersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorServer).GetInterceptHolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connector_GetInterceptHolders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorServer).GetInterceptHolders(ctx, req.(*InterceptHoldersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Connector_ServiceDesc is the grpc.ServiceDesc for Connector service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1281,6 +1579,10 @@ var Connector_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LeaveIngest",
 			Handler:    _Connector_LeaveIngest_Handler,
 		},
+		{
+			MethodName: "ListIngests",
+			Handler:    _Connector_ListIngests_Handler,
+		},
 		{
 			MethodName: "CreateIntercept",
 			Handler:    _Connector_CreateIntercept_Handler,
@@ -1349,13 +1651,43 @@ var Connector_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAgentConfig",
 			Handler:    _Connector_GetAgentConfig_Handler,
 		},
+		{
+			MethodName: "GetVirtualIPs",
+			Handler:    _Connector_GetVirtualIPs_Handler,
+		},
+		{
+			MethodName: "GetRouteConflicts",
+			Handler:    _Connector_GetRouteConflicts_Handler,
+		},
+		{
+			MethodName: "GetDNSSearchPaths",
+			Handler:    _Connector_GetDNSSearchPaths_Handler,
+		},
+		{
+			MethodName: "UpdateRouting",
+			Handler:    _Connector_UpdateRouting_Handler,
+		},
+		{
+			MethodName: "GetInterceptHolders",
+			Handler:    _Connector_GetInterceptHolders_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _Connector_WatchStatus_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "WatchWorkloads",
 			Handler:       _Connector_WatchWorkloads_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchInterceptTraffic",
+			Handler:       _Connector_WatchInterceptTraffic_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "connector/connector.proto",
 }
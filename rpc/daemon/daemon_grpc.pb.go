@@ -35,6 +35,11 @@ const (
 	Daemon_TranslateEnvIPs_FullMethodName       = "/telepresence.daemon.Daemon/TranslateEnvIPs"
 	Daemon_WaitForNetwork_FullMethodName        = "/telepresence.daemon.Daemon/WaitForNetwork"
 	Daemon_WaitForAgentIP_FullMethodName        = "/telepresence.daemon.Daemon/WaitForAgentIP"
+	Daemon_GetVirtualIPs_FullMethodName         = "/telepresence.daemon.Daemon/GetVirtualIPs"
+	Daemon_GetRouteConflicts_FullMethodName     = "/telepresence.daemon.Daemon/GetRouteConflicts"
+	Daemon_GetDNSSearchPaths_FullMethodName     = "/telepresence.daemon.Daemon/GetDNSSearchPaths"
+	Daemon_UpdateRouting_FullMethodName         = "/telepresence.daemon.Daemon/UpdateRouting"
+	Daemon_GetTrafficActivity_FullMethodName    = "/telepresence.daemon.Daemon/GetTrafficActivity"
 )
 
 // DaemonClient is the client API for Daemon service.
@@ -70,6 +75,18 @@ type DaemonClient interface {
 	WaitForNetwork(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// WaitForAgentIP waits for the network of an intercepted agent to become ready.
 	WaitForAgentIP(ctx context.Context, in *WaitForAgentIPRequest, opts ...grpc.CallOption) (*WaitForAgentIPResponse, error)
+	// GetVirtualIPs returns the current translations between remote IPs and virtual IPs.
+	GetVirtualIPs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VirtualIPs, error)
+	// GetRouteConflicts returns the conflicts between the subnets that the session routes and
+	// existing routes, as detected the last time that a conflict was found.
+	GetRouteConflicts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RouteConflicts, error)
+	// GetDNSSearchPaths returns the search paths and drop suffixes that are currently in use by the DNS resolver.
+	GetDNSSearchPaths(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DNSSearchPaths, error)
+	// UpdateRouting adds or removes also-proxy and never-proxy subnets of the current session and
+	// updates the routes accordingly, without the need to reconnect.
+	UpdateRouting(ctx context.Context, in *UpdateRoutingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetTrafficActivity returns the last time that traffic flowed through the tunnels of the current session.
+	GetTrafficActivity(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TrafficActivity, error)
 }
 
 type daemonClient struct {
@@ -210,6 +227,56 @@ func (c *daemonClient) WaitForAgentIP(ctx context.Context, in *WaitForAgentIPReq
 	return out, nil
 }
 
+func (c *daemonClient) GetVirtualIPs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VirtualIPs, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VirtualIPs)
+	err := c.cc.Invoke(ctx, Daemon_GetVirtualIPs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) GetRouteConflicts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RouteConflicts, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RouteConflicts)
+	err := c.cc.Invoke(ctx, Daemon_GetRouteConflicts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) GetDNSSearchPaths(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DNSSearchPaths, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DNSSearchPaths)
+	err := c.cc.Invoke(ctx, Daemon_GetDNSSearchPaths_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) UpdateRouting(ctx context.Context, in *UpdateRoutingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Daemon_UpdateRouting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) GetTrafficActivity(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TrafficActivity, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrafficActivity)
+	err := c.cc.Invoke(ctx, Daemon_GetTrafficActivity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DaemonServer is the server API for Daemon service.
 // All implementations must embed UnimplementedDaemonServer
 // for forward compatibility.
@@ -243,6 +310,18 @@ type DaemonServer interface {
 	WaitForNetwork(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	// WaitForAgentIP waits for the network of an intercepted agent to become ready.
 	WaitForAgentIP(context.Context, *WaitForAgentIPRequest) (*WaitForAgentIPResponse, error)
+	// GetVirtualIPs returns the current translations between remote IPs and virtual IPs.
+	GetVirtualIPs(context.Context, *emptypb.Empty) (*VirtualIPs, error)
+	// GetRouteConflicts returns the conflicts between the subnets that the session routes and
+	// existing routes, as detected the last time that a conflict was found.
+	GetRouteConflicts(context.Context, *emptypb.Empty) (*RouteConflicts, error)
+	// GetDNSSearchPaths returns the search paths and drop suffixes that are currently in use by the DNS resolver.
+	GetDNSSearchPaths(context.Context, *emptypb.Empty) (*DNSSearchPaths, error)
+	// UpdateRouting adds or removes also-proxy and never-proxy subnets of the current session and
+	// updates the routes accordingly, without the need to reconnect.
+	UpdateRouting(context.Context, *UpdateRoutingRequest) (*emptypb.Empty, error)
+	// GetTrafficActivity returns the last time that traffic flowed through the tunnels of the current session.
+	GetTrafficActivity(context.Context, *emptypb.Empty) (*TrafficActivity, error)
 	mustEmbedUnimplementedDaemonServer()
 }
 
@@ -292,6 +371,21 @@ func (UnimplementedDaemonServer) WaitForNetwork(context.Context, *emptypb.Empty)
 func (UnimplementedDaemonServer) WaitForAgentIP(context.Context, *WaitForAgentIPRequest) (*WaitForAgentIPResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WaitForAgentIP not implemented")
 }
+func (UnimplementedDaemonServer) GetVirtualIPs(context.Context, *emptypb.Empty) (*VirtualIPs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVirtualIPs not implemented")
+}
+func (UnimplementedDaemonServer) GetRouteConflicts(context.Context, *emptypb.Empty) (*RouteConflicts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRouteConflicts not implemented")
+}
+func (UnimplementedDaemonServer) GetDNSSearchPaths(context.Context, *emptypb.Empty) (*DNSSearchPaths, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDNSSearchPaths not implemented")
+}
+func (UnimplementedDaemonServer) UpdateRouting(context.Context, *UpdateRoutingRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRouting not implemented")
+}
+func (UnimplementedDaemonServer) GetTrafficActivity(context.Context, *emptypb.Empty) (*TrafficActivity, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrafficActivity not implemented")
+}
 func (UnimplementedDaemonServer) mustEmbedUnimplementedDaemonServer() {}
 func (UnimplementedDaemonServer) testEmbeddedByValue()                {}
 
@@ -547,6 +641,96 @@ func _Daemon_WaitForAgentIP_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Daemon_GetVirtualIPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).GetVirtualIPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Daemon_GetVirtualIPs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).GetVirtualIPs(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_GetRouteConflicts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).GetRouteConflicts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Daemon_GetRouteConflicts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).GetRouteConflicts(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_GetDNSSearchPaths_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).GetDNSSearchPaths(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Daemon_GetDNSSearchPaths_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).GetDNSSearchPaths(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_UpdateRouting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRoutingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).UpdateRouting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Daemon_UpdateRouting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).UpdateRouting(ctx, req.(*UpdateRoutingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_GetTrafficActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).GetTrafficActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Daemon_GetTrafficActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).GetTrafficActivity(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Daemon_ServiceDesc is the grpc.ServiceDesc for Daemon service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -606,6 +790,26 @@ var Daemon_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "WaitForAgentIP",
 			Handler:    _Daemon_WaitForAgentIP_Handler,
 		},
+		{
+			MethodName: "GetVirtualIPs",
+			Handler:    _Daemon_GetVirtualIPs_Handler,
+		},
+		{
+			MethodName: "GetRouteConflicts",
+			Handler:    _Daemon_GetRouteConflicts_Handler,
+		},
+		{
+			MethodName: "GetDNSSearchPaths",
+			Handler:    _Daemon_GetDNSSearchPaths_Handler,
+		},
+		{
+			MethodName: "UpdateRouting",
+			Handler:    _Daemon_UpdateRouting_Handler,
+		},
+		{
+			MethodName: "GetTrafficActivity",
+			Handler:    _Daemon_GetTrafficActivity_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "daemon/daemon.proto",